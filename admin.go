@@ -0,0 +1,816 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// NewAdminHandler serves operator actions on a running process:
+//
+//	GET  /admin/orders               the OrderList of each tenant
+//	POST /admin/orders               place an order for the client, see PlaceOrderAs
+//	POST /admin/orders/cancel-all    cancel open orders, see CancelAll
+//	POST /admin/drain                drain and log out for maintenance, see Drain
+//	POST /admin/resume               start drained tenants again
+//	GET  /admin/venue                the symbols each tenant holds halted
+//	POST /admin/venue/resume         clear a symbol halt, see VenueStatus.Resume
+//	POST /admin/breaker/reset        close the order circuit breaker, see CircuitBreaker
+//	POST /admin/price-breaker/reset  close the fill price breaker of a symbol
+//	GET  /admin/scheduled            the orders each tenant holds for later
+//	POST /admin/scheduled/cancel     cancel a scheduled order, see OrderScheduler
+//	POST /admin/messages             send a custom message, see SendCustom
+//
+// orders takes the source (local or venue) and timeout query parameters.
+// cancel-all takes the symbol, portfolio, older-than and pace query
+// parameters and answers the CancelResult of each order as JSON. POST orders
+// takes an OrderRequest as JSON and the tenant parameter, which is required
+// with several tenants, and answers the ClOrdID of the order. drain takes
+// the cancel, pace, wait and reason parameters. drain and resume apply to
+// the tenant parameter, or to every tenant without it, as do venue/resume,
+// which takes the symbol parameter and answers whether it was halted, and
+// breaker/reset and price-breaker/reset, which answer whether each breaker
+// was open; price-breaker/reset takes the symbol parameter. scheduled/cancel
+// takes the id parameter and answers 404 when no tenant holds it. messages
+// sends the custom message of the type parameter with each field parameter,
+// TAG=VALUE, on the tenant parameter, which is required with several tenants.
+func NewAdminHandler(manager *Manager) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/orders", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		source := query.Get("source")
+		timeout, ok := durationParam(w, query, "timeout", 10*time.Second)
+		if !ok {
+			return
+		}
+		if source != "" && source != "local" && source != "venue" {
+			http.Error(w, "invalid source: "+source, http.StatusBadRequest)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		lists := []OrderList{}
+		for _, tenant := range manager.Tenants() {
+			if source != "venue" {
+				lists = append(lists, tenant.App.LocalOrders())
+				continue
+			}
+			list, err := tenant.App.VenueOrders(ctx)
+			if err != nil {
+				http.Error(w, tenant.Name+": "+err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			lists = append(lists, list)
+		}
+		writeDebugJSON(w, lists)
+	})
+	mux.HandleFunc("POST /admin/orders", func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := PrincipalFromContext(r.Context())
+		if !ok {
+			http.Error(w, ErrForbidden.Error(), http.StatusForbidden)
+			return
+		}
+		tenant, err := adminTenant(manager, r.URL.Query().Get("tenant"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var req OrderRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid order: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		clOrdId, err := tenant.App.PlaceOrderAs(principal, req)
+		if err != nil {
+			var riskErr *RiskError
+			status := http.StatusUnprocessableEntity
+			switch {
+			case errors.Is(err, ErrForbidden):
+				status = http.StatusForbidden
+			case errors.As(err, &riskErr) && strings.HasPrefix(riskErr.Rule, "GatewayQuota"):
+				status = http.StatusTooManyRequests
+			case errors.Is(err, ErrNotLoggedOn):
+				status = http.StatusServiceUnavailable
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeDebugJSON(w, map[string]string{"clOrdId": clOrdId})
+	})
+	mux.HandleFunc("POST /admin/orders/cancel-all", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := CancelFilter{Symbol: query.Get("symbol"), PortfolioId: query.Get("portfolio")}
+		var ok bool
+		if filter.OlderThan, ok = durationParam(w, query, "older-than", 0); !ok {
+			return
+		}
+		pace, ok := durationParam(w, query, "pace", 0)
+		if !ok {
+			return
+		}
+
+		results := []CancelResult{}
+		for _, tenant := range manager.Tenants() {
+			tenant.App.logger().Printf("Cancel all requested by %s: symbol=%q portfolio=%q older-than=%s",
+				adminPrincipal(r), filter.Symbol, filter.PortfolioId, filter.OlderThan)
+			for _, result := range tenant.App.CancelAll(filter, pace) {
+				result.Tenant = tenant.Name
+				results = append(results, result)
+			}
+		}
+		writeDebugJSON(w, results)
+	})
+	mux.HandleFunc("POST /admin/drain", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		opts := DrainOptions{Cancel: query.Get("cancel") == "true", Reason: query.Get("reason")}
+		var ok bool
+		if opts.Pace, ok = durationParam(w, query, "pace", 100*time.Millisecond); !ok {
+			return
+		}
+		if opts.Wait, ok = durationParam(w, query, "wait", 30*time.Second); !ok {
+			return
+		}
+		if opts.Reason == "" {
+			opts.Reason = "maintenance"
+		}
+
+		log.Printf("Drain requested by %s: tenant=%q cancel=%v wait=%s", adminPrincipal(r), query.Get("tenant"), opts.Cancel, opts.Wait)
+		var results []DrainResult
+		if name := query.Get("tenant"); name != "" {
+			results = []DrainResult{manager.Drain(r.Context(), name, opts)}
+		} else {
+			results = manager.DrainAll(r.Context(), opts)
+		}
+		writeDebugJSON(w, results)
+	})
+	mux.HandleFunc("POST /admin/resume", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("tenant")
+		log.Printf("Resume requested by %s: tenant=%q", adminPrincipal(r), name)
+		var err error
+		if name != "" {
+			err = manager.Start(name)
+		} else {
+			err = manager.StartAll()
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeDebugJSON(w, map[string]bool{"resumed": true})
+	})
+	mux.HandleFunc("GET /admin/venue", func(w http.ResponseWriter, r *http.Request) {
+		halted := make(map[string]map[string]string)
+		for _, tenant := range manager.Tenants() {
+			if tenant.App.Venue != nil {
+				halted[tenant.Name] = tenant.App.Venue.Halted()
+			}
+		}
+		writeDebugJSON(w, halted)
+	})
+	mux.HandleFunc("POST /admin/venue/resume", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		symbol := query.Get("symbol")
+		if symbol == "" {
+			http.Error(w, "symbol is required", http.StatusBadRequest)
+			return
+		}
+		tenants, ok := adminTenants(w, manager, query.Get("tenant"))
+		if !ok {
+			return
+		}
+
+		log.Printf("Venue resume of %s requested by %s: tenant=%q", symbol, adminPrincipal(r), query.Get("tenant"))
+		resumed := make(map[string]bool)
+		for _, tenant := range tenants {
+			if tenant.App.Venue != nil {
+				resumed[tenant.Name] = tenant.App.Venue.Resume(symbol)
+			}
+		}
+		writeDebugJSON(w, resumed)
+	})
+	mux.HandleFunc("POST /admin/breaker/reset", func(w http.ResponseWriter, r *http.Request) {
+		tenants, ok := adminTenants(w, manager, r.URL.Query().Get("tenant"))
+		if !ok {
+			return
+		}
+		log.Printf("Circuit breaker reset requested by %s: tenant=%q", adminPrincipal(r), r.URL.Query().Get("tenant"))
+		wasOpen := make(map[string]bool)
+		for _, tenant := range tenants {
+			if tenant.App.Breaker != nil {
+				wasOpen[tenant.Name] = tenant.App.Breaker.Reset()
+			}
+		}
+		writeDebugJSON(w, wasOpen)
+	})
+	mux.HandleFunc("POST /admin/price-breaker/reset", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		symbol := query.Get("symbol")
+		if symbol == "" {
+			http.Error(w, "symbol is required", http.StatusBadRequest)
+			return
+		}
+		tenants, ok := adminTenants(w, manager, query.Get("tenant"))
+		if !ok {
+			return
+		}
+		log.Printf("Price breaker reset of %s requested by %s: tenant=%q", symbol, adminPrincipal(r), query.Get("tenant"))
+		wasOpen := make(map[string]bool)
+		for _, tenant := range tenants {
+			if tenant.App.PriceBreakers != nil {
+				wasOpen[tenant.Name] = tenant.App.PriceBreakers.Reset(symbol)
+			}
+		}
+		writeDebugJSON(w, wasOpen)
+	})
+	mux.HandleFunc("GET /admin/scheduled", func(w http.ResponseWriter, r *http.Request) {
+		scheduled := make(map[string][]ScheduledOrder)
+		for _, tenant := range manager.Tenants() {
+			if tenant.App.Scheduler != nil {
+				scheduled[tenant.Name] = tenant.App.Scheduler.Pending()
+			}
+		}
+		writeDebugJSON(w, scheduled)
+	})
+	mux.HandleFunc("POST /admin/scheduled/cancel", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		id := query.Get("id")
+		if id == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		tenants, ok := adminTenants(w, manager, query.Get("tenant"))
+		if !ok {
+			return
+		}
+
+		log.Printf("Cancel of scheduled order %s requested by %s", id, adminPrincipal(r))
+		canceled := make(map[string]bool)
+		for _, tenant := range tenants {
+			if tenant.App.Scheduler == nil {
+				continue
+			}
+			err := tenant.App.Scheduler.Cancel(id)
+			if err == nil {
+				canceled[tenant.Name] = true
+			} else if !errors.Is(err, ErrNoScheduledOrder) {
+				http.Error(w, tenant.Name+": "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		if len(canceled) == 0 {
+			http.Error(w, ErrNoScheduledOrder.Error()+" "+id, http.StatusNotFound)
+			return
+		}
+		writeDebugJSON(w, canceled)
+	})
+	mux.HandleFunc("POST /admin/messages", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		tenant, err := adminTenant(manager, query.Get("tenant"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fields := make(map[int]string)
+		for _, field := range query["field"] {
+			tag, value, err := parseTagValue(field)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			fields[tag] = value
+		}
+
+		msgType := query.Get("type")
+		tenant.App.logger().Printf("Custom %s message requested by %s", msgType, adminPrincipal(r))
+		if err := tenant.App.SendCustom(msgType, fields); err != nil {
+			status := http.StatusServiceUnavailable
+			if errors.Is(err, ErrUnregisteredMessage) || errors.Is(err, ErrInvalidMessage) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeDebugJSON(w, map[string]bool{"sent": true})
+	})
+	return mux
+}
+
+// adminTenant returns the tenant name, or the only tenant when name is empty
+func adminTenant(manager *Manager, name string) (*Tenant, error) {
+	if name != "" {
+		if tenant, ok := manager.Get(name); ok {
+			return tenant, nil
+		}
+		return nil, errors.New("unknown tenant: " + name)
+	}
+	tenants := manager.Tenants()
+	if len(tenants) != 1 {
+		return nil, errors.New("tenant is required with several tenants")
+	}
+	return tenants[0], nil
+}
+
+// adminTenants returns the tenant name, or every tenant when name is empty,
+// answering 400 for an unknown tenant
+func adminTenants(w http.ResponseWriter, manager *Manager, name string) ([]*Tenant, bool) {
+	if name == "" {
+		return manager.Tenants(), true
+	}
+	tenant, err := adminTenant(manager, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return []*Tenant{tenant}, true
+}
+
+// durationParam parses the duration query parameter name, answering 400 when
+// it is invalid or negative
+func durationParam(w http.ResponseWriter, query url.Values, name string, fallback time.Duration) (time.Duration, bool) {
+	value := query.Get(name)
+	if value == "" {
+		return fallback, true
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		http.Error(w, "invalid "+name+": "+value, http.StatusBadRequest)
+		return 0, false
+	}
+	return parsed, true
+}
+
+// adminPrincipal names the client of an admin request, for the logs
+func adminPrincipal(r *http.Request) string {
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		return principal.Name
+	}
+	return "admin"
+}
+
+// adminClient calls the NewAdminHandler endpoints of a running process
+type adminClient struct {
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+// adminClientFlags registers the flags locating the running process, returning
+// a function that builds its client once the flags are parsed
+func adminClientFlags(flags *flag.FlagSet) func() (*adminClient, error) {
+	config := flags.String("config", "fix.cfg", "config file the client runs with")
+	addr := flags.String("url", "", "URL of the running client (defaults to DebugListenAddr)")
+	token := flags.String("token", os.Getenv("PRIMEFIX_GATEWAY_TOKEN"), "trade API token (defaults to PRIMEFIX_GATEWAY_TOKEN)")
+	caFile := flags.String("cacert", "", "CA certificate to verify the client's GatewayCertFile with")
+	return func() (*adminClient, error) {
+		client := &adminClient{URL: *addr, Token: *token, Client: &http.Client{Timeout: time.Minute}}
+		if client.URL == "" {
+			settings, err := LoadFIXConfig(*config)
+			if err != nil {
+				return nil, err
+			}
+			listen, err := settings.GlobalSettings().Setting("DebugListenAddr")
+			if err != nil {
+				return nil, errors.New("pass -url or set DebugListenAddr")
+			}
+			scheme := "http"
+			if settings.GlobalSettings().HasSetting("GatewayCertFile") {
+				scheme = "https"
+			}
+			client.URL = scheme + "://" + listen
+		}
+		if *caFile != "" {
+			pem, err := os.ReadFile(*caFile)
+			if err != nil {
+				return nil, err
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates in %s", *caFile)
+			}
+			client.Client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}
+		}
+		return client, nil
+	}
+}
+
+// call calls the admin endpoint at path with query, decoding its JSON answer into out
+func (c *adminClient) call(method, path string, query url.Values, out any) error {
+	req, err := http.NewRequest(method, strings.TrimSuffix(c.URL, "/")+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(text)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// runOrderCancelAll implements `order cancel-all`, asking the running client
+// to cancel its matching open orders and printing the result of each
+func runOrderCancelAll(args []string) int {
+	flags := flag.NewFlagSet("order cancel-all", flag.ContinueOnError)
+	symbol := flags.String("symbol", "", "only cancel orders for this symbol, e.g. ETH-USD")
+	portfolio := flags.String("portfolio", "", "only cancel orders of this portfolio")
+	olderThan := flags.Duration("older-than", 0, "only cancel orders placed at least this long ago")
+	pace := flags.Duration("pace", 100*time.Millisecond, "wait between cancels")
+	newClient := adminClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to locate the running client:", err)
+		return 2
+	}
+
+	query := url.Values{}
+	query.Set("symbol", *symbol)
+	query.Set("portfolio", *portfolio)
+	query.Set("older-than", olderThan.String())
+	query.Set("pace", pace.String())
+	var results []CancelResult
+	if err := client.call(http.MethodPost, "/admin/orders/cancel-all", query, &results); err != nil {
+		fmt.Fprintln(os.Stderr, "Cancel all failed:", err)
+		return 1
+	}
+
+	status := 0
+	for _, result := range results {
+		outcome := "cancel sent"
+		if result.Error != "" {
+			outcome, status = "failed: "+result.Error, 1
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\n", result.Tenant, result.ClOrdID, result.Symbol, outcome)
+	}
+	fmt.Printf("%d orders\n", len(results))
+	return status
+}
+
+// runOrderList implements `order list`, printing the open orders of the
+// running client as it tracks them, or as the venue reports them with the
+// orphans of either side flagged
+func runOrderList(args []string) int {
+	flags := flag.NewFlagSet("order list", flag.ContinueOnError)
+	source := flags.String("source", "local", "local, or venue to ask the venue and diff")
+	timeout := flags.Duration("timeout", 10*time.Second, "how long to wait for the venue to answer")
+	newClient := adminClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *source != "local" && *source != "venue" {
+		fmt.Fprintln(os.Stderr, "Unknown source:", *source)
+		return 2
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to locate the running client:", err)
+		return 2
+	}
+	client.Client.Timeout = *timeout + time.Minute
+
+	query := url.Values{}
+	query.Set("source", *source)
+	query.Set("timeout", timeout.String())
+	var lists []OrderList
+	if err := client.call(http.MethodGet, "/admin/orders", query, &lists); err != nil {
+		fmt.Fprintln(os.Stderr, "Order list failed:", err)
+		return 1
+	}
+
+	status := 0
+	for _, list := range lists {
+		fmt.Printf("%s (%s)\n", list.Tenant, list.Source)
+		if !list.Complete {
+			fmt.Println("  venue did not finish answering; orders only it knows of may be missing")
+			status = 1
+		}
+		for _, entry := range list.Orders {
+			line := fmt.Sprintf("  %s\t%s\t%s\t%s\tlocal=%s", entry.ClOrdID, entry.OrderID, entry.Symbol, entry.Side, orNone(entry.Local))
+			if list.Source == "venue" {
+				line += "\tvenue=" + orNone(entry.Venue)
+			}
+			if entry.Orphan != "" {
+				line += "\tORPHAN(" + entry.Orphan + ")"
+				status = 1
+			}
+			fmt.Println(line)
+		}
+	}
+	return status
+}
+
+// runShadowReport implements `shadow report`, printing how the orders the
+// running client mirrored to its shadows compare
+func runShadowReport(args []string) int {
+	flags := flag.NewFlagSet("shadow report", flag.ContinueOnError)
+	all := flags.Bool("all", false, "print every order, not only the diverged ones")
+	asJSON := flags.Bool("json", false, "print the reports as JSON")
+	newClient := adminClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to locate the running client:", err)
+		return 2
+	}
+
+	var reports map[string]ShadowReport
+	if err := client.call(http.MethodGet, "/debug/shadow", url.Values{}, &reports); err != nil {
+		fmt.Fprintln(os.Stderr, "Shadow report failed:", err)
+		return 1
+	}
+	if *asJSON {
+		data, _ := json.MarshalIndent(reports, "", "  ")
+		fmt.Println(string(data))
+		return 0
+	}
+	if len(reports) == 0 {
+		fmt.Fprintln(os.Stderr, "No tenant runs with ShadowTenant set")
+		return 2
+	}
+
+	status := 0
+	for _, tenant := range slices.Sorted(maps.Keys(reports)) {
+		report := reports[tenant]
+		fmt.Printf("%s -> %s: %d orders, %d matched, %d diverged, %d open\n",
+			tenant, report.Target, report.Orders, report.Matched, report.Diverged, report.Open)
+		for _, pair := range report.Pairs {
+			if len(pair.Diffs) > 0 {
+				status = 1
+			} else if !*all {
+				continue
+			}
+			fmt.Printf("  %s\t%s\t%s %s %s\tproduction=%s shadow=%s\n", pair.Production.ClOrdID, orDash(pair.Shadow.ClOrdID),
+				pair.Side, pair.Quantity, pair.Symbol, legSummary(pair.Production), legSummary(pair.Shadow))
+			for _, diff := range pair.Diffs {
+				fmt.Println("    " + diff)
+			}
+		}
+	}
+	return status
+}
+
+// legSummary describes a ShadowLeg in a few words
+func legSummary(leg ShadowLeg) string {
+	switch {
+	case leg.Error != "":
+		return "not-sent"
+	case leg.Rejected != "":
+		return "rejected(" + string(leg.Rejected) + ")"
+	case leg.OrdStatus == "":
+		return "pending"
+	}
+	summary := OrdStatusName(leg.OrdStatus) + "/" + orZero(leg.CumQty)
+	if leg.AvgPx != "" {
+		summary += "@" + leg.AvgPx
+	}
+	return summary
+}
+
+// runMessageSend implements `message send`, asking the running client to
+// send a registered custom message
+func runMessageSend(args []string) int {
+	flags := flag.NewFlagSet("message send", flag.ContinueOnError)
+	msgType := flags.String("type", "", "MsgType registered in MessageDescriptorsPath")
+	tenant := flags.String("tenant", "", "tenant to send on, required with several tenants")
+	var fields fieldFlags
+	flags.Var(&fields, "field", "TAG=VALUE body field, repeatable")
+	newClient := adminClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *msgType == "" {
+		fmt.Fprintln(os.Stderr, "-type is required")
+		return 2
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to locate the running client:", err)
+		return 2
+	}
+
+	query := url.Values{"type": {*msgType}, "field": fields}
+	if *tenant != "" {
+		query.Set("tenant", *tenant)
+	}
+	var sent map[string]bool
+	if err := client.call(http.MethodPost, "/admin/messages", query, &sent); err != nil {
+		fmt.Fprintln(os.Stderr, "Message send failed:", err)
+		return 1
+	}
+	fmt.Println("Sent", *msgType)
+	return 0
+}
+
+// runScheduleList implements `schedule list`, printing the orders the running
+// client holds for submission later, soonest first
+func runScheduleList(args []string) int {
+	flags := flag.NewFlagSet("schedule list", flag.ContinueOnError)
+	newClient := adminClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to locate the running client:", err)
+		return 2
+	}
+
+	var scheduled map[string][]ScheduledOrder
+	if err := client.call(http.MethodGet, "/admin/scheduled", url.Values{}, &scheduled); err != nil {
+		fmt.Fprintln(os.Stderr, "Schedule list failed:", err)
+		return 1
+	}
+	count := 0
+	for _, name := range slices.Sorted(maps.Keys(scheduled)) {
+		for _, order := range scheduled[name] {
+			req := order.Request
+			fmt.Printf("%s\t%s\t%s\t%s %s %s %s", name, order.Id, order.SubmitAt.Format(time.RFC3339), req.OrdType, req.Side, req.Quantity, req.Symbol)
+			if req.LimitPrice != "" {
+				fmt.Printf(" @ %s", req.LimitPrice)
+			}
+			fmt.Println()
+			count++
+		}
+	}
+	fmt.Printf("%d scheduled orders\n", count)
+	return 0
+}
+
+// runScheduleCancel implements `schedule cancel`, asking the running client to
+// drop a scheduled order before it is submitted
+func runScheduleCancel(args []string) int {
+	flags := flag.NewFlagSet("schedule cancel", flag.ContinueOnError)
+	id := flags.String("id", "", "id of the scheduled order, see schedule list")
+	tenant := flags.String("tenant", "", "only look for the order on this tenant")
+	newClient := adminClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *id == "" {
+		fmt.Fprintln(os.Stderr, "-id is required")
+		return 2
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to locate the running client:", err)
+		return 2
+	}
+
+	query := url.Values{"id": {*id}}
+	if *tenant != "" {
+		query.Set("tenant", *tenant)
+	}
+	var canceled map[string]bool
+	if err := client.call(http.MethodPost, "/admin/scheduled/cancel", query, &canceled); err != nil {
+		fmt.Fprintln(os.Stderr, "Schedule cancel failed:", err)
+		return 1
+	}
+	for _, name := range slices.Sorted(maps.Keys(canceled)) {
+		fmt.Printf("%s\t%s\tcanceled\n", name, *id)
+	}
+	return 0
+}
+
+// runVenueResume implements `venue resume`, asking the running client to
+// clear the halt it holds on a symbol
+func runVenueResume(args []string) int {
+	flags := flag.NewFlagSet("venue resume", flag.ContinueOnError)
+	symbol := flags.String("symbol", "", "symbol to resume, e.g. ETH-USD")
+	tenant := flags.String("tenant", "", "only resume on this tenant")
+	newClient := adminClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *symbol == "" {
+		fmt.Fprintln(os.Stderr, "-symbol is required")
+		return 2
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to locate the running client:", err)
+		return 2
+	}
+
+	query := url.Values{"symbol": {*symbol}}
+	if *tenant != "" {
+		query.Set("tenant", *tenant)
+	}
+	var resumed map[string]bool
+	if err := client.call(http.MethodPost, "/admin/venue/resume", query, &resumed); err != nil {
+		fmt.Fprintln(os.Stderr, "Venue resume failed:", err)
+		return 1
+	}
+	for _, name := range slices.Sorted(maps.Keys(resumed)) {
+		outcome := "was not halted"
+		if resumed[name] {
+			outcome = "resumed"
+		}
+		fmt.Printf("%s\t%s\t%s\n", name, *symbol, outcome)
+	}
+	return 0
+}
+
+// runBreakerReset implements `breaker reset`, asking the running client to
+// close its order circuit breakers, or with -symbol the fill price breaker of
+// that symbol, so it submits orders again
+func runBreakerReset(args []string) int {
+	flags := flag.NewFlagSet("breaker reset", flag.ContinueOnError)
+	tenant := flags.String("tenant", "", "only reset the breaker of this tenant")
+	symbol := flags.String("symbol", "", "reset the fill price breaker of this symbol instead")
+	newClient := adminClientFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	client, err := newClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to locate the running client:", err)
+		return 2
+	}
+
+	path, setting := "/admin/breaker/reset", "CircuitBreakerRejects"
+	query := url.Values{}
+	if *symbol != "" {
+		path, setting = "/admin/price-breaker/reset", "FillPriceBreakerPercent"
+		query.Set("symbol", *symbol)
+	}
+	if *tenant != "" {
+		query.Set("tenant", *tenant)
+	}
+	var wasOpen map[string]bool
+	if err := client.call(http.MethodPost, path, query, &wasOpen); err != nil {
+		fmt.Fprintln(os.Stderr, "Breaker reset failed:", err)
+		return 1
+	}
+	if len(wasOpen) == 0 {
+		fmt.Fprintln(os.Stderr, "No tenant runs with", setting, "set")
+		return 2
+	}
+	for _, name := range slices.Sorted(maps.Keys(wasOpen)) {
+		outcome := "was closed"
+		if wasOpen[name] {
+			outcome = "reset"
+		}
+		fmt.Printf("%s\t%s\n", name, outcome)
+	}
+	return 0
+}
+
+// fieldFlags collects repeated -field TAG=VALUE flags
+type fieldFlags []string
+
+func (f *fieldFlags) String() string { return strings.Join(*f, " ") }
+
+func (f *fieldFlags) Set(value string) error {
+	if _, _, err := parseTagValue(value); err != nil {
+		return err
+	}
+	*f = append(*f, value)
+	return nil
+}
+
+func orNone(state OrderState) string {
+	if state == "" {
+		return "-"
+	}
+	return string(state)
+}
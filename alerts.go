@@ -0,0 +1,220 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Alert is an operational anomaly worth paging someone about
+type Alert struct {
+	// Key identifies the condition; alerts with the same key are deduplicated
+	Key      string
+	Severity string // critical, error, warning or info
+	Summary  string
+	Time     time.Time
+	Tenant   string // tenant raising the alert, empty in single-tenant use
+
+	// Suppressed counts alerts with the same key dropped since the last one sent
+	Suppressed int
+}
+
+// label returns the summary prefixed with the tenant, if any
+func (alert Alert) label() string {
+	if alert.Tenant == "" {
+		return alert.Summary
+	}
+	return alert.Tenant + ": " + alert.Summary
+}
+
+// AlertSink delivers alerts to an external system
+type AlertSink interface {
+	Send(alert Alert) error
+}
+
+// SlackSink posts alerts to a Slack incoming webhook
+type SlackSink struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+func (s *SlackSink) Send(alert Alert) error {
+	text := fmt.Sprintf("[%s] %s", alert.Severity, alert.label())
+	if alert.Suppressed > 0 {
+		text += fmt.Sprintf(" (%d similar alerts suppressed)", alert.Suppressed)
+	}
+	return postJSON(s.Client, s.WebhookURL, map[string]string{"text": text})
+}
+
+// PagerDutySink triggers PagerDuty Events API v2 incidents
+type PagerDutySink struct {
+	RoutingKey string
+	Client     *http.Client
+}
+
+func (s *PagerDutySink) Send(alert Alert) error {
+	source, _ := os.Hostname()
+	dedupKey := alert.Key
+	if alert.Tenant != "" {
+		dedupKey = alert.Tenant + "/" + alert.Key
+	}
+	return postJSON(s.Client, "https://events.pagerduty.com/v2/enqueue", map[string]any{
+		"routing_key":  s.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    dedupKey,
+		"payload": map[string]any{
+			"summary":   alert.label(),
+			"source":    source,
+			"severity":  alert.Severity,
+			"timestamp": alert.Time.UTC().Format(time.RFC3339),
+			"custom_details": map[string]any{
+				"suppressed": alert.Suppressed,
+				"tenant":     alert.Tenant,
+			},
+		},
+	})
+}
+
+func postJSON(client *http.Client, url string, body any) error {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert post to %s failed: %s", url, resp.Status)
+	}
+	return nil
+}
+
+// Alerter fans alerts out to its sinks, sending at most one alert per key per
+// window and counting the ones it suppresses
+type Alerter struct {
+	mu         sync.Mutex
+	sinks      []AlertSink
+	window     time.Duration
+	lastSent   map[string]time.Time
+	suppressed map[string]int
+	inflight   sync.WaitGroup
+}
+
+// NewAlerter creates an alerter delivering to sinks
+func NewAlerter(window time.Duration, sinks ...AlertSink) *Alerter {
+	return &Alerter{
+		sinks:      sinks,
+		window:     window,
+		lastSent:   make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// Reconfigure replaces the dedup window and sinks of the alerter
+func (a *Alerter) Reconfigure(window time.Duration, sinks ...AlertSink) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.window = window
+	a.sinks = sinks
+}
+
+// Alert logs alert and delivers it asynchronously unless one with the same
+// key was sent within the window
+func (a *Alerter) Alert(alert Alert) {
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	log.Printf("ALERT [%s] %s", alert.Severity, alert.label())
+
+	a.mu.Lock()
+	if last, ok := a.lastSent[alert.Key]; ok && alert.Time.Sub(last) < a.window {
+		a.suppressed[alert.Key]++
+		a.mu.Unlock()
+		return
+	}
+	alert.Suppressed = a.suppressed[alert.Key]
+	a.lastSent[alert.Key] = alert.Time
+	delete(a.suppressed, alert.Key)
+	sinks := a.sinks
+	a.mu.Unlock()
+
+	for _, sink := range sinks {
+		a.inflight.Add(1)
+		go func(sink AlertSink) {
+			defer a.inflight.Done()
+			if err := sink.Send(alert); err != nil {
+				log.Println("Failed to deliver alert:", err)
+			}
+		}(sink)
+	}
+}
+
+// Flush waits for alerts still being delivered, e.g. before exiting
+func (a *Alerter) Flush() {
+	a.inflight.Wait()
+}
+
+// eventWindow counts events within a sliding time window
+type eventWindow struct {
+	mu     sync.Mutex
+	window time.Duration
+	times  []time.Time
+}
+
+func newEventWindow(window time.Duration) *eventWindow {
+	return &eventWindow{window: window}
+}
+
+// add records an event at now and returns how many fall within the window
+func (w *eventWindow) add(now time.Time) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cutoff := now.Add(-w.window)
+	kept := w.times[:0]
+	for _, t := range w.times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	w.times = append(kept, now)
+	return len(w.times)
+}
+
+// setWindow changes the window length
+func (w *eventWindow) setWindow(window time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.window = window
+}
+
+// reset forgets all recorded events
+func (w *eventWindow) reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.times = w.times[:0]
+}
@@ -0,0 +1,153 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAlerterDeduplicates(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	sink := &alertRecorder{}
+	alerter := NewAlerter(time.Minute, sink)
+
+	tests := []struct {
+		key        string
+		at         time.Duration
+		sent       bool
+		suppressed int
+	}{
+		{"logout", 0, true, 0},
+		{"logout", 30 * time.Second, false, 0},
+		{"stuck-order:1", 40 * time.Second, true, 0},
+		{"logout", 50 * time.Second, false, 0},
+		{"logout", 61 * time.Second, true, 2},
+	}
+	for i, tt := range tests {
+		before := len(sink.keys())
+		alerter.Alert(Alert{Key: tt.key, Severity: "error", Summary: tt.key, Time: start.Add(tt.at)})
+		alerter.Flush()
+
+		sink.mu.Lock()
+		sent := len(sink.alerts) > before
+		var last Alert
+		if sent {
+			last = sink.alerts[len(sink.alerts)-1]
+		}
+		sink.mu.Unlock()
+		if sent != tt.sent || sent && last.Suppressed != tt.suppressed {
+			t.Errorf("alert %d (%s at %s): sent %t with %d suppressed, want %t with %d", i, tt.key, tt.at, sent, last.Suppressed, tt.sent, tt.suppressed)
+		}
+	}
+}
+
+func TestSlackSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"delivered", http.StatusOK, false},
+		{"webhook down", http.StatusInternalServerError, true},
+	}
+	for _, tt := range tests {
+		var text string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			text = body["text"]
+			w.WriteHeader(tt.status)
+		}))
+		sink := &SlackSink{WebhookURL: server.URL, Client: server.Client()}
+		err := sink.Send(Alert{Severity: "critical", Summary: "logged out", Tenant: "desk", Suppressed: 3})
+		server.Close()
+
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if want := "[critical] desk: logged out (3 similar alerts suppressed)"; text != want {
+			t.Errorf("%s: posted %q, want %q", tt.name, text, want)
+		}
+	}
+}
+
+// redirectTransport sends every request to server, whatever its URL
+type redirectTransport struct{ server *httptest.Server }
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(t.server.URL)
+	r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+	return t.server.Client().Transport.RoundTrip(r)
+}
+
+func TestAlertTenantLabels(t *testing.T) {
+	tests := []struct {
+		name         string
+		tenant       string
+		status       int
+		wantSummary  string
+		wantDedupKey string
+		wantErr      bool
+	}{
+		{"single tenant", "", http.StatusAccepted, "logged out", "session-lost", false},
+		{"tenant", "desk-a", http.StatusAccepted, "desk-a: logged out", "desk-a/session-lost", false},
+		{"pagerduty down", "desk-a", http.StatusServiceUnavailable, "desk-a: logged out", "desk-a/session-lost", true},
+	}
+	for _, tt := range tests {
+		recorder := &alertRecorder{}
+		app := &FixApplication{Tenant: tt.tenant, Alerts: NewAlerter(time.Minute, recorder)}
+		app.alert("session-lost", "critical", "logged out")
+		app.Alerts.Flush()
+		if len(recorder.alerts) != 1 || recorder.alerts[0].Tenant != tt.tenant {
+			t.Fatalf("%s: alerts %+v, want one for tenant %q", tt.name, recorder.alerts, tt.tenant)
+		}
+
+		var event struct {
+			DedupKey string `json:"dedup_key"`
+			Payload  struct {
+				Summary       string         `json:"summary"`
+				CustomDetails map[string]any `json:"custom_details"`
+			} `json:"payload"`
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&event)
+			w.WriteHeader(tt.status)
+		}))
+		sink := &PagerDutySink{RoutingKey: "routing", Client: &http.Client{Transport: redirectTransport{server}}}
+		err := sink.Send(recorder.alerts[0])
+		server.Close()
+
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if event.DedupKey != tt.wantDedupKey || event.Payload.Summary != tt.wantSummary {
+			t.Errorf("%s: dedup key %q summary %q, want %q and %q", tt.name, event.DedupKey, event.Payload.Summary, tt.wantDedupKey, tt.wantSummary)
+		}
+		if got := event.Payload.CustomDetails["tenant"]; got != tt.tenant {
+			t.Errorf("%s: tenant detail %v, want %q", tt.name, got, tt.tenant)
+		}
+	}
+	var report ExecutionReport
+	app := &FixApplication{Tenant: "desk-a", OnExecutionReport: func(r ExecutionReport) { report = r }}
+	app.processExecutionReport(parsedExecutionReport(t))
+	if report.Tenant != "desk-a" {
+		t.Errorf("ExecutionReport tenant %q, want desk-a", report.Tenant)
+	}
+}
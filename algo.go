@@ -0,0 +1,344 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AlgoParams are the optional strategy parameters of TWAP and VWAP orders. A
+// zero value leaves the parameter to Prime's default.
+type AlgoParams struct {
+	// MaxParticipationRate caps the share of market volume the strategy may
+	// take, as a fraction in (0, 1]. Sent as ParticipationRate (849).
+	MaxParticipationRate decimal.Decimal
+
+	// PriceCap is the highest price a BUY may execute at and PriceFloor the
+	// lowest a SELL may. Either is sent as the limit Price (44) and may be
+	// used instead of LimitPrice.
+	PriceCap   decimal.Decimal
+	PriceFloor decimal.Decimal
+}
+
+// applyAlgoParams validates the algo parameters of req, returning it with a
+// price cap or floor folded into LimitPrice
+func applyAlgoParams(req OrderRequest) (OrderRequest, error) {
+	params := req.Algo
+	if params.MaxParticipationRate.IsZero() && params.PriceCap.IsZero() && params.PriceFloor.IsZero() {
+		return req, nil
+	}
+	if !isAlgo(req.OrdType) {
+		return req, errors.New("algo parameters are only valid on TWAP and VWAP orders")
+	}
+
+	rate := params.MaxParticipationRate
+	if rate.IsNegative() || rate.GreaterThan(decimal.NewFromInt(1)) {
+		return req, fmt.Errorf("participation rate %s is outside (0, 1]", rate)
+	}
+
+	var bound decimal.Decimal
+	switch {
+	case !params.PriceCap.IsZero() && req.Side != "BUY":
+		return req, errors.New("a price cap only applies to BUY orders, use a price floor")
+	case !params.PriceFloor.IsZero() && req.Side != "SELL":
+		return req, errors.New("a price floor only applies to SELL orders, use a price cap")
+	case !params.PriceCap.IsZero():
+		bound = params.PriceCap
+	case !params.PriceFloor.IsZero():
+		bound = params.PriceFloor
+	default:
+		return req, nil
+	}
+	if !bound.IsPositive() {
+		return req, fmt.Errorf("price bound %s must be positive", bound)
+	}
+	if req.LimitPrice != "" {
+		if limit, err := decimal.NewFromString(req.LimitPrice); err != nil || !limit.Equal(bound) {
+			return req, fmt.Errorf("limit price %s conflicts with price bound %s", req.LimitPrice, bound)
+		}
+	}
+	req.LimitPrice = bound.String()
+	return req, nil
+}
+
+// SliceExecution is a single child fill of an algo parent order
+type SliceExecution struct {
+	ExecID   string
+	Quantity decimal.Decimal
+	Price    decimal.Decimal
+	Time     time.Time
+}
+
+// AlgoOrder is a TWAP or VWAP parent order together with the child slice
+// executions Prime has reported against it
+type AlgoOrder struct {
+	ClOrdID    string
+	Strategy   string // TWAP or VWAP
+	Symbol     string
+	Side       string
+	StartTime  time.Time
+	ExpireTime time.Time
+
+	Quantity  decimal.Decimal
+	FilledQty decimal.Decimal
+	Notional  decimal.Decimal
+	Slices    []SliceExecution
+
+	Complete    bool
+	CompletedAt time.Time
+	State       OrderState
+	Metadata    map[string]string
+
+	// CancelRequested is when a cancel of the whole strategy was sent, zero
+	// if none is outstanding
+	CancelRequested time.Time
+}
+
+// Unfilled returns the parent quantity left unexecuted
+func (o AlgoOrder) Unfilled() decimal.Decimal {
+	return o.Quantity.Sub(o.FilledQty)
+}
+
+// AvgPrice returns the volume weighted price of the slices filled so far
+func (o AlgoOrder) AvgPrice() decimal.Decimal {
+	if o.FilledQty.IsZero() {
+		return decimal.Zero
+	}
+	return o.Notional.Div(o.FilledQty)
+}
+
+// PercentComplete returns the filled share of the parent quantity, 0-100
+func (o AlgoOrder) PercentComplete() float64 {
+	if o.Quantity.IsZero() {
+		return 0
+	}
+	pct, _ := o.FilledQty.Div(o.Quantity).Mul(decimal.NewFromInt(100)).Float64()
+	return pct
+}
+
+// PercentElapsed returns how far now is through the order's schedule, 0-100
+func (o AlgoOrder) PercentElapsed(now time.Time) float64 {
+	total := o.ExpireTime.Sub(o.StartTime)
+	if total <= 0 || now.Before(o.StartTime) {
+		return 0
+	}
+	if now.After(o.ExpireTime) {
+		return 100
+	}
+	return 100 * float64(now.Sub(o.StartTime)) / float64(total)
+}
+
+// ScheduleRatio compares fill progress with schedule progress at now: 1 means
+// the parent is exactly on schedule, below 1 behind and above 1 ahead of it.
+// It says nothing about the share of market volume taken.
+func (o AlgoOrder) ScheduleRatio(now time.Time) float64 {
+	elapsed := o.PercentElapsed(now)
+	if elapsed == 0 {
+		return 0
+	}
+	return o.PercentComplete() / elapsed
+}
+
+// AlgoTracker aggregates child slice executions up to their algo parent orders
+type AlgoTracker struct {
+	mu      sync.Mutex
+	orders  map[string]*AlgoOrder
+	waiters map[string][]chan AlgoOrder
+
+	// OnParentComplete is called once when a parent order reaches a terminal state
+	OnParentComplete func(order AlgoOrder)
+
+	// Clock starts the schedule of orders without a StartTime; nil uses the
+	// wall clock
+	Clock Clock
+
+	// Retention is how long Get and Wait still find a completed parent
+	// before Evict drops it
+	Retention time.Duration
+}
+
+// NewAlgoTracker creates an empty AlgoTracker
+func NewAlgoTracker() *AlgoTracker {
+	return &AlgoTracker{
+		orders:  make(map[string]*AlgoOrder),
+		waiters: make(map[string][]chan AlgoOrder),
+	}
+}
+
+// Add starts tracking the parent order placed for req under clOrdID
+func (t *AlgoTracker) Add(clOrdID string, req OrderRequest) {
+	quantity, _ := decimal.NewFromString(req.Quantity)
+	start := req.StartTime
+	if start.IsZero() {
+		start = clockOrSystem(t.Clock).Now()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.orders[clOrdID] = &AlgoOrder{
+		ClOrdID:    clOrdID,
+		Strategy:   req.OrdType,
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		StartTime:  start,
+		ExpireTime: req.ExpireTime,
+		Quantity:   quantity,
+		State:      OrderPendingNew,
+		Metadata:   req.Metadata,
+	}
+}
+
+// Get returns a copy of the parent order clOrdID
+func (t *AlgoTracker) Get(clOrdID string) (AlgoOrder, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[clOrdID]
+	if !ok {
+		return AlgoOrder{}, false
+	}
+	copied := *order
+	copied.Slices = append([]SliceExecution(nil), order.Slices...)
+	return copied, true
+}
+
+// Wait returns a channel that receives the parent order clOrdID once it is
+// complete, with its fill statistics at that point
+func (t *AlgoTracker) Wait(clOrdID string) (<-chan AlgoOrder, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[clOrdID]
+	if !ok {
+		return nil, false
+	}
+	done := make(chan AlgoOrder, 1)
+	if order.Complete {
+		done <- *order
+	} else {
+		t.waiters[clOrdID] = append(t.waiters[clOrdID], done)
+	}
+	return done, true
+}
+
+// RequestCancel records that a cancel of the parent clOrdID is being sent.
+// Prime cancels the strategy as a whole, so only a parent can be cancelled.
+func (t *AlgoTracker) RequestCancel(clOrdID string, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[clOrdID]
+	switch {
+	case !ok:
+		return errors.New("no algo parent order " + clOrdID)
+	case order.Complete:
+		return errors.New("algo order " + clOrdID + " is already " + string(order.State))
+	case !order.CancelRequested.IsZero():
+		return errors.New("algo order " + clOrdID + " already has a cancel outstanding")
+	}
+	order.CancelRequested = now
+	return nil
+}
+
+// OnCancelReject clears the outstanding cancel of the parent clOrdID, which
+// keeps working
+func (t *AlgoTracker) OnCancelReject(clOrdID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if order, ok := t.orders[clOrdID]; ok && !order.CancelRequested.IsZero() {
+		log.Printf("Cancel of algo order %s rejected, strategy still working at %.1f%%", clOrdID, order.PercentComplete())
+		order.CancelRequested = time.Time{}
+	}
+}
+
+// OnExecutionReport records a slice execution against its parent, and
+// completes the parent once it reaches a terminal state. Reports for a cancel
+// of the parent carry its ClOrdID as OrigClOrdID.
+func (t *AlgoTracker) OnExecutionReport(report ExecutionReport, now time.Time) {
+	t.mu.Lock()
+
+	order, ok := t.orders[report.ClOrdID]
+	if !ok {
+		order, ok = t.orders[report.OrigClOrdID]
+	}
+	if !ok || order.Complete {
+		t.mu.Unlock()
+		return
+	}
+
+	switch report.ExecType {
+	case "1", "2", "F": // Partial fill, Fill, Trade
+		qty, qtyErr := decimal.NewFromString(report.LastShares)
+		px, pxErr := decimal.NewFromString(report.LastPx)
+		if qtyErr == nil && pxErr == nil && qty.IsPositive() {
+			order.Slices = append(order.Slices, SliceExecution{ExecID: report.ExecID, Quantity: qty, Price: px, Time: now})
+			order.FilledQty = order.FilledQty.Add(qty)
+			order.Notional = order.Notional.Add(qty.Mul(px))
+		}
+	}
+
+	if state, ok := orderStateFromOrdStatus[report.OrdStatus]; ok {
+		order.State = state
+	}
+
+	var completed *AlgoOrder
+	if order.State.Terminal() {
+		order.Complete = true
+		order.CompletedAt = now
+		copied := *order
+		completed = &copied
+		for _, done := range t.waiters[order.ClOrdID] {
+			done <- copied
+		}
+		delete(t.waiters, order.ClOrdID)
+	}
+	t.mu.Unlock()
+
+	if completed != nil && t.OnParentComplete != nil {
+		t.OnParentComplete(*completed)
+	}
+}
+
+// Evict drops the parents that have been complete for longer than the
+// Retention, returning how many it dropped
+func (t *AlgoTracker) Evict(now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	evicted := 0
+	for clOrdID, order := range t.orders {
+		if order.Complete && now.Sub(order.CompletedAt) >= t.Retention {
+			delete(t.orders, clOrdID)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// WatchEvictions calls Evict every interval until stop is closed
+func (t *AlgoTracker) WatchEvictions(interval time.Duration, stop <-chan struct{}) {
+	clock := clockOrSystem(t.Clock)
+	for sleepUntil(clock, clock.Now().Add(interval), stop) {
+		t.Evict(clock.Now())
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+func TestAlgoTrackerEvictsCompletedParents(t *testing.T) {
+	now := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tracker := NewAlgoTracker()
+	tracker.Retention = time.Hour
+	tracker.Add("done", OrderRequest{OrdType: "TWAP", Quantity: "1"})
+	tracker.Add("working", OrderRequest{OrdType: "TWAP", Quantity: "1"})
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "done", ExecType: "2", OrdStatus: "2", LastShares: "1", LastPx: "100"}, now)
+
+	if n := tracker.Evict(now.Add(59 * time.Minute)); n != 0 {
+		t.Fatalf("evicted %d parents within the retention", n)
+	}
+	if n := tracker.Evict(now.Add(time.Hour)); n != 1 {
+		t.Fatalf("evicted %d parents after the retention, want 1", n)
+	}
+	if _, ok := tracker.Get("done"); ok {
+		t.Error("completed parent still tracked")
+	}
+	if _, ok := tracker.Get("working"); !ok {
+		t.Error("working parent evicted")
+	}
+}
+
+func TestAlgoParentCancel(t *testing.T) {
+	now := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tracker := NewAlgoTracker()
+	tracker.Add("parent", OrderRequest{OrdType: "TWAP", Side: "BUY", Quantity: "10"})
+	tracker.Add("filled", OrderRequest{OrdType: "TWAP", Side: "BUY", Quantity: "1"})
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "filled", ExecType: "2", OrdStatus: "2", LastShares: "1", LastPx: "100"}, now)
+
+	tests := []struct {
+		name    string
+		clOrdID string
+		reject  bool // reject the cancel afterwards
+		wantErr bool
+	}{
+		{"unknown parent", "missing", false, true},
+		{"complete parent", "filled", false, true},
+		{"cancel sent", "parent", true, false},
+		{"cancel again after reject", "parent", false, false},
+		{"cancel already outstanding", "parent", false, true},
+	}
+	for _, tt := range tests {
+		if err := tracker.RequestCancel(tt.clOrdID, now); (err != nil) != tt.wantErr {
+			t.Errorf("%s: RequestCancel = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if tt.reject {
+			tracker.OnCancelReject(tt.clOrdID)
+			if order, _ := tracker.Get(tt.clOrdID); !order.CancelRequested.IsZero() {
+				t.Errorf("%s: cancel still outstanding after reject", tt.name)
+			}
+		}
+	}
+
+	done, ok := tracker.Wait("parent")
+	if !ok {
+		t.Fatal("parent not tracked")
+	}
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "parent", ExecType: "1", OrdStatus: "1", LastShares: "4", LastPx: "100"}, now)
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "cancel", OrigClOrdID: "parent", ExecType: "4", OrdStatus: "4"}, now)
+	select {
+	case order := <-done:
+		if order.State != OrderCanceled || !order.FilledQty.Equal(decimal.NewFromInt(4)) || order.PercentComplete() != 40 {
+			t.Errorf("canceled parent = %s filled %s (%.0f%%)", order.State, order.FilledQty, order.PercentComplete())
+		}
+	default:
+		t.Fatal("Wait did not resolve on the cancel")
+	}
+
+	if _, err := (&FixApplication{}).CancelAlgo("parent"); err == nil {
+		t.Error("CancelAlgo without a tracker succeeded")
+	}
+	if _, err := (&FixApplication{Algos: tracker}).CancelAlgo("missing"); err == nil {
+		t.Error("CancelAlgo of an unknown parent succeeded")
+	}
+}
+
+func TestApplyAlgoParams(t *testing.T) {
+	d := decimal.RequireFromString
+	tests := []struct {
+		name      string
+		req       OrderRequest
+		wantLimit string
+		wantErr   bool
+	}{
+		{"no params", OrderRequest{OrdType: "LIMIT", Side: "BUY", LimitPrice: "100"}, "100", false},
+		{"participation only", OrderRequest{OrdType: "TWAP", Side: "BUY", Algo: AlgoParams{MaxParticipationRate: d("0.2")}}, "", false},
+		{"cap folded into limit", OrderRequest{OrdType: "VWAP", Side: "BUY", Algo: AlgoParams{PriceCap: d("2500")}}, "2500", false},
+		{"floor matching limit", OrderRequest{OrdType: "TWAP", Side: "SELL", LimitPrice: "2400.0", Algo: AlgoParams{PriceFloor: d("2400")}}, "2400", false},
+		{"params on a limit order", OrderRequest{OrdType: "LIMIT", Side: "BUY", Algo: AlgoParams{PriceCap: d("2500")}}, "", true},
+		{"participation above one", OrderRequest{OrdType: "TWAP", Side: "BUY", Algo: AlgoParams{MaxParticipationRate: d("1.5")}}, "", true},
+		{"cap on a sell", OrderRequest{OrdType: "TWAP", Side: "SELL", Algo: AlgoParams{PriceCap: d("2500")}}, "", true},
+		{"floor on a buy", OrderRequest{OrdType: "TWAP", Side: "BUY", Algo: AlgoParams{PriceFloor: d("2400")}}, "", true},
+		{"negative cap", OrderRequest{OrdType: "TWAP", Side: "BUY", Algo: AlgoParams{PriceCap: d("-1")}}, "", true},
+		{"cap conflicts with limit", OrderRequest{OrdType: "TWAP", Side: "BUY", LimitPrice: "2600", Algo: AlgoParams{PriceCap: d("2500")}}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := applyAlgoParams(tt.req)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.LimitPrice != tt.wantLimit {
+			t.Errorf("%s: LimitPrice = %q, want %q", tt.name, got.LimitPrice, tt.wantLimit)
+		}
+	}
+
+	req := OrderRequest{Symbol: "ETH-USD", OrdType: "TWAP", Side: "BUY", Quantity: "10", LimitPrice: "2500",
+		ExpireTime: time.Now().Add(time.Hour), Algo: AlgoParams{MaxParticipationRate: d("0.2")}}
+	builder := newOrderBuilder("SENDER", "COIN")
+	if got := bodyString(builder.build(req, "portfolio", time.Now()), quickfix.Tag(849)); got != "0.2" {
+		t.Errorf("ParticipationRate = %q, want 0.2", got)
+	}
+	req.Algo = AlgoParams{}
+	if builder.build(req, "portfolio", time.Now()).Body.Has(quickfix.Tag(849)) {
+		t.Error("ParticipationRate sent without a rate")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// CommandSigner computes the HMAC by running a command that is given the
+// message on stdin and prints the MAC on stdout. It reaches keys held where
+// the process cannot link to them, such as a PKCS#11 HSM through
+//
+//	pkcs11-tool --module /usr/lib/softhsm/libsofthsm2.so --login --sign --mechanism SHA256-HMAC --id 01
+type CommandSigner struct {
+	Path string
+	Args []string
+	// Output is how the command prints the MAC: raw (the default), hex or base64
+	Output string
+	// Env is the environment of the command; nil inherits the process's
+	Env []string
+}
+
+// ParseCommandSigner parses a command line, split on spaces without a
+// shell, and the encoding of its output
+func ParseCommandSigner(command, output string) (*CommandSigner, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, errors.New("signing command is empty")
+	}
+	switch output {
+	case "", "raw", "hex", "base64":
+	default:
+		return nil, fmt.Errorf("unknown signing command output %q, want raw, hex or base64", output)
+	}
+	return &CommandSigner{Path: fields[0], Args: fields[1:], Output: output}, nil
+}
+
+// Sign runs the command on message and returns the MAC it prints
+func (s *CommandSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, s.Path, s.Args...)
+	cmd.Stdin = bytes.NewReader(message)
+	cmd.Env = s.Env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("signing command %s: %w: %s", s.Path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	mac := stdout.Bytes()
+	var err error
+	switch s.Output {
+	case "hex":
+		mac, err = hex.DecodeString(strings.TrimSpace(string(mac)))
+	case "base64":
+		mac, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(mac)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("signing command %s: invalid %s output: %w", s.Path, s.Output, err)
+	}
+	if len(mac) != 32 {
+		return nil, fmt.Errorf("signing command %s printed %d bytes, want the 32 of HMAC-SHA256", s.Path, len(mac))
+	}
+	return mac, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// KMSSigner computes the HMAC with an AWS KMS HMAC_SHA_256 key through the
+// GenerateMac API, so the signing key never leaves KMS. The key must hold
+// the Prime signing key as imported key material.
+type KMSSigner struct {
+	KeyID    string // key ID, ARN or alias of the HMAC key
+	Region   string
+	Endpoint string // overrides https://kms.<Region>.amazonaws.com, e.g. for a VPC endpoint
+
+	// Credentials returns the credentials to call KMS with; nil reads them
+	// from the environment
+	Credentials func() (AWSCredentials, error)
+	Client      *http.Client
+	Now         func() time.Time // nil for the wall clock
+}
+
+// NewKMSSigner returns a Signer for the HMAC key keyID in region
+func NewKMSSigner(keyID, region string) *KMSSigner {
+	return &KMSSigner{KeyID: keyID, Region: region}
+}
+
+// KMSError is an error answered by KMS
+type KMSError struct {
+	Status  int
+	Type    string
+	Message string
+}
+
+func (e *KMSError) Error() string {
+	return fmt.Sprintf("kms: %d %s: %s", e.Status, e.Type, e.Message)
+}
+
+// Sign returns the HMAC-SHA256 of message computed by KMS
+func (s *KMSSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	if s.KeyID == "" || s.Region == "" {
+		return nil, errors.New("kms: key ID and region are required")
+	}
+	credentials := EnvAWSCredentials
+	if s.Credentials != nil {
+		credentials = s.Credentials
+	}
+	creds, err := credentials()
+	if err != nil {
+		return nil, fmt.Errorf("kms: %w", err)
+	}
+
+	// []byte fields are sent and answered as base64
+	payload, err := json.Marshal(struct {
+		KeyId        string
+		Message      []byte
+		MacAlgorithm string
+	}{s.KeyID, message, "HMAC_SHA_256"})
+	if err != nil {
+		return nil, err
+	}
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://kms." + s.Region + ".amazonaws.com/"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.GenerateMac")
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	signV4(req, payload, creds, s.Region, "kms", now())
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kms: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, fmt.Errorf("kms: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &failure)
+		return nil, &KMSError{Status: resp.StatusCode, Type: failure.Type, Message: failure.Message}
+	}
+	var result struct {
+		Mac []byte
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("kms: invalid GenerateMac response: %w", err)
+	}
+	if len(result.Mac) != 32 {
+		return nil, fmt.Errorf("kms: GenerateMac returned %d bytes, want the 32 of HMAC_SHA_256", len(result.Mac))
+	}
+	return result.Mac, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeKMS answers GenerateMac with the HMAC of key, as KMS does for an
+// imported HMAC_SHA_256 key
+func fakeKMS(t *testing.T, key string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Amz-Target"); got != "TrentService.GenerateMac" {
+			t.Errorf("X-Amz-Target = %q", got)
+		}
+		if auth := r.Header.Get("Authorization"); !strings.Contains(auth, "/us-east-1/kms/aws4_request") {
+			t.Errorf("Authorization = %q", auth)
+		}
+		var request struct {
+			KeyId        string
+			Message      []byte
+			MacAlgorithm string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		if request.KeyId != "alias/prime" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"NotFoundException","message":"Alias is not found."}`))
+			return
+		}
+		mac, _ := NewHMACSigner([]byte(key)).Sign(r.Context(), request.Message)
+		json.NewEncoder(w).Encode(map[string][]byte{"Mac": mac})
+	}))
+}
+
+func TestKMSSignerSignsLogon(t *testing.T) {
+	vector := logonVectors[0]
+	server := fakeKMS(t, vector.key)
+	defer server.Close()
+
+	signer := &KMSSigner{
+		KeyID:    "alias/prime",
+		Region:   "us-east-1",
+		Endpoint: server.URL,
+		Credentials: func() (AWSCredentials, error) {
+			return AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+		},
+		Now: func() time.Time { return time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC) },
+	}
+	signature, err := SignLogon(context.Background(), signer, vector.logon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature != vector.signature {
+		t.Errorf("signature = %s, want %s", signature, vector.signature)
+	}
+
+	signer.KeyID = "alias/missing"
+	_, err = signer.Sign(context.Background(), []byte("x"))
+	var kmsErr *KMSError
+	if !errors.As(err, &kmsErr) || kmsErr.Type != "NotFoundException" {
+		t.Errorf("err = %v, want NotFoundException", err)
+	}
+}
+
+func TestCommandSigner(t *testing.T) {
+	signer := &CommandSigner{Path: "sh", Args: []string{"-c", "cat >/dev/null; printf %064d 0"}, Output: "hex"}
+	mac, err := signer.Sign(context.Background(), []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mac) != 32 {
+		t.Errorf("len(mac) = %d, want 32", len(mac))
+	}
+
+	signer.Output = "raw" // 64 bytes of text is not a MAC
+	if _, err := signer.Sign(context.Background(), []byte("message")); err == nil {
+		t.Error("want an error for a 64-byte MAC")
+	}
+
+	if _, err := ParseCommandSigner("pkcs11-tool --sign", "pem"); err == nil {
+		t.Error("want an error for an unknown output")
+	}
+}
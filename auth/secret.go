@@ -0,0 +1,122 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrSecretDestroyed is returned when a destroyed Secret is used
+var ErrSecretDestroyed = errors.New("secret has been destroyed")
+
+// redacted is how a Secret prints, whatever the verb
+const redacted = "REDACTED"
+
+// Secret holds a credential outside the Go heap, locked out of swap where the
+// platform allows it, until Destroy zeroes it. It formats and marshals as
+// REDACTED, so it cannot leak through a log line, an error or a panic value.
+// A nil Secret is empty.
+type Secret struct {
+	mu     sync.RWMutex
+	mem    []byte // whole pages
+	n      int
+	mapped bool // mem is an mmap of its own rather than heap
+	locked bool
+	gone   bool
+}
+
+// NewSecret copies value into a new Secret and zeroes value
+func NewSecret(value []byte) *Secret {
+	s := &Secret{n: len(value)}
+	if len(value) > 0 {
+		s.mem, s.mapped, s.locked = allocSecret(len(value))
+		copy(s.mem, value)
+		clear(value)
+	}
+	return s
+}
+
+// NewSecretString copies value into a new Secret. The string itself cannot
+// be zeroed; prefer NewSecret where the credential is already in bytes.
+func NewSecretString(value string) *Secret {
+	return NewSecret([]byte(value))
+}
+
+// Use calls f with the secret, which f must neither retain nor modify
+func (s *Secret) Use(f func(value []byte) error) error {
+	if s == nil {
+		return f(nil)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.gone {
+		return ErrSecretDestroyed
+	}
+	return f(s.mem[:s.n:s.n])
+}
+
+// Reveal returns the secret as a string, for protocols that send it in the
+// clear. Unlike the Secret the string cannot be zeroed.
+func (s *Secret) Reveal() (string, error) {
+	var value string
+	err := s.Use(func(v []byte) error {
+		value = string(v)
+		return nil
+	})
+	return value, err
+}
+
+// Len returns the length of the secret
+func (s *Secret) Len() int {
+	if s == nil {
+		return 0
+	}
+	return s.n
+}
+
+// Locked reports whether the secret is locked in memory. Locking fails when
+// RLIMIT_MEMLOCK is exhausted or the platform has no mlock.
+func (s *Secret) Locked() bool {
+	return s != nil && s.locked
+}
+
+// Destroy zeroes and releases the secret; later uses return ErrSecretDestroyed
+func (s *Secret) Destroy() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.gone {
+		return
+	}
+	s.gone = true
+	if s.mem != nil {
+		freeSecret(s.mem, s.mapped, s.locked)
+		s.mem = nil
+	}
+}
+
+func (s *Secret) String() string   { return redacted }
+func (s *Secret) GoString() string { return redacted }
+
+// Format prints REDACTED for every verb, including %x and %#v
+func (s *Secret) Format(f fmt.State, _ rune) { fmt.Fprint(f, redacted) }
+
+func (s *Secret) MarshalJSON() ([]byte, error) { return json.Marshal(redacted) }
+func (s *Secret) MarshalText() ([]byte, error) { return []byte(redacted), nil }
@@ -0,0 +1,62 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSecretNeverFormats(t *testing.T) {
+	value := []byte("my-passphrase")
+	secret := NewSecret(value)
+	if !bytes.Equal(value, make([]byte, len(value))) {
+		t.Errorf("NewSecret left its input as %q", value)
+	}
+
+	wrapped := struct{ Passphrase *Secret }{secret}
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+		if got := fmt.Sprintf(verb, wrapped); bytes.Contains([]byte(got), []byte("my-passphrase")) || bytes.Contains([]byte(got), []byte("6d792d")) {
+			t.Errorf("%s printed %s", verb, got)
+		}
+	}
+	if data, _ := json.Marshal(wrapped); string(data) != `{"Passphrase":"REDACTED"}` {
+		t.Errorf("json = %s", data)
+	}
+
+	if got, err := secret.Reveal(); err != nil || got != "my-passphrase" {
+		t.Errorf("Reveal = %q, %v", got, err)
+	}
+	secret.Destroy()
+	if _, err := secret.Reveal(); !errors.Is(err, ErrSecretDestroyed) {
+		t.Errorf("Reveal after Destroy: %v", err)
+	}
+	secret.Destroy()
+}
+
+func TestHMACSignerDestroy(t *testing.T) {
+	signer := NewHMACSigner([]byte("my-signing-key"))
+	if _, err := signer.Sign(context.Background(), []byte("message")); err != nil {
+		t.Fatal(err)
+	}
+	signer.Destroy()
+	if _, err := signer.Sign(context.Background(), []byte("message")); !errors.Is(err, ErrSecretDestroyed) {
+		t.Errorf("Sign after Destroy: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package auth
+
+import (
+	"os"
+	"syscall"
+)
+
+// allocSecret maps n bytes of anonymous memory and tries to lock it so the
+// kernel never swaps it out, falling back to the heap
+func allocSecret(n int) (mem []byte, mapped, locked bool) {
+	page := os.Getpagesize()
+	size := (n + page - 1) / page * page
+	mem, err := syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		return make([]byte, n), false, false
+	}
+	return mem, true, syscall.Mlock(mem) == nil
+}
+
+// freeSecret zeroes mem and returns it to the kernel when it was mapped
+func freeSecret(mem []byte, mapped, locked bool) {
+	clear(mem)
+	if !mapped {
+		return
+	}
+	if locked {
+		syscall.Munlock(mem)
+	}
+	syscall.Munmap(mem)
+}
@@ -0,0 +1,91 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth computes the signatures Coinbase Prime authenticates FIX
+// logons with. Signing goes through a Signer so the key can live outside the
+// process, e.g. in a KMS or HSM.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// Signer computes the HMAC-SHA256 of a message with the API signing key
+type Signer interface {
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// HMACSigner signs with a signing key held in process memory, in a Secret
+type HMACSigner struct {
+	key *Secret
+}
+
+// NewHMACSigner returns a Signer for the API signing key secret, which it
+// moves into a Secret: secret is zeroed
+func NewHMACSigner(secret []byte) *HMACSigner {
+	return &HMACSigner{key: NewSecret(secret)}
+}
+
+// Sign returns the HMAC-SHA256 of message. The hash keeps padded copies of
+// the key on the heap only until it is collected.
+func (s *HMACSigner) Sign(_ context.Context, message []byte) ([]byte, error) {
+	var mac []byte
+	err := s.key.Use(func(key []byte) error {
+		h := hmac.New(sha256.New, key)
+		h.Write(message)
+		mac = h.Sum(nil)
+		return nil
+	})
+	return mac, err
+}
+
+// Destroy zeroes the signing key; Sign fails afterwards
+func (s *HMACSigner) Destroy() {
+	s.key.Destroy()
+}
+
+// Logon holds the fields of a Logon (35=A) the signature covers
+type Logon struct {
+	SendingTime  string // SendingTime (52), e.g. 20240102-15:04:05.000
+	MsgType      string // MsgType (35), always "A"
+	MsgSeqNum    string // MsgSeqNum (34)
+	AccessKey    string // AccessKey (9407)
+	TargetCompID string // TargetCompID (56)
+	Passphrase   string // Password (554)
+}
+
+// PreImage returns the message Prime expects signed: the fields concatenated
+// without separators in the order SendingTime, MsgType, MsgSeqNum, AccessKey,
+// TargetCompID, Passphrase
+func (l Logon) PreImage() []byte {
+	preImage := make([]byte, 0, len(l.SendingTime)+len(l.MsgType)+len(l.MsgSeqNum)+len(l.AccessKey)+len(l.TargetCompID)+len(l.Passphrase))
+	preImage = append(preImage, l.SendingTime...)
+	preImage = append(preImage, l.MsgType...)
+	preImage = append(preImage, l.MsgSeqNum...)
+	preImage = append(preImage, l.AccessKey...)
+	preImage = append(preImage, l.TargetCompID...)
+	return append(preImage, l.Passphrase...)
+}
+
+// SignLogon returns the base64 signature of logon for RawData (96)
+func SignLogon(ctx context.Context, signer Signer, logon Logon) (string, error) {
+	mac, err := signer.Sign(ctx, logon.PreImage())
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(mac), nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Vectors computed independently of this package, with HMAC-SHA256 over the
+// concatenated fields and standard base64
+var logonVectors = []struct {
+	logon     Logon
+	key       string
+	preImage  string
+	signature string
+}{
+	{
+		logon:     Logon{SendingTime: "20240102-15:04:05.000", MsgType: "A", MsgSeqNum: "1", AccessKey: "my-access-key", TargetCompID: "COIN", Passphrase: "my-passphrase"},
+		key:       "my-signing-key",
+		preImage:  "20240102-15:04:05.000A1my-access-keyCOINmy-passphrase",
+		signature: "R7zrX1a8Z/+tWBT8iYtn/1vKP03VFC7POAFbBEjlWv8=",
+	},
+	{
+		// Separators in values are signed as they are
+		logon:     Logon{SendingTime: "20250630-23:59:59.999", MsgType: "A", MsgSeqNum: "1", AccessKey: "AK", TargetCompID: "COIN", Passphrase: "p@ss|word="},
+		key:       "c2VjcmV0",
+		preImage:  "20250630-23:59:59.999A1AKCOINp@ss|word=",
+		signature: "mQPfUpziUICbtrK71DB5GFLnMQG+SX3yp++7cszKizU=",
+	},
+}
+
+func TestLogonVectors(t *testing.T) {
+	for _, vector := range logonVectors {
+		if preImage := string(vector.logon.PreImage()); preImage != vector.preImage {
+			t.Errorf("PreImage() = %q, want %q", preImage, vector.preImage)
+		}
+		signature, err := SignLogon(context.Background(), NewHMACSigner([]byte(vector.key)), vector.logon)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signature != vector.signature {
+			t.Errorf("SignLogon(%q) = %s, want %s", vector.preImage, signature, vector.signature)
+		}
+	}
+}
+
+type failingSigner struct{}
+
+func (failingSigner) Sign(context.Context, []byte) ([]byte, error) {
+	return nil, errors.New("key unavailable")
+}
+
+func TestSignLogonError(t *testing.T) {
+	if _, err := SignLogon(context.Background(), failingSigner{}, logonVectors[0].logon); err == nil {
+		t.Fatal("expected the signer's error")
+	}
+}
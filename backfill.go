@@ -0,0 +1,47 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/quickfixgo/quickfix"
+)
+
+// rewindForBackfill moves the next expected inbound sequence number of the
+// drop-copy session back by window, so that the venue's Logon reads as a gap
+// and quickfix requests a resend of the missed messages itself. Replayed
+// ExecutionReports arrive with PossDupFlag set and are deduplicated by ExecID
+// against the ExecutionStore. Sending a ResendRequest of our own after logon
+// recovers nothing: quickfix drops resent messages numbered below the
+// expected one before they reach FromApp.
+//
+// The rewind is relative to the persisted inbound sequence number, so it only
+// reaches messages from before a restart when the session uses a file store
+// and ResetOnLogon=N. quickfix stores are not goroutine safe, so call it from
+// the session's own callbacks, i.e. ToAdmin for the outgoing Logon.
+func rewindForBackfill(sessionId quickfix.SessionID, window int) (int, error) {
+	next, err := quickfix.GetExpectedTargetNum(sessionId)
+	if err != nil {
+		return 0, err
+	}
+
+	begin := next - window
+	if begin < 1 {
+		begin = 1
+	}
+	if begin == next {
+		return begin, nil
+	}
+	return begin, quickfix.SetNextTargetMsgSeqNum(sessionId, begin)
+}
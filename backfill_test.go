@@ -0,0 +1,194 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+
+	"prime-fix-go/auth"
+)
+
+// seededStoreFactory creates memory stores numbering from sender and target
+// and holding messages, each saved under its MsgSeqNum
+type seededStoreFactory struct {
+	sender, target int
+	messages       []*quickfix.Message
+}
+
+func (f seededStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	store, err := quickfix.NewMemoryStoreFactory().Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range f.messages {
+		seqNum, _ := msg.Header.GetInt(quickfix.Tag(34))
+		if err := store.SaveMessage(seqNum, msg.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := store.SetNextSenderMsgSeqNum(f.sender); err != nil {
+		return nil, err
+	}
+	return store, store.SetNextTargetMsgSeqNum(f.target)
+}
+
+func TestBackfillOnLogonRewindsFromExpectedTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		window int
+		want   string
+	}{
+		{"within the session", 3, "7"},
+		{"clamped to the first message", 100, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The venue has sent 9 messages before, so its Logon is number 10
+			app := &FixApplication{TargetCompId: "PRIME", BackfillWindow: tt.window}
+			venue, _ := startVenueWith(t, app, seededStoreFactory{sender: 1, target: 10}, seededStoreFactory{sender: 10, target: 1})
+
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				venue.mu.Lock()
+				resends := slices.Clone(venue.resends)
+				venue.mu.Unlock()
+				if len(resends) > 0 {
+					if resends[0] != tt.want {
+						t.Errorf("BeginSeqNo = %s, want %s", resends[0], tt.want)
+					}
+					return
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("no ResendRequest")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+// sentExecution is the drop copy of a fill the venue sent as message seqNum
+func sentExecution(seqNum int, execId string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(8), "FIXT.1.1")
+	msg.Header.SetString(quickfix.Tag(35), "8")
+	msg.Header.SetInt(quickfix.Tag(34), seqNum)
+	msg.Header.SetString(quickfix.Tag(49), "PRIME")
+	msg.Header.SetString(quickfix.Tag(56), "CLIENT")
+	msg.Header.SetString(quickfix.Tag(52), "20240102-15:04:05.000")
+	msg.Body.SetString(quickfix.Tag(37), "order-1")
+	msg.Body.SetString(quickfix.Tag(11), "1")
+	msg.Body.SetString(quickfix.Tag(17), execId)
+	msg.Body.SetString(quickfix.Tag(150), "F")
+	msg.Body.SetString(quickfix.Tag(39), "1")
+	msg.Body.SetString(quickfix.Tag(55), "BTC-USD")
+	msg.Body.SetString(quickfix.Tag(32), "1")
+	msg.Body.SetString(quickfix.Tag(31), "100")
+	return msg
+}
+
+func TestBackfillRecoversMissedExecutions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "executions.jsonl")
+	executions, err := OpenExecutionStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer executions.Close()
+	if err := executions.Append(ExecutionReport{ExecID: "E0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The client had processed up to message 10 but only persisted E0; the
+	// venue sent E1 twice and E2 before its Logon, number 11
+	app := &FixApplication{TargetCompId: "PRIME", BackfillWindow: 5, Executions: executions, Dedup: NewExecDedup(10)}
+	venueStores := seededStoreFactory{sender: 11, target: 1, messages: []*quickfix.Message{
+		sentExecution(7, "E0"), sentExecution(8, "E1"), sentExecution(9, "E1"), sentExecution(10, "E2"),
+	}}
+	startVenueWith(t, app, seededStoreFactory{sender: 1, target: 11}, venueStores)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !executions.Has("E2") {
+		if time.Now().After(deadline) {
+			t.Fatal("E2 was not backfilled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	reports, err := LoadExecutions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var execIds []string
+	for _, report := range reports {
+		execIds = append(execIds, report.ExecID)
+	}
+	if want := []string{"E0", "E1", "E2"}; !slices.Equal(execIds, want) {
+		t.Fatalf("persisted executions %v, want %v", execIds, want)
+	}
+}
+
+func TestRewindForBackfillUnknownSession(t *testing.T) {
+	if _, err := rewindForBackfill(quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "X", TargetCompID: "Y"}, 10); err == nil {
+		t.Error("backfill of an unknown session succeeded")
+	}
+}
+
+func TestLogonSignsItsHeader(t *testing.T) {
+	signer := auth.NewHMACSigner([]byte("key"))
+	tests := []struct {
+		name   string
+		seqNum int
+		reset  bool
+		want   string
+	}{
+		{"continuing sequence", 42, false, "42"},
+		{"reset on logon", 42, true, "1"},
+		{"first logon", 1, false, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &FixApplication{ApiKey: "access", TargetCompId: "COIN", Passphrase: auth.NewSecretString("passphrase"), Signer: signer}
+			logon := quickfix.NewMessage()
+			logon.Header.SetString(quickfix.Tag(35), "A")
+			logon.Header.SetInt(quickfix.Tag(34), tt.seqNum)
+			logon.Header.SetString(quickfix.Tag(52), "20240102-15:04:05.000")
+			if tt.reset {
+				logon.Body.SetBool(quickfix.Tag(141), true)
+			}
+			app.ToAdmin(logon, quickfix.SessionID{})
+
+			want, err := auth.SignLogon(context.Background(), signer, auth.Logon{
+				SendingTime:  "20240102-15:04:05.000",
+				MsgType:      "A",
+				MsgSeqNum:    tt.want,
+				AccessKey:    "access",
+				TargetCompID: "COIN",
+				Passphrase:   "passphrase",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bodyString(logon, quickfix.Tag(96)); got != want {
+				t.Errorf("signature %q, want the one over MsgSeqNum %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+// ReadQuotes reads recorded quotes from CSV rows of time,symbol,bid,ask with
+// the time in RFC 3339, skipping a header row
+func ReadQuotes(r io.Reader) ([]Quote, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	var quotes []Quote
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return quotes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && record[0] == "time" {
+			continue
+		}
+
+		quote := Quote{Symbol: record[1]}
+		if quote.Time, err = time.Parse(time.RFC3339Nano, record[0]); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if quote.Bid, err = decimal.NewFromString(record[2]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid bid: %w", line, err)
+		}
+		if quote.Ask, err = decimal.NewFromString(record[3]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid ask: %w", line, err)
+		}
+		quotes = append(quotes, quote)
+	}
+}
+
+// Backtest replays recorded quotes through a Strategy. The strategy trades
+// through a FixApplication like it does live, paper trading against the
+// quotes on a FakeClock, so order validation, tracking and fills take the
+// same path as in production and time only moves with the data.
+type Backtest struct {
+	Strategy Strategy
+
+	// The paper venue's latencies and slippage, see PaperVenue, and the
+	// seed of its random draws
+	AckLatency  LatencyDistribution
+	FillLatency LatencyDistribution
+	Slippage    SlippageModel
+	Seed        int64
+
+	// Settle is how long after the last quote outstanding reports are
+	// given to arrive, a minute by default
+	Settle time.Duration
+
+	// Setup, when set, configures the application before the run, e.g. to
+	// add the risk checks or order rules used live
+	Setup func(app *FixApplication)
+
+	// Logger receives the application's logging; by default it is discarded
+	Logger *log.Logger
+}
+
+// BacktestResult is the outcome of a Backtest
+type BacktestResult struct {
+	Start, End time.Time
+	Quotes     int
+	Executions []ExecutionReport
+	Positions  []Position
+}
+
+// Fills returns the executions that filled quantity
+func (r BacktestResult) Fills() []ExecutionReport {
+	var fills []ExecutionReport
+	for _, report := range r.Executions {
+		switch report.ExecType {
+		case "1", "2", "F": // Partial fill, Fill, Trade
+			fills = append(fills, report)
+		}
+	}
+	return fills
+}
+
+// Run feeds quotes, which must be in time order, through the strategy
+func (b Backtest) Run(quotes []Quote) (BacktestResult, error) {
+	if b.Strategy == nil {
+		return BacktestResult{}, errors.New("backtest has no strategy")
+	}
+	if len(quotes) == 0 {
+		return BacktestResult{}, errors.New("backtest has no quotes")
+	}
+
+	clock := NewFakeClock(quotes[0].Time)
+	venue := NewPaperVenue(clock, b.Seed)
+	venue.AckLatency, venue.FillLatency, venue.Slippage = b.AckLatency, b.FillLatency, b.Slippage
+
+	logger := b.Logger
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	app := &FixApplication{
+		Clock:     clock,
+		Logger:    logger,
+		Tracker:   NewOrderTracker(time.Minute),
+		Positions: NewPositionTracker(),
+		Paper:     venue,
+		Strategy:  b.Strategy,
+	}
+	app.Tracker.Clock = clock
+	result := BacktestResult{Start: quotes[0].Time}
+	app.OnExecutionReport = func(report ExecutionReport) {
+		result.Executions = append(result.Executions, report)
+	}
+	venue.Deliver = func(msg *quickfix.Message) { app.FromApp(msg, quickfix.SessionID{}) }
+	if b.Setup != nil {
+		b.Setup(app)
+	}
+
+	for i, quote := range quotes {
+		if quote.Time.Before(clock.Now()) {
+			return result, fmt.Errorf("quote %d at %s is out of order", i+1, quote.Time.Format(time.RFC3339Nano))
+		}
+		clock.Set(quote.Time)
+		app.OnQuote(quote.Symbol, quote.Bid, quote.Ask)
+		result.Quotes++
+	}
+
+	settle := b.Settle
+	if settle == 0 {
+		settle = time.Minute
+	}
+	clock.Advance(settle)
+
+	result.End = clock.Now()
+	result.Positions = app.Positions.All()
+	return result, nil
+}
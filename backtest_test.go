@@ -0,0 +1,147 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// buyThenTakeProfit buys once at market and, once filled, offers the same
+// quantity at a fixed profit
+type buyThenTakeProfit struct {
+	bought bool
+	err    error
+}
+
+func (s *buyThenTakeProfit) OnQuote(trader Trader, quote Quote) {
+	if s.bought {
+		return
+	}
+	s.bought = true
+	_, s.err = trader.PlaceOrder(OrderRequest{Symbol: quote.Symbol, OrdType: "MARKET", Side: "BUY", Quantity: "2"})
+}
+
+func (s *buyThenTakeProfit) OnExecution(trader Trader, report ExecutionReport) {
+	if report.Side != "1" || report.ExecType != "F" {
+		return
+	}
+	price := decimal.RequireFromString(report.LastPx).Add(decimal.NewFromInt(100))
+	_, s.err = trader.PlaceOrder(OrderRequest{Symbol: report.Symbol, OrdType: "LIMIT", Side: "SELL", Quantity: report.LastShares, LimitPrice: price.String()})
+}
+
+func TestBacktestRunsStrategy(t *testing.T) {
+	quotes, err := ReadQuotes(strings.NewReader(`time,symbol,bid,ask
+2024-01-02T15:00:00Z,BTC-USD,49990,50000
+2024-01-02T15:00:01Z,BTC-USD,50050,50060
+2024-01-02T15:00:02Z,BTC-USD,50110,50120
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strategy := &buyThenTakeProfit{}
+	result, err := Backtest{
+		Strategy:    strategy,
+		AckLatency:  LatencyDistribution{Kind: "fixed", A: 20 * time.Millisecond},
+		FillLatency: LatencyDistribution{Kind: "fixed", A: 5 * time.Millisecond},
+		Slippage:    SlippageModel{FixedBps: 2},
+	}.Run(quotes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strategy.err != nil {
+		t.Fatal(strategy.err)
+	}
+
+	fills := result.Fills()
+	if len(fills) != 2 {
+		t.Fatalf("expected 2 fills, got %d of %d executions", len(fills), len(result.Executions))
+	}
+	// The buy pays the ask plus 2bps and the sell rests until the bid reaches it
+	if fills[0].LastPx != "50010" || fills[1].LastPx != "50110" {
+		t.Fatalf("fills at %s and %s, want 50010 and 50110", fills[0].LastPx, fills[1].LastPx)
+	}
+	if !fills[1].ReceivedAt.Equal(quotes[2].Time.Add(5 * time.Millisecond)) {
+		t.Fatalf("sell filled at %s, want 5ms after the third quote", fills[1].ReceivedAt)
+	}
+	if len(result.Positions) != 1 || !result.Positions[0].NetQty.IsZero() {
+		t.Fatalf("expected a flat position, got %+v", result.Positions)
+	}
+}
+
+func TestBacktestRejectsQuotesOutOfOrder(t *testing.T) {
+	quotes, err := ReadQuotes(strings.NewReader(`2024-01-02T15:00:01Z,BTC-USD,1,2
+2024-01-02T15:00:00Z,BTC-USD,1,2
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Backtest{Strategy: &buyThenTakeProfit{}}).Run(quotes); err == nil {
+		t.Fatal("expected an error for quotes out of order")
+	}
+}
+
+// bothSides buys at the ask and sells at the bid on the first quote
+type bothSides struct {
+	ids []string
+	err error
+}
+
+func (s *bothSides) OnQuote(trader Trader, quote Quote) {
+	if len(s.ids) > 0 {
+		return
+	}
+	for _, order := range []OrderRequest{
+		{Symbol: quote.Symbol, OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: quote.Ask.String()},
+		{Symbol: quote.Symbol, OrdType: "LIMIT", Side: "SELL", Quantity: "1", LimitPrice: quote.Bid.String()},
+	} {
+		id, err := trader.PlaceOrder(order)
+		if err != nil {
+			s.err = err
+		}
+		s.ids = append(s.ids, id)
+	}
+}
+
+func (s *bothSides) OnExecution(Trader, ExecutionReport) {}
+
+// Orders placed at the same instant of the backtest clock get distinct ids
+func TestBacktestOrdersAtOneInstant(t *testing.T) {
+	quotes, err := ReadQuotes(strings.NewReader(`time,symbol,bid,ask
+2024-01-02T15:00:00Z,BTC-USD,49990,50000
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	strategy := &bothSides{}
+	result, err := Backtest{Strategy: strategy}.Run(quotes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strategy.err != nil {
+		t.Fatal(strategy.err)
+	}
+	if len(strategy.ids) != 2 || strategy.ids[0] == strategy.ids[1] {
+		t.Fatalf("ids = %v, want two distinct ids", strategy.ids)
+	}
+	fills := result.Fills()
+	if len(fills) != 2 || fills[0].ClOrdID == fills[1].ClOrdID {
+		t.Fatalf("fills = %+v, want one per order", fills)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// InsufficientFundsError is returned when the portfolio clearly cannot fund an order
+type InsufficientFundsError struct {
+	Currency  string
+	Required  decimal.Decimal
+	Available decimal.Decimal
+}
+
+// Shortfall returns how much more of Currency the order needs
+func (e *InsufficientFundsError) Shortfall() decimal.Decimal {
+	return e.Required.Sub(e.Available)
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient %s: order needs %s, %s available, short by %s",
+		e.Currency, e.Required, e.Available, e.Shortfall())
+}
+
+// BalanceChecker checks new orders against the portfolio's available balance
+// from the Prime REST API: the quote currency for buys and the base currency
+// for sells. A failed lookup lets the order through, as do buys whose
+// notional cannot be estimated; Prime remains the final check.
+type BalanceChecker struct {
+	REST      *PrimeREST
+	Positions *PositionTracker // last prices for market buys, optional
+	Timeout   time.Duration
+}
+
+// Check returns an *InsufficientFundsError if req clearly cannot be funded.
+// symbol is the Prime symbol of req, e.g. ETH-USD.
+func (c *BalanceChecker) Check(req OrderRequest, symbol string) error {
+	base, quote, ok := strings.Cut(symbol, "-")
+	if !ok {
+		return nil
+	}
+	qty, err := decimal.NewFromString(req.Quantity)
+	if err != nil {
+		return nil
+	}
+
+	currency, required := base, qty
+	if req.Side == "BUY" {
+		price, err := decimal.NewFromString(req.LimitPrice)
+		if err != nil && c.Positions != nil {
+			price = c.Positions.Get(req.Symbol).LastPrice
+		}
+		if !price.IsPositive() {
+			return nil
+		}
+		currency, required = quote, qty.Mul(price)
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	balance, err := c.REST.Balance(ctx, currency)
+	if err != nil {
+		log.Printf("Balance preflight skipped, %s lookup failed: %v", currency, err)
+		return nil
+	}
+	if available := balance.Available(); required.GreaterThan(available) {
+		return &InsufficientFundsError{Currency: currency, Required: required, Available: available}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"prime-fix-go/auth"
+)
+
+func TestBalanceChecker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-CB-ACCESS-SIGNATURE") == "" {
+			http.Error(w, `{"message":"unsigned"}`, http.StatusUnauthorized)
+			return
+		}
+		switch symbol := r.URL.Query().Get("symbols"); symbol {
+		case "usd":
+			fmt.Fprint(w, `{"balances":[{"symbol":"usd","amount":"1200","holds":"200"}]}`)
+		case "eth":
+			fmt.Fprint(w, `{"balances":[{"symbol":"eth","amount":"1.5","holds":"0.5"}]}`)
+		default:
+			http.Error(w, `{"message":"unknown currency"}`, http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	rest := &PrimeREST{BaseURL: server.URL, PortfolioId: "portfolio", Signer: auth.NewHMACSigner([]byte("key")), Client: server.Client()}
+	checker := &BalanceChecker{REST: rest}
+
+	tests := []struct {
+		name      string
+		req       OrderRequest
+		symbol    string
+		shortfall string // empty means the order passes
+	}{
+		{"funded buy", OrderRequest{Side: "BUY", Quantity: "2", LimitPrice: "500"}, "ETH-USD", ""},
+		{"underfunded buy", OrderRequest{Side: "BUY", Quantity: "3", LimitPrice: "500"}, "ETH-USD", "500"},
+		{"funded sell", OrderRequest{Side: "SELL", Quantity: "1"}, "ETH-USD", ""},
+		{"underfunded sell", OrderRequest{Side: "SELL", Quantity: "1.25"}, "ETH-USD", "0.25"},
+		{"market buy without a price", OrderRequest{Side: "BUY", Quantity: "100"}, "ETH-USD", ""},
+		{"failed lookup", OrderRequest{Side: "SELL", Quantity: "100"}, "BTC-USD", ""},
+		{"unparsable symbol", OrderRequest{Side: "SELL", Quantity: "100"}, "ETHUSD", ""},
+	}
+	for _, tt := range tests {
+		err := checker.Check(tt.req, tt.symbol)
+		var funds *InsufficientFundsError
+		if tt.shortfall == "" {
+			if err != nil {
+				t.Errorf("%s: Check = %v, want nil", tt.name, err)
+			}
+			continue
+		}
+		if !errors.As(err, &funds) {
+			t.Errorf("%s: Check = %v, want InsufficientFundsError", tt.name, err)
+			continue
+		}
+		if !funds.Shortfall().Equal(decimal.RequireFromString(tt.shortfall)) {
+			t.Errorf("%s: shortfall %s, want %s", tt.name, funds.Shortfall(), tt.shortfall)
+		}
+	}
+
+	var restErr *PrimeRESTError
+	if _, err := rest.Balance(context.Background(), "BTC"); !errors.As(err, &restErr) || restErr.Status != http.StatusBadRequest || restErr.Message != "unknown currency" {
+		t.Errorf("Balance error = %v", err)
+	}
+}
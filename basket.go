@@ -0,0 +1,278 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BasketIdKey is the metadata key carrying the basket id on each order of a basket
+const BasketIdKey = "basket_id"
+
+// ErrNoBaskets is returned by basket operations when no BasketManager is configured
+var ErrNoBaskets = errors.New("basket manager is not configured")
+
+// BasketOrder is the progress of one order of a basket
+type BasketOrder struct {
+	ClOrdID   string
+	Symbol    string
+	Side      string
+	Quantity  decimal.Decimal
+	FilledQty decimal.Decimal
+	State     OrderState
+}
+
+// Basket is the aggregate progress of a basket of orders
+type Basket struct {
+	Id     string
+	Orders []BasketOrder // in submission order
+	Done   bool          // every order is terminal
+}
+
+// Counts returns the number of orders of the basket in each state
+func (b Basket) Counts() map[OrderState]int {
+	counts := make(map[OrderState]int)
+	for _, order := range b.Orders {
+		counts[order.State]++
+	}
+	return counts
+}
+
+// PercentComplete returns the average filled share of the basket's orders, 0-100
+func (b Basket) PercentComplete() float64 {
+	if len(b.Orders) == 0 {
+		return 0
+	}
+	total := decimal.Zero
+	for _, order := range b.Orders {
+		if order.Quantity.IsPositive() {
+			total = total.Add(order.FilledQty.Div(order.Quantity))
+		}
+	}
+	pct, _ := total.Div(decimal.NewFromInt(int64(len(b.Orders)))).Mul(decimal.NewFromInt(100)).Float64()
+	return pct
+}
+
+// BasketManager submits groups of orders together and aggregates their progress
+type BasketManager struct {
+	mu      sync.Mutex
+	baskets map[string]*Basket
+	orders  map[string]*Basket // order ClOrdID -> basket
+	held    heldReports
+
+	validate func(req OrderRequest) error
+	place    func(req OrderRequest) (string, error)
+	cancel   func(clOrdID string) error
+
+	// OnBasketComplete is called once every order of a basket is terminal
+	OnBasketComplete func(basket Basket)
+}
+
+// NewBasketManager creates a manager checking orders with validate before
+// placing any of them through place, and cancelling them through cancel
+func NewBasketManager(validate func(req OrderRequest) error, place func(req OrderRequest) (string, error), cancel func(clOrdID string) error) *BasketManager {
+	return &BasketManager{
+		baskets:  make(map[string]*Basket),
+		orders:   make(map[string]*Basket),
+		validate: validate,
+		place:    place,
+		cancel:   cancel,
+	}
+}
+
+// Submit validates every request and only then places them, each tagged with
+// the basket id in its metadata. Nothing is sent if any request fails
+// validation; if a send fails part way the orders already placed are
+// cancelled. Risk limits are checked per order, not for the basket as a whole.
+func (m *BasketManager) Submit(reqs []OrderRequest) (string, error) {
+	if len(reqs) == 0 {
+		return "", errors.New("empty basket")
+	}
+
+	basket := &Basket{Id: "basket-" + strconv.FormatInt(time.Now().UnixNano(), 10)}
+	for i, req := range reqs {
+		quantity, err := decimal.NewFromString(req.Quantity)
+		if err != nil || !quantity.IsPositive() {
+			return "", fmt.Errorf("basket order %d: quantity must be a positive decimal", i)
+		}
+		if err := m.validate(req); err != nil {
+			return "", fmt.Errorf("basket order %d: %w", i, err)
+		}
+		basket.Orders = append(basket.Orders, BasketOrder{
+			Symbol:   req.Symbol,
+			Side:     req.Side,
+			Quantity: quantity,
+			State:    OrderPendingNew,
+		})
+	}
+
+	// Reports racing the placements are held until the basket is tracked
+	m.mu.Lock()
+	m.held.placing++
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.track(basket)
+		for _, report := range m.held.release(m.tracked) {
+			m.apply(report)
+		}
+	}()
+
+	for i, req := range reqs {
+		metadata := make(map[string]string, len(req.Metadata)+1)
+		for key, value := range req.Metadata {
+			metadata[key] = value
+		}
+		metadata[BasketIdKey] = basket.Id
+		req.Metadata = metadata
+
+		clOrdId, err := m.place(req)
+		if err != nil {
+			log.Printf("Basket %s order %d failed, cancelling the %d already placed: %v", basket.Id, i, i, err)
+			for _, placed := range basket.Orders[:i] {
+				if err := m.cancel(placed.ClOrdID); err != nil {
+					log.Printf("Failed to cancel basket %s order %s: %v", basket.Id, placed.ClOrdID, err)
+				}
+			}
+			basket.Orders = basket.Orders[:i]
+			basket.Done = true
+			return basket.Id, fmt.Errorf("basket order %d: %w", i, err)
+		}
+		basket.Orders[i].ClOrdID = clOrdId
+	}
+	return basket.Id, nil
+}
+
+// tracked reports whether clOrdID is an order of a tracked basket; callers must hold mu
+func (m *BasketManager) tracked(clOrdID string) bool {
+	_, ok := m.orders[clOrdID]
+	return ok
+}
+
+func (m *BasketManager) track(basket *Basket) {
+	m.baskets[basket.Id] = basket
+	for _, order := range basket.Orders {
+		m.orders[order.ClOrdID] = basket
+	}
+}
+
+// Get returns a copy of the basket id
+func (m *BasketManager) Get(id string) (Basket, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	basket, ok := m.baskets[id]
+	if !ok {
+		return Basket{}, false
+	}
+	copied := *basket
+	copied.Orders = append([]BasketOrder(nil), basket.Orders...)
+	return copied, true
+}
+
+// Cancel cancels every order of the basket that is still working, returning
+// the first error
+func (m *BasketManager) Cancel(id string) error {
+	m.mu.Lock()
+	basket, ok := m.baskets[id]
+	var working []string
+	if ok {
+		for _, order := range basket.Orders {
+			if !order.State.Terminal() {
+				working = append(working, order.ClOrdID)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return errors.New("unknown basket " + id)
+	}
+	var first error
+	for _, clOrdId := range working {
+		if err := m.cancel(clOrdId); err != nil {
+			log.Printf("Failed to cancel basket %s order %s: %v", id, clOrdId, err)
+			if first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// OnExecutionReport updates the progress of the basket order report refers to
+func (m *BasketManager) OnExecutionReport(report ExecutionReport) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tracked(report.ClOrdID) || m.tracked(report.OrigClOrdID) || !m.held.hold(report) {
+		m.apply(report)
+	}
+}
+
+// apply updates the basket order of report; callers must hold mu
+func (m *BasketManager) apply(report ExecutionReport) {
+	basket, ok := m.orders[report.ClOrdID]
+	if !ok {
+		basket, ok = m.orders[report.OrigClOrdID]
+	}
+	if !ok || basket.Done {
+		return
+	}
+
+	var order *BasketOrder
+	for i := range basket.Orders {
+		if id := basket.Orders[i].ClOrdID; id == report.ClOrdID || id == report.OrigClOrdID {
+			order = &basket.Orders[i]
+		}
+	}
+	if order == nil {
+		return
+	}
+	if report.ExecType == "5" && order.ClOrdID != report.ClOrdID { // Replace
+		delete(m.orders, order.ClOrdID)
+		order.ClOrdID = report.ClOrdID
+		m.orders[order.ClOrdID] = basket
+	}
+
+	switch report.ExecType {
+	case "1", "2", "F": // Partial fill, Fill, Trade
+		if qty, err := decimal.NewFromString(report.LastShares); err == nil {
+			order.FilledQty = order.FilledQty.Add(qty)
+		}
+	}
+	if state, ok := orderStateFromOrdStatus[report.OrdStatus]; ok && ValidateTransition(order.State, state) == nil {
+		order.State = state
+	}
+
+	for _, order := range basket.Orders {
+		if !order.State.Terminal() {
+			return
+		}
+	}
+	basket.Done = true
+	log.Printf("Basket %s complete: %d orders, %.1f%% filled", basket.Id, len(basket.Orders), basket.PercentComplete())
+	if m.OnBasketComplete != nil {
+		m.OnBasketComplete(*basket)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBasketAggregatesProgress(t *testing.T) {
+	legs := &legRecorder{}
+	baskets := NewBasketManager(func(OrderRequest) error { return nil }, legs.place, legs.cancel)
+	var completed []Basket
+	baskets.OnBasketComplete = func(basket Basket) { completed = append(completed, basket) }
+
+	id, err := baskets.Submit([]OrderRequest{
+		{Symbol: "BTC-USD", Side: "BUY", Quantity: "2", Metadata: map[string]string{"desk": "a"}},
+		{Symbol: "ETH-USD", Side: "SELL", Quantity: "4"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, req := range legs.placed {
+		if req.Metadata[BasketIdKey] != id {
+			t.Fatalf("order %+v not tagged with basket %s", req, id)
+		}
+	}
+	if legs.placed[0].Metadata["desk"] != "a" {
+		t.Fatal("basket dropped the order's own metadata")
+	}
+
+	baskets.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "2", OrdStatus: "2", LastShares: "2"})
+	baskets.OnExecutionReport(ExecutionReport{ClOrdID: "2", ExecType: "1", OrdStatus: "1", LastShares: "1"})
+	basket, _ := baskets.Get(id)
+	if basket.Done || basket.PercentComplete() != 62.5 || basket.Counts()[OrderFilled] != 1 || basket.Counts()[OrderPartiallyFilled] != 1 {
+		t.Fatalf("basket %+v, %.1f%% complete", basket, basket.PercentComplete())
+	}
+
+	// The second order is replaced, then canceled under its new ClOrdID
+	baskets.OnExecutionReport(ExecutionReport{ClOrdID: "2r", OrigClOrdID: "2", ExecType: "5", OrdStatus: "1"})
+	baskets.OnExecutionReport(ExecutionReport{ClOrdID: "2r", ExecType: "4", OrdStatus: "4"})
+
+	basket, _ = baskets.Get(id)
+	if !basket.Done || basket.Orders[1].ClOrdID != "2r" || basket.Orders[1].State != OrderCanceled {
+		t.Fatalf("basket %+v", basket)
+	}
+	if len(completed) != 1 || completed[0].Id != id {
+		t.Fatalf("completed %+v", completed)
+	}
+}
+
+func TestBasketSubmitValidatesEveryOrder(t *testing.T) {
+	legs := &legRecorder{}
+	errLimit := errors.New("over the order limit")
+	validate := func(req OrderRequest) error {
+		if req.Symbol == "SOL-USD" {
+			return errLimit
+		}
+		return nil
+	}
+	baskets := NewBasketManager(validate, legs.place, legs.cancel)
+
+	tests := []struct {
+		name string
+		reqs []OrderRequest
+	}{
+		{"empty", nil},
+		{"bad quantity", []OrderRequest{{Symbol: "BTC-USD", Quantity: "1"}, {Symbol: "ETH-USD", Quantity: "-1"}}},
+		{"fails risk", []OrderRequest{{Symbol: "BTC-USD", Quantity: "1"}, {Symbol: "SOL-USD", Quantity: "1"}}},
+	}
+	for _, tt := range tests {
+		if _, err := baskets.Submit(tt.reqs); err == nil {
+			t.Errorf("%s: Submit succeeded", tt.name)
+		}
+	}
+	if len(legs.placed) != 0 {
+		t.Fatalf("placed %+v from invalid baskets", legs.placed)
+	}
+	if _, err := baskets.Submit(tests[2].reqs); !errors.Is(err, errLimit) {
+		t.Fatalf("Submit = %v, want %v", err, errLimit)
+	}
+}
+
+func TestBasketCancelsPlacedOrdersOnSendFailure(t *testing.T) {
+	legs := &legRecorder{failAt: 3}
+	baskets := NewBasketManager(func(OrderRequest) error { return nil }, legs.place, legs.cancel)
+
+	id, err := baskets.Submit([]OrderRequest{{Quantity: "1"}, {Quantity: "1"}, {Quantity: "1"}})
+	if err == nil {
+		t.Fatal("Submit hid the failed send")
+	}
+	if !slices.Equal(legs.canceled, []string{"1", "2"}) {
+		t.Fatalf("canceled %v, want [1 2]", legs.canceled)
+	}
+	if basket, ok := baskets.Get(id); !ok || !basket.Done || len(basket.Orders) != 2 {
+		t.Fatalf("Get(%s) = %+v, %t", id, basket, ok)
+	}
+}
+
+func TestBasketCancel(t *testing.T) {
+	legs := &legRecorder{}
+	baskets := NewBasketManager(func(OrderRequest) error { return nil }, legs.place, legs.cancel)
+	id, _ := baskets.Submit([]OrderRequest{{Quantity: "1"}, {Quantity: "1"}})
+	baskets.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "2", OrdStatus: "2", LastShares: "1"})
+
+	if err := baskets.Cancel(id); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(legs.canceled, []string{"2"}) {
+		t.Fatalf("canceled %v, want only the working order", legs.canceled)
+	}
+	if err := baskets.Cancel("basket-unknown"); err == nil {
+		t.Fatal("canceled an unknown basket")
+	}
+}
+
+func TestBasketSendsOutsideItsLock(t *testing.T) {
+	legs := &legRecorder{}
+	var baskets *BasketManager
+	place := func(req OrderRequest) (string, error) {
+		clOrdId, err := legs.place(req)
+		// Each order fills before its send returns
+		baskets.OnExecutionReport(ExecutionReport{ClOrdID: clOrdId, ExecType: "2", OrdStatus: "2", LastShares: req.Quantity})
+		return clOrdId, err
+	}
+	baskets = NewBasketManager(func(OrderRequest) error { return nil }, place, legs.cancel)
+
+	id, err := baskets.Submit([]OrderRequest{{Quantity: "1"}, {Quantity: "2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if basket, _ := baskets.Get(id); !basket.Done || basket.PercentComplete() != 100 {
+		t.Fatalf("basket %+v, %.0f%% complete", basket, basket.PercentComplete())
+	}
+}
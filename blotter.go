@@ -0,0 +1,275 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	blotterOrders = 20 // open orders shown
+	blotterFills  = 10 // recent fills kept
+	blotterLogs   = 8  // log lines kept
+)
+
+// Blotter is a live terminal view of the running client: the sessions and
+// their message rates, the open orders, the recent fills and the log. It
+// redraws every Interval and reads one command per line:
+//
+//	c ClOrdID  cancel an order
+//	ca         cancel every open order
+//	q          stop the client
+//
+// The log is written to the Blotter, see Write, so it does not scroll the
+// view away.
+type Blotter struct {
+	Manager  *Manager
+	In       io.Reader
+	Out      io.Writer
+	Interval time.Duration
+
+	// Log, when set, also receives everything written to the Blotter, so
+	// the reason for a fatal error outlives the view
+	Log io.Writer
+
+	mu     sync.Mutex
+	logs   []string
+	fills  []ExecutionReport
+	stats  map[string]SessionStats
+	status string
+}
+
+// NewBlotter creates a Blotter of manager's tenants reading commands from in
+// and drawing to out every second
+func NewBlotter(manager *Manager, in io.Reader, out io.Writer) *Blotter {
+	return &Blotter{Manager: manager, In: in, Out: out, Interval: time.Second, stats: make(map[string]SessionStats)}
+}
+
+// Write keeps the last lines of p for the log pane
+func (b *Blotter) Write(p []byte) (int, error) {
+	if b.Log != nil {
+		b.Log.Write(p)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		b.logs = append(b.logs, line)
+	}
+	if len(b.logs) > blotterLogs {
+		b.logs = b.logs[len(b.logs)-blotterLogs:]
+	}
+	return len(p), nil
+}
+
+// OnExecutionReport keeps the fill carried by report, if any
+func (b *Blotter) OnExecutionReport(report ExecutionReport) {
+	switch report.ExecType {
+	case "1", "2", "F": // Partial fill, Fill, Trade
+	default:
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fills = append(b.fills, report)
+	if len(b.fills) > blotterFills {
+		b.fills = b.fills[len(b.fills)-blotterFills:]
+	}
+}
+
+// Run draws the blotter and runs commands until q is entered or In ends
+func (b *Blotter) Run() {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(b.In)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	ticker := time.NewTicker(b.Interval)
+	defer ticker.Stop()
+	for {
+		b.draw(time.Now())
+		select {
+		case <-ticker.C:
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			if quit := b.Command(line); quit {
+				return
+			}
+		}
+	}
+}
+
+// Command runs one command line, reporting whether it asked to quit
+func (b *Blotter) Command(line string) bool {
+	fields := strings.Fields(line)
+	var status string
+	switch {
+	case len(fields) == 0:
+		return false
+	case fields[0] == "q":
+		return true
+	case fields[0] == "c" && len(fields) == 2:
+		status = b.cancel(fields[1])
+	case fields[0] == "ca":
+		status = b.cancelAll()
+	default:
+		status = fmt.Sprintf("unknown command %q", line)
+	}
+	b.mu.Lock()
+	b.status = status
+	b.mu.Unlock()
+	return false
+}
+
+func (b *Blotter) cancel(clOrdID string) string {
+	for _, tenant := range b.Manager.Tenants() {
+		if tenant.App.Tracker == nil {
+			continue
+		}
+		if _, ok := tenant.App.Tracker.Get(clOrdID); !ok {
+			continue
+		}
+		if err := tenant.App.CancelOrder(clOrdID); err != nil {
+			return fmt.Sprintf("cancel %s failed: %v", clOrdID, err)
+		}
+		return "cancel " + clOrdID + " sent"
+	}
+	return "unknown order " + clOrdID
+}
+
+func (b *Blotter) cancelAll() string {
+	sent, failed := 0, 0
+	for _, tenant := range b.Manager.Tenants() {
+		for _, result := range tenant.App.CancelAll(CancelFilter{}, 0) {
+			if result.Error != "" {
+				failed++
+			} else {
+				sent++
+			}
+		}
+	}
+	return fmt.Sprintf("cancel all: %d sent, %d failed", sent, failed)
+}
+
+// openOrders returns the orders of app that are not yet terminal, by ClOrdID
+func openOrders(app *FixApplication) []Order {
+	if app.Tracker == nil {
+		return nil
+	}
+	orders, _ := app.Tracker.Snapshot()
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ClOrdID < orders[j].ClOrdID })
+	return orders
+}
+
+// rates returns the messages per second in and out of a session since its
+// stats were last taken
+func (b *Blotter) rates(name string, stats SessionStats) (in, out float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if last, ok := b.stats[name]; ok && stats.Time.After(last.Time) {
+		seconds := stats.Time.Sub(last.Time).Seconds()
+		in = float64(stats.MessagesIn-last.MessagesIn) / seconds
+		out = float64(stats.MessagesOut-last.MessagesOut) / seconds
+	}
+	b.stats[name] = stats
+	return in, out
+}
+
+func (b *Blotter) draw(now time.Time) {
+	var frame bytes.Buffer
+	frame.WriteString("\x1b[H\x1b[2J") // cursor home, clear screen
+	b.Render(&frame, now)
+	b.Out.Write(frame.Bytes())
+}
+
+// Render writes one frame of the blotter taken at now
+func (b *Blotter) Render(w io.Writer, now time.Time) {
+	fmt.Fprintf(w, "prime-fix-go %s   %s\n\n", BuildInfo().Version, now.Format("2006-01-02 15:04:05"))
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SESSION\tSTATUS\tIN/s\tOUT/s\tSEQ OUT\tSEQ IN\tRECONNECTS")
+	for _, tenant := range b.Manager.Tenants() {
+		stats := tenant.App.SessionStats()
+		status := "logged out"
+		switch {
+		case tenant.App.Paper != nil:
+			status = "paper"
+		case stats.LoggedOn:
+			status = "logged on"
+		}
+		in, out := b.rates(tenant.Name, stats)
+		fmt.Fprintf(table, "%s\t%s\t%.1f\t%.1f\t%d\t%d\t%d\n",
+			tenant.Name, status, in, out, stats.NextSenderMsgSeqNum, stats.NextTargetMsgSeqNum, stats.Reconnects)
+	}
+	table.Flush()
+
+	fmt.Fprintln(w, "\nOPEN ORDERS")
+	table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SESSION\tCLORDID\tSYMBOL\tSIDE\tQTY\tPRICE\tFILLED\tSTATE")
+	shown, total := 0, 0
+	for _, tenant := range b.Manager.Tenants() {
+		for _, order := range openOrders(tenant.App) {
+			total++
+			if shown == blotterOrders {
+				continue
+			}
+			shown++
+			state := string(order.State)
+			if order.Pending != "" {
+				state += " (" + string(order.Pending) + ")"
+			}
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				tenant.Name, order.ClOrdID, order.Symbol, order.Side, order.Quantity, order.LimitPrice, order.CumQty, state)
+		}
+	}
+	table.Flush()
+	if total > shown {
+		fmt.Fprintf(w, "... and %d more\n", total-shown)
+	}
+
+	// The tenants above may log, so the lock is only taken from here on
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fmt.Fprintln(w, "\nRECENT FILLS")
+	table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "TIME\tSESSION\tCLORDID\tSYMBOL\tSIDE\tQTY\tPRICE")
+	for i := len(b.fills) - 1; i >= 0; i-- {
+		fill := b.fills[i]
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			fill.ReceivedAt.Format("15:04:05"), fill.Tenant, fill.ClOrdID, fill.Symbol, SideName(fill.Side), fill.LastShares, fill.LastPx)
+	}
+	table.Flush()
+
+	fmt.Fprintln(w, "\nLOG")
+	for _, line := range b.logs {
+		fmt.Fprintln(w, line)
+	}
+
+	fmt.Fprintf(w, "\n%s\nc ClOrdID: cancel  ca: cancel all  q: quit\n> ", b.status)
+}
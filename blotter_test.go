@@ -0,0 +1,60 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlotterRendersOrdersFillsAndLog(t *testing.T) {
+	app := &FixApplication{Tracker: NewOrderTracker(time.Minute)}
+	app.Tracker.Add(Order{ClOrdID: "ord-1", Symbol: "BTC-USD", Side: "BUY", Quantity: "2", LimitPrice: "100"})
+	app.Tracker.Add(Order{ClOrdID: "ord-2", State: OrderFilled})
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app}); err != nil {
+		t.Fatal(err)
+	}
+
+	blotter := NewBlotter(manager, strings.NewReader(""), &bytes.Buffer{})
+	blotter.OnExecutionReport(ExecutionReport{ExecType: "1", Tenant: "desk", ClOrdID: "ord-1", Symbol: "BTC-USD", Side: "1", LastShares: "0.5", LastPx: "99.5"})
+	blotter.OnExecutionReport(ExecutionReport{ExecType: "0", ClOrdID: "ord-3"}) // not a fill
+	for i := range blotterLogs + 2 {
+		fmt.Fprintf(blotter, "line %d\n", i)
+	}
+	if quit := blotter.Command("c ord-9"); quit {
+		t.Fatal("cancel asked to quit")
+	}
+
+	var frame bytes.Buffer
+	blotter.Render(&frame, time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC))
+	out := frame.String()
+	for _, want := range []string{"desk", "logged out", "ord-1", "99.5", "line 9", "unknown order ord-9"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("frame is missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"ord-2", "ord-3", "line 1\n"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("frame shows %q:\n%s", unwanted, out)
+		}
+	}
+	if !blotter.Command("q") {
+		t.Fatal("q did not quit")
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// BracketRequest is an entry order protected by a take-profit limit order and
+// a stop-limit order, both submitted on the opposite side once the entry fills
+type BracketRequest struct {
+	Entry           OrderRequest
+	TakeProfitPrice string
+	StopPrice       string
+	StopLimitPrice  string
+}
+
+// BracketState is the progress of a bracket through its legs
+type BracketState string
+
+const (
+	BracketWorkingEntry BracketState = "WorkingEntry"
+	BracketWorkingExits BracketState = "WorkingExits"
+	BracketClosed       BracketState = "Closed"
+
+	// BracketUnprotected is a filled entry missing an exit that could not be placed
+	BracketUnprotected BracketState = "Unprotected"
+)
+
+// Bracket is a bracket order and the ClOrdIDs of its legs
+type Bracket struct {
+	EntryClOrdID      string
+	TakeProfitClOrdID string
+	StopClOrdID       string
+	State             BracketState
+
+	request      BracketRequest
+	quantity     decimal.Decimal // of the filled entry the exits protect
+	placingExits bool
+}
+
+// BracketManager works bracket orders client-side: it submits the exit legs
+// when the entry fills, shrinks the other exit as one partially fills and
+// cancels it once one fills in full
+type BracketManager struct {
+	mu       sync.Mutex
+	brackets map[string]*Bracket // keyed by the ClOrdID of every leg
+	held     heldReports
+
+	place   func(req OrderRequest) (string, error)
+	cancel  func(clOrdID string) error
+	replace func(clOrdID, quantity, limitPrice string) error
+
+	// OnUnprotected is called when an exit of a filled entry cannot be placed
+	OnUnprotected func(bracket Bracket, err error)
+}
+
+// NewBracketManager creates a manager placing, cancelling and replacing legs
+// through place, cancel and replace
+func NewBracketManager(place func(req OrderRequest) (string, error), cancel func(clOrdID string) error, replace func(clOrdID, quantity, limitPrice string) error) *BracketManager {
+	return &BracketManager{
+		brackets: make(map[string]*Bracket),
+		place:    place,
+		cancel:   cancel,
+		replace:  replace,
+	}
+}
+
+// Submit places the entry order of req, returning its ClOrdID which also identifies the bracket
+func (m *BracketManager) Submit(req BracketRequest) (string, error) {
+	if req.TakeProfitPrice == "" || req.StopPrice == "" || req.StopLimitPrice == "" {
+		return "", errors.New("bracket requires take-profit, stop and stop-limit prices")
+	}
+
+	// Reports racing the placement are held until the bracket is registered
+	m.mu.Lock()
+	m.held.placing++
+	m.mu.Unlock()
+
+	clOrdId, err := m.place(req.Entry)
+
+	m.mu.Lock()
+	if err == nil {
+		m.brackets[clOrdId] = &Bracket{EntryClOrdID: clOrdId, State: BracketWorkingEntry, request: req}
+	}
+	actions := m.replay()
+	m.mu.Unlock()
+
+	runActions(actions)
+	return clOrdId, err
+}
+
+// Get returns a copy of the bracket one of whose legs is clOrdID
+func (m *BracketManager) Get(clOrdID string) (Bracket, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bracket, ok := m.brackets[clOrdID]
+	if !ok {
+		return Bracket{}, false
+	}
+	return *bracket, true
+}
+
+// OnExecutionReport advances the bracket the report belongs to. Legs are
+// placed and cancelled after the manager's lock is released, so a slow send
+// does not hold up the session.
+func (m *BracketManager) OnExecutionReport(report ExecutionReport) {
+	m.mu.Lock()
+	var actions []func()
+	if _, _, ok := m.leg(report); ok || !m.held.hold(report) {
+		actions = m.apply(report)
+	}
+	m.mu.Unlock()
+
+	runActions(actions)
+}
+
+// leg returns the bracket of report and the ClOrdID of its leg. Reports for
+// a cancel or replace of a leg carry the leg's ClOrdID as OrigClOrdID.
+// Callers must hold mu.
+func (m *BracketManager) leg(report ExecutionReport) (*Bracket, string, bool) {
+	if bracket, ok := m.brackets[report.ClOrdID]; ok {
+		return bracket, report.ClOrdID, true
+	}
+	bracket, ok := m.brackets[report.OrigClOrdID]
+	return bracket, report.OrigClOrdID, ok
+}
+
+// apply advances the bracket of report, returning the sends it calls for;
+// callers must hold mu
+func (m *BracketManager) apply(report ExecutionReport) []func() {
+	bracket, leg, ok := m.leg(report)
+	if !ok || bracket.State == BracketClosed {
+		return nil
+	}
+
+	// A replaced exit goes by the ClOrdID of the replace from now on
+	if report.ExecType == "5" && leg != report.ClOrdID && leg != bracket.EntryClOrdID {
+		switch leg {
+		case bracket.TakeProfitClOrdID:
+			bracket.TakeProfitClOrdID = report.ClOrdID
+		case bracket.StopClOrdID:
+			bracket.StopClOrdID = report.ClOrdID
+		}
+		m.brackets[report.ClOrdID] = bracket
+		leg = report.ClOrdID
+	}
+
+	state := orderStateFromOrdStatus[report.OrdStatus]
+	switch {
+	case leg == bracket.EntryClOrdID:
+		if !state.Terminal() || bracket.placingExits || bracket.State != BracketWorkingEntry {
+			return nil
+		}
+		// A partially filled entry that is then canceled is still protected
+		filled, _ := decimal.NewFromString(report.CumQty)
+		if !filled.IsPositive() {
+			bracket.State = BracketClosed
+			return nil
+		}
+		bracket.placingExits = true
+		bracket.quantity = filled
+		m.held.placing++
+		return []func(){func() { m.submitExits(bracket, filled.String()) }}
+
+	case report.ExecType == "1" || report.ExecType == "2" || report.ExecType == "F":
+		sibling, siblingPrice := bracket.StopClOrdID, bracket.request.StopLimitPrice
+		if leg == bracket.StopClOrdID {
+			sibling, siblingPrice = bracket.TakeProfitClOrdID, bracket.request.TakeProfitPrice
+		}
+		if state != OrderFilled {
+			// One exit has partially executed; the other only protects the rest
+			filled, _ := decimal.NewFromString(report.CumQty)
+			remaining := bracket.quantity.Sub(filled)
+			if sibling == "" || !filled.IsPositive() || !remaining.IsPositive() {
+				return nil
+			}
+			return []func(){func() {
+				if err := m.replace(sibling, remaining.String(), siblingPrice); err != nil {
+					log.Printf("Failed to replace bracket leg %s down to %s: %v", sibling, remaining, err)
+				}
+			}}
+		}
+
+		// One exit has filled; cancel the other
+		bracket.State = BracketClosed
+		if sibling == "" {
+			return nil
+		}
+		return []func(){func() {
+			if err := m.cancel(sibling); err != nil {
+				log.Printf("Failed to cancel bracket leg %s: %v", sibling, err)
+			}
+		}}
+	}
+	return nil
+}
+
+// submitExits places both exit legs for quantity. It runs without mu, which
+// apply has counted it as a placement in flight under.
+func (m *BracketManager) submitExits(bracket *Bracket, quantity string) {
+	entry := bracket.request.Entry
+	exitSide := "SELL"
+	if entry.Side == "SELL" {
+		exitSide = "BUY"
+	}
+	// The exits carry whatever confirmed the entry, or the fat-finger check
+	// that passed the entry would reject them
+	exit := OrderRequest{
+		Symbol:        entry.Symbol,
+		Side:          exitSide,
+		Quantity:      quantity,
+		Force:         entry.Force,
+		Metadata:      entry.Metadata,
+		CorrelationId: entry.CorrelationId,
+	}
+
+	takeProfit := exit
+	takeProfit.OrdType = "LIMIT"
+	takeProfit.LimitPrice = bracket.request.TakeProfitPrice
+	takeProfitId, takeProfitErr := m.place(takeProfit)
+
+	stop := exit
+	stop.OrdType = "STOP_LIMIT"
+	stop.LimitPrice = bracket.request.StopLimitPrice
+	stop.StopPrice = bracket.request.StopPrice
+	stopId, stopErr := m.place(stop)
+
+	m.mu.Lock()
+	if takeProfitErr == nil {
+		bracket.TakeProfitClOrdID = takeProfitId
+		m.brackets[takeProfitId] = bracket
+	}
+	if stopErr == nil {
+		bracket.StopClOrdID = stopId
+		m.brackets[stopId] = bracket
+	}
+	bracket.placingExits = false
+	bracket.State = BracketWorkingExits
+	err := errors.Join(takeProfitErr, stopErr)
+	if err != nil {
+		bracket.State = BracketUnprotected
+	}
+	unprotected := *bracket
+	actions := m.replay()
+	m.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Bracket %s is unprotected, failed to place its exits: %v", bracket.EntryClOrdID, err)
+		if m.OnUnprotected != nil {
+			m.OnUnprotected(unprotected, err)
+		}
+	}
+	runActions(actions)
+}
+
+// replay ends a placement and applies the reports held for orders it
+// registered; callers must hold mu
+func (m *BracketManager) replay() []func() {
+	var actions []func()
+	for _, report := range m.held.release(func(clOrdID string) bool { _, ok := m.brackets[clOrdID]; return ok }) {
+		actions = append(actions, m.apply(report)...)
+	}
+	return actions
+}
+
+// heldReports keeps the ExecutionReports that arrive while a manager places
+// orders outside its lock, since a fast fill can beat the placement's return
+// and so the registration of its ClOrdID
+type heldReports struct {
+	placing int
+	reports []ExecutionReport
+}
+
+// hold keeps report, of an order not yet registered, while a placement is in
+// flight and reports whether it did
+func (h *heldReports) hold(report ExecutionReport) bool {
+	if h.placing == 0 {
+		return false
+	}
+	h.reports = append(h.reports, report)
+	return true
+}
+
+// release ends a placement, returning in arrival order the held reports of
+// the orders registered is true for. The others are dropped once no placement
+// is left in flight.
+func (h *heldReports) release(registered func(clOrdID string) bool) []ExecutionReport {
+	h.placing--
+	var ready, waiting []ExecutionReport
+	for _, report := range h.reports {
+		if registered(report.ClOrdID) {
+			ready = append(ready, report)
+		} else if h.placing > 0 {
+			waiting = append(waiting, report)
+		}
+	}
+	h.reports = waiting
+	return ready
+}
+
+func runActions(actions []func()) {
+	for _, action := range actions {
+		action()
+	}
+}
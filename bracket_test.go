@@ -0,0 +1,278 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// legRecorder places and cancels orders in memory for the client-side order
+// managers, numbering ClOrdIDs from 1
+type legRecorder struct {
+	placed   []OrderRequest
+	canceled []string
+	replaced []string // ClOrdID:quantity@price
+	failAt   int      // place fails for this order, counting from 1
+}
+
+func (r *legRecorder) place(req OrderRequest) (string, error) {
+	if len(r.placed)+1 == r.failAt {
+		return "", errors.New("venue unavailable")
+	}
+	r.placed = append(r.placed, req)
+	return strconv.Itoa(len(r.placed)), nil
+}
+
+func (r *legRecorder) cancel(clOrdID string) error {
+	r.canceled = append(r.canceled, clOrdID)
+	return nil
+}
+
+func (r *legRecorder) replace(clOrdID, quantity, limitPrice string) error {
+	r.replaced = append(r.replaced, clOrdID+":"+quantity+"@"+limitPrice)
+	return nil
+}
+
+func TestBracketSubmitsExitsOnEntryFill(t *testing.T) {
+	legs := &legRecorder{}
+	brackets := NewBracketManager(legs.place, legs.cancel, legs.replace)
+	id, err := brackets.Submit(BracketRequest{
+		Entry:           OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "2", LimitPrice: "100"},
+		TakeProfitPrice: "110",
+		StopPrice:       "95",
+		StopLimitPrice:  "94",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	brackets.OnExecutionReport(ExecutionReport{ClOrdID: id, ExecType: "1", OrdStatus: "1", CumQty: "1"})
+	if len(legs.placed) != 1 {
+		t.Fatalf("exits placed on a partial entry fill: %+v", legs.placed)
+	}
+	// The rest of the entry is canceled; the filled part is still protected.
+	// The cancel's report goes by the cancel request's ClOrdID.
+	brackets.OnExecutionReport(ExecutionReport{ClOrdID: "cancel-1", OrigClOrdID: id, ExecType: "4", OrdStatus: "4", CumQty: "1"})
+
+	bracket, _ := brackets.Get(id)
+	if bracket.State != BracketWorkingExits || bracket.TakeProfitClOrdID != "2" || bracket.StopClOrdID != "3" {
+		t.Fatalf("bracket %+v", bracket)
+	}
+	takeProfit, stop := legs.placed[1], legs.placed[2]
+	if takeProfit.Side != "SELL" || takeProfit.OrdType != "LIMIT" || takeProfit.Quantity != "1" || takeProfit.LimitPrice != "110" {
+		t.Fatalf("take-profit %+v", takeProfit)
+	}
+	if stop.Side != "SELL" || stop.OrdType != "STOP_LIMIT" || stop.Quantity != "1" || stop.StopPrice != "95" || stop.LimitPrice != "94" {
+		t.Fatalf("stop %+v", stop)
+	}
+	if leg, ok := brackets.Get("3"); !ok || leg.EntryClOrdID != id {
+		t.Fatalf("Get(stop) = %+v, %t", leg, ok)
+	}
+}
+
+func TestBracketCancelsSiblingExit(t *testing.T) {
+	tests := []struct {
+		name     string
+		executed string
+		sibling  string
+	}{
+		{"take-profit fills", "2", "3"},
+		{"stop fills", "3", "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legs := &legRecorder{}
+			brackets := NewBracketManager(legs.place, legs.cancel, legs.replace)
+			id, _ := brackets.Submit(BracketRequest{
+				Entry:           OrderRequest{Symbol: "BTC-USD", Side: "SELL", Quantity: "1"},
+				TakeProfitPrice: "90",
+				StopPrice:       "105",
+				StopLimitPrice:  "106",
+			})
+			brackets.OnExecutionReport(ExecutionReport{ClOrdID: id, ExecType: "2", OrdStatus: "2", CumQty: "1"})
+			if legs.placed[1].Side != "BUY" {
+				t.Fatalf("exit side %s for a SELL entry", legs.placed[1].Side)
+			}
+
+			brackets.OnExecutionReport(ExecutionReport{ClOrdID: tt.executed, ExecType: "1", OrdStatus: "1"})
+			brackets.OnExecutionReport(ExecutionReport{ClOrdID: tt.executed, ExecType: "2", OrdStatus: "2"})
+
+			if !slices.Equal(legs.canceled, []string{tt.sibling}) {
+				t.Fatalf("canceled %v, want [%s]", legs.canceled, tt.sibling)
+			}
+			if bracket, _ := brackets.Get(id); bracket.State != BracketClosed {
+				t.Fatalf("state = %s, want %s", bracket.State, BracketClosed)
+			}
+		})
+	}
+}
+
+func TestBracketShrinksSiblingOnPartialExitFill(t *testing.T) {
+	tests := []struct {
+		name      string
+		executed  string
+		sibling   string
+		replaced  string
+		newLegId  string
+		stopMoves bool
+	}{
+		{"take-profit partially fills", "2", "3", "3:2@94", "3r", true},
+		{"stop partially fills", "3", "2", "2:2@110", "2r", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legs := &legRecorder{}
+			brackets := NewBracketManager(legs.place, legs.cancel, legs.replace)
+			id, _ := brackets.Submit(BracketRequest{
+				Entry:           OrderRequest{Symbol: "BTC-USD", Side: "BUY", Quantity: "3"},
+				TakeProfitPrice: "110",
+				StopPrice:       "95",
+				StopLimitPrice:  "94",
+			})
+			brackets.OnExecutionReport(ExecutionReport{ClOrdID: id, ExecType: "2", OrdStatus: "2", CumQty: "3"})
+
+			brackets.OnExecutionReport(ExecutionReport{ClOrdID: tt.executed, ExecType: "1", OrdStatus: "1", CumQty: "1"})
+			if !slices.Equal(legs.replaced, []string{tt.replaced}) || len(legs.canceled) != 0 {
+				t.Fatalf("replaced %v, canceled %v, want the sibling replaced to %s", legs.replaced, legs.canceled, tt.replaced)
+			}
+			if bracket, _ := brackets.Get(id); bracket.State != BracketWorkingExits {
+				t.Fatalf("state = %s after a partial exit fill", bracket.State)
+			}
+
+			// The sibling's replace is acknowledged under its new ClOrdID,
+			// which the final cancel then goes by
+			brackets.OnExecutionReport(ExecutionReport{ClOrdID: tt.newLegId, OrigClOrdID: tt.sibling, ExecType: "5", OrdStatus: "0"})
+			bracket, _ := brackets.Get(tt.newLegId)
+			if moved := bracket.StopClOrdID == tt.newLegId; bracket.EntryClOrdID != id || moved != tt.stopMoves {
+				t.Fatalf("bracket %+v after the replace of %s", bracket, tt.sibling)
+			}
+
+			brackets.OnExecutionReport(ExecutionReport{ClOrdID: tt.executed, ExecType: "2", OrdStatus: "2", CumQty: "3"})
+			if !slices.Equal(legs.canceled, []string{tt.newLegId}) {
+				t.Fatalf("canceled %v, want [%s]", legs.canceled, tt.newLegId)
+			}
+			if bracket, _ := brackets.Get(id); bracket.State != BracketClosed {
+				t.Fatalf("state = %s, want %s", bracket.State, BracketClosed)
+			}
+		})
+	}
+}
+
+func TestBracketClosesUnfilledEntry(t *testing.T) {
+	legs := &legRecorder{}
+	brackets := NewBracketManager(legs.place, legs.cancel, legs.replace)
+	id, _ := brackets.Submit(BracketRequest{Entry: OrderRequest{Quantity: "1"}, TakeProfitPrice: "1", StopPrice: "1", StopLimitPrice: "1"})
+
+	brackets.OnExecutionReport(ExecutionReport{ClOrdID: id, ExecType: "8", OrdStatus: "8", CumQty: "0"})
+
+	if bracket, _ := brackets.Get(id); bracket.State != BracketClosed || len(legs.placed) != 1 {
+		t.Fatalf("bracket %+v, placed %d orders", bracket, len(legs.placed))
+	}
+}
+
+func TestBracketSubmitValidates(t *testing.T) {
+	legs := &legRecorder{}
+	brackets := NewBracketManager(legs.place, legs.cancel, legs.replace)
+	if _, err := brackets.Submit(BracketRequest{TakeProfitPrice: "1", StopPrice: "1"}); err == nil {
+		t.Fatal("Submit accepted a bracket without a stop-limit price")
+	}
+
+	legs.failAt = 1
+	if _, err := brackets.Submit(BracketRequest{TakeProfitPrice: "1", StopPrice: "1", StopLimitPrice: "1"}); err == nil {
+		t.Fatal("Submit hid the entry's placement error")
+	}
+	if len(legs.placed) != 0 {
+		t.Fatalf("placed %+v", legs.placed)
+	}
+}
+
+func TestBracketExitsCarryEntryConfirmation(t *testing.T) {
+	legs := &legRecorder{}
+	brackets := NewBracketManager(legs.place, legs.cancel, legs.replace)
+	id, _ := brackets.Submit(BracketRequest{
+		Entry:           OrderRequest{Symbol: "BTC-USD", Side: "BUY", Quantity: "500", Force: true, CorrelationId: "c-1"},
+		TakeProfitPrice: "110",
+		StopPrice:       "95",
+		StopLimitPrice:  "94",
+	})
+	brackets.OnExecutionReport(ExecutionReport{ClOrdID: id, ExecType: "2", OrdStatus: "2", CumQty: "500"})
+
+	for _, exit := range legs.placed[1:] {
+		if !exit.Force || exit.CorrelationId != "c-1" {
+			t.Fatalf("exit %+v does not carry the entry's Force and CorrelationId", exit)
+		}
+	}
+}
+
+func TestBracketUnprotectedWhenExitFails(t *testing.T) {
+	legs := &legRecorder{failAt: 3} // the stop
+	brackets := NewBracketManager(legs.place, legs.cancel, legs.replace)
+	var unprotected []Bracket
+	brackets.OnUnprotected = func(bracket Bracket, err error) { unprotected = append(unprotected, bracket) }
+	id, _ := brackets.Submit(BracketRequest{Entry: OrderRequest{Side: "BUY", Quantity: "1"}, TakeProfitPrice: "110", StopPrice: "95", StopLimitPrice: "94"})
+
+	brackets.OnExecutionReport(ExecutionReport{ClOrdID: id, ExecType: "2", OrdStatus: "2", CumQty: "1"})
+
+	bracket, _ := brackets.Get(id)
+	if bracket.State != BracketUnprotected || bracket.TakeProfitClOrdID != "2" || bracket.StopClOrdID != "" {
+		t.Fatalf("bracket %+v, want %s with only the take-profit", bracket, BracketUnprotected)
+	}
+	if len(unprotected) != 1 || unprotected[0].EntryClOrdID != id {
+		t.Fatalf("unprotected %+v", unprotected)
+	}
+
+	// The take-profit still works, with no stop left to cancel
+	brackets.OnExecutionReport(ExecutionReport{ClOrdID: "2", ExecType: "2", OrdStatus: "2"})
+	if bracket, _ := brackets.Get(id); bracket.State != BracketClosed || len(legs.canceled) != 0 {
+		t.Fatalf("bracket %+v, canceled %v", bracket, legs.canceled)
+	}
+}
+
+func TestBracketSendsOutsideItsLock(t *testing.T) {
+	legs := &legRecorder{}
+	var brackets *BracketManager
+	place := func(req OrderRequest) (string, error) {
+		clOrdId, err := legs.place(req)
+		// The venue fills the entry before the send returns, on the session
+		// goroutine, which must not wait for the manager's lock
+		if clOrdId == "1" {
+			brackets.OnExecutionReport(ExecutionReport{ClOrdID: clOrdId, ExecType: "2", OrdStatus: "2", CumQty: "1"})
+			brackets.Get(clOrdId)
+		}
+		return clOrdId, err
+	}
+	brackets = NewBracketManager(place, legs.cancel, legs.replace)
+
+	done := make(chan string)
+	go func() {
+		id, _ := brackets.Submit(BracketRequest{Entry: OrderRequest{Side: "BUY", Quantity: "1"}, TakeProfitPrice: "110", StopPrice: "95", StopLimitPrice: "94"})
+		done <- id
+	}()
+	var id string
+	select {
+	case id = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Submit deadlocked on a report arriving during the send")
+	}
+
+	// The early fill was applied once the entry was registered
+	if bracket, _ := brackets.Get(id); bracket.State != BracketWorkingExits || len(legs.placed) != 3 {
+		t.Fatalf("bracket %+v, placed %d orders", bracket, len(legs.placed))
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X main.version=v1.2.3 -X main.commit=$(git rev-parse HEAD) -X main.buildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Without them the module version and VCS stamp of the Go toolchain are used.
+var (
+	version   string
+	commit    string
+	buildTime string
+)
+
+// Build identifies the running binary
+type Build struct {
+	Version   string
+	Commit    string    `json:",omitempty"`
+	BuiltAt   time.Time `json:",omitempty"`
+	Modified  bool      `json:",omitempty"` // built from a tree with uncommitted changes
+	GoVersion string
+	OS        string
+	Arch      string
+}
+
+// BuildInfo returns the version, commit and build time of the binary
+func BuildInfo() Build {
+	build := Build{
+		Version:   version,
+		Commit:    commit,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	build.BuiltAt, _ = time.Parse(time.RFC3339, buildTime)
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if build.Version == "" {
+			build.Version = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if build.Commit == "" {
+					build.Commit = setting.Value
+				}
+			case "vcs.time":
+				if build.BuiltAt.IsZero() {
+					build.BuiltAt, _ = time.Parse(time.RFC3339, setting.Value)
+				}
+			case "vcs.modified":
+				build.Modified = setting.Value == "true"
+			}
+		}
+	}
+	if build.Version == "" {
+		build.Version = "(devel)"
+	}
+	return build
+}
+
+// String returns a one line banner, e.g.
+// "prime-fix-go v1.2.3 (commit 1a2b3c4d5e6f, built 2025-01-01T00:00:00Z, go1.23.2 linux/amd64)"
+func (b Build) String() string {
+	details := ""
+	if b.Commit != "" {
+		commit := b.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		if b.Modified {
+			commit += "-dirty"
+		}
+		details += "commit " + commit + ", "
+	}
+	if !b.BuiltAt.IsZero() {
+		details += "built " + b.BuiltAt.UTC().Format(time.RFC3339) + ", "
+	}
+	return fmt.Sprintf("prime-fix-go %s (%s%s %s/%s)", b.Version, details, b.GoVersion, b.OS, b.Arch)
+}
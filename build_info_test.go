@@ -0,0 +1,64 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildString(t *testing.T) {
+	built := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		build Build
+		want  string
+	}{
+		{"release", Build{Version: "v1.2.3", Commit: "1a2b3c4d5e6f7a8b", BuiltAt: built, GoVersion: "go1.23.2", OS: "linux", Arch: "amd64"},
+			"prime-fix-go v1.2.3 (commit 1a2b3c4d5e6f, built 2025-01-01T00:00:00Z, go1.23.2 linux/amd64)"},
+		{"dirty tree", Build{Version: "(devel)", Commit: "1a2b3c", Modified: true, GoVersion: "go1.23.2", OS: "darwin", Arch: "arm64"},
+			"prime-fix-go (devel) (commit 1a2b3c-dirty, go1.23.2 darwin/arm64)"},
+		{"no VCS stamp", Build{Version: "(devel)", GoVersion: "go1.23.2", OS: "linux", Arch: "amd64"},
+			"prime-fix-go (devel) (go1.23.2 linux/amd64)"},
+	}
+	for _, tt := range tests {
+		if got := tt.build.String(); got != tt.want {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildInfoLinkerFlags(t *testing.T) {
+	defer func(v, c, b string) { version, commit, buildTime = v, c, b }(version, commit, buildTime)
+
+	tests := []struct {
+		name      string
+		buildTime string
+		wantBuilt time.Time
+	}{
+		{"stamped", "2025-01-01T00:00:00Z", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"malformed build time", "yesterday", time.Time{}},
+	}
+	for _, tt := range tests {
+		version, commit, buildTime = "v1.2.3", "1a2b3c4d", tt.buildTime
+		build := BuildInfo()
+		if build.Version != "v1.2.3" || build.Commit != "1a2b3c4d" {
+			t.Errorf("%s: version %q commit %q, want the linker values", tt.name, build.Version, build.Commit)
+		}
+		if !build.BuiltAt.Equal(tt.wantBuilt) {
+			t.Errorf("%s: BuiltAt = %s, want %s", tt.name, build.BuiltAt, tt.wantBuilt)
+		}
+	}
+}
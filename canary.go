@@ -0,0 +1,256 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// CanaryMetadataKey marks canary orders in their metadata, so consumers of
+// ExecutionReports can tell them from real orders
+const CanaryMetadataKey = "canary"
+
+// Canary validates the full order path in sandbox: on every Interval
+// boundary it places Request, a minimal order priced not to fill, cancels it
+// once acknowledged and alerts unless the cancel is confirmed within Timeout
+type Canary struct {
+	Request  OrderRequest
+	Interval time.Duration
+	Timeout  time.Duration
+	Clock    Clock
+
+	// Ready reports whether orders can be sent; ticks while it is false are skipped
+	Ready  func() bool
+	Place  func(req OrderRequest) (string, error)
+	Cancel func(clOrdID string) error
+	Alert  func(key, severity, summary string)
+
+	mu      sync.Mutex
+	run     *canaryRun // round trip in flight
+	timer   Timer
+	stopped bool
+	last    CanaryResult
+}
+
+// CanaryResult is the outcome of one canary round trip
+type CanaryResult struct {
+	ClOrdID   string
+	Started   time.Time
+	RoundTrip time.Duration // from placing to the cancel confirmation
+	Failure   string        // empty when the round trip completed
+}
+
+type canaryRun struct {
+	clOrdID string
+	started time.Time
+	acked   bool
+	timeout Timer
+}
+
+// Start schedules the first canary at the next Interval boundary
+func (c *Canary) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Clock = clockOrSystem(c.Clock)
+	c.stopped = false
+	c.schedule()
+}
+
+// Stop cancels the schedule; a round trip in flight still completes
+func (c *Canary) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopped = true
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+}
+
+// Last returns the outcome of the latest completed round trip
+func (c *Canary) Last() CanaryResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// schedule arms the next tick on the Interval boundary after now, so canaries
+// land at the same wall clock times whenever the client started; callers
+// must hold mu
+func (c *Canary) schedule() {
+	now := c.Clock.Now()
+	next := now.Truncate(c.Interval).Add(c.Interval)
+	c.timer = c.Clock.AfterFunc(next.Sub(now), c.tick)
+}
+
+func (c *Canary) tick() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped {
+		return
+	}
+	c.schedule()
+	if c.run != nil {
+		return // the previous round trip is still out; its timeout reports it
+	}
+	if c.Ready != nil && !c.Ready() {
+		log.Println("Skipping canary order: session is not logged on")
+		return
+	}
+
+	// mu is held across Place so the acknowledgement cannot be missed
+	started := c.Clock.Now()
+	clOrdID, err := c.Place(c.Request)
+	if err != nil {
+		c.record(CanaryResult{ClOrdID: clOrdID, Started: started, Failure: "order not sent: " + err.Error()})
+		return
+	}
+	run := &canaryRun{clOrdID: clOrdID, started: started}
+	run.timeout = c.Clock.AfterFunc(c.Timeout, func() { c.expire(run) })
+	c.run = run
+}
+
+// OnExecutionReport advances the round trip in flight
+func (c *Canary) OnExecutionReport(report ExecutionReport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	run := c.run
+	if run == nil || report.ClOrdID != run.clOrdID && report.OrigClOrdID != run.clOrdID {
+		return
+	}
+	switch report.ExecType {
+	case "0": // New
+		if run.acked {
+			return
+		}
+		run.acked = true
+		if err := c.Cancel(run.clOrdID); err != nil {
+			c.finish(run, "cancel not sent: "+err.Error())
+		}
+	case "4": // Canceled
+		c.finish(run, "")
+	case "8": // Rejected
+		c.finish(run, "order rejected: "+report.Text)
+	case "1", "2", "F": // Partial fill, Fill, Trade
+		c.finish(run, "order filled at "+report.LastPx)
+	}
+}
+
+// expire fails run when it has not completed within Timeout
+func (c *Canary) expire(run *canaryRun) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.run != run {
+		return
+	}
+	state := "acknowledgement"
+	if run.acked {
+		state = "cancel confirmation"
+		// Best effort, so a slow venue does not leave the order resting
+		if err := c.Cancel(run.clOrdID); err != nil && !errors.Is(err, ErrNotLoggedOn) {
+			log.Printf("Failed to cancel canary order %s: %v", run.clOrdID, err)
+		}
+	}
+	c.finish(run, fmt.Sprintf("no %s within %s", state, c.Timeout))
+}
+
+// finish completes run; callers must hold mu
+func (c *Canary) finish(run *canaryRun, failure string) {
+	run.timeout.Stop()
+	c.run = nil
+	c.record(CanaryResult{
+		ClOrdID:   run.clOrdID,
+		Started:   run.started,
+		RoundTrip: c.Clock.Now().Sub(run.started),
+		Failure:   failure,
+	})
+}
+
+// record logs result and alerts on failure; callers must hold mu
+func (c *Canary) record(result CanaryResult) {
+	recovered := c.last.Failure != "" && result.Failure == ""
+	c.last = result
+
+	switch {
+	case result.Failure != "":
+		log.Printf("Canary order %s failed: %s", result.ClOrdID, result.Failure)
+		if c.Alert != nil {
+			c.Alert("canary", "error", "Canary order round trip failed: "+result.Failure)
+		}
+	case recovered:
+		log.Printf("Canary order %s round trip completed in %s, recovered", result.ClOrdID, result.RoundTrip)
+	default:
+		log.Printf("Canary order %s round trip completed in %s", result.ClOrdID, result.RoundTrip)
+	}
+}
+
+// canarySetting builds the canary for app from the Canary* settings, which
+// are only allowed with Environment=sandbox
+func canarySetting(settings *quickfix.SessionSettings, app *FixApplication) (*Canary, error) {
+	if environment, _ := settings.Setting("Environment"); environment != "sandbox" {
+		return nil, errors.New("canary orders require Environment=sandbox")
+	}
+	interval, err := settings.DurationSetting("CanaryInterval")
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid CanaryInterval %s", interval)
+	}
+	timeout := 30 * time.Second
+	if settings.HasSetting("CanaryTimeout") {
+		if timeout, err = settings.DurationSetting("CanaryTimeout"); err != nil {
+			return nil, err
+		}
+	}
+
+	req := OrderRequest{OrdType: "LIMIT", Side: "BUY", Metadata: map[string]string{CanaryMetadataKey: "true"}}
+	for setting, value := range map[string]*string{
+		"CanarySymbol":   &req.Symbol,
+		"CanaryQuantity": &req.Quantity,
+		"CanaryPrice":    &req.LimitPrice,
+	} {
+		if *value, err = settings.Setting(setting); err != nil {
+			return nil, err
+		}
+	}
+	if side, err := settings.Setting("CanarySide"); err == nil {
+		req.Side = side
+	}
+	if _, ok := SideCode(req.Side); !ok {
+		return nil, fmt.Errorf("invalid CanarySide %q", req.Side)
+	}
+
+	return &Canary{
+		Request:  req,
+		Interval: interval,
+		Timeout:  timeout,
+		Clock:    app.Clock,
+		Ready:    func() bool { return app.Paper != nil || app.IsLoggedOn() },
+		Place:    app.PlaceOrder,
+		Cancel:   app.CancelOrder,
+		Alert:    app.alert,
+	}, nil
+}
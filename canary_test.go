@@ -0,0 +1,80 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCanaryRoundTrip(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 2, 15, 0, 10, 0, time.UTC))
+	var placed, cancelled []string
+	var alerts []string
+	canary := &Canary{
+		Request:  OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "0.0001", LimitPrice: "1"},
+		Interval: time.Minute,
+		Timeout:  10 * time.Second,
+		Clock:    clock,
+		Place: func(req OrderRequest) (string, error) {
+			placed = append(placed, "c"+strconv.Itoa(len(placed)+1))
+			return placed[len(placed)-1], nil
+		},
+		Cancel: func(clOrdID string) error {
+			cancelled = append(cancelled, clOrdID)
+			return nil
+		},
+		Alert: func(key, severity, summary string) { alerts = append(alerts, summary) },
+	}
+	canary.Start()
+
+	// The first canary goes out on the next minute boundary
+	clock.Advance(49 * time.Second)
+	if len(placed) != 0 {
+		t.Fatal("canary placed before the interval boundary")
+	}
+	clock.Advance(time.Second)
+	if len(placed) != 1 {
+		t.Fatalf("expected a canary at 15:01:00, got %d", len(placed))
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	canary.OnExecutionReport(ExecutionReport{ClOrdID: "c1", ExecType: "0"})
+	if len(cancelled) != 1 || cancelled[0] != "c1" {
+		t.Fatalf("expected c1 to be cancelled on ack, got %v", cancelled)
+	}
+	clock.Advance(100 * time.Millisecond)
+	canary.OnExecutionReport(ExecutionReport{ClOrdID: "x1", OrigClOrdID: "c1", ExecType: "4"})
+	if last := canary.Last(); last.Failure != "" || last.RoundTrip != 200*time.Millisecond {
+		t.Fatalf("unexpected result %+v", last)
+	}
+
+	// The next canary is never acknowledged
+	clock.Advance(time.Minute)
+	if len(placed) != 2 {
+		t.Fatalf("expected a second canary, got %d", len(placed))
+	}
+	clock.Advance(10 * time.Second)
+	if len(alerts) != 1 || canary.Last().Failure == "" {
+		t.Fatalf("expected a timeout alert, got %v", alerts)
+	}
+
+	canary.Stop()
+	clock.Advance(time.Hour)
+	if len(placed) != 2 {
+		t.Fatalf("canary placed after Stop: %d", len(placed))
+	}
+}
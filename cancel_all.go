@@ -0,0 +1,76 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// CancelFilter selects the open orders cancelled by CancelAll. Empty fields
+// match every order.
+type CancelFilter struct {
+	Symbol      string
+	PortfolioId string
+	// OlderThan only matches orders placed at least this long ago. Orders
+	// with no placement time, such as those restored from an older snapshot,
+	// are left alone.
+	OlderThan time.Duration
+}
+
+// Matches reports whether order is selected by the filter at now
+func (f CancelFilter) Matches(order Order, now time.Time) bool {
+	if f.Symbol != "" && !strings.EqualFold(order.Symbol, f.Symbol) {
+		return false
+	}
+	if f.PortfolioId != "" && order.PortfolioId != f.PortfolioId {
+		return false
+	}
+	if f.OlderThan > 0 && (order.PlacedAt.IsZero() || now.Sub(order.PlacedAt) < f.OlderThan) {
+		return false
+	}
+	return true
+}
+
+// CancelResult is the outcome of one cancel sent by CancelAll. Error is
+// empty when the cancel was sent, which does not mean Prime accepted it.
+type CancelResult struct {
+	Tenant  string `json:",omitempty"`
+	ClOrdID string
+	Symbol  string
+	Error   string `json:",omitempty"`
+}
+
+// CancelAll cancels the open orders matching filter, waiting pace between
+// cancels so a large book does not burst the session. Orders with a cancel
+// already pending are skipped.
+func (a *FixApplication) CancelAll(filter CancelFilter, pace time.Duration) []CancelResult {
+	var results []CancelResult
+	now := a.now()
+	for _, order := range openOrders(a) {
+		if order.Pending == OrderPendingCancel || !filter.Matches(order, now) {
+			continue
+		}
+		if len(results) > 0 && pace > 0 {
+			time.Sleep(pace)
+		}
+		result := CancelResult{ClOrdID: order.ClOrdID, Symbol: order.Symbol}
+		if err := a.CancelOrder(order.ClOrdID); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
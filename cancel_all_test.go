@@ -0,0 +1,79 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCancelAllFiltersOpenOrders(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	app := &FixApplication{Clock: NewFakeClock(now), Tracker: NewOrderTracker(time.Minute)}
+	app.Tracker.Add(Order{ClOrdID: "old-eth", Symbol: "ETH-USD", PortfolioId: "p1", PlacedAt: now.Add(-time.Hour)})
+	app.Tracker.Add(Order{ClOrdID: "new-eth", Symbol: "ETH-USD", PortfolioId: "p1", PlacedAt: now.Add(-time.Minute)})
+	app.Tracker.Add(Order{ClOrdID: "old-btc", Symbol: "BTC-USD", PortfolioId: "p1", PlacedAt: now.Add(-time.Hour)})
+	app.Tracker.Add(Order{ClOrdID: "other-portfolio", Symbol: "ETH-USD", PortfolioId: "p2", PlacedAt: now.Add(-time.Hour)})
+	app.Tracker.Add(Order{ClOrdID: "restored", Symbol: "ETH-USD", PortfolioId: "p1"})
+	app.Tracker.Add(Order{ClOrdID: "filled", Symbol: "ETH-USD", PortfolioId: "p1", PlacedAt: now.Add(-time.Hour), State: OrderFilled})
+
+	results := app.CancelAll(CancelFilter{Symbol: "eth-usd", PortfolioId: "p1", OlderThan: 10 * time.Minute}, 0)
+	if len(results) != 1 || results[0].ClOrdID != "old-eth" {
+		t.Fatalf("results = %+v, want only old-eth", results)
+	}
+	// Not logged on, so the cancel fails and is reported rather than dropped
+	if results[0].Error == "" {
+		t.Errorf("cancel of old-eth reported sent while logged out")
+	}
+
+	if results := app.CancelAll(CancelFilter{}, 0); len(results) != 5 {
+		t.Errorf("unfiltered cancel all = %+v, want the 5 open orders", results)
+	}
+}
+
+func TestAdminCancelAll(t *testing.T) {
+	app := &FixApplication{Tracker: NewOrderTracker(time.Minute)}
+	app.Tracker.Add(Order{ClOrdID: "ord-1", Symbol: "ETH-USD"})
+	app.Tracker.Add(Order{ClOrdID: "ord-2", Symbol: "BTC-USD"})
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app}); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewAdminHandler(manager)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/orders/cancel-all?older-than=soon", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid older-than answered %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/orders/cancel-all", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET answered %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/orders/cancel-all?symbol=ETH-USD&pace=1ms", nil))
+	var results []CancelResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatal(err, rec.Body.String())
+	}
+	if len(results) != 1 || results[0].Tenant != "desk" || results[0].ClOrdID != "ord-1" {
+		t.Errorf("results = %+v", results)
+	}
+}
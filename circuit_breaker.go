@@ -0,0 +1,109 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for submissions while the circuit breaker is tripped
+var ErrCircuitOpen = errors.New("order circuit breaker is open, reset it before submitting")
+
+// CircuitBreaker blocks order submission after a burst of rejects: once
+// threshold rejects arrive within the window it trips and stays open until
+// Reset is called, so a misconfiguration cannot keep hammering the venue
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	rejects   *eventWindow
+	threshold int
+	window    time.Duration
+	open      bool
+	openedAt  time.Time
+
+	// OnTrip is called when the breaker opens
+	OnTrip func(rejects int)
+}
+
+// NewCircuitBreaker creates a breaker tripping on threshold rejects within window
+func NewCircuitBreaker(threshold int, window time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		rejects:   newEventWindow(window),
+		threshold: threshold,
+		window:    window,
+	}
+}
+
+// SetThreshold changes the number of rejects within window that trips the breaker
+func (b *CircuitBreaker) SetThreshold(threshold int, window time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.threshold = threshold
+	b.window = window
+	b.rejects.setWindow(window)
+}
+
+// Allow returns ErrCircuitOpen while the breaker is tripped
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// IsOpen reports whether the breaker is tripped, and since when
+func (b *CircuitBreaker) IsOpen() (bool, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open, b.openedAt
+}
+
+// RecordReject counts an order reject at now, tripping the breaker when the
+// threshold is reached
+func (b *CircuitBreaker) RecordReject(now time.Time) {
+	count := b.rejects.add(now)
+
+	b.mu.Lock()
+	if b.open || count < b.threshold {
+		b.mu.Unlock()
+		return
+	}
+	b.open = true
+	b.openedAt = now
+	window := b.window
+	b.mu.Unlock()
+
+	log.Printf("Circuit breaker tripped after %d rejects within %s", count, window)
+	if b.OnTrip != nil {
+		b.OnTrip(count)
+	}
+}
+
+// Reset closes the breaker and forgets past rejects, reporting whether it was open
+func (b *CircuitBreaker) Reset() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.open
+	b.open = false
+	b.openedAt = time.Time{}
+	b.rejects.reset()
+	log.Println("Circuit breaker reset")
+	return wasOpen
+}
@@ -0,0 +1,134 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		rejects []time.Duration // offsets from start
+		open    bool
+	}{
+		{"below threshold", []time.Duration{0, time.Second}, false},
+		{"threshold within window", []time.Duration{0, time.Second, 2 * time.Second}, true},
+		{"threshold spread past window", []time.Duration{0, 6 * time.Second, 12 * time.Second}, false},
+		{"old rejects slide out", []time.Duration{0, 9 * time.Second, 11 * time.Second, 12 * time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaker := NewCircuitBreaker(3, 10*time.Second)
+			trips := 0
+			breaker.OnTrip = func(rejects int) { trips++ }
+			for _, offset := range tt.rejects {
+				breaker.RecordReject(start.Add(offset))
+			}
+
+			open, openedAt := breaker.IsOpen()
+			if open != tt.open {
+				t.Fatalf("open = %t, want %t", open, tt.open)
+			}
+			if err := breaker.Allow(); (err != nil) != tt.open || (err != nil && !errors.Is(err, ErrCircuitOpen)) {
+				t.Errorf("Allow = %v", err)
+			}
+			if tt.open && (trips != 1 || openedAt != start.Add(tt.rejects[len(tt.rejects)-1])) {
+				t.Errorf("trips = %d, openedAt = %s", trips, openedAt)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerStaysOpenUntilReset(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	trips := 0
+	breaker.OnTrip = func(rejects int) { trips++ }
+	breaker.RecordReject(now)
+	breaker.RecordReject(now)
+	breaker.RecordReject(now.Add(time.Hour))
+	if open, _ := breaker.IsOpen(); !open || trips != 1 {
+		t.Fatalf("open = %t after %d trips", open, trips)
+	}
+
+	if !breaker.Reset() {
+		t.Error("Reset of an open breaker reported it closed")
+	}
+	if err := breaker.Allow(); err != nil {
+		t.Errorf("Allow after reset = %v", err)
+	}
+	// Rejects from before the reset no longer count
+	breaker.RecordReject(now.Add(time.Hour))
+	if open, _ := breaker.IsOpen(); open {
+		t.Error("tripped on rejects from before the reset")
+	}
+	if breaker.Reset() {
+		t.Error("Reset of a closed breaker reported it open")
+	}
+}
+
+func TestCircuitBreakerSetThreshold(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(5, time.Minute)
+	breaker.RecordReject(now)
+	breaker.SetThreshold(2, time.Minute)
+	breaker.RecordReject(now.Add(time.Second))
+	if open, _ := breaker.IsOpen(); !open {
+		t.Error("did not trip at the lowered threshold")
+	}
+}
+
+func TestAdminBreakerReset(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	tripped := &FixApplication{Breaker: NewCircuitBreaker(1, time.Minute)}
+	tripped.Breaker.RecordReject(now)
+	manager := NewManager()
+	for _, tenant := range []*Tenant{
+		{Name: "desk", App: tripped},
+		{Name: "other", App: &FixApplication{Breaker: NewCircuitBreaker(1, time.Minute)}},
+		{Name: "unguarded", App: &FixApplication{}},
+	} {
+		if err := manager.Add(tenant); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := NewAdminHandler(manager)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/breaker/reset?tenant=nope", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown tenant answered %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/breaker/reset", nil))
+	var wasOpen map[string]bool
+	if err := json.Unmarshal(rec.Body.Bytes(), &wasOpen); err != nil {
+		t.Fatal(err, rec.Body.String())
+	}
+	if len(wasOpen) != 2 || !wasOpen["desk"] || wasOpen["other"] {
+		t.Errorf("wasOpen = %v", wasOpen)
+	}
+	if err := tripped.Breaker.Allow(); err != nil {
+		t.Errorf("Allow after reset = %v", err)
+	}
+}
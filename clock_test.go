@@ -0,0 +1,44 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedulerFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	var submitted []string
+	scheduler, err := NewOrderScheduler("", time.Minute, clock, func(req OrderRequest) (string, error) {
+		submitted = append(submitted, req.Symbol)
+		return "1", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scheduler.Schedule(OrderRequest{Symbol: "BTC-USD"}, clock.Now().Add(2*time.Hour))
+	scheduler.Schedule(OrderRequest{Symbol: "ETH-USD"}, clock.Now().Add(time.Hour))
+
+	clock.Advance(59 * time.Minute)
+	if len(submitted) != 0 {
+		t.Fatalf("submitted %v before due", submitted)
+	}
+	clock.Advance(2 * time.Hour)
+	if len(submitted) != 2 || submitted[0] != "ETH-USD" || submitted[1] != "BTC-USD" {
+		t.Fatalf("submitted %v, want ETH-USD then BTC-USD", submitted)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Codec encodes ExecutionReports for event sinks. The protobuf and Avro
+// codecs follow the schemas in schemas/, from which downstream consumers can
+// generate their bindings.
+type Codec interface {
+	Name() string
+	ContentType() string
+	Encode(report ExecutionReport) ([]byte, error)
+}
+
+// CodecByName returns the codec named "json", "protobuf" or "avro"
+func CodecByName(name string) (Codec, error) {
+	switch name {
+	case "json":
+		return JSONCodec{}, nil
+	case "protobuf":
+		return ProtobufCodec{}, nil
+	case "avro":
+		return AvroCodec{}, nil
+	}
+	return nil, fmt.Errorf("unknown codec %q", name)
+}
+
+// JSONCodec encodes reports as JSON objects with the ExecutionReport field names
+type JSONCodec struct{}
+
+func (JSONCodec) Name() string        { return "json" }
+func (JSONCodec) ContentType() string { return "application/json" }
+
+func (JSONCodec) Encode(report ExecutionReport) ([]byte, error) {
+	return json.Marshal(report)
+}
+
+// reportStrings returns the string fields of report in schema order, the
+// first 20 fields of both schemas
+func reportStrings(report ExecutionReport) []string {
+	return []string{
+		report.ExecID, report.ExecType, report.OrdStatus, report.OrderID,
+		report.ClOrdID, report.OrigClOrdID, report.Symbol, report.Side,
+		report.Quantity, report.Price, report.LastShares, report.LastPx,
+		report.CumQty, report.AvgPx, report.OrdRejReason, report.Text,
+		report.TransactTime, report.Commission, report.CommType, report.CommCurrency,
+	}
+}
+
+// unixNanos returns t as Unix nanoseconds, 0 for the zero time
+func unixNanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// ProtobufCodec encodes reports as primefix.v1.ExecutionReport messages,
+// see schemas/execution_report.proto
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) Name() string        { return "protobuf" }
+func (ProtobufCodec) ContentType() string { return "application/x-protobuf" }
+
+func (ProtobufCodec) Encode(report ExecutionReport) ([]byte, error) {
+	var b []byte
+	for i, value := range reportStrings(report) {
+		b = protoString(b, i+1, value)
+	}
+	for _, fee := range report.MiscFees {
+		var m []byte
+		m = protoString(m, 1, fee.Amount)
+		m = protoString(m, 2, fee.Currency)
+		m = protoString(m, 3, fee.Type)
+		b = protoBytes(b, 21, m)
+	}
+	for i, t := range []time.Time{report.SentAt, report.TransactedAt, report.ReceivedAt} {
+		if nanos := unixNanos(t); nanos != 0 {
+			b = protoVarint(b, 22+i, uint64(nanos))
+		}
+	}
+	for i, flag := range []bool{report.PossDup, report.PossResend} {
+		if flag {
+			b = protoVarint(b, 25+i, 1)
+		}
+	}
+	for _, key := range sortedKeys(report.Metadata) { // deterministic encoding
+		var entry []byte
+		entry = protoString(entry, 1, key)
+		entry = protoString(entry, 2, report.Metadata[key])
+		b = protoBytes(b, 27, entry)
+	}
+	b = protoString(b, 28, report.ArrivalMid)
+	b = protoString(b, 29, report.CorrelationId)
+	b = protoString(b, 30, report.Tenant)
+	return b, nil
+}
+
+// protoVarint appends field as a varint (wire type 0)
+func protoVarint(b []byte, field int, value uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3)
+	return binary.AppendUvarint(b, value)
+}
+
+// protoBytes appends field as length delimited (wire type 2)
+func protoBytes(b []byte, field int, value []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+// protoString appends a string field, omitting it when empty as proto3 does
+func protoString(b []byte, field int, value string) []byte {
+	if value == "" {
+		return b
+	}
+	return protoBytes(b, field, []byte(value))
+}
+
+// AvroCodec encodes reports in Avro binary encoding against the
+// primefix.v1.ExecutionReport schema, see schemas/execution_report.avsc. The
+// schema itself is not included, so readers need it to decode.
+type AvroCodec struct{}
+
+func (AvroCodec) Name() string        { return "avro" }
+func (AvroCodec) ContentType() string { return "application/avro" }
+
+func (AvroCodec) Encode(report ExecutionReport) ([]byte, error) {
+	var b []byte
+	for _, value := range reportStrings(report) {
+		b = avroString(b, value)
+	}
+	if len(report.MiscFees) > 0 {
+		b = binary.AppendVarint(b, int64(len(report.MiscFees)))
+		for _, fee := range report.MiscFees {
+			b = avroString(b, fee.Amount)
+			b = avroString(b, fee.Currency)
+			b = avroString(b, fee.Type)
+		}
+	}
+	b = binary.AppendVarint(b, 0) // end of array
+	for _, t := range []time.Time{report.SentAt, report.TransactedAt, report.ReceivedAt} {
+		if nanos := unixNanos(t); nanos == 0 {
+			b = binary.AppendVarint(b, 0) // null branch
+		} else {
+			b = binary.AppendVarint(b, 1) // long branch
+			b = binary.AppendVarint(b, nanos)
+		}
+	}
+	for _, flag := range []bool{report.PossDup, report.PossResend} {
+		if flag {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+	}
+	if len(report.Metadata) > 0 {
+		b = binary.AppendVarint(b, int64(len(report.Metadata)))
+		for _, key := range sortedKeys(report.Metadata) {
+			b = avroString(b, key)
+			b = avroString(b, report.Metadata[key])
+		}
+	}
+	b = binary.AppendVarint(b, 0) // end of map
+	b = avroString(b, report.ArrivalMid)
+	b = avroString(b, report.CorrelationId)
+	b = avroString(b, report.Tenant)
+	return b, nil
+}
+
+// avroString appends value as a zig-zag length followed by its bytes
+func avroString(b []byte, value string) []byte {
+	b = binary.AppendVarint(b, int64(len(value)))
+	return append(b, value...)
+}
@@ -0,0 +1,45 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCodecEncodings(t *testing.T) {
+	report := ExecutionReport{ExecID: "e", PossDup: true, Metadata: map[string]string{"k": "v"}}
+
+	proto := []byte{
+		0x0a, 0x01, 'e', // exec_id = 1
+		0xc8, 0x01, 0x01, // poss_dup = 25
+		0xda, 0x01, 0x06, 0x0a, 0x01, 'k', 0x12, 0x01, 'v', // metadata = 27
+	}
+	if got, _ := (ProtobufCodec{}).Encode(report); !bytes.Equal(got, proto) {
+		t.Errorf("protobuf encoding\n got % x\nwant % x", got, proto)
+	}
+
+	avro := append([]byte{0x02, 'e'}, make([]byte, 19)...) // exec_id and 19 empty strings
+	avro = append(avro,
+		0x00,             // no misc fees
+		0x00, 0x00, 0x00, // null times
+		0x01, 0x00, // poss_dup, poss_resend
+		0x02, 0x02, 'k', 0x02, 'v', 0x00, // metadata
+		0x00, 0x00, 0x00, // arrival_mid, correlation_id, tenant
+	)
+	if got, _ := (AvroCodec{}).Encode(report); !bytes.Equal(got, avro) {
+		t.Errorf("avro encoding\n got % x\nwant % x", got, avro)
+	}
+}
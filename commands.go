@@ -0,0 +1,777 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// runCommand runs an offline subcommand instead of starting the session,
+// returning the process exit code
+func runCommand(args []string) int {
+	switch {
+	case len(args) >= 2 && args[0] == "report" && args[1] == "eod":
+		return runEODReport(args[2:])
+	case len(args) >= 1 && args[0] == "version":
+		return runVersion(args[1:])
+	case len(args) >= 2 && args[0] == "secret" && args[1] == "keygen":
+		return runSecretKeygen()
+	case len(args) >= 2 && args[0] == "secret" && args[1] == "encrypt":
+		return runSecretEncrypt()
+	case len(args) >= 1 && args[0] == "diff":
+		return runTagDiff(args[1:])
+	case len(args) >= 1 && args[0] == "support-bundle":
+		return runSupportBundle(args[1:])
+	case len(args) >= 1 && args[0] == "convert":
+		return runConvert(args[1:])
+	case len(args) >= 2 && args[0] == "session" && args[1] == "stats":
+		return runSessionStats(args[2:])
+	case len(args) >= 2 && args[0] == "session" && args[1] == "reset-seq":
+		return runResetSeq(args[2:])
+	case len(args) >= 2 && args[0] == "session" && args[1] == "timeline":
+		return runSessionTimeline(args[2:])
+	case len(args) >= 2 && args[0] == "messages" && args[1] == "search":
+		return runMessagesSearch(args[2:])
+	case len(args) >= 2 && args[0] == "gateway" && args[1] == "token":
+		return runGatewayToken(args[2:])
+	case len(args) >= 1 && args[0] == "purge":
+		return runPurge(args[1:])
+	case len(args) >= 1 && args[0] == "init":
+		return runInit(args[1:])
+	case len(args) >= 1 && args[0] == "doctor":
+		return runDoctor(args[1:])
+	case len(args) >= 2 && args[0] == "dashboards" && args[1] == "export":
+		return runDashboardsExport(args[2:])
+	case len(args) >= 2 && args[0] == "order" && args[1] == "cancel-all":
+		return runOrderCancelAll(args[2:])
+	case len(args) >= 2 && args[0] == "order" && args[1] == "list":
+		return runOrderList(args[2:])
+	case len(args) >= 2 && args[0] == "shadow" && args[1] == "report":
+		return runShadowReport(args[2:])
+	case len(args) >= 2 && args[0] == "message" && args[1] == "send":
+		return runMessageSend(args[2:])
+	case len(args) >= 2 && args[0] == "schedule" && args[1] == "list":
+		return runScheduleList(args[2:])
+	case len(args) >= 2 && args[0] == "schedule" && args[1] == "cancel":
+		return runScheduleCancel(args[2:])
+	case len(args) >= 2 && args[0] == "venue" && args[1] == "resume":
+		return runVenueResume(args[2:])
+	case len(args) >= 2 && args[0] == "breaker" && args[1] == "reset":
+		return runBreakerReset(args[2:])
+	}
+	fmt.Fprintln(os.Stderr, "usage: prime-fix-go [[flags] | version [-json] | report eod [flags] | secret keygen | secret encrypt | diff -template file [message file] | support-bundle [flags] | convert [-format json|fixml] [file] | session stats [flags] | session reset-seq [flags] | session timeline [flags] | messages search [flags] | gateway token -name name -role read|trade | purge -before time [flags] | init [flags] | doctor [flags] | dashboards export [flags] | order list [flags] | order cancel-all [flags] | shadow report [flags] | message send -type type [flags] | schedule list [flags] | schedule cancel -id id [flags] | venue resume -symbol symbol [flags] | breaker reset [-symbol symbol] [flags]]")
+	return 2
+}
+
+// runVersion implements `version`, printing the build of the binary
+func runVersion(args []string) int {
+	flags := flag.NewFlagSet("version", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the build as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if !*asJSON {
+		fmt.Println(BuildInfo())
+		return 0
+	}
+	data, err := json.MarshalIndent(BuildInfo(), "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to encode build info:", err)
+		return 1
+	}
+	fmt.Println(string(data))
+	return 0
+}
+
+// runDashboardsExport implements `dashboards export`, printing the Grafana
+// dashboard of the registered metrics
+func runDashboardsExport(args []string) int {
+	flags := flag.NewFlagSet("dashboards export", flag.ContinueOnError)
+	title := flags.String("title", "Prime FIX", "dashboard title")
+	uid := flags.String("uid", "prime-fix-go", "dashboard uid, which Grafana updates the dashboard by on import")
+	out := flags.String("out", "", "file to write the dashboard to (defaults to stdout)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	data, err := json.MarshalIndent(GrafanaDashboard(*title, *uid), "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to encode dashboard:", err)
+		return 1
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to write dashboard:", err)
+		return 1
+	}
+	return 0
+}
+
+// runSecretKeygen implements `secret keygen`, printing a new base64 key for PRIMEFIX_SECRET_KEY
+func runSecretKeygen() int {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to generate key:", err)
+		return 1
+	}
+	fmt.Println(base64.StdEncoding.EncodeToString(key))
+	return 0
+}
+
+// runGatewayToken implements `gateway token`, printing a new API token and
+// the GatewayTokensPath line that grants it role
+func runGatewayToken(args []string) int {
+	flags := flag.NewFlagSet("gateway token", flag.ContinueOnError)
+	name := flags.String("name", "", "client the token is for, recorded with its requests")
+	roleName := flags.String("role", string(RoleRead), "read or trade")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	role, err := parseRole(*roleName)
+	if *name == "" || strings.ContainsAny(*name, " \t") || err != nil {
+		fmt.Fprintln(os.Stderr, "usage: prime-fix-go gateway token -name name -role read|trade")
+		return 2
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to generate token:", err)
+		return 1
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	fmt.Println("Token (give to the client, it is not stored):", token)
+	fmt.Println("Add to GatewayTokensPath:")
+	fmt.Println(*name, role, HashGatewayToken(token))
+	return 0
+}
+
+// runSecretEncrypt implements `secret encrypt`, encrypting stdin under PRIMEFIX_SECRET_KEY
+func runSecretEncrypt() int {
+	key, err := secretKeyFromEnv()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read key:", err)
+		return 2
+	}
+	plaintext, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read secret:", err)
+		return 1
+	}
+	value, err := EncryptSecret(key, bytes.TrimRight(plaintext, "\r\n"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to encrypt secret:", err)
+		return 1
+	}
+	fmt.Println(value)
+	return 0
+}
+
+// runTagDiff implements `diff`, comparing a message read from a file or stdin
+// with a known-good template message
+func runTagDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	template := flags.String("template", "", "file holding a message Prime accepted")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *template == "" || flags.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "usage: prime-fix-go diff -template file [message file]")
+		return 2
+	}
+
+	want, err := os.ReadFile(*template)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read template:", err)
+		return 1
+	}
+	var got []byte
+	if flags.NArg() == 1 {
+		got, err = os.ReadFile(flags.Arg(0))
+	} else {
+		got, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read message:", err)
+		return 1
+	}
+
+	diffs, err := DiffFIX(string(got), string(want))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to parse:", err)
+		return 2
+	}
+	if err := WriteTagDiffs(os.Stdout, diffs); err != nil {
+		return 1
+	}
+	if len(diffs) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runConvert implements `convert`, printing every FIX message found in a file
+// or stdin, such as a FIX log, as one line of JSON or FIXML
+func runConvert(args []string) int {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	format := flags.String("format", "json", "output format: json or fixml")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if (*format != "json" && *format != "fixml") || flags.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "usage: prime-fix-go convert [-format json|fixml] [file]")
+		return 2
+	}
+
+	in := io.Reader(os.Stdin)
+	if flags.NArg() == 1 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to open messages:", err)
+			return 1
+		}
+		defer file.Close()
+		in = file
+	}
+
+	status := 0
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		// Log lines carry a prefix before the message itself
+		start := strings.Index(scanner.Text(), "8=FIX")
+		if start < 0 {
+			continue
+		}
+		converted, err := ConvertRawFIX(scanner.Text()[start:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Skipping unparseable message:", err)
+			status = 1
+			continue
+		}
+		var out []byte
+		if *format == "fixml" {
+			out, err = converted.FIXML()
+		} else {
+			out, err = json.Marshal(converted)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to convert message:", err)
+			return 1
+		}
+		fmt.Println(string(out))
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read messages:", err)
+		return 1
+	}
+	return status
+}
+
+// runSessionStats implements `session stats`, printing the stats the running
+// client last saved to SessionStatsPath
+func runSessionStats(args []string) int {
+	flags := flag.NewFlagSet("session stats", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file the client runs with")
+	path := flags.String("path", "", "stats file (defaults to SessionStatsPath)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	paths := []string{*path}
+	if *path == "" {
+		tenants, err := LoadTenantConfigs(*config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load config:", err)
+			return 1
+		}
+		paths = nil
+		for _, tenant := range tenants {
+			if p, err := tenant.Settings.GlobalSettings().Setting("SessionStatsPath"); err == nil {
+				paths = append(paths, p)
+			}
+		}
+		if len(paths) == 0 {
+			fmt.Fprintln(os.Stderr, "SessionStatsPath is not set in", *config)
+			return 2
+		}
+	}
+
+	status := 0
+	for i, p := range paths {
+		stats, err := LoadSessionStats(p)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to read session stats:", err)
+			status = 1
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		WriteSessionStatsReport(os.Stdout, stats, time.Now())
+	}
+	return status
+}
+
+// runSessionTimeline implements `session timeline`, printing the journaled
+// session lifecycle events of a period, oldest first
+func runSessionTimeline(args []string) int {
+	flags := flag.NewFlagSet("session timeline", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file the client runs with")
+	path := flags.String("path", "", "journal file (defaults to SessionJournalPath)")
+	tenant := flags.String("tenant", "", "only events of this tenant")
+	since := flags.String("since", "", "only events from this long ago (e.g. 24h) or this RFC 3339 time")
+	until := flags.String("until", "", "only events before this long ago or this RFC 3339 time")
+	asJSON := flags.Bool("json", false, "print the events as JSON lines")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	now := time.Now()
+	from, err := parseSearchTime(*since, now)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid -since:", err)
+		return 2
+	}
+	to, err := parseSearchTime(*until, now)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid -until:", err)
+		return 2
+	}
+
+	paths := []string{*path}
+	if *path == "" {
+		tenants, err := LoadTenantConfigs(*config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load config:", err)
+			return 1
+		}
+		paths = nil
+		for _, tenant := range tenants {
+			if p, err := tenant.Settings.GlobalSettings().Setting("SessionJournalPath"); err == nil && !slices.Contains(paths, p) {
+				paths = append(paths, p)
+			}
+		}
+		if len(paths) == 0 {
+			fmt.Fprintln(os.Stderr, "SessionJournalPath is not set in", *config)
+			return 2
+		}
+	}
+
+	status := 0
+	var events []SessionEvent
+	for _, p := range paths {
+		read, err := ReadSessionJournal(p, from, to)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to read session journal:", err)
+			status = 1
+			continue
+		}
+		for _, event := range read {
+			if *tenant == "" || event.Tenant == *tenant {
+				events = append(events, event)
+			}
+		}
+	}
+	slices.SortStableFunc(events, func(a, b SessionEvent) int { return a.Time.Compare(b.Time) })
+
+	if !*asJSON {
+		WriteSessionTimeline(os.Stdout, events)
+		return status
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	for _, event := range events {
+		encoder.Encode(event)
+	}
+	return status
+}
+
+// runMessagesSearch implements `messages search`, printing the archived
+// messages matching the given filters, oldest first
+func runMessagesSearch(args []string) int {
+	flags := flag.NewFlagSet("messages search", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file the client runs with")
+	path := flags.String("path", "", "archive file (defaults to MessageArchivePath)")
+	clOrdId := flags.String("clordid", "", "ClOrdID or OrigClOrdID to match")
+	orderId := flags.String("orderid", "", "OrderID to match")
+	msgType := flags.String("type", "", "MsgType to match, e.g. 8")
+	since := flags.String("since", "", "only messages from this long ago (e.g. 1h) or this RFC 3339 time")
+	until := flags.String("until", "", "only messages before this long ago or this RFC 3339 time")
+	raw := flags.Bool("raw", false, "print only the raw messages")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	now := time.Now()
+	query := MessageQuery{MsgType: *msgType, ClOrdID: *clOrdId, OrderID: *orderId}
+	var err error
+	if query.Since, err = parseSearchTime(*since, now); err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid -since:", err)
+		return 2
+	}
+	if query.Until, err = parseSearchTime(*until, now); err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid -until:", err)
+		return 2
+	}
+
+	paths := []string{*path}
+	if *path == "" {
+		tenants, err := LoadTenantConfigs(*config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load config:", err)
+			return 1
+		}
+		paths = nil
+		for _, tenant := range tenants {
+			if p, err := tenant.Settings.GlobalSettings().Setting("MessageArchivePath"); err == nil && !slices.Contains(paths, p) {
+				paths = append(paths, p)
+			}
+		}
+		if len(paths) == 0 {
+			fmt.Fprintln(os.Stderr, "MessageArchivePath is not set in", *config)
+			return 2
+		}
+	}
+
+	status := 0
+	for _, p := range paths {
+		messages, err := SearchMessages(p, query)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to search messages:", err)
+			status = 1
+			continue
+		}
+		for _, message := range messages {
+			text := strings.ReplaceAll(message.Raw, "\x01", "|")
+			if *raw {
+				fmt.Println(text)
+				continue
+			}
+			fmt.Printf("%s %-3s %-2s %s\n", message.Time.Format(time.RFC3339Nano), message.Direction, message.MsgType, text)
+		}
+	}
+	return status
+}
+
+// parseSearchTime parses value as a duration before now, an RFC 3339 time
+// or a UTC date; empty is the zero time
+func parseSearchTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// runDoctor implements `doctor`, checking each tenant can reach Prime
+func runDoctor(args []string) int {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file the client runs with")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout of each network check")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	tenants, err := LoadTenantConfigs(*config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load config:", err)
+		return 1
+	}
+	status := 0
+	for _, tenant := range tenants {
+		fmt.Println("Tenant", tenant.Name)
+		if !printDoctorChecks(os.Stdout, RunDoctor(tenant, *timeout)) {
+			status = 1
+		}
+	}
+	return status
+}
+
+// runPurge implements `purge`, deleting the executions, archived messages and
+// archived orders older than -before from the stores of a stopped client
+func runPurge(args []string) int {
+	flags := flag.NewFlagSet("purge", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file the client runs with")
+	tenantName := flags.String("tenant", "", "tenant to purge, every tenant by default")
+	before := flags.String("before", "", "purge records older than this date, RFC 3339 time or duration ago")
+	dryRun := flags.Bool("dry-run", false, "count what would be purged without changing anything")
+	yes := flags.Bool("yes", false, "purge without asking for confirmation")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	now := time.Now()
+	cutoff, err := parseSearchTime(*before, now)
+	if *before == "" || err != nil || flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "usage: prime-fix-go purge -before time [-config file] [-tenant name] [-dry-run] [-yes]")
+		return 2
+	}
+
+	tenants, err := LoadTenantConfigs(*config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load config:", err)
+		return 1
+	}
+	var retentions []*Retention
+	for _, tenant := range tenants {
+		if *tenantName != "" && tenant.Name != *tenantName {
+			continue
+		}
+		retention, err := retentionSetting(tenant.Settings.GlobalSettings(), nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Tenant %s: %v\n", tenant.Name, err)
+			return 1
+		}
+		if !*dryRun {
+			if err := checkStopped(tenant.Settings, now); err != nil {
+				fmt.Fprintf(os.Stderr, "Tenant %s: %v\n", tenant.Name, err)
+				return 1
+			}
+		}
+		retentions = append(retentions, retention)
+	}
+	if len(retentions) == 0 {
+		fmt.Fprintln(os.Stderr, "No tenant named", *tenantName)
+		return 2
+	}
+
+	if !*dryRun && !*yes {
+		fmt.Printf("Purge records older than %s? Open orders and the last %s are kept. [y/N] ",
+			cutoff.Format(time.RFC3339), retentions[0].Policy.SettlementPeriod)
+		var answer string
+		fmt.Scanln(&answer)
+		if answer != "y" && answer != "Y" && answer != "yes" {
+			fmt.Println("Aborted, nothing changed")
+			return 1
+		}
+	}
+
+	verb := "Purged"
+	if *dryRun {
+		verb = "Would purge"
+	}
+	for _, retention := range retentions {
+		results, err := retention.Purge(cutoff, now, *dryRun)
+		for _, result := range results {
+			fmt.Printf("%s %d records from the %s %s, keeping %d (%d protected as open)\n",
+				verb, result.Purged, result.Store, result.Path, result.Kept, result.Protected)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Purge failed:", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// runResetSeq implements `session reset-seq`, the guided repair of a
+// sequence desync: without -in or -out it shows the stored sequence numbers
+// and how to pick new ones, with them it edits the store of the stopped client
+func runResetSeq(args []string) int {
+	flags := flag.NewFlagSet("session reset-seq", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file the client runs with")
+	tenantName := flags.String("tenant", "", "tenant to repair, required with several sessions")
+	in := flags.Int("in", 0, "next MsgSeqNum expected from Prime")
+	out := flags.Int("out", 0, "next MsgSeqNum to send to Prime")
+	yes := flags.Bool("yes", false, "apply without asking for confirmation")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *in < 0 || *out < 0 {
+		fmt.Fprintln(os.Stderr, "usage: prime-fix-go session reset-seq [-config file] [-tenant name] [-in N] [-out M] [-yes]")
+		return 2
+	}
+
+	tenants, err := LoadTenantConfigs(*config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load config:", err)
+		return 1
+	}
+	var tenant *TenantConfig
+	for i := range tenants {
+		if tenants[i].Name == *tenantName || (*tenantName == "" && len(tenants) == 1) {
+			tenant = &tenants[i]
+		}
+	}
+	if tenant == nil {
+		fmt.Fprintln(os.Stderr, "Choose a tenant with -tenant")
+		return 2
+	}
+
+	repair, err := NewSeqRepair(*tenant)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Cannot repair sequence numbers:", err)
+		return 1
+	}
+	current, err := repair.Current()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read the store:", err)
+		return 1
+	}
+	fmt.Printf("Session %s, store %s\n", repair.SessionID, repair.StorePath)
+	fmt.Printf("Next MsgSeqNum in (from Prime): %d\n", current.NextTarget)
+	fmt.Printf("Next MsgSeqNum out (to Prime):  %d\n", current.NextSender)
+
+	if *in == 0 && *out == 0 {
+		fmt.Println()
+		fmt.Println("To repair a desync, stop the client and rerun with the numbers to use:")
+		fmt.Println("  -out N  when Prime logs out with \"MsgSeqNum too low, expecting N\"")
+		fmt.Println("  -in N   with N the MsgSeqNum of the next message Prime will send, e.g.")
+		fmt.Println("          one more than the last 34= received in the FIX log")
+		fmt.Println("The current sequence number files are backed up before they are changed.")
+		return 0
+	}
+
+	if err := repair.CheckStopped(time.Now()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !*yes {
+		fmt.Printf("Set in=%d out=%d (0 leaves a number unchanged)? [y/N] ", *in, *out)
+		var answer string
+		fmt.Scanln(&answer)
+		if answer != "y" && answer != "Y" && answer != "yes" {
+			fmt.Println("Aborted, nothing changed")
+			return 1
+		}
+	}
+	if err := repair.Apply(*in, *out, time.Now()); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to update the store:", err)
+		return 1
+	}
+	updated, err := repair.Current()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read back the store:", err)
+		return 1
+	}
+	fmt.Printf("Sequence numbers now in=%d out=%d\n", updated.NextTarget, updated.NextSender)
+	return 0
+}
+
+// runSupportBundle implements `support-bundle`
+func runSupportBundle(args []string) int {
+	flags := flag.NewFlagSet("support-bundle", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file the client runs with")
+	out := flags.String("out", "", "output file (defaults to primefix-support-<time>.tar.gz)")
+	since := flags.Duration("since", 24*time.Hour, "include logs modified within this long")
+	maxLog := flags.Int64("max-log-size", 10<<20, "bytes kept from the end of each log")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	path := *out
+	if path == "" {
+		path = "primefix-support-" + time.Now().UTC().Format("20060102-150405") + ".tar.gz"
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to create bundle:", err)
+		return 1
+	}
+	err = WriteSupportBundle(file, SupportBundleOptions{ConfigPath: *config, Since: *since, MaxLogSize: *maxLog})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		fmt.Fprintln(os.Stderr, "Failed to write bundle:", err)
+		return 1
+	}
+	fmt.Println("Wrote", path)
+	return 0
+}
+
+// runEODReport implements `report eod`
+func runEODReport(args []string) int {
+	flags := flag.NewFlagSet("report eod", flag.ContinueOnError)
+	store := flags.String("store", "", "execution store path (defaults to ExecutionStorePath in fix.cfg)")
+	date := flags.String("date", "", "trading day as YYYY-MM-DD (defaults to today)")
+	timezone := flags.String("tz", "UTC", "timezone the trading day is in")
+	format := flags.String("format", "csv", "output format: csv or json")
+	orders := flags.Bool("orders", false, "report the slippage of each order instead of each symbol's totals")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	loc, err := time.LoadLocation(*timezone)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid timezone:", err)
+		return 2
+	}
+	day := time.Now().In(loc)
+	if *date != "" {
+		if day, err = time.ParseInLocation("2006-01-02", *date, loc); err != nil {
+			fmt.Fprintln(os.Stderr, "Invalid date:", err)
+			return 2
+		}
+	}
+
+	path := *store
+	if path == "" {
+		settings, err := LoadFIXConfig("fix.cfg")
+		if err == nil {
+			path, err = settings.GlobalSettings().Setting("ExecutionStorePath")
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "No execution store: pass -store or set ExecutionStorePath")
+			return 2
+		}
+	}
+
+	reports, err := LoadExecutions(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to read execution store:", err)
+		return 1
+	}
+
+	var eod interface {
+		WriteCSV(w io.Writer) error
+		WriteJSON(w io.Writer) error
+	} = BuildEODReport(reports, day, loc)
+	if *orders {
+		eod = BuildSlippageReport(reports, day, loc)
+	}
+	switch *format {
+	case "csv":
+		err = eod.WriteCSV(os.Stdout)
+	case "json":
+		err = eod.WriteJSON(os.Stdout)
+	default:
+		fmt.Fprintln(os.Stderr, "Unknown format:", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to write report:", err)
+		return 1
+	}
+	return 0
+}
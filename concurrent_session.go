@@ -0,0 +1,83 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrConcurrentSession is reported when sessions keep dropping right after
+// logon, which is what Prime does when two instances log on with the same
+// CompIDs: each logon kicks the other session off
+var ErrConcurrentSession = errors.New("FIX session keeps dropping right after logon: another instance is likely logged on with the same SenderCompID and TargetCompID; stop it, or give this instance its own credentials, before restarting")
+
+// ConcurrentSessionDetector spots the logon-then-disconnect loop of two
+// instances sharing a session: MaxBounces consecutive sessions that each end
+// within Window of logging on
+type ConcurrentSessionDetector struct {
+	mu      sync.Mutex
+	logonAt time.Time
+	bounces int
+
+	MaxBounces int
+	Window     time.Duration
+
+	// OnDetected is called once when MaxBounces is reached
+	OnDetected func(err error)
+}
+
+// NewConcurrentSessionDetector creates a detector reporting maxBounces
+// sessions in a row that last less than window
+func NewConcurrentSessionDetector(maxBounces int, window time.Duration) *ConcurrentSessionDetector {
+	return &ConcurrentSessionDetector{MaxBounces: maxBounces, Window: window}
+}
+
+// LoggedOn records a logon at now
+func (d *ConcurrentSessionDetector) LoggedOn(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.logonAt = now
+}
+
+// LoggedOut records the end of the session at now. A session that lasted
+// Window or longer resets the count.
+func (d *ConcurrentSessionDetector) LoggedOut(now time.Time) {
+	d.mu.Lock()
+	if d.logonAt.IsZero() {
+		d.mu.Unlock()
+		return // logon never completed, which LogonGuard handles
+	}
+	if now.Sub(d.logonAt) < d.Window {
+		d.bounces++
+	} else {
+		d.bounces = 0
+	}
+	d.logonAt = time.Time{}
+	detected := d.MaxBounces > 0 && d.bounces == d.MaxBounces
+	d.mu.Unlock()
+
+	if detected && d.OnDetected != nil {
+		d.OnDetected(ErrConcurrentSession)
+	}
+}
+
+// Detected reports whether the loop has been spotted
+func (d *ConcurrentSessionDetector) Detected() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.MaxBounces > 0 && d.bounces >= d.MaxBounces
+}
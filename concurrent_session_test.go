@@ -0,0 +1,65 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConcurrentSessionDetector(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		sessions  []time.Duration // how long each session lasted; negative means logon never completed
+		want      bool
+		wantCalls int
+	}{
+		{"bouncing", []time.Duration{time.Second, time.Second, time.Second}, true, 1},
+		{"keeps bouncing", []time.Duration{time.Second, time.Second, time.Second, time.Second}, true, 1},
+		{"long session resets", []time.Duration{time.Second, time.Second, time.Hour, time.Second}, false, 0},
+		{"failed logons not counted", []time.Duration{time.Second, -1, -1, time.Second}, false, 0},
+	}
+	for _, tt := range tests {
+		detector := NewConcurrentSessionDetector(3, 10*time.Second)
+		calls := 0
+		detector.OnDetected = func(err error) {
+			calls++
+			if !errors.Is(err, ErrConcurrentSession) {
+				t.Errorf("%s: detected %v", tt.name, err)
+			}
+		}
+		now := start
+		for _, lasted := range tt.sessions {
+			if lasted >= 0 {
+				detector.LoggedOn(now)
+				now = now.Add(lasted)
+			}
+			detector.LoggedOut(now)
+			now = now.Add(time.Second)
+		}
+		if detector.Detected() != tt.want || calls != tt.wantCalls {
+			t.Errorf("%s: detected %t with %d calls, want %t with %d", tt.name, detector.Detected(), calls, tt.want, tt.wantCalls)
+		}
+	}
+
+	disabled := NewConcurrentSessionDetector(0, 10*time.Second)
+	disabled.LoggedOn(start)
+	disabled.LoggedOut(start)
+	if disabled.Detected() {
+		t.Error("detector without MaxBounces detected a loop")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// correlationTextPrefix marks the correlation ID when it shares Text (58)
+// with other free text
+const correlationTextPrefix = "cid="
+
+// newCorrelationId returns a random 16 character correlation ID
+func newCorrelationId() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// formatCorrelationId returns the value stamped in tag for id
+func formatCorrelationId(tag quickfix.Tag, id string) string {
+	if tag == quickfix.Tag(58) {
+		return correlationTextPrefix + id
+	}
+	return id
+}
+
+// parseCorrelationId extracts the correlation ID from the value of tag,
+// which for Text (58) may be surrounded by other text
+func parseCorrelationId(tag quickfix.Tag, value string) string {
+	if tag != quickfix.Tag(58) {
+		return value
+	}
+	for _, field := range strings.Fields(value) {
+		if id, ok := strings.CutPrefix(field, correlationTextPrefix); ok {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestCorrelationId(t *testing.T) {
+	tests := []struct {
+		name   string
+		tag    quickfix.Tag
+		id     string
+		wire   string // value of tag on the order
+		report string // value of tag on the report
+		want   string
+	}{
+		{"custom tag", 20001, "abc123", "abc123", "abc123", "abc123"},
+		{"no id after one", 20001, "", "", "", ""},
+		{"text tag", 58, "abc123", "cid=abc123", "partial fill cid=abc123 ok", "abc123"},
+		{"text without an id", 58, "abc123", "cid=abc123", "partial fill", ""},
+	}
+	builder := newOrderBuilder("SENDER", "COIN")
+	for _, tt := range tests {
+		builder.correlationTag = tt.tag
+		req := OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "1000", CorrelationId: tt.id}
+		if got := bodyString(builder.build(req, "portfolio", time.Now()), tt.tag); got != tt.wire {
+			t.Errorf("%s: sent %q, want %q", tt.name, got, tt.wire)
+		}
+		if got := parseCorrelationId(tt.tag, tt.report); got != tt.want {
+			t.Errorf("%s: parsed %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if a, b := newCorrelationId(), newCorrelationId(); len(a) != 16 || a == b {
+		t.Errorf("correlation IDs %q and %q", a, b)
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/datadictionary"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrUnregisteredMessage is returned for a custom message whose MsgType
+	// has no MessageDescriptor
+	ErrUnregisteredMessage = errors.New("message type is not registered")
+	// ErrInvalidMessage is returned for a custom message failing its
+	// descriptor or data dictionary checks
+	ErrInvalidMessage = errors.New("invalid message")
+)
+
+// protectedMsgTypes cannot be sent as custom messages: the session protocol
+// belongs to the engine, and orders and cancels must go through PlaceOrder,
+// CancelOrder and ReplaceOrder so they are validated, risk checked and tracked
+var protectedMsgTypes = []string{"0", "2", "3", "4", "5", "A", "D", "F", "G"}
+
+// sessionTag reports whether tag belongs to the header or trailer, which the
+// session sets
+func sessionTag(tag int) bool {
+	return headerTags[tag] || trailerTags[tag]
+}
+
+// MessageField declares a body field a custom message may carry
+type MessageField struct {
+	Tag  int    `json:"tag"`
+	Name string `json:"name,omitempty"`
+	// Type is a FIX data type, e.g. STRING, INT, QTY, PRICE, CHAR, BOOLEAN
+	// or UTCTIMESTAMP; empty takes the type from the data dictionary
+	Type     string   `json:"type,omitempty"`
+	Required bool     `json:"required,omitempty"`
+	Values   []string `json:"values,omitempty"` // allowed values, any when empty
+}
+
+// MessageDescriptor registers a message type the client does not model so it
+// can be sent with SendCustom
+type MessageDescriptor struct {
+	Name   string         `json:"name"`
+	Fields []MessageField `json:"fields"`
+}
+
+// MessageDescriptors are the registered custom messages by MsgType
+type MessageDescriptors map[string]MessageDescriptor
+
+// LoadMessageDescriptors reads a JSON object of MsgTypes to their MessageDescriptor
+func LoadMessageDescriptors(path string) (MessageDescriptors, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var descriptors MessageDescriptors
+	if err := json.Unmarshal(data, &descriptors); err != nil {
+		return nil, fmt.Errorf("invalid message descriptors %s: %w", path, err)
+	}
+	for msgType, descriptor := range descriptors {
+		if err := descriptor.check(msgType); err != nil {
+			return nil, fmt.Errorf("message descriptors %s: %w", path, err)
+		}
+	}
+	return descriptors, nil
+}
+
+// check validates the descriptor of msgType
+func (d MessageDescriptor) check(msgType string) error {
+	if slices.Contains(protectedMsgTypes, msgType) {
+		return fmt.Errorf("%s (%s) cannot be sent as a custom message", msgTypeNames[msgType], msgType)
+	}
+	if msgType == "" || d.Name == "" {
+		return fmt.Errorf("%q: MsgType and name are required", msgType)
+	}
+	seen := make(map[int]bool)
+	for _, field := range d.Fields {
+		switch {
+		case field.Tag <= 0:
+			return fmt.Errorf("%s: invalid tag %d", msgType, field.Tag)
+		case sessionTag(field.Tag):
+			return fmt.Errorf("%s: tag %d is set by the session", msgType, field.Tag)
+		case seen[field.Tag]:
+			return fmt.Errorf("%s: tag %d is declared twice", msgType, field.Tag)
+		}
+		if err := checkFieldValue(field.Type, "", nil); errors.Is(err, errUnknownFieldType) {
+			return fmt.Errorf("%s: tag %d: %w", msgType, field.Tag, err)
+		}
+		seen[field.Tag] = true
+	}
+	return nil
+}
+
+// Build validates fields against the descriptor of msgType and, when dict is
+// set, the data dictionary, and returns the message to send. Repeating
+// groups are not supported.
+func (d MessageDescriptors) Build(msgType string, fields map[int]string, dict *datadictionary.DataDictionary) (*quickfix.Message, error) {
+	descriptor, ok := d[msgType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnregisteredMessage, msgType)
+	}
+
+	declared := make(map[int]MessageField, len(descriptor.Fields))
+	var errs []error
+	for _, field := range descriptor.Fields {
+		declared[field.Tag] = field
+		if _, ok := fields[field.Tag]; field.Required && !ok {
+			errs = append(errs, fmt.Errorf("required tag %d (%s) is missing", field.Tag, field.Name))
+		}
+	}
+
+	var dictDef *datadictionary.MessageDef
+	if dict != nil {
+		dictDef = dict.Messages[msgType]
+	}
+	if dictDef != nil {
+		for tag := range dictDef.RequiredTags {
+			if _, ok := fields[tag]; !ok && !sessionTag(tag) {
+				errs = append(errs, fmt.Errorf("tag %d is required by the data dictionary", tag))
+			}
+		}
+	}
+
+	tags := make([]int, 0, len(fields))
+	for tag := range fields {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	for _, tag := range tags {
+		value := fields[tag]
+		field, ok := declared[tag]
+		if !ok {
+			errs = append(errs, fmt.Errorf("tag %d is not declared for %s", tag, descriptor.Name))
+			continue
+		}
+		if err := checkFieldValue(field.Type, value, field.Values); err != nil {
+			errs = append(errs, fmt.Errorf("tag %d: %w", tag, err))
+		}
+		if dict == nil {
+			continue
+		}
+		if fieldType, ok := dict.FieldTypeByTag[tag]; ok {
+			if err := checkFieldValue(fieldType.Type, value, slices.Sorted(maps.Keys(fieldType.Enums))); err != nil {
+				errs = append(errs, fmt.Errorf("tag %d: data dictionary: %w", tag, err))
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidMessage, descriptor.Name, err)
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.SetField(quickfix.Tag(35), quickfix.FIXString(msgType))
+	for _, tag := range tags {
+		msg.Body.SetField(quickfix.Tag(tag), quickfix.FIXString(fields[tag]))
+	}
+	return msg, nil
+}
+
+var errUnknownFieldType = errors.New("unknown field type")
+
+// checkFieldValue checks value is of the FIX data type fixType and one of
+// values, when there are any. Types without a check accept any value.
+func checkFieldValue(fixType, value string, values []string) error {
+	var err error
+	switch fixType {
+	case "", "STRING", "MULTIPLEVALUESTRING", "MULTIPLECHARVALUE", "CURRENCY", "EXCHANGE", "COUNTRY", "DATA", "LOCALMKTDATE", "MONTHYEAR", "UTCDATEONLY", "UTCTIMEONLY":
+	case "INT", "LENGTH", "SEQNUM", "NUMINGROUP", "DAYOFMONTH":
+		if value != "" {
+			_, err = strconv.Atoi(value)
+		}
+	case "FLOAT", "QTY", "PRICE", "PRICEOFFSET", "AMT", "PERCENTAGE":
+		if value != "" {
+			_, err = decimal.NewFromString(value)
+		}
+	case "CHAR":
+		if value != "" && len(value) != 1 {
+			err = errors.New("not a single character")
+		}
+	case "BOOLEAN":
+		if value != "" && value != "Y" && value != "N" {
+			err = errors.New("not Y or N")
+		}
+	case "UTCTIMESTAMP":
+		if value != "" {
+			_, err = time.Parse("20060102-15:04:05.999999999", value)
+		}
+	default:
+		return fmt.Errorf("%w %q", errUnknownFieldType, fixType)
+	}
+	if err != nil {
+		return fmt.Errorf("%q is not a valid %s", value, fixType)
+	}
+	if len(values) > 0 && value != "" && !slices.Contains(values, value) {
+		return fmt.Errorf("%q is not one of %v", value, values)
+	}
+	return nil
+}
+
+// parseTagValue parses a TAG=VALUE field of a custom message
+func parseTagValue(field string) (int, string, error) {
+	tag, value, ok := strings.Cut(field, "=")
+	n, err := strconv.Atoi(tag)
+	if !ok || err != nil || n <= 0 {
+		return 0, "", fmt.Errorf("invalid field %q, want TAG=VALUE", field)
+	}
+	return n, value, nil
+}
+
+// SendCustom sends a message of a type the client does not model, e.g. for a
+// new Prime feature. Its MsgType must be registered in Messages and its
+// fields pass the descriptor and data dictionary checks.
+func (a *FixApplication) SendCustom(msgType string, fields map[int]string) error {
+	msg, err := a.Messages.Build(msgType, fields, a.Dictionary)
+	if err != nil {
+		return err
+	}
+	a.logger().Printf("Sending custom %s message: %s", a.Messages[msgType].Name, msg)
+	return a.Send(msg)
+}
@@ -0,0 +1,109 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/datadictionary"
+)
+
+const customMessageDictionary = `<fix type="FIX" major="4" minor="2">
+ <header><field name="BeginString" required="Y"/><field name="MsgType" required="Y"/></header>
+ <trailer><field name="CheckSum" required="Y"/></trailer>
+ <messages>
+  <message name="UserRequest" msgtype="BE" msgcat="app">
+   <field name="UserRequestID" required="Y"/>
+   <field name="UserRequestType" required="Y"/>
+  </message>
+ </messages>
+ <components/>
+ <fields>
+  <field number="8" name="BeginString" type="STRING"/>
+  <field number="35" name="MsgType" type="STRING"/>
+  <field number="10" name="CheckSum" type="STRING"/>
+  <field number="923" name="UserRequestID" type="STRING"/>
+  <field number="924" name="UserRequestType" type="INT">
+   <value enum="1" description="LOGONUSER"/>
+   <value enum="4" description="REQUESTINDIVIDUALUSERSTATUS"/>
+  </field>
+ </fields>
+</fix>`
+
+func TestCustomMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.json")
+	os.WriteFile(path, []byte(`{
+		"BE": {"name": "UserRequest", "fields": [
+			{"tag": 923, "name": "UserRequestID", "required": true},
+			{"tag": 924, "name": "UserRequestType"},
+			{"tag": 9999, "name": "Note", "type": "STRING", "values": ["a", "b"]}
+		]}
+	}`), 0644)
+	descriptors, err := LoadMessageDescriptors(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict, err := datadictionary.ParseSrc(strings.NewReader(customMessageDictionary))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := descriptors.Build("BE", map[int]string{923: "r1", 924: "4", 9999: "a"}, dict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgType, _ := msg.Header.GetString(quickfix.Tag(35)); msgType != "BE" || bodyString(msg, quickfix.Tag(924)) != "4" {
+		t.Fatalf("built %s", msg)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		fields map[int]string
+		want   string
+	}{
+		{"dictionary required", map[int]string{923: "r1"}, "tag 924 is required by the data dictionary"},
+		{"dictionary enum", map[int]string{923: "r1", 924: "9"}, `tag 924: data dictionary: "9" is not one of [1 4]`},
+		{"dictionary type", map[int]string{923: "r1", 924: "x"}, `"x" is not a valid INT`},
+		{"undeclared", map[int]string{923: "r1", 924: "1", 58: "hi"}, "tag 58 is not declared"},
+		{"descriptor values", map[int]string{923: "r1", 924: "1", 9999: "c"}, `tag 9999: "c" is not one of [a b]`},
+	} {
+		_, err := descriptors.Build("BE", tc.fields, dict)
+		if !errors.Is(err, ErrInvalidMessage) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got %v, want %q", tc.name, err, tc.want)
+		}
+	}
+	if _, err := descriptors.Build("BE", map[int]string{924: "1"}, nil); err == nil || !strings.Contains(err.Error(), "required tag 923") {
+		t.Errorf("missing required field: %v", err)
+	}
+	if _, err := descriptors.Build("XX", nil, dict); !errors.Is(err, ErrUnregisteredMessage) {
+		t.Errorf("unregistered type: %v", err)
+	}
+
+	for _, invalid := range []string{
+		`{"D": {"name": "NewOrderSingle", "fields": []}}`,
+		`{"U1": {"name": "Custom", "fields": [{"tag": 49}]}}`,
+		`{"U1": {"name": "Custom", "fields": [{"tag": 58, "type": "TEXTISH"}]}}`,
+	} {
+		os.WriteFile(path, []byte(invalid), 0644)
+		if _, err := LoadMessageDescriptors(path); err == nil {
+			t.Errorf("loaded %s", invalid)
+		}
+	}
+}
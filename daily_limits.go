@@ -0,0 +1,263 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DailyLimits are cumulative limits over a trading day. A zero limit is not enforced.
+type DailyLimits struct {
+	MaxNotional decimal.Decimal
+	MaxOrders   int
+
+	// ResetTime ("15:04") in Location is when the trading day rolls over
+	ResetTime string
+	Location  *time.Location
+}
+
+// DailyCounters are the running totals for one trading day
+type DailyCounters struct {
+	TradingDay string
+	Orders     int
+	Notional   decimal.Decimal
+}
+
+// DailyReservation is an order's place under the daily limits from the check
+// until it is sent, see DailyLimiter.Reserve
+type DailyReservation struct {
+	day      string
+	notional decimal.Decimal
+	orders   int // counted orders, none for a replace
+}
+
+// DailyLimiter enforces DailyLimits, persisting its counters to path (when not
+// empty) so a restart mid-day resumes from the same totals
+type DailyLimiter struct {
+	mu       sync.Mutex
+	limits   DailyLimits
+	reset    time.Duration // offset of ResetTime from midnight
+	path     string
+	counters DailyCounters
+	held     decimal.Decimal // notional of reserved orders not yet sent
+}
+
+// NewDailyLimiter creates a limiter, reloading the counters persisted at path
+func NewDailyLimiter(limits DailyLimits, path string) (*DailyLimiter, error) {
+	if limits.Location == nil {
+		limits.Location = time.UTC
+	}
+	if limits.ResetTime == "" {
+		limits.ResetTime = "00:00"
+	}
+	resetAt, err := time.Parse("15:04", limits.ResetTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid daily reset time %q: %w", limits.ResetTime, err)
+	}
+
+	l := &DailyLimiter{
+		limits: limits,
+		reset:  time.Duration(resetAt.Hour())*time.Hour + time.Duration(resetAt.Minute())*time.Minute,
+		path:   path,
+	}
+	if path == "" {
+		return l, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &l.counters); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// SetLimits replaces the notional and order count limits; the trading day
+// boundary cannot be changed on a running limiter
+func (l *DailyLimiter) SetLimits(maxNotional decimal.Decimal, maxOrders int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits.MaxNotional = maxNotional
+	l.limits.MaxOrders = maxOrders
+}
+
+// tradingDay returns the date of the trading day containing now
+func (l *DailyLimiter) tradingDay(now time.Time) string {
+	return now.In(l.limits.Location).Add(-l.reset).Format("2006-01-02")
+}
+
+// roll resets the counters when now is in a new trading day; callers must hold mu
+func (l *DailyLimiter) roll(now time.Time) {
+	if day := l.tradingDay(now); day != l.counters.TradingDay {
+		l.counters = DailyCounters{TradingDay: day}
+		l.held = decimal.Zero
+	}
+}
+
+// Counters returns the totals of the current trading day
+func (l *DailyLimiter) Counters(now time.Time) DailyCounters {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.roll(now)
+	return l.counters
+}
+
+// Check returns a *RiskError if placing req would breach a daily limit
+func (l *DailyLimiter) Check(req OrderRequest, now time.Time) *RiskError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.roll(now)
+	return l.check(req)
+}
+
+// Reserve checks req like Check and, when it passes, counts the order and
+// holds its notional under the same lock, so concurrent orders cannot all
+// pass against the same totals. Release the reservation once the order is
+// sent or has failed to send.
+func (l *DailyLimiter) Reserve(req OrderRequest, now time.Time) (DailyReservation, *RiskError) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.roll(now)
+	if err := l.check(req); err != nil {
+		return DailyReservation{}, err
+	}
+
+	reservation := DailyReservation{day: l.counters.TradingDay, notional: orderNotional(req), orders: 1}
+	l.counters.Orders++
+	l.held = l.held.Add(reservation.notional)
+	l.persist()
+	return reservation, nil
+}
+
+// ReserveReplace is Reserve for a replace of a working order to req, which
+// holds its new notional but does not count as another order
+func (l *DailyLimiter) ReserveReplace(req OrderRequest, now time.Time) (DailyReservation, *RiskError) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.roll(now)
+	if err := l.checkNotional(req); err != nil {
+		return DailyReservation{}, err
+	}
+
+	reservation := DailyReservation{day: l.counters.TradingDay, notional: orderNotional(req)}
+	l.held = l.held.Add(reservation.notional)
+	return reservation, nil
+}
+
+// Release stops holding the notional of reservation, and uncounts its order
+// when it was not sent. Reservations from a past trading day are ignored.
+func (l *DailyLimiter) Release(reservation DailyReservation, sent bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if reservation.day != l.counters.TradingDay {
+		return
+	}
+
+	l.held = l.held.Sub(reservation.notional)
+	if !sent && reservation.orders > 0 {
+		l.counters.Orders -= reservation.orders
+		l.persist()
+	}
+}
+
+// check is Check for the current trading day; callers must hold mu
+func (l *DailyLimiter) check(req OrderRequest) *RiskError {
+	if l.limits.MaxOrders > 0 && l.counters.Orders+1 > l.limits.MaxOrders {
+		return &RiskError{
+			Rule:      "MaxDailyOrders",
+			Symbol:    req.Symbol,
+			Limit:     decimal.NewFromInt(int64(l.limits.MaxOrders)),
+			Projected: decimal.NewFromInt(int64(l.counters.Orders + 1)),
+		}
+	}
+	return l.checkNotional(req)
+}
+
+// checkNotional checks the daily notional limit alone; callers must hold mu
+func (l *DailyLimiter) checkNotional(req OrderRequest) *RiskError {
+	if l.limits.MaxNotional.IsPositive() {
+		projected := l.counters.Notional.Add(l.held).Add(orderNotional(req))
+		if projected.GreaterThan(l.limits.MaxNotional) {
+			return &RiskError{Rule: "MaxDailyNotional", Symbol: req.Symbol, Limit: l.limits.MaxNotional, Projected: projected}
+		}
+	}
+
+	return nil
+}
+
+// orderNotional returns the notional of a priced order, zero without a limit price
+func orderNotional(req OrderRequest) decimal.Decimal {
+	qty, qtyErr := decimal.NewFromString(req.Quantity)
+	px, pxErr := decimal.NewFromString(req.LimitPrice)
+	if qtyErr != nil || pxErr != nil {
+		return decimal.Zero
+	}
+	return qty.Mul(px)
+}
+
+// RecordFill adds the traded notional of the fill carried by report, if any
+func (l *DailyLimiter) RecordFill(report ExecutionReport, now time.Time) {
+	switch report.ExecType {
+	case "1", "2", "F": // Partial fill, Fill, Trade
+	default:
+		return
+	}
+	qty, err := decimal.NewFromString(report.LastShares)
+	if err != nil {
+		return
+	}
+	px, err := decimal.NewFromString(report.LastPx)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.roll(now)
+
+	l.counters.Notional = l.counters.Notional.Add(qty.Mul(px))
+	l.persist()
+}
+
+// persist writes the counters to path; callers must hold mu
+func (l *DailyLimiter) persist() {
+	if l.path == "" {
+		return
+	}
+
+	data, err := json.Marshal(l.counters)
+	if err == nil {
+		tmp := l.path + ".tmp"
+		if err = os.WriteFile(tmp, data, 0600); err == nil {
+			err = os.Rename(tmp, l.path)
+		}
+	}
+	if err != nil {
+		log.Println("Failed to persist daily counters:", err)
+	}
+}
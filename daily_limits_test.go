@@ -0,0 +1,150 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDailyLimiter(t *testing.T) {
+	d := decimal.RequireFromString
+	day := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	buy := func(qty, px string) OrderRequest {
+		return OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: qty, LimitPrice: px}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		limits DailyLimits
+		before func(l *DailyLimiter)
+		req    OrderRequest
+		now    time.Time
+		want   string // blocking rule, empty when allowed
+	}{
+		{"within limits", DailyLimits{MaxOrders: 2, MaxNotional: d("1000")}, nil, buy("1", "500"), day, ""},
+		{"order count", DailyLimits{MaxOrders: 1}, func(l *DailyLimiter) { l.Reserve(buy("1", "1"), day) }, buy("1", "1"), day, "MaxDailyOrders"},
+		{"unsent order uncounted", DailyLimits{MaxOrders: 1}, func(l *DailyLimiter) {
+			reservation, _ := l.Reserve(buy("1", "1"), day)
+			l.Release(reservation, false)
+		}, buy("1", "1"), day, ""},
+		{"order notional", DailyLimits{MaxNotional: d("1000")}, nil, buy("3", "500"), day, "MaxDailyNotional"},
+		{"filled notional", DailyLimits{MaxNotional: d("1000")}, func(l *DailyLimiter) {
+			l.RecordFill(ExecutionReport{ExecType: "F", LastShares: "1", LastPx: "800"}, day)
+		}, buy("1", "500"), day, "MaxDailyNotional"},
+		{"held notional of an order being sent", DailyLimits{MaxNotional: d("1000")}, func(l *DailyLimiter) { l.Reserve(buy("1", "800"), day) }, buy("1", "500"), day, "MaxDailyNotional"},
+		{"released notional", DailyLimits{MaxNotional: d("1000")}, func(l *DailyLimiter) {
+			reservation, _ := l.Reserve(buy("1", "800"), day)
+			l.Release(reservation, true)
+		}, buy("1", "500"), day, ""},
+		{"new trading day", DailyLimits{MaxOrders: 1, ResetTime: "17:00"}, func(l *DailyLimiter) { l.Reserve(buy("1", "1"), day) }, buy("1", "1"), day.Add(2 * time.Hour), ""},
+		{"before the reset time", DailyLimits{MaxOrders: 1, ResetTime: "17:00"}, func(l *DailyLimiter) { l.Reserve(buy("1", "1"), day) }, buy("1", "1"), day.Add(time.Hour), "MaxDailyOrders"},
+	} {
+		limiter, err := NewDailyLimiter(tc.limits, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.before != nil {
+			tc.before(limiter)
+		}
+		checkErr := limiter.Check(tc.req, tc.now)
+		_, reserveErr := limiter.Reserve(tc.req, tc.now)
+		for _, err := range []*RiskError{checkErr, reserveErr} {
+			switch {
+			case tc.want == "" && err != nil:
+				t.Errorf("%s: blocked by %v", tc.name, err)
+			case tc.want != "" && (err == nil || err.Rule != tc.want):
+				t.Errorf("%s: got %v, want %s", tc.name, err, tc.want)
+			}
+		}
+	}
+}
+
+func TestDailyLimiterReservesAtomically(t *testing.T) {
+	limiter, err := NewDailyLimiter(DailyLimits{MaxOrders: 10}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	passed := 0
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limiter.Reserve(OrderRequest{Symbol: "ETH-USD", Quantity: "1", LimitPrice: "1"}, now); err == nil {
+				mu.Lock()
+				passed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if passed != 10 || limiter.Counters(now).Orders != 10 {
+		t.Errorf("%d orders passed, %d counted, want 10", passed, limiter.Counters(now).Orders)
+	}
+}
+
+func TestDailyLimiterPersistsCounters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily.json")
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	limiter, err := NewDailyLimiter(DailyLimits{MaxOrders: 2}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reservation, _ := limiter.Reserve(OrderRequest{Quantity: "1", LimitPrice: "10"}, now)
+	limiter.Release(reservation, true)
+	limiter.RecordFill(ExecutionReport{ExecType: "1", LastShares: "1", LastPx: "10"}, now)
+
+	restarted, err := NewDailyLimiter(DailyLimits{MaxOrders: 2}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counters := restarted.Counters(now)
+	if counters.Orders != 1 || !counters.Notional.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("counters after restart = %+v", counters)
+	}
+	// A reservation from before a restart or a roll is not released twice
+	restarted.Release(DailyReservation{day: "2024-01-01"}, false)
+	if restarted.Counters(now).Orders != 1 {
+		t.Error("released a reservation of another trading day")
+	}
+}
+
+func TestPlaceOrderReleasesUnsentReservation(t *testing.T) {
+	limiter, err := NewDailyLimiter(DailyLimits{MaxOrders: 1}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &FixApplication{Risk: NewRiskChecker(RiskLimits{}, NewPositionTracker())}
+	app.Risk.Daily = limiter
+
+	// Not logged on, so neither order is sent and neither uses up the limit
+	for range 2 {
+		if _, err := app.PlaceOrder(OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "10"}); !errors.Is(err, ErrNotLoggedOn) {
+			t.Fatalf("PlaceOrder = %v, want ErrNotLoggedOn", err)
+		}
+	}
+	if orders := limiter.Counters(time.Now()).Orders; orders != 0 {
+		t.Errorf("%d orders counted", orders)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"strings"
+)
+
+// GrafanaDashboard generates a Grafana dashboard with a panel for every
+// registered metric, grouped into a row per Metric.Row. Panels query the
+// Prometheus data source picked with the dashboard's datasource variable
+// and can be narrowed to tenants with its tenant variable.
+func GrafanaDashboard(title, uid string) map[string]any {
+	datasource := map[string]any{"type": "prometheus", "uid": "${datasource}"}
+
+	var panels []map[string]any
+	id, y := 1, 0
+	for _, row := range metricRows() {
+		panels = append(panels, map[string]any{
+			"id": id, "type": "row", "title": row, "collapsed": false,
+			"gridPos": map[string]int{"x": 0, "y": y, "w": 24, "h": 1},
+		})
+		id++
+		y++
+
+		x := 0
+		for _, metric := range metrics {
+			if metric.Row != row {
+				continue
+			}
+			title, unit := metric.Help, metric.Unit
+			switch {
+			case metric.Type == "counter":
+				title, unit = title+" per second", "ops"
+			case metric.Type == "histogram":
+				title += ", 99th percentile"
+			case unit == "":
+				unit = "short"
+			}
+			panels = append(panels, map[string]any{
+				"id": id, "type": "timeseries", "title": title, "description": metric.Name,
+				"datasource": datasource,
+				"gridPos":    map[string]int{"x": x, "y": y, "w": 12, "h": 8},
+				"fieldConfig": map[string]any{
+					"defaults":  map[string]any{"unit": unit},
+					"overrides": []any{},
+				},
+				"targets": []map[string]any{{
+					"refId":        "A",
+					"datasource":   datasource,
+					"expr":         metricQuery(metric),
+					"legendFormat": metricLegend(metric),
+				}},
+			})
+			id++
+			if x += 12; x == 24 {
+				x = 0
+				y += 8
+			}
+		}
+		if x != 0 {
+			y += 8
+		}
+	}
+
+	return map[string]any{
+		"title":         title,
+		"uid":           uid,
+		"tags":          []string{"prime-fix-go"},
+		"timezone":      "utc",
+		"schemaVersion": 39,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"templating": map[string]any{"list": []map[string]any{
+			{"name": "datasource", "type": "datasource", "query": "prometheus", "label": "Data source"},
+			{
+				"name": "tenant", "type": "query", "label": "Tenant", "datasource": datasource,
+				"query":      "label_values(primefix_session_logged_on, tenant)",
+				"multi":      true,
+				"includeAll": true,
+				"current":    map[string]any{"text": "All", "value": "$__all"},
+				"refresh":    2,
+			},
+		}},
+		"panels": panels,
+	}
+}
+
+// metricRows returns the dashboard rows in the order metrics first use them
+func metricRows() []string {
+	var rows []string
+	for _, metric := range metrics {
+		if !slices.Contains(rows, metric.Row) {
+			rows = append(rows, metric.Row)
+		}
+	}
+	return rows
+}
+
+// metricQuery returns the PromQL a panel shows metric with: the per second
+// rate of a counter, the 99th percentile of a histogram and a gauge as is
+func metricQuery(metric Metric) string {
+	by := strings.Join(append([]string{"tenant"}, metric.Labels...), ", ")
+	selector := `{tenant=~"$tenant"}`
+	switch metric.Type {
+	case "counter":
+		return "sum by (" + by + ") (rate(" + metric.Name + selector + "[$__rate_interval]))"
+	case "histogram":
+		return "histogram_quantile(0.99, sum by (le, " + by + ") (rate(" + metric.Name + "_bucket" + selector + "[$__rate_interval])))"
+	}
+	return "sum by (" + by + ") (" + metric.Name + selector + ")"
+}
+
+func metricLegend(metric Metric) string {
+	legend := "{{tenant}}"
+	for _, label := range metric.Labels {
+		legend += " {{" + label + "}}"
+	}
+	return legend
+}
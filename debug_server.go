@@ -0,0 +1,147 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	rpprof "runtime/pprof"
+	"time"
+)
+
+// QueueDepths is the number of items waiting in a tenant's internal queues
+type QueueDepths struct {
+	Dispatcher  []int    `json:",omitempty"` // per worker
+	Sinks       []int    `json:",omitempty"`
+	SinkDropped []uint64 `json:",omitempty"`
+	Outbox      int
+	Scheduled   int
+}
+
+// QueueDepths returns the current depth of the application's queues
+func (a *FixApplication) QueueDepths() QueueDepths {
+	var depths QueueDepths
+	if a.Dispatcher != nil {
+		depths.Dispatcher = a.Dispatcher.Depths()
+	}
+	for _, sink := range a.Sinks {
+		depths.Sinks = append(depths.Sinks, sink.Len())
+		depths.SinkDropped = append(depths.SinkDropped, sink.Dropped())
+	}
+	if a.Outbox != nil {
+		depths.Outbox = a.Outbox.Pending()
+	}
+	if a.Scheduler != nil {
+		depths.Scheduled = len(a.Scheduler.Pending())
+	}
+	return depths
+}
+
+// RuntimeStats is a summary of the Go runtime's goroutines and memory
+type RuntimeStats struct {
+	Build      Build
+	Goroutines int
+	HeapAlloc  uint64
+	HeapInuse  uint64
+	Sys        uint64
+	NumGC      uint32
+	LastGC     time.Time
+	Time       time.Time
+}
+
+func runtimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats := RuntimeStats{
+		Build:      BuildInfo(),
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  mem.HeapAlloc,
+		HeapInuse:  mem.HeapInuse,
+		Sys:        mem.Sys,
+		NumGC:      mem.NumGC,
+		Time:       time.Now(),
+	}
+	if mem.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(mem.LastGC))
+	}
+	return stats
+}
+
+// NewDebugHandler serves diagnostics for a running process:
+//
+//	/debug/pprof/             the standard pprof profiles
+//	/debug/goroutines         a full dump of every goroutine's stack
+//	/debug/runtime            goroutine count and memory as JSON
+//	/debug/queues             the QueueDepths of each tenant as JSON
+//	/debug/execution-quality  the day's slippage of each tenant as JSON
+//	/metrics                  every metric of every tenant for Prometheus
+//
+// It exposes internals and profiling load, so it is served through
+// GatewayHandler, which requires authentication off loopback.
+func NewDebugHandler(manager *Manager) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	mux.HandleFunc("/debug/goroutines", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		rpprof.Lookup("goroutine").WriteTo(w, 2)
+	})
+	mux.HandleFunc("/debug/runtime", func(w http.ResponseWriter, r *http.Request) {
+		writeDebugJSON(w, runtimeStats())
+	})
+	mux.HandleFunc("/debug/queues", func(w http.ResponseWriter, r *http.Request) {
+		depths := make(map[string]QueueDepths)
+		for _, tenant := range manager.Tenants() {
+			depths[tenant.Name] = tenant.App.QueueDepths()
+		}
+		writeDebugJSON(w, depths)
+	})
+	mux.HandleFunc("/debug/execution-quality", func(w http.ResponseWriter, r *http.Request) {
+		quality := make(map[string]ExecutionQualityReport)
+		for _, tenant := range manager.Tenants() {
+			if tenant.App.Quality != nil {
+				quality[tenant.Name] = tenant.App.Quality.Report()
+			}
+		}
+		writeDebugJSON(w, quality)
+	})
+	mux.HandleFunc("/debug/shadow", func(w http.ResponseWriter, r *http.Request) {
+		shadows := make(map[string]ShadowReport)
+		for _, tenant := range manager.Tenants() {
+			if tenant.App.Shadow != nil {
+				shadows[tenant.Name] = tenant.App.Shadow.Report()
+			}
+		}
+		writeDebugJSON(w, shadows)
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteMetrics(w, manager)
+	})
+	return mux
+}
+
+func writeDebugJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
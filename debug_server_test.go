@@ -0,0 +1,63 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	app := &FixApplication{Dispatcher: NewDispatcher(2, 8, func(ExecutionReport) {})}
+	defer app.Dispatcher.Close()
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app}); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewDebugHandler(manager)
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/debug/runtime", http.StatusOK, `"Goroutines"`},
+		{"/debug/queues", http.StatusOK, `"Dispatcher": [`},
+		{"/debug/execution-quality", http.StatusOK, "{}"},
+		{"/debug/goroutines", http.StatusOK, "goroutine "},
+		{"/metrics", http.StatusOK, `tenant="desk"`},
+		{"/debug/unknown", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.wantStatus || !strings.Contains(rec.Body.String(), tt.wantBody) {
+			t.Errorf("%s: %d %q, want %d containing %q", tt.path, rec.Code, rec.Body.String(), tt.wantStatus, tt.wantBody)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/queues", nil))
+	var depths map[string]QueueDepths
+	if err := json.Unmarshal(rec.Body.Bytes(), &depths); err != nil {
+		t.Fatal(err)
+	}
+	if len(depths["desk"].Dispatcher) != 2 {
+		t.Errorf("queue depths %+v, want one per dispatcher worker", depths)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ExecDedup remembers the most recently seen executions, keyed on
+// ExecID/ExecType, in a bounded LRU so resent ExecutionReports are only
+// processed once
+type ExecDedup struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// NewExecDedup creates a dedup cache holding up to capacity executions
+func NewExecDedup(capacity int) *ExecDedup {
+	return &ExecDedup{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element, capacity),
+	}
+}
+
+// Seen records the execution and reports whether it had already been seen
+func (d *ExecDedup) Seen(execId, execType string) bool {
+	key := execId + "/" + execType
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.remember(key)
+}
+
+// remember moves key to the front, evicting the oldest key when over
+// capacity, and reports whether it was already present; callers must hold mu
+func (d *ExecDedup) remember(key string) bool {
+	if elem, ok := d.entries[key]; ok {
+		d.order.MoveToFront(elem)
+		return true
+	}
+
+	d.entries[key] = d.order.PushFront(key)
+	if d.order.Len() > d.capacity {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.entries, oldest.Value.(string))
+	}
+	return false
+}
+
+// Keys returns the remembered ExecID/ExecType keys, oldest first
+func (d *ExecDedup) Keys() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	keys := make([]string, 0, d.order.Len())
+	for elem := d.order.Back(); elem != nil; elem = elem.Prev() {
+		keys = append(keys, elem.Value.(string))
+	}
+	return keys
+}
+
+// Restore remembers keys taken by Keys, oldest first
+func (d *ExecDedup) Restore(keys []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range keys {
+		d.remember(key)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExecDedupSeen(t *testing.T) {
+	dedup := NewExecDedup(2)
+	steps := []struct {
+		execID, execType string
+		seen             bool
+	}{
+		{"1", "0", false},
+		{"1", "0", true},
+		{"1", "F", false}, // same ExecID, different ExecType
+		{"2", "F", false}, // evicts 1/0, the least recently seen
+		{"1", "F", true},
+		{"1", "0", false},
+		{"2", "F", false}, // evicted by 1/0, as 1/F was seen more recently
+	}
+	for i, step := range steps {
+		if seen := dedup.Seen(step.execID, step.execType); seen != step.seen {
+			t.Fatalf("step %d: Seen(%s, %s) = %t, want %t", i, step.execID, step.execType, seen, step.seen)
+		}
+	}
+}
+
+func TestExecDedupRestore(t *testing.T) {
+	dedup := NewExecDedup(3)
+	for _, execID := range []string{"1", "2", "3", "2"} {
+		dedup.Seen(execID, "F")
+	}
+	keys := dedup.Keys()
+	if want := []string{"1/F", "3/F", "2/F"}; !slices.Equal(keys, want) {
+		t.Fatalf("Keys() = %v, want %v", keys, want)
+	}
+
+	// A smaller cache keeps the most recent keys
+	restored := NewExecDedup(2)
+	restored.Restore(keys)
+	if want := []string{"3/F", "2/F"}; !slices.Equal(restored.Keys(), want) {
+		t.Fatalf("restored Keys() = %v, want %v", restored.Keys(), want)
+	}
+	if !restored.Seen("2", "F") || restored.Seen("1", "F") {
+		t.Fatal("restored cache lost its order")
+	}
+}
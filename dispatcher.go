@@ -0,0 +1,120 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// Dispatcher hands parsed ExecutionReports to a handler on a bounded pool of
+// worker goroutines so the quickfix session goroutine is not blocked by
+// application code.
+//
+// Ordering: each worker owns a FIFO queue and reports are sharded onto workers
+// by a hash of their ClOrdID (falling back to OrderID when ClOrdID is absent).
+// Reports for the same ClOrdID are therefore always handled one at a time and in
+// the order they arrived from the session. Reports for different ClOrdIDs may be
+// handled concurrently and in any relative order; this includes the old and new
+// ClOrdID of a cancel/replace. When a worker's queue is full Dispatch blocks
+// rather than dropping, so the guarantee holds under load at the cost of
+// back-pressure on the session goroutine.
+type Dispatcher struct {
+	handler func(report ExecutionReport)
+	queues  []chan ExecutionReport
+	wg      sync.WaitGroup
+
+	// mu is held for reading while a report is queued, so Close waits for
+	// Dispatch rather than closing a queue under it
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewDispatcher starts workers goroutines, each buffering up to queueSize reports
+func NewDispatcher(workers, queueSize int, handler func(report ExecutionReport)) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	d := &Dispatcher{
+		handler: handler,
+		queues:  make([]chan ExecutionReport, workers),
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan ExecutionReport, queueSize)
+		d.wg.Add(1)
+		go d.work(d.queues[i])
+	}
+	return d
+}
+
+func (d *Dispatcher) work(queue chan ExecutionReport) {
+	defer d.wg.Done()
+	for report := range queue {
+		d.handler(report)
+	}
+}
+
+// Dispatch queues report on the worker owning its ClOrdID. Once the
+// Dispatcher is closed, report is handled on the caller after the queued ones.
+func (d *Dispatcher) Dispatch(report ExecutionReport) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		d.wg.Wait()
+		d.handler(report)
+		return
+	}
+
+	key := report.ClOrdID
+	if key == "" {
+		key = report.OrderID
+	}
+	d.queues[d.shard(key)] <- report
+}
+
+func (d *Dispatcher) shard(key string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(d.queues)))
+}
+
+// Depths returns the number of reports waiting in each worker's queue
+func (d *Dispatcher) Depths() []int {
+	depths := make([]int, len(d.queues))
+	for i, queue := range d.queues {
+		depths[i] = len(queue)
+	}
+	return depths
+}
+
+// Close stops queueing reports and waits for queued reports to be handled.
+// Closing again does nothing.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.mu.Unlock()
+	d.wg.Wait()
+}
@@ -0,0 +1,122 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDispatcherPerOrderOrdering(t *testing.T) {
+	const orders, reportsPerOrder = 16, 200
+
+	var mu sync.Mutex
+	seen := make(map[string][]int)
+
+	d := NewDispatcher(4, 8, func(report ExecutionReport) {
+		seq, _ := strconv.Atoi(report.Quantity)
+		mu.Lock()
+		seen[report.ClOrdID] = append(seen[report.ClOrdID], seq)
+		mu.Unlock()
+	})
+
+	for seq := 0; seq < reportsPerOrder; seq++ {
+		for order := 0; order < orders; order++ {
+			d.Dispatch(ExecutionReport{ClOrdID: "order-" + strconv.Itoa(order), Quantity: strconv.Itoa(seq)})
+		}
+	}
+	d.Close()
+
+	if len(seen) != orders {
+		t.Fatalf("expected reports for %d orders, got %d", orders, len(seen))
+	}
+	for clOrdID, seqs := range seen {
+		if len(seqs) != reportsPerOrder {
+			t.Fatalf("%s: expected %d reports, got %d", clOrdID, reportsPerOrder, len(seqs))
+		}
+		for i, seq := range seqs {
+			if seq != i {
+				t.Fatalf("%s: report %d delivered at position %d", clOrdID, seq, i)
+			}
+		}
+	}
+}
+
+func TestManagerCloseDrainsDispatchers(t *testing.T) {
+	var mu sync.Mutex
+	handled := 0
+	app := &FixApplication{}
+	// A negative queue size must not panic, it just leaves the queues unbuffered
+	app.Dispatcher = NewDispatcher(1, -1, func(report ExecutionReport) {
+		mu.Lock()
+		handled++
+		mu.Unlock()
+	})
+	stop := make(chan struct{})
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app, stop: stop}); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Dispatcher.Dispatch(ExecutionReport{ClOrdID: "1"})
+	manager.Close()
+	manager.Close()
+	select {
+	case <-stop:
+	default:
+		t.Error("the tenant's watchers were not stopped")
+	}
+
+	// A report racing the shutdown is handled rather than sent on a closed queue
+	app.Dispatcher.Dispatch(ExecutionReport{ClOrdID: "2"})
+	mu.Lock()
+	defer mu.Unlock()
+	if handled != 2 {
+		t.Errorf("handled %d reports, want 2", handled)
+	}
+}
+
+func TestManagerRemoveStopsTenant(t *testing.T) {
+	stop := make(chan struct{})
+	app := &FixApplication{Dispatcher: NewDispatcher(1, 1, func(report ExecutionReport) {})}
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app, stop: stop}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"desk", false},
+		{"desk", true}, // already removed
+		{"other", true},
+	}
+	for _, tt := range tests {
+		if err := manager.Remove(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("Remove(%s) = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+	select {
+	case <-stop:
+	default:
+		t.Error("Remove left the tenant's watchers running")
+	}
+	if err := manager.Backoff("desk", time.Second); err == nil {
+		t.Error("Backoff of a removed tenant succeeded")
+	}
+}
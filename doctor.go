@@ -0,0 +1,142 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+// DoctorCheck is the outcome of one connectivity doctor check
+type DoctorCheck struct {
+	Name   string
+	Detail string
+	Err    error
+}
+
+// credentialSettings are the credentials the client logs on with, by setting
+// and the environment variable that overrides it
+var credentialSettings = [][2]string{
+	{"AccessKey", "ACCESS_KEY"},
+	{"SigningKey", "SIGNING_KEY"},
+	{"Passphrase", "PASSPHRASE"},
+	{"PortfolioId", "PORTFOLIO_ID"},
+}
+
+// RunDoctor checks that the tenant can connect to Prime: its credentials are
+// set and readable, its store and log directories are writable, its CompIDs
+// and host fit its Environment, and its FIX host accepts connections, over
+// TLS when SSLEnable=Y. It stops short of logging on.
+func RunDoctor(config TenantConfig, timeout time.Duration) []DoctorCheck {
+	settings := config.Settings.GlobalSettings()
+	var checks []DoctorCheck
+
+	for _, credential := range credentialSettings {
+		if credential[0] == "SigningKey" && signingBackend(settings) != SigningBackendKey {
+			continue // the key stays in KMS or the HSM
+		}
+		check := DoctorCheck{Name: "credential " + credential[0]}
+		value, ok := os.LookupEnv(credential[1])
+		if ok {
+			check.Detail = "from " + credential[1]
+		} else {
+			value, _ = settings.Setting(credential[0])
+		}
+		if value, err := resolveSecret(value); err != nil {
+			check.Err = err
+		} else if value == "" {
+			check.Err = fmt.Errorf("%s is not set", credential[0])
+		}
+		checks = append(checks, check)
+	}
+
+	for _, setting := range []string{"FileStorePath", "FileLogPath"} {
+		dir, err := settings.Setting(setting)
+		if err != nil {
+			continue
+		}
+		checks = append(checks, DoctorCheck{Name: setting, Detail: dir, Err: checkWritableDir(dir)})
+	}
+
+	if environment, err := settings.Setting("Environment"); err == nil {
+		checks = append(checks, DoctorCheck{Name: "environment", Detail: environment, Err: ValidateEnvironment(settings)})
+	}
+
+	host, err := settings.Setting("SocketConnectHost")
+	if err != nil {
+		return append(checks, DoctorCheck{Name: "connect", Err: errors.New("SocketConnectHost is not set")})
+	}
+	port, err := settings.Setting("SocketConnectPort")
+	if err != nil {
+		return append(checks, DoctorCheck{Name: "connect", Err: errors.New("SocketConnectPort is not set")})
+	}
+	addr := net.JoinHostPort(host, port)
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	checks = append(checks, DoctorCheck{Name: "connect", Detail: addr, Err: err})
+	if err != nil {
+		return checks
+	}
+	defer conn.Close()
+
+	if useTLS, _ := settings.BoolSetting("SSLEnable"); useTLS {
+		conn.SetDeadline(time.Now().Add(timeout))
+		client := tls.Client(conn, &tls.Config{ServerName: host})
+		check := DoctorCheck{Name: "TLS handshake", Detail: host, Err: client.Handshake()}
+		if check.Err == nil {
+			state := client.ConnectionState()
+			check.Detail = fmt.Sprintf("%s, %s", host, tls.VersionName(state.Version))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkWritableDir creates dir if needed and checks a file can be written in it
+func checkWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// printDoctorChecks writes one line per check, returning whether all passed
+func printDoctorChecks(w io.Writer, checks []DoctorCheck) bool {
+	ok := true
+	for _, check := range checks {
+		status := "ok  "
+		if check.Err != nil {
+			status, ok = "FAIL", false
+		}
+		line := status + " " + check.Name
+		if check.Detail != "" {
+			line += " (" + check.Detail + ")"
+		}
+		if check.Err != nil {
+			line += ": " + check.Err.Error()
+		}
+		fmt.Fprintln(w, line)
+	}
+	return ok
+}
@@ -0,0 +1,111 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDraining is returned for orders placed while a tenant drains before logging out
+var ErrDraining = errors.New("session is draining for maintenance, not accepting orders")
+
+// DrainOptions configures Drain
+type DrainOptions struct {
+	// Cancel cancels the open orders, Pace apart, instead of only waiting
+	// for them to complete
+	Cancel bool
+	Pace   time.Duration
+	// Wait is how long to wait for open orders to complete before logging
+	// out regardless
+	Wait time.Duration
+	// Reason is sent as the Text of the Logout
+	Reason string
+}
+
+// DrainResult is the outcome of draining a tenant. Open holds the ClOrdIDs
+// still open when it logged out.
+type DrainResult struct {
+	Tenant  string
+	Cancels []CancelResult `json:",omitempty"`
+	Open    []string       `json:",omitempty"`
+	Error   string         `json:",omitempty"`
+}
+
+// Draining reports whether the application is refusing orders to drain
+func (a *FixApplication) Draining() bool {
+	return a.draining.Load()
+}
+
+// Drain prepares the tenant name for maintenance: it stops accepting new
+// orders, cancels its open orders when opts.Cancel is set, waits up to
+// opts.Wait for them to complete, then logs out as Logout does. The tenant
+// accepts orders again once started.
+func (m *Manager) Drain(ctx context.Context, name string, opts DrainOptions) DrainResult {
+	result := DrainResult{Tenant: name}
+	tenant, ok := m.Get(name)
+	if !ok {
+		result.Error = fmt.Sprintf("unknown tenant %q", name)
+		return result
+	}
+	app := tenant.App
+	app.draining.Store(true)
+	app.logger().Printf("Draining: cancel=%v wait=%s", opts.Cancel, opts.Wait)
+
+	if opts.Cancel {
+		result.Cancels = app.CancelAll(CancelFilter{}, opts.Pace)
+	}
+
+	wait, cancel := context.WithTimeout(ctx, opts.Wait)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for len(openOrders(app)) > 0 && wait.Err() == nil {
+		select {
+		case <-ticker.C:
+		case <-wait.Done():
+		}
+	}
+	for _, order := range openOrders(app) {
+		result.Open = append(result.Open, order.ClOrdID)
+	}
+	if len(result.Open) > 0 {
+		app.logger().Printf("Logging out with %d orders still open", len(result.Open))
+	}
+
+	if err := m.Logout(ctx, name, opts.Reason); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// DrainAll drains every tenant at once, see Drain
+func (m *Manager) DrainAll(ctx context.Context, opts DrainOptions) []DrainResult {
+	tenants := m.Tenants()
+	results := make([]DrainResult, len(tenants))
+	var wg sync.WaitGroup
+	for i, tenant := range tenants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.Drain(ctx, tenant.Name, opts)
+		}()
+	}
+	wg.Wait()
+	return results
+}
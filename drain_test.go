@@ -0,0 +1,55 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDrainRefusesOrdersAndLogsOutWithOpenOrders(t *testing.T) {
+	app := &FixApplication{Tracker: NewOrderTracker(time.Minute), Paper: NewPaperVenue(nil, 1)}
+	app.Tracker.Add(Order{ClOrdID: "ord-1", Symbol: "ETH-USD"})
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app}); err != nil {
+		t.Fatal(err)
+	}
+
+	placed := make(chan struct{})
+	go func() {
+		defer close(placed)
+		time.Sleep(20 * time.Millisecond)
+		if _, err := app.PlaceOrder(OrderRequest{Symbol: "ETH-USD", Side: "BUY", OrdType: "LIMIT", Quantity: "1", LimitPrice: "100"}); !errors.Is(err, ErrDraining) {
+			t.Errorf("order placed while draining: %v", err)
+		}
+	}()
+	result := manager.Drain(context.Background(), "desk", DrainOptions{Wait: 50 * time.Millisecond, Reason: "maintenance"})
+	<-placed
+	if result.Error != "" || len(result.Open) != 1 || result.Open[0] != "ord-1" {
+		t.Fatalf("drain = %+v, want logged out with ord-1 open", result)
+	}
+	if !app.Draining() {
+		t.Fatal("drained tenant accepts orders before it is started")
+	}
+
+	if err := manager.Start("desk"); err != nil {
+		t.Fatal(err)
+	}
+	if app.Draining() {
+		t.Error("tenant still draining after start")
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// fixEnum translates between the wire codes of a FIX enum field and names
+type fixEnum struct {
+	names map[string]string // code -> name
+	codes map[string]string // name -> code
+}
+
+func newFixEnum(names map[string]string) fixEnum {
+	codes := make(map[string]string, len(names))
+	for code, name := range names {
+		codes[name] = code
+	}
+	return fixEnum{names: names, codes: codes}
+}
+
+// name returns the name of code, or code itself when it is not known
+func (e fixEnum) name(code string) string {
+	if name, ok := e.names[code]; ok {
+		return name
+	}
+	return code
+}
+
+func (e fixEnum) code(name string) (string, bool) {
+	code, ok := e.codes[name]
+	return code, ok
+}
+
+var (
+	sideEnum = newFixEnum(map[string]string{
+		"1": "BUY",
+		"2": "SELL",
+	})
+
+	ordStatusEnum = newFixEnum(map[string]string{
+		"0": "NEW",
+		"1": "PARTIALLY_FILLED",
+		"2": "FILLED",
+		"3": "DONE_FOR_DAY",
+		"4": "CANCELED",
+		"5": "REPLACED",
+		"6": "PENDING_CANCEL",
+		"7": "STOPPED",
+		"8": "REJECTED",
+		"A": "PENDING_NEW",
+		"C": "EXPIRED",
+		"E": "PENDING_REPLACE",
+	})
+
+	execTypeEnum = newFixEnum(map[string]string{
+		"0": "NEW",
+		"1": "PARTIAL_FILL",
+		"2": "FILL",
+		"3": "DONE_FOR_DAY",
+		"4": "CANCELED",
+		"5": "REPLACED",
+		"6": "PENDING_CANCEL",
+		"7": "STOPPED",
+		"8": "REJECTED",
+		"A": "PENDING_NEW",
+		"C": "EXPIRED",
+		"D": "RESTATED",
+		"E": "PENDING_REPLACE",
+		"F": "TRADE",
+		"I": "ORDER_STATUS",
+	})
+
+	timeInForceEnum = newFixEnum(map[string]string{
+		"0": "DAY",
+		"1": "GTC",
+		"3": "IOC",
+		"4": "FOK",
+		"6": "GTD",
+	})
+
+	cxlRejReasonEnum = newFixEnum(map[string]string{
+		"0":  "TOO_LATE_TO_CANCEL",
+		"1":  "UNKNOWN_ORDER",
+		"2":  "BROKER_OPTION",
+		"3":  "ALREADY_PENDING",
+		"99": "OTHER",
+	})
+)
+
+// SideName returns the name of a Side (54) code, e.g. "BUY" for "1"
+func SideName(code string) string { return sideEnum.name(code) }
+
+// SideCode returns the Side (54) code for a name such as "SELL"
+func SideCode(name string) (string, bool) { return sideEnum.code(name) }
+
+// OrdStatusName returns the name of an OrdStatus (39) code, e.g. "FILLED" for "2"
+func OrdStatusName(code string) string { return ordStatusEnum.name(code) }
+
+// OrdStatusCode returns the OrdStatus (39) code for a name
+func OrdStatusCode(name string) (string, bool) { return ordStatusEnum.code(name) }
+
+// ExecTypeName returns the name of an ExecType (150) code, e.g. "TRADE" for "F"
+func ExecTypeName(code string) string { return execTypeEnum.name(code) }
+
+// ExecTypeCode returns the ExecType (150) code for a name
+func ExecTypeCode(name string) (string, bool) { return execTypeEnum.code(name) }
+
+// TimeInForceName returns the name of a TimeInForce (59) code, e.g. "IOC" for "3"
+func TimeInForceName(code string) string { return timeInForceEnum.name(code) }
+
+// TimeInForceCode returns the TimeInForce (59) code for a name
+func TimeInForceCode(name string) (string, bool) { return timeInForceEnum.code(name) }
+
+// CxlRejReasonName returns the name of a CxlRejReason (102) code
+func CxlRejReasonName(code string) string { return cxlRejReasonEnum.name(code) }
+
+// CxlRejReasonCode returns the CxlRejReason (102) code for a name
+func CxlRejReasonCode(name string) (string, bool) { return cxlRejReasonEnum.code(name) }
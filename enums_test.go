@@ -0,0 +1,47 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestFixEnums(t *testing.T) {
+	tests := []struct {
+		field  string
+		nameOf func(string) string
+		codeOf func(string) (string, bool)
+		code   string
+		name   string
+	}{
+		{"Side", SideName, SideCode, "2", "SELL"},
+		{"OrdStatus", OrdStatusName, OrdStatusCode, "E", "PENDING_REPLACE"},
+		{"ExecType", ExecTypeName, ExecTypeCode, "F", "TRADE"},
+		{"TimeInForce", TimeInForceName, TimeInForceCode, "3", "IOC"},
+		{"CxlRejReason", CxlRejReasonName, CxlRejReasonCode, "99", "OTHER"},
+	}
+	for _, tt := range tests {
+		if got := tt.nameOf(tt.code); got != tt.name {
+			t.Errorf("%s name of %q = %q, want %q", tt.field, tt.code, got, tt.name)
+		}
+		if got, ok := tt.codeOf(tt.name); !ok || got != tt.code {
+			t.Errorf("%s code of %q = %q, %t, want %q", tt.field, tt.name, got, ok, tt.code)
+		}
+		if got := tt.nameOf("Z9"); got != "Z9" {
+			t.Errorf("%s name of unknown code = %q, want it unchanged", tt.field, got)
+		}
+		if got, ok := tt.codeOf("NOT_A_NAME"); ok {
+			t.Errorf("%s code of unknown name = %q", tt.field, got)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// envPrefix prefixes the environment variables that configure the client
+const envPrefix = "PRIMEFIX_"
+
+// envSettings are the settings that can be set from the environment, each as
+// envPrefix followed by the setting name in upper snake case, e.g.
+// SenderCompID as PRIMEFIX_SENDER_COMP_ID, and on the command line with -set
+var envSettings = []string{
+	// Session
+	"BeginString",
+	"SenderCompID",
+	"TargetCompID",
+	"SocketConnectHost",
+	"SocketConnectPort",
+	"HeartBtInt",
+	"ReconnectInterval",
+	"LogLevel",
+	"TextLogRedaction",
+	"StartTime",
+	"EndTime",
+	"ResetOnLogon",
+	"ResetOnLogout",
+	"ResetOnDisconnect",
+	"SSLEnable",
+	"SSLProtocols",
+	"ClientCertificateKeyFile",
+	"UseDataDictionary",
+	"DataDictionary",
+	"ValidateUserDefinedFields",
+	"ValidateIncomingMessage",
+	"FileStorePath",
+	"MessageStoreEncryption",
+	"ExecutionRetention",
+	"MessageArchiveRetention",
+	"TrackerArchiveRetention",
+	"SettlementPeriod",
+	"RetentionPurgeInterval",
+	"FileLogPath",
+
+	// Client
+	"ExecutionReportFastPathTags",
+	"AsyncDispatchWorkers",
+	"AsyncDispatchQueueSize",
+	"ExecutionStorePath",
+	"ExecutionWebhookURL",
+	"ExecutionOutboxPath",
+	"ExecutionWebhookCodec",
+	"BackfillResendWindow",
+	"ExecDedupCapacity",
+	"ResendPolicy",
+	"UnknownMessagePolicy",
+	"SymbolMapPath",
+	"OrderRulesPath",
+	"SymbolDefaultsPath",
+	"MessageDescriptorsPath",
+	"PaperTrading",
+	"PaperAckLatency",
+	"PaperFillLatency",
+	"PaperSlippageBps",
+	"PaperImpactBps",
+	"PaperDepth",
+	"PaperNoiseBps",
+	"PaperSeed",
+	"ShadowTenant",
+	"Environment",
+	"SandboxSenderCompIDPattern",
+	"ProductionSenderCompIDPattern",
+	"CanaryInterval",
+	"CanaryTimeout",
+	"CanarySymbol",
+	"CanarySide",
+	"CanaryQuantity",
+	"CanaryPrice",
+	"PendingRequestTimeout",
+	"ScheduledOrdersPath",
+	"MaxSymbolExposure",
+	"MaxPortfolioExposure",
+	"FatFingerNotional",
+	"MaxDailyNotional",
+	"MaxDailyOrders",
+	"DailyResetTime",
+	"DailyResetTimezone",
+	"DailyCountersPath",
+	"SlackWebhookURL",
+	"PagerDutyRoutingKey",
+	"AlertDedupWindow",
+	"RejectStormCount",
+	"RejectStormWindow",
+	"CircuitBreakerRejects",
+	"CircuitBreakerWindow",
+	"FillPriceBreakerPercent",
+	"RejectRetryMax",
+	"RejectRetryBackoff",
+	"LeaderLockPath",
+	"LeaderPollInterval",
+	"SnapshotPath",
+	"LogonMaxFailures",
+	"LogonBackoffBase",
+	"LogonBackoffMax",
+	"ConcurrentSessionMaxBounces",
+	"ConcurrentSessionWindow",
+	"MaintenanceWindows",
+	"MaintenanceTimezone",
+	"MaintenanceDrainLead",
+	"MaintenanceCancelOrders",
+	"StrictMode",
+	"Blotter",
+	"MaxMessagesPerSecond",
+	"SendQueueDepth",
+	"PrimeRestURL",
+	"BalancePreflight",
+	"RestCancelFallback",
+	"RestOrderIdTag",
+	"CorrelationIdTag",
+	"WireTapPath",
+	"WireTapHex",
+	"SessionStatsPath",
+	"SessionStatsInterval",
+	"MessageArchivePath",
+	"SessionJournalPath",
+	"SlowHandlerThreshold",
+	"DebugListenAddr",
+	"GatewayTokensPath",
+	"GatewayClientRoles",
+	"GatewayCertFile",
+	"GatewayKeyFile",
+	"GatewayClientCAFile",
+	"GatewayQuotas",
+	"FeatureAsyncDispatch",
+	"FeatureRestFallback",
+	"FeatureMarketData",
+	"FeatureBackfill",
+	"TrackerRetention",
+	"TrackerArchivePath",
+	"TrackerCacheSize",
+	"AlgoRetention",
+	"LogSampling",
+	"AccessKey",
+	"SigningKey",
+	"SigningBackend",
+	"KMSKeyId",
+	"KMSRegion",
+	"KMSEndpoint",
+	"PKCS11Command",
+	"PKCS11Output",
+	"Passphrase",
+	"PortfolioId",
+}
+
+// envDefaults seed the settings when there is no config file at all. They
+// match fix.cfg except that no data dictionary file is required.
+var envDefaults = []string{
+	"ConnectionType=initiator",
+	"BeginString=FIX.4.2",
+	"TargetCompID=COIN",
+	"SocketConnectPort=4198",
+	"HeartBtInt=30",
+	"ReconnectInterval=10",
+	"StartTime=00:00:00",
+	"EndTime=00:00:00",
+	"ResetOnLogon=Y",
+	"ResetOnLogout=N",
+	"ResetOnDisconnect=Y",
+	"SSLEnable=Y",
+	"SSLProtocols=Tls12",
+	"UseDataDictionary=N",
+	"ValidateUserDefinedFields=N",
+	"ValidateIncomingMessage=N",
+}
+
+// envName returns the environment variable for setting, e.g.
+// PRIMEFIX_SSL_ENABLE for SSLEnable
+func envName(setting string) string {
+	runes := []rune(setting)
+	var b strings.Builder
+	b.WriteString(envPrefix)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// flagOverrides are the settings given on the command line, see parseConfigFlags
+var flagOverrides = make(map[string]string)
+
+// configFlags are command line flags for the settings most often changed
+// per run; any setting of envSettings can be given with -set
+var configFlags = []struct{ name, setting, usage string }{
+	{"host", "SocketConnectHost", "FIX host to connect to"},
+	{"port", "SocketConnectPort", "FIX port to connect to"},
+	{"sender-comp-id", "SenderCompID", "SenderCompID to log on as"},
+	{"portfolio", "PortfolioId", "Prime portfolio to trade in"},
+	{"heartbeat", "HeartBtInt", "heartbeat interval in seconds"},
+	{"log-level", "LogLevel", "debug logs every FIX message, info none"},
+}
+
+// parseConfigFlags parses the client's command line into flagOverrides,
+// returning the config file to load
+func parseConfigFlags(args []string) (string, error) {
+	flags := flag.NewFlagSet("prime-fix-go", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file")
+	values := make(map[string]*string)
+	for _, f := range configFlags {
+		values[f.setting] = flags.String(f.name, "", f.usage+" ("+f.setting+")")
+	}
+	var set []string
+	flags.Func("set", "override a setting, as `Setting=value`; repeatable", func(value string) error {
+		setting, _, ok := strings.Cut(value, "=")
+		if !ok || !slices.Contains(envSettings, setting) {
+			return fmt.Errorf("%q does not name an overridable setting", value)
+		}
+		set = append(set, value)
+		return nil
+	})
+	tui := flags.Bool("tui", false, "show the live blotter on the terminal (Blotter=Y)")
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if flags.NArg() > 0 {
+		return "", fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	// Named flags win over -set, and later -set values over earlier ones
+	for _, value := range set {
+		setting, value, _ := strings.Cut(value, "=")
+		flagOverrides[setting] = value
+	}
+	if *tui {
+		flagOverrides["Blotter"] = "Y"
+	}
+	flags.Visit(func(f *flag.Flag) {
+		for _, cf := range configFlags {
+			if cf.name == f.Name {
+				flagOverrides[cf.setting] = *values[cf.setting]
+			}
+		}
+	})
+	return *config, nil
+}
+
+// configOverrides returns the settings set in the environment and on the
+// command line, the command line taking precedence
+func configOverrides() map[string]string {
+	overrides := make(map[string]string)
+	for _, setting := range envSettings {
+		if value, ok := os.LookupEnv(envName(setting)); ok {
+			overrides[setting] = value
+		}
+	}
+	for setting, value := range flagOverrides {
+		overrides[setting] = value
+	}
+	return overrides
+}
+
+// resolveConfig returns the config text of the file at path with the
+// environment and command line overrides applied in its [DEFAULT] section.
+// A setting resolves, in order of precedence, from:
+//
+//  1. a command line flag
+//  2. its PRIMEFIX_ environment variable
+//  3. the [SESSION] section of the file
+//  4. the [DEFAULT] section of the file
+//  5. envDefaults, only when there is no file
+//
+// Without a file the config is generated from envDefaults and the overrides
+// alone, which must then at least name the SenderCompID and SocketConnectHost.
+func resolveConfig(path string) (string, error) {
+	overrides := configOverrides()
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && len(overrides) > 0 {
+		for _, required := range []string{"SenderCompID", "SocketConnectHost"} {
+			if _, ok := overrides[required]; !ok {
+				return "", fmt.Errorf("no %s and neither %s nor a flag sets %s", path, envName(required), required)
+			}
+		}
+		return "[DEFAULT]\n" + strings.Join(envDefaults, "\n") + "\n" + envLines(overrides) + "[SESSION]\n", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// Drop overridden settings from every section so the overrides win
+	// over session specific values too
+	var b strings.Builder
+	hasDefault := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if key, _, ok := strings.Cut(line, "="); ok {
+			if _, overridden := overrides[strings.TrimSpace(key)]; overridden {
+				continue
+			}
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+		if strings.TrimSpace(line) == "[DEFAULT]" {
+			b.WriteString(envLines(overrides))
+			hasDefault = true
+		}
+	}
+	if !hasDefault && len(overrides) > 0 {
+		return "[DEFAULT]\n" + envLines(overrides) + b.String(), scanner.Err()
+	}
+	return b.String(), scanner.Err()
+}
+
+func envLines(overrides map[string]string) string {
+	var b strings.Builder
+	for _, setting := range sortedKeys(overrides) {
+		b.WriteString(setting + "=" + overrides[setting] + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fix.cfg")
+	config := "[DEFAULT]\nHeartBtInt=30\nReconnectInterval=10\nSocketConnectPort=4198\n" +
+		"[SESSION]\nBeginString=FIX.4.2\nSenderCompID=FILE\nTargetCompID=COIN\nSocketConnectHost=file.example.com\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("PRIMEFIX_SOCKET_CONNECT_HOST", "env.example.com")
+	t.Setenv("PRIMEFIX_HEART_BT_INT", "15")
+	t.Cleanup(func() { flagOverrides = make(map[string]string) })
+	configPath, err := parseConfigFlags([]string{"-config", path, "-heartbeat", "5", "-set", "HeartBtInt=10", "-set", "SocketConnectPort=4199"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configPath != path {
+		t.Fatalf("config path %q, want %q", configPath, path)
+	}
+
+	settings, err := LoadFIXConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := settings.SessionSettings()
+	if len(session) != 1 {
+		t.Fatalf("expected one session, got %d", len(session))
+	}
+	for _, s := range session {
+		for setting, want := range map[string]string{
+			"HeartBtInt":        "5",               // flag over -set and environment
+			"SocketConnectPort": "4199",            // -set over file
+			"SocketConnectHost": "env.example.com", // environment over session section
+			"ReconnectInterval": "10",              // file
+			"SenderCompID":      "FILE",
+		} {
+			if got, _ := s.Setting(setting); got != want {
+				t.Errorf("%s = %q, want %q", setting, got, want)
+			}
+		}
+	}
+}
+
+func TestConfigFlagsRejectUnknownSetting(t *testing.T) {
+	t.Cleanup(func() { flagOverrides = make(map[string]string) })
+	if _, err := parseConfigFlags([]string{"-set", "NoSuchSetting=1"}); err == nil {
+		t.Fatal("expected an error for an unknown setting")
+	}
+}
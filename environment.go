@@ -0,0 +1,100 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Prime environments a tenant can declare with Environment
+const (
+	EnvironmentSandbox    = "sandbox"
+	EnvironmentProduction = "production"
+)
+
+// hostEnvironment guesses the environment of a FIX host from its name. Hosts
+// given as an IP address or localhost, such as a TLS tunnel or wire tap, give
+// nothing away and return "".
+func hostEnvironment(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	switch {
+	case host == "localhost" || net.ParseIP(host) != nil:
+		return ""
+	case strings.Contains(host, "sandbox"):
+		return EnvironmentSandbox
+	case strings.HasSuffix(host, ".coinbase.com"):
+		return EnvironmentProduction
+	}
+	return ""
+}
+
+// ValidateEnvironment checks the CompIDs and host of a tenant belong to the
+// Environment it declares, so production credentials are never pointed at
+// the sandbox or the other way round. SenderCompIDs are matched against
+// SandboxSenderCompIDPattern and ProductionSenderCompIDPattern when set.
+// Tenants without an Environment are not checked.
+func ValidateEnvironment(settings *quickfix.SessionSettings) error {
+	environment, err := settings.Setting("Environment")
+	if err != nil {
+		return nil
+	}
+	var other string
+	switch environment {
+	case EnvironmentSandbox:
+		other = EnvironmentProduction
+	case EnvironmentProduction:
+		other = EnvironmentSandbox
+	default:
+		return fmt.Errorf("unknown Environment %q, want sandbox or production", environment)
+	}
+
+	var errs []error
+	if target, _ := settings.Setting("TargetCompID"); target != "COIN" {
+		errs = append(errs, fmt.Errorf("TargetCompID is %q, Prime's is COIN in every environment", target))
+	}
+
+	sender, _ := settings.Setting("SenderCompID")
+	patterns := make(map[string]*regexp.Regexp)
+	for _, env := range []string{EnvironmentSandbox, EnvironmentProduction} {
+		setting := strings.ToUpper(env[:1]) + env[1:] + "SenderCompIDPattern"
+		value, err := settings.Setting(setting)
+		if err != nil {
+			continue
+		}
+		if patterns[env], err = regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid %s: %w", setting, err)
+		}
+	}
+	own, theirs := patterns[environment], patterns[other]
+	switch {
+	case own != nil && own.MatchString(sender):
+	case theirs != nil && theirs.MatchString(sender):
+		errs = append(errs, fmt.Errorf("SenderCompID %s is a %s CompID but Environment=%s", sender, other, environment))
+	case own != nil:
+		errs = append(errs, fmt.Errorf("SenderCompID %s does not match the %s pattern %s", sender, environment, own))
+	}
+
+	host, _ := settings.Setting("SocketConnectHost")
+	if hostEnvironment(host) == other {
+		errs = append(errs, fmt.Errorf("SocketConnectHost %s is a %s host but Environment=%s", host, other, environment))
+	}
+	return errors.Join(errs...)
+}
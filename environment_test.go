@@ -0,0 +1,54 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestValidateEnvironment(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		settings map[string]string
+		want     string // empty when valid
+	}{
+		{"no environment", map[string]string{"SenderCompID": "PROD-1", "TargetCompID": "X"}, ""},
+		{"sandbox", map[string]string{"Environment": "sandbox", "SenderCompID": "SANDBOX-1", "TargetCompID": "COIN", "SocketConnectHost": "fix.prime.sandbox.coinbase.com"}, ""},
+		{"tunnel", map[string]string{"Environment": "production", "SenderCompID": "PROD-1", "TargetCompID": "COIN", "SocketConnectHost": "127.0.0.1"}, ""},
+		{"production CompID in sandbox", map[string]string{"Environment": "sandbox", "SenderCompID": "PROD-1", "TargetCompID": "COIN"}, "SenderCompID PROD-1 is a production CompID but Environment=sandbox"},
+		{"unknown CompID", map[string]string{"Environment": "production", "SenderCompID": "desk", "TargetCompID": "COIN"}, "does not match the production pattern"},
+		{"sandbox host in production", map[string]string{"Environment": "production", "SenderCompID": "PROD-1", "TargetCompID": "COIN", "SocketConnectHost": "fix.prime.sandbox.coinbase.com"}, "is a sandbox host but Environment=production"},
+		{"production host in sandbox", map[string]string{"Environment": "sandbox", "SenderCompID": "SANDBOX-1", "TargetCompID": "COIN", "SocketConnectHost": "fix.prime.coinbase.com"}, "is a production host"},
+		{"target", map[string]string{"Environment": "sandbox", "SenderCompID": "SANDBOX-1", "TargetCompID": "CB"}, "TargetCompID is \"CB\""},
+		{"unknown environment", map[string]string{"Environment": "staging"}, "unknown Environment"},
+	} {
+		settings := quickfix.NewSessionSettings()
+		settings.Set("SandboxSenderCompIDPattern", "^SANDBOX-")
+		settings.Set("ProductionSenderCompIDPattern", "^PROD-")
+		for key, value := range tc.settings {
+			settings.Set(key, value)
+		}
+		err := ValidateEnvironment(settings)
+		switch {
+		case tc.want == "" && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+			t.Errorf("%s: got %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SymbolSummary is the day's activity in one symbol
+type SymbolSummary struct {
+	Symbol         string
+	OrdersPlaced   int
+	OrdersRejected int
+	Fills          int
+	FilledQty      decimal.Decimal
+	FilledNotional decimal.Decimal
+	Fees           decimal.Decimal
+
+	// AvgSlippageBps is the notional weighted slippage of fills against the
+	// arrival mid of their order, in basis points, positive when worse than
+	// mid. Only fills of orders placed with an ArrivalMid are included.
+	AvgSlippageBps decimal.Decimal
+	// ImplementationShortfall is what the same fills cost over filling them
+	// at their arrival mids, in the quote currency
+	ImplementationShortfall decimal.Decimal
+
+	slippageNotional decimal.Decimal
+	slippageSum      decimal.Decimal
+}
+
+// EODReport summarizes one trading day from the execution store
+type EODReport struct {
+	Date    string
+	Symbols []SymbolSummary
+}
+
+// BuildEODReport summarizes the executions whose TransactTime falls on day in
+// loc. Executions without a TransactTime cannot be attributed and are skipped.
+func BuildEODReport(reports []ExecutionReport, day time.Time, loc *time.Location) EODReport {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1)
+
+	bySymbol := make(map[string]*SymbolSummary)
+	for _, report := range reports {
+		transactTime := reportTime(report)
+		if transactTime.IsZero() || transactTime.Before(start) || !transactTime.Before(end) {
+			continue
+		}
+
+		summary, ok := bySymbol[report.Symbol]
+		if !ok {
+			summary = &SymbolSummary{Symbol: report.Symbol}
+			bySymbol[report.Symbol] = summary
+		}
+
+		switch report.ExecType {
+		case "0": // New
+			summary.OrdersPlaced++
+		case "8": // Rejected
+			summary.OrdersRejected++
+		case "1", "2", "F": // Partial fill, Fill, Trade
+			qty, qtyErr := decimal.NewFromString(report.LastShares)
+			px, pxErr := decimal.NewFromString(report.LastPx)
+			if qtyErr != nil || pxErr != nil {
+				continue
+			}
+			notional := qty.Mul(px)
+			summary.Fills++
+			summary.FilledQty = summary.FilledQty.Add(qty)
+			summary.FilledNotional = summary.FilledNotional.Add(notional)
+			summary.Fees = summary.Fees.Add(report.Fee())
+
+			if mid, err := decimal.NewFromString(report.ArrivalMid); err == nil && mid.IsPositive() {
+				bps := px.Sub(mid).Div(mid).Mul(decimal.NewFromInt(10000))
+				if report.Side == "2" { // Sell
+					bps = bps.Neg()
+				}
+				summary.slippageSum = summary.slippageSum.Add(bps.Mul(notional))
+				summary.slippageNotional = summary.slippageNotional.Add(notional)
+				summary.ImplementationShortfall = summary.ImplementationShortfall.Add(bps.Mul(notional).Div(decimal.NewFromInt(10000)))
+			}
+		}
+	}
+
+	eod := EODReport{Date: start.Format("2006-01-02")}
+	for _, summary := range bySymbol {
+		if summary.slippageNotional.IsPositive() {
+			summary.AvgSlippageBps = summary.slippageSum.Div(summary.slippageNotional).Round(2)
+		}
+		eod.Symbols = append(eod.Symbols, *summary)
+	}
+	sort.Slice(eod.Symbols, func(i, j int) bool { return eod.Symbols[i].Symbol < eod.Symbols[j].Symbol })
+	return eod
+}
+
+// BuildSlippageReport returns the slippage of each order filled on day in
+// loc, see ExecutionQuality
+func BuildSlippageReport(reports []ExecutionReport, day time.Time, loc *time.Location) ExecutionQualityReport {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1)
+
+	quality := NewExecutionQuality(loc)
+	for _, report := range reports {
+		transactTime := reportTime(report)
+		if transactTime.IsZero() || transactTime.Before(start) || !transactTime.Before(end) {
+			continue
+		}
+		quality.OnExecutionReport(report, transactTime)
+	}
+	result := quality.Report()
+	result.Date = start.Format("2006-01-02")
+	return result
+}
+
+// reportTime returns the TransactTime of report, zero when it has none.
+// Records persisted before TransactedAt existed only have the raw string.
+func reportTime(report ExecutionReport) time.Time {
+	if !report.TransactedAt.IsZero() {
+		return report.TransactedAt
+	}
+	transactTime, _ := time.Parse(fixTimestampFormat, report.TransactTime)
+	return transactTime
+}
+
+// WriteJSON writes the report as indented JSON
+func (r EODReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes one row per symbol
+func (r EODReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"date", "symbol", "orders_placed", "orders_rejected", "fills", "filled_qty", "filled_notional", "fees", "avg_slippage_bps", "implementation_shortfall"})
+	for _, s := range r.Symbols {
+		writer.Write([]string{
+			r.Date,
+			s.Symbol,
+			strconv.Itoa(s.OrdersPlaced),
+			strconv.Itoa(s.OrdersRejected),
+			strconv.Itoa(s.Fills),
+			s.FilledQty.String(),
+			s.FilledNotional.String(),
+			s.Fees.String(),
+			s.AvgSlippageBps.String(),
+			s.ImplementationShortfall.String(),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
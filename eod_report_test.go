@@ -0,0 +1,63 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildEODReport(t *testing.T) {
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	reports := []ExecutionReport{
+		{Symbol: "ETH-USD", ExecType: "0", TransactedAt: at(9)},
+		{Symbol: "ETH-USD", ExecType: "F", LastShares: "2", LastPx: "100", Commission: "0.5", TransactedAt: at(10)},
+		{Symbol: "ETH-USD", ExecType: "F", LastShares: "1", LastPx: "110", TransactedAt: at(23)},
+		{Symbol: "BTC-USD", ExecType: "8", TransactedAt: at(11)},
+		{Symbol: "ETH-USD", ExecType: "F", LastShares: "5", LastPx: "100", TransactedAt: at(-1)}, // the day before
+		{Symbol: "ETH-USD", ExecType: "F", LastShares: "5", LastPx: "100"},                       // no TransactTime
+		{Symbol: "ETH-USD", ExecType: "F", LastShares: "bad", LastPx: "100", TransactedAt: at(12)},
+	}
+
+	eod := BuildEODReport(reports, day, time.UTC)
+	if eod.Date != "2025-06-02" || len(eod.Symbols) != 2 {
+		t.Fatalf("report %+v", eod)
+	}
+	tests := []struct {
+		got, want SymbolSummary
+	}{
+		{eod.Symbols[0], SymbolSummary{Symbol: "BTC-USD", OrdersRejected: 1}},
+		{eod.Symbols[1], SymbolSummary{Symbol: "ETH-USD", OrdersPlaced: 1, Fills: 2, FilledQty: decimal.NewFromInt(3), FilledNotional: decimal.NewFromInt(310), Fees: decimal.RequireFromString("0.5")}},
+	}
+	for _, tt := range tests {
+		got, want := tt.got, tt.want
+		if got.Symbol != want.Symbol || got.OrdersPlaced != want.OrdersPlaced || got.OrdersRejected != want.OrdersRejected || got.Fills != want.Fills ||
+			!got.FilledQty.Equal(want.FilledQty) || !got.FilledNotional.Equal(want.FilledNotional) || !got.Fees.Equal(want.Fees) {
+			t.Errorf("summary %+v, want %+v", got, want)
+		}
+	}
+
+	var csv strings.Builder
+	if err := eod.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[2], "2025-06-02,ETH-USD,1,0,2,3,310,0.5,") {
+		t.Errorf("csv:\n%s", csv.String())
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// OverflowPolicy decides what an EventQueue does when its consumer falls
+// behind and the buffer is full
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes Push wait for room, slowing the producer down
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest buffered event and counts it
+	OverflowDropOldest
+	// OverflowSpill appends events to a file on disk until the consumer
+	// catches up, keeping delivery order
+	OverflowSpill
+)
+
+// ParseOverflowPolicy parses "block", "drop-oldest" or "spill"
+func ParseOverflowPolicy(value string) (OverflowPolicy, error) {
+	switch value {
+	case "block":
+		return OverflowBlock, nil
+	case "drop-oldest":
+		return OverflowDropOldest, nil
+	case "spill":
+		return OverflowSpill, nil
+	}
+	return OverflowBlock, fmt.Errorf("unknown overflow policy %q", value)
+}
+
+// EventQueue delivers ExecutionReports to one consumer on its own goroutine
+// through a bounded buffer, so a slow sink never grows memory without bound
+// and, unless its policy is OverflowBlock, never holds up the FIX session
+type EventQueue struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	buf      []ExecutionReport
+	capacity int
+	policy   OverflowPolicy
+	dropped  uint64
+	closed   bool
+	done     chan struct{}
+	handler  func(report ExecutionReport)
+
+	// Spill file: events are appended at the end and read back from offset,
+	// with spilled counting the events not yet read back
+	spillPath string
+	spill     *os.File
+	offset    int64
+	spilled   int
+}
+
+// NewEventQueue starts a queue of capacity events delivering to handler.
+// spillPath names the overflow file and is only used by OverflowSpill.
+func NewEventQueue(capacity int, policy OverflowPolicy, spillPath string, handler func(report ExecutionReport)) (*EventQueue, error) {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if policy == OverflowSpill && spillPath == "" {
+		return nil, errors.New("the spill overflow policy needs a spill path")
+	}
+
+	q := &EventQueue{
+		capacity:  capacity,
+		policy:    policy,
+		done:      make(chan struct{}),
+		handler:   handler,
+		spillPath: spillPath,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	go q.run()
+	return q, nil
+}
+
+// Push queues report for delivery, applying the overflow policy when the buffer is full
+func (q *EventQueue) Push(report ExecutionReport) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	// Once spilling, everything goes to disk until it is drained so order holds
+	if q.spilled > 0 || len(q.buf) >= q.capacity {
+		switch q.policy {
+		case OverflowBlock:
+			for len(q.buf) >= q.capacity && !q.closed {
+				q.cond.Wait()
+			}
+		case OverflowDropOldest:
+			q.buf = q.buf[1:]
+			q.dropped++
+			if q.dropped == 1 || q.dropped%1000 == 0 {
+				log.Printf("Event queue overflow, %d events dropped so far", q.dropped)
+			}
+		case OverflowSpill:
+			if err := q.spillEvent(report); err != nil {
+				log.Println("Failed to spill event, dropping it:", err)
+				q.dropped++
+			}
+			q.cond.Broadcast()
+			return
+		}
+	}
+	q.buf = append(q.buf, report)
+	q.cond.Broadcast()
+}
+
+// Dropped returns how many events were discarded on overflow
+func (q *EventQueue) Dropped() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// Len returns the number of events waiting for delivery, in memory and on disk
+func (q *EventQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.buf) + q.spilled
+}
+
+// Close stops accepting events and waits for the queued ones to be delivered
+func (q *EventQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	<-q.done
+}
+
+func (q *EventQueue) run() {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		for len(q.buf) == 0 && q.spilled == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.buf) == 0 && q.spilled == 0 {
+			q.mu.Unlock()
+			if q.spill != nil {
+				q.spill.Close()
+				os.Remove(q.spillPath)
+			}
+			return
+		}
+
+		var report ExecutionReport
+		if len(q.buf) > 0 {
+			report = q.buf[0]
+			q.buf = q.buf[1:]
+		} else {
+			var err error
+			if report, err = q.unspillEvent(); err != nil {
+				log.Println("Failed to read spilled event, dropping the spill file:", err)
+				q.resetSpill()
+				q.mu.Unlock()
+				continue
+			}
+		}
+		q.cond.Broadcast()
+		q.mu.Unlock()
+
+		q.handler(report)
+	}
+}
+
+// spillEvent appends report to the spill file; callers must hold mu
+func (q *EventQueue) spillEvent(report ExecutionReport) error {
+	if q.spill == nil {
+		file, err := os.OpenFile(q.spillPath, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		q.spill = file
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if _, err := q.spill.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	if _, err := q.spill.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	q.spilled++
+	return nil
+}
+
+// unspillEvent reads the next spilled event; callers must hold mu
+func (q *EventQueue) unspillEvent() (ExecutionReport, error) {
+	var report ExecutionReport
+	if _, err := q.spill.Seek(q.offset, io.SeekStart); err != nil {
+		return report, err
+	}
+	line, err := bufio.NewReader(q.spill).ReadBytes('\n')
+	if err != nil {
+		return report, err
+	}
+	q.offset += int64(len(line))
+	q.spilled--
+	if q.spilled == 0 {
+		q.resetSpill()
+	}
+	return report, json.Unmarshal(line, &report)
+}
+
+// resetSpill empties the spill file once it has been read back
+func (q *EventQueue) resetSpill() {
+	q.spilled = 0
+	q.offset = 0
+	if q.spill != nil {
+		q.spill.Truncate(0)
+	}
+}
+
+// eventQueueSetting creates the EventQueue for the sink named name from its
+// <name>QueueSize, <name>Overflow and <name>SpillPath settings. The default
+// is a 1024 event queue that blocks when full.
+func eventQueueSetting(settings *quickfix.SessionSettings, name string, handler func(report ExecutionReport)) (*EventQueue, error) {
+	capacity, err := settings.IntSetting(name + "QueueSize")
+	if err != nil {
+		capacity = 1024
+	}
+	policy := OverflowBlock
+	if value, err := settings.Setting(name + "Overflow"); err == nil {
+		if policy, err = ParseOverflowPolicy(value); err != nil {
+			return nil, fmt.Errorf("%sOverflow: %w", name, err)
+		}
+	}
+	spillPath, _ := settings.Setting(name + "SpillPath")
+	return NewEventQueue(capacity, policy, spillPath, handler)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestEventQueueSpillKeepsOrder(t *testing.T) {
+	const reports = 500
+
+	release := make(chan struct{})
+	var seen []int
+	q, err := NewEventQueue(4, OverflowSpill, filepath.Join(t.TempDir(), "spill.jsonl"), func(report ExecutionReport) {
+		<-release
+		seq, _ := strconv.Atoi(report.Quantity)
+		seen = append(seen, seq)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The consumer is stalled, so all but a handful of reports go to disk
+	for seq := 0; seq < reports; seq++ {
+		q.Push(ExecutionReport{Quantity: strconv.Itoa(seq)})
+	}
+	close(release)
+	q.Close()
+
+	if len(seen) != reports {
+		t.Fatalf("expected %d reports, got %d", reports, len(seen))
+	}
+	for i, seq := range seen {
+		if seq != i {
+			t.Fatalf("report %d delivered at position %d", seq, i)
+		}
+	}
+	if q.Dropped() != 0 {
+		t.Fatalf("expected no drops, got %d", q.Dropped())
+	}
+}
+
+func TestEventQueueDropOldest(t *testing.T) {
+	release := make(chan struct{})
+	var seen []string
+	q, _ := NewEventQueue(2, OverflowDropOldest, "", func(report ExecutionReport) {
+		<-release
+		seen = append(seen, report.ExecID)
+	})
+
+	q.Push(ExecutionReport{ExecID: "1"})
+	// Wait for the consumer to pick up the first report and stall on it
+	for q.Len() > 0 {
+		runtime.Gosched()
+	}
+	for _, execID := range []string{"2", "3", "4", "5"} {
+		q.Push(ExecutionReport{ExecID: execID})
+	}
+	close(release)
+	q.Close()
+
+	if q.Dropped() != 2 {
+		t.Fatalf("expected 2 drops, got %d", q.Dropped())
+	}
+	if want := []string{"1", "4", "5"}; len(seen) != len(want) || seen[1] != "4" || seen[2] != "5" {
+		t.Fatalf("expected %v, got %v", want, seen)
+	}
+}
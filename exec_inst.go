@@ -0,0 +1,98 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ExecInst is one value of the multi-value ExecInst (18) field
+type ExecInst string
+
+const (
+	// ExecInstPostOnly only adds liquidity; Prime rejects the order rather
+	// than let it take. This is the FIX "No cross" code.
+	ExecInstPostOnly ExecInst = "A"
+	ExecInstNoCross  ExecInst = ExecInstPostOnly
+
+	ExecInstParticipateDontInitiate ExecInst = "6"
+	ExecInstDoNotIncrease           ExecInst = "E"
+	ExecInstDoNotReduce             ExecInst = "F"
+	ExecInstAllOrNone               ExecInst = "G"
+)
+
+var execInstNames = map[ExecInst]string{
+	ExecInstPostOnly:                "PostOnly",
+	ExecInstParticipateDontInitiate: "ParticipateDontInitiate",
+	ExecInstDoNotIncrease:           "DoNotIncrease",
+	ExecInstDoNotReduce:             "DoNotReduce",
+	ExecInstAllOrNone:               "AllOrNone",
+}
+
+// execInstOrdTypes limits instructions to the order types they make sense on
+var execInstOrdTypes = map[ExecInst][]string{
+	ExecInstPostOnly:                {"LIMIT"},
+	ExecInstParticipateDontInitiate: {"LIMIT"},
+}
+
+// execInstConflicts are pairs of instructions that cannot be combined
+var execInstConflicts = [][2]ExecInst{
+	{ExecInstPostOnly, ExecInstParticipateDontInitiate},
+	{ExecInstPostOnly, ExecInstAllOrNone},
+}
+
+// String returns the name of the instruction
+func (e ExecInst) String() string {
+	if name, ok := execInstNames[e]; ok {
+		return name
+	}
+	return string(e)
+}
+
+// validateExecInst checks that insts are known, distinct, compatible with
+// each other and valid on ordType
+func validateExecInst(ordType string, insts []ExecInst) error {
+	seen := make(map[ExecInst]bool, len(insts))
+	for _, inst := range insts {
+		if _, ok := execInstNames[inst]; !ok {
+			return fmt.Errorf("unsupported ExecInst %q", string(inst))
+		}
+		if seen[inst] {
+			return fmt.Errorf("ExecInst %s given twice", inst)
+		}
+		seen[inst] = true
+
+		if ordTypes, ok := execInstOrdTypes[inst]; ok && !slices.Contains(ordTypes, ordType) {
+			return fmt.Errorf("ExecInst %s is not valid on %s orders", inst, ordType)
+		}
+	}
+	for _, pair := range execInstConflicts {
+		if seen[pair[0]] && seen[pair[1]] {
+			return fmt.Errorf("ExecInst %s cannot be combined with %s", pair[0], pair[1])
+		}
+	}
+	return nil
+}
+
+// formatExecInst joins insts into the space separated ExecInst (18) value
+func formatExecInst(insts []ExecInst) string {
+	values := make([]string, len(insts))
+	for i, inst := range insts {
+		values[i] = string(inst)
+	}
+	return strings.Join(values, " ")
+}
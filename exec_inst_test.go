@@ -0,0 +1,56 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestExecInst(t *testing.T) {
+	tests := []struct {
+		name    string
+		ordType string
+		insts   []ExecInst
+		wire    string // empty means tag 18 is not sent
+		wantErr bool
+	}{
+		{"post only", "LIMIT", []ExecInst{ExecInstPostOnly}, "A", false},
+		{"combined", "MARKET", []ExecInst{ExecInstDoNotIncrease, ExecInstAllOrNone}, "E G", false},
+		{"none after combined", "LIMIT", nil, "", false},
+		{"unknown", "LIMIT", []ExecInst{"Z"}, "", true},
+		{"repeated", "LIMIT", []ExecInst{ExecInstDoNotReduce, ExecInstDoNotReduce}, "", true},
+		{"post only on market", "MARKET", []ExecInst{ExecInstPostOnly}, "", true},
+		{"conflicting", "LIMIT", []ExecInst{ExecInstPostOnly, ExecInstAllOrNone}, "", true},
+	}
+	builder := newOrderBuilder("SENDER", "COIN")
+	for _, tt := range tests {
+		if err := validateExecInst(tt.ordType, tt.insts); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateExecInst = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if tt.wantErr {
+			continue
+		}
+		req := OrderRequest{Symbol: "ETH-USD", OrdType: tt.ordType, Side: "BUY", Quantity: "1", LimitPrice: "1000", ExecInst: tt.insts}
+		if got := bodyString(builder.build(req, "portfolio", time.Now()), quickfix.Tag(18)); got != tt.wire {
+			t.Errorf("%s: ExecInst = %q, want %q", tt.name, got, tt.wire)
+		}
+	}
+	if got := ExecInstNoCross.String(); got != "PostOnly" {
+		t.Errorf("String = %q", got)
+	}
+}
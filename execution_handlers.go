@@ -0,0 +1,59 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// ExecutionHandlers routes ExecutionReports to a handler per outcome, so
+// consumers need not switch on ExecType themselves. Nil handlers are skipped,
+// as are reports with no handler here, such as status answers.
+type ExecutionHandlers struct {
+	OnNew         func(report ExecutionReport)
+	OnPartialFill func(report ExecutionReport)
+	OnFill        func(report ExecutionReport)
+	OnCanceled    func(report ExecutionReport)
+	OnRejected    func(report ExecutionReport)
+	OnReplaced    func(report ExecutionReport)
+	OnExpired     func(report ExecutionReport)
+}
+
+// Handle calls the handler for the ExecType of report. Trades (F) are told
+// apart by OrdStatus: a trade that completes the order is a fill.
+func (h *ExecutionHandlers) Handle(report ExecutionReport) {
+	var handler func(ExecutionReport)
+	switch report.ExecType {
+	case "0": // New
+		handler = h.OnNew
+	case "1": // Partial fill
+		handler = h.OnPartialFill
+	case "2": // Fill
+		handler = h.OnFill
+	case "F": // Trade
+		if report.OrdStatus == "2" {
+			handler = h.OnFill
+		} else {
+			handler = h.OnPartialFill
+		}
+	case "4": // Canceled
+		handler = h.OnCanceled
+	case "8": // Rejected
+		handler = h.OnRejected
+	case "5": // Replaced
+		handler = h.OnReplaced
+	case "C": // Expired
+		handler = h.OnExpired
+	}
+	if handler != nil {
+		handler(report)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MidBook keeps the latest mid of each symbol from the quotes fed to OnQuote,
+// so orders can be stamped with the mid at the moment they are submitted
+type MidBook struct {
+	mu   sync.Mutex
+	mids map[string]decimal.Decimal
+}
+
+// NewMidBook creates an empty MidBook
+func NewMidBook() *MidBook {
+	return &MidBook{mids: make(map[string]decimal.Decimal)}
+}
+
+// OnQuote records the mid of bid and ask, ignoring one-sided quotes
+func (b *MidBook) OnQuote(symbol string, bid, ask decimal.Decimal) {
+	if !bid.IsPositive() || !ask.IsPositive() {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mids[symbol] = bid.Add(ask).Div(decimal.NewFromInt(2))
+}
+
+// Mid returns the latest mid of symbol
+func (b *MidBook) Mid(symbol string) (decimal.Decimal, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	mid, ok := b.mids[symbol]
+	return mid, ok
+}
+
+// OrderSlippage is how the fills of one order compare with its arrival mid
+type OrderSlippage struct {
+	OrderID    string
+	ClOrdID    string
+	Symbol     string
+	Side       string
+	ArrivalMid decimal.Decimal
+	FilledQty  decimal.Decimal
+	AvgPx      decimal.Decimal
+
+	// SlippageBps is AvgPx against ArrivalMid in basis points, positive when
+	// worse than mid
+	SlippageBps decimal.Decimal
+	// Shortfall is what the fills cost over filling all of them at the
+	// arrival mid, in the quote currency
+	Shortfall decimal.Decimal
+
+	notional decimal.Decimal
+}
+
+func (o *OrderSlippage) add(qty, px decimal.Decimal) {
+	o.FilledQty = o.FilledQty.Add(qty)
+	o.notional = o.notional.Add(qty.Mul(px))
+	o.AvgPx = o.notional.Div(o.FilledQty)
+
+	shortfall := o.notional.Sub(o.FilledQty.Mul(o.ArrivalMid))
+	if o.Side == "2" { // Sell
+		shortfall = shortfall.Neg()
+	}
+	o.Shortfall = shortfall
+	o.SlippageBps = shortfall.Div(o.FilledQty.Mul(o.ArrivalMid)).Mul(decimal.NewFromInt(10000)).Round(2)
+}
+
+// ExecutionQuality follows the slippage of every order filled during the
+// trading day, starting afresh with the first fill of a new day. Only orders
+// placed with an ArrivalMid are followed.
+type ExecutionQuality struct {
+	mu     sync.Mutex
+	loc    *time.Location
+	day    string
+	orders map[string]*OrderSlippage
+	keys   []string
+}
+
+// NewExecutionQuality creates an ExecutionQuality whose days run midnight to
+// midnight in loc
+func NewExecutionQuality(loc *time.Location) *ExecutionQuality {
+	return &ExecutionQuality{loc: loc, orders: make(map[string]*OrderSlippage)}
+}
+
+// OnExecutionReport adds the fill carried by report, made at time at
+func (q *ExecutionQuality) OnExecutionReport(report ExecutionReport, at time.Time) {
+	switch report.ExecType {
+	case "1", "2", "F": // Partial fill, Fill, Trade
+	default:
+		return
+	}
+	mid, err := decimal.NewFromString(report.ArrivalMid)
+	if err != nil || !mid.IsPositive() {
+		return
+	}
+	qty, qtyErr := decimal.NewFromString(report.LastShares)
+	px, pxErr := decimal.NewFromString(report.LastPx)
+	if qtyErr != nil || pxErr != nil || !qty.IsPositive() {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if day := at.In(q.loc).Format("2006-01-02"); day != q.day {
+		q.day = day
+		q.orders = make(map[string]*OrderSlippage)
+		q.keys = nil
+	}
+
+	// The OrderID stays the same across replaces, the ClOrdID does not
+	key := report.OrderID
+	if key == "" {
+		key = report.ClOrdID
+	}
+	order, ok := q.orders[key]
+	if !ok {
+		order = &OrderSlippage{OrderID: report.OrderID, ClOrdID: report.ClOrdID, Symbol: report.Symbol, Side: report.Side, ArrivalMid: mid}
+		q.orders[key] = order
+		q.keys = append(q.keys, key)
+	}
+	order.add(qty, px)
+}
+
+// ExecutionQualityReport is the slippage of the day's orders and the
+// implementation shortfall of each symbol
+type ExecutionQualityReport struct {
+	Date      string
+	Orders    []OrderSlippage
+	Shortfall map[string]decimal.Decimal
+}
+
+// Report returns the current day's orders in the order of their first fill
+func (q *ExecutionQuality) Report() ExecutionQualityReport {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	report := ExecutionQualityReport{Date: q.day, Shortfall: make(map[string]decimal.Decimal)}
+	for _, key := range q.keys {
+		order := q.orders[key]
+		report.Orders = append(report.Orders, *order)
+		report.Shortfall[order.Symbol] = report.Shortfall[order.Symbol].Add(order.Shortfall)
+	}
+	return report
+}
+
+// WriteJSON writes the report as indented JSON
+func (r ExecutionQualityReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes one row per order
+func (r ExecutionQualityReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"date", "order_id", "clordid", "symbol", "side", "arrival_mid", "filled_qty", "avg_px", "slippage_bps", "shortfall"})
+	for _, o := range r.Orders {
+		writer.Write([]string{
+			r.Date,
+			o.OrderID,
+			o.ClOrdID,
+			o.Symbol,
+			SideName(o.Side),
+			o.ArrivalMid.String(),
+			o.FilledQty.String(),
+			o.AvgPx.String(),
+			o.SlippageBps.String(),
+			o.Shortfall.String(),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
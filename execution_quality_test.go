@@ -0,0 +1,55 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestExecutionQualitySlippageAndShortfall(t *testing.T) {
+	quality := NewExecutionQuality(time.UTC)
+	day := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	fill := func(orderID, side, qty, px string) ExecutionReport {
+		return ExecutionReport{ExecType: "F", OrderID: orderID, ClOrdID: "c" + orderID, Symbol: "BTC-USD", Side: side, LastShares: qty, LastPx: px, ArrivalMid: "100"}
+	}
+	quality.OnExecutionReport(fill("1", "1", "1", "101"), day)
+	quality.OnExecutionReport(fill("1", "1", "1", "103"), day)
+	quality.OnExecutionReport(fill("2", "2", "2", "99"), day)
+	quality.OnExecutionReport(ExecutionReport{ExecType: "F", OrderID: "3", LastShares: "1", LastPx: "1"}, day) // no arrival mid
+
+	report := quality.Report()
+	if report.Date != "2024-01-02" || len(report.Orders) != 2 {
+		t.Fatalf("report %+v, want 2 orders on 2024-01-02", report)
+	}
+	buy, sell := report.Orders[0], report.Orders[1]
+	if !buy.AvgPx.Equal(decimal.NewFromInt(102)) || !buy.SlippageBps.Equal(decimal.NewFromInt(200)) || !buy.Shortfall.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("buy: avg %s slippage %sbps shortfall %s, want 102, 200bps, 4", buy.AvgPx, buy.SlippageBps, buy.Shortfall)
+	}
+	if !sell.SlippageBps.Equal(decimal.NewFromInt(100)) || !sell.Shortfall.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("sell: slippage %sbps shortfall %s, want 100bps, 2", sell.SlippageBps, sell.Shortfall)
+	}
+	if shortfall := report.Shortfall["BTC-USD"]; !shortfall.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("day's shortfall %s, want 6", shortfall)
+	}
+
+	// The next day starts afresh
+	quality.OnExecutionReport(fill("4", "1", "1", "100"), day.Add(24*time.Hour))
+	if report := quality.Report(); report.Date != "2024-01-03" || len(report.Orders) != 1 {
+		t.Fatalf("next day's report %+v, want 1 order on 2024-01-03", report)
+	}
+}
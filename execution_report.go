@@ -0,0 +1,163 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ExecutionReport holds the fields extracted from an inbound ExecutionReport (35=8)
+type ExecutionReport struct {
+	ExecID       string
+	ExecType     string
+	OrdStatus    string
+	OrderID      string
+	ClOrdID      string
+	OrigClOrdID  string
+	Symbol       string
+	Side         string
+	Quantity     string
+	Price        string
+	LastShares   string
+	LastPx       string
+	CumQty       string
+	AvgPx        string
+	OrdRejReason string
+	Text         string
+	TransactTime string
+	Commission   string
+	CommType     string
+	CommCurrency string
+	MiscFees     []MiscFee `json:",omitempty"`
+
+	// SentAt and TransactedAt are SendingTime (52) and TransactTime (60)
+	// parsed, zero when absent, and ReceivedAt is when the report was read
+	// off the socket by the local clock
+	SentAt       time.Time
+	TransactedAt time.Time
+	ReceivedAt   time.Time
+
+	// PossDup and PossResend mirror the header flags so handlers can tell
+	// replayed reports from originals
+	PossDup    bool
+	PossResend bool
+
+	// Metadata is the caller metadata of the tracked order the report belongs to
+	Metadata map[string]string `json:",omitempty"`
+	// ArrivalMid is the arrival mid of the tracked order, used for slippage
+	ArrivalMid string `json:",omitempty"`
+	// CorrelationId joins the report to the logs of the order it belongs to
+	CorrelationId string `json:",omitempty"`
+	// Tenant is the tenant whose session received the report
+	Tenant string `json:",omitempty"`
+}
+
+// parseExecutionReport fills report from msg, reading the raw field bytes
+// directly instead of going through a FieldValueReader per tag
+func parseExecutionReport(msg *quickfix.Message, report *ExecutionReport) {
+	report.ExecID = bodyString(msg, quickfix.Tag(17))        // ExecID
+	report.ExecType = bodyString(msg, quickfix.Tag(150))     // ExecType
+	report.OrdStatus = bodyString(msg, quickfix.Tag(39))     // OrdStatus
+	report.OrderID = bodyString(msg, quickfix.Tag(37))       // OrderID
+	report.ClOrdID = bodyString(msg, quickfix.Tag(11))       // Client Order ID
+	report.OrigClOrdID = bodyString(msg, quickfix.Tag(41))   // Original Client Order ID
+	report.Symbol = bodyString(msg, quickfix.Tag(55))        // Symbol
+	report.Side = bodyString(msg, quickfix.Tag(54))          // Side (Buy/Sell)
+	report.Quantity = bodyString(msg, quickfix.Tag(38))      // Order Quantity
+	report.Price = bodyString(msg, quickfix.Tag(44))         // Limit Price
+	report.LastShares = bodyString(msg, quickfix.Tag(32))    // Quantity of this fill
+	report.LastPx = bodyString(msg, quickfix.Tag(31))        // Price of this fill
+	report.CumQty = bodyString(msg, quickfix.Tag(14))        // Cumulative filled quantity
+	report.AvgPx = bodyString(msg, quickfix.Tag(6))          // Average fill price
+	report.OrdRejReason = bodyString(msg, quickfix.Tag(103)) // OrdRejReason
+	report.Text = bodyString(msg, quickfix.Tag(58))          // Text
+	report.TransactTime = bodyString(msg, quickfix.Tag(60))  // TransactTime
+	report.Commission = bodyString(msg, quickfix.Tag(12))    // Commission
+	report.CommType = bodyString(msg, quickfix.Tag(13))      // CommType
+	report.CommCurrency = bodyString(msg, quickfix.Tag(479)) // CommCurrency
+	report.MiscFees = parseMiscFees(msg)
+	report.PossDup, report.PossResend = resendFlags(msg)
+
+	report.SentAt, _ = msg.Header.GetTime(quickfix.Tag(52))     // SendingTime
+	report.TransactedAt, _ = msg.Body.GetTime(quickfix.Tag(60)) // TransactTime
+	report.ReceivedAt = msg.ReceiveTime
+}
+
+// ClockDelta returns how long after the venue sent the report it was received
+// locally: transit latency plus any offset between the two clocks. It is
+// zero when the report carried no SendingTime.
+func (r ExecutionReport) ClockDelta() time.Duration {
+	if r.SentAt.IsZero() {
+		return 0
+	}
+	return r.ReceivedAt.Sub(r.SentAt)
+}
+
+// bodyString returns the value of tag in the message body, or "" if it is absent
+func bodyString(msg *quickfix.Message, tag quickfix.Tag) string {
+	value, err := msg.Body.GetBytes(tag)
+	if err != nil {
+		return ""
+	}
+	return string(value)
+}
+
+// ExecutionReportView is an opt-in, allocation free alternative to
+// ExecutionReport for latency sensitive consumers. It extracts only the tags it
+// was created with, exposing their values as byte slices that alias the
+// message. A view is reused between messages, so its values are only valid
+// until the next call to Load.
+type ExecutionReportView struct {
+	tags   []quickfix.Tag
+	values [][]byte
+}
+
+// NewExecutionReportView creates a view extracting the given tags
+func NewExecutionReportView(tags ...quickfix.Tag) *ExecutionReportView {
+	return &ExecutionReportView{
+		tags:   tags,
+		values: make([][]byte, len(tags)),
+	}
+}
+
+// Load points the view at the configured tags of msg, clearing any tag the
+// message does not carry
+func (v *ExecutionReportView) Load(msg *quickfix.Message) {
+	for i, tag := range v.tags {
+		value, err := msg.Body.GetBytes(tag)
+		if err != nil {
+			value = nil
+		}
+		v.values[i] = value
+	}
+}
+
+// Get returns the value of tag from the last loaded message, or nil if the tag
+// was absent or is not part of the view
+func (v *ExecutionReportView) Get(tag quickfix.Tag) []byte {
+	for i, t := range v.tags {
+		if t == tag {
+			return v.values[i]
+		}
+	}
+	return nil
+}
+
+// Has reports whether the last loaded message carried tag
+func (v *ExecutionReportView) Has(tag quickfix.Tag) bool {
+	return v.Get(tag) != nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func parsedExecutionReport(tb testing.TB) *quickfix.Message {
+	report := quickfix.NewMessage()
+	report.Header.SetString(quickfix.Tag(8), "FIX.4.2")
+	report.Header.SetString(quickfix.Tag(35), "8")
+	report.Header.SetString(quickfix.Tag(49), "COIN")
+	report.Header.SetString(quickfix.Tag(56), "SENDER")
+	report.Header.SetInt(quickfix.Tag(34), 2)
+	report.Header.SetString(quickfix.Tag(52), "20250101-00:00:00.000")
+	report.Body.SetString(quickfix.Tag(1), "portfolio")
+	report.Body.SetString(quickfix.Tag(11), "1700000000000000000")
+	report.Body.SetString(quickfix.Tag(17), "exec-1")
+	report.Body.SetString(quickfix.Tag(37), "order-1")
+	report.Body.SetString(quickfix.Tag(38), "0.0015")
+	report.Body.SetString(quickfix.Tag(39), "0")
+	report.Body.SetString(quickfix.Tag(54), "1")
+	report.Body.SetString(quickfix.Tag(55), "ETH-USD")
+	report.Body.SetString(quickfix.Tag(150), "0")
+
+	msg := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(msg, bytes.NewBufferString(report.String())); err != nil {
+		tb.Fatal(err)
+	}
+	return msg
+}
+
+func BenchmarkParseExecutionReport(b *testing.B) {
+	msg := parsedExecutionReport(b)
+	var report ExecutionReport
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		parseExecutionReport(msg, &report)
+	}
+}
+
+func BenchmarkExecutionReportView(b *testing.B) {
+	msg := parsedExecutionReport(b)
+	view := NewExecutionReportView(quickfix.Tag(150), quickfix.Tag(37), quickfix.Tag(11), quickfix.Tag(54), quickfix.Tag(38))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		view.Load(msg)
+	}
+}
+
+func TestExecutionHandlersRouteByExecType(t *testing.T) {
+	var got []string
+	record := func(name string) func(ExecutionReport) {
+		return func(report ExecutionReport) { got = append(got, name+":"+report.ExecID) }
+	}
+	handlers := &ExecutionHandlers{
+		OnPartialFill: record("partial"),
+		OnFill:        record("fill"),
+		OnCanceled:    record("canceled"),
+		OnRejected:    record("rejected"),
+		OnReplaced:    record("replaced"),
+		OnExpired:     record("expired"),
+	}
+	for _, report := range []ExecutionReport{
+		{ExecID: "1", ExecType: "0"}, // no OnNew handler
+		{ExecID: "2", ExecType: "1", OrdStatus: "1"},
+		{ExecID: "3", ExecType: "F", OrdStatus: "1"},
+		{ExecID: "4", ExecType: "F", OrdStatus: "2"},
+		{ExecID: "5", ExecType: "2", OrdStatus: "2"},
+		{ExecID: "6", ExecType: "4"},
+		{ExecID: "7", ExecType: "8"},
+		{ExecID: "8", ExecType: "5"},
+		{ExecID: "9", ExecType: "C"},
+		{ExecID: "10", ExecType: "I", OrdStatus: "2"}, // status answers are not fills
+	} {
+		handlers.Handle(report)
+	}
+	want := []string{"partial:2", "partial:3", "fill:4", "fill:5", "canceled:6", "rejected:7", "replaced:8", "expired:9"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("handled %v, want %v", got, want)
+	}
+}
+
+func TestExecutionViewKeepsBookkeeping(t *testing.T) {
+	app := &FixApplication{
+		Tracker:       NewOrderTracker(time.Minute),
+		Dedup:         NewExecDedup(16),
+		ExecutionView: NewExecutionReportView(quickfix.Tag(150), quickfix.Tag(11)),
+	}
+	app.Tracker.Add(Order{ClOrdID: "1700000000000000000", Symbol: "ETH-USD", Side: "BUY", Quantity: "0.0015"})
+	var viewed []string
+	app.OnExecutionView = func(view *ExecutionReportView) {
+		order, _ := app.Tracker.Get(string(view.Get(quickfix.Tag(11))))
+		viewed = append(viewed, string(view.Get(quickfix.Tag(150)))+"/"+string(order.State))
+	}
+
+	msg := parsedExecutionReport(t)
+	for range 2 {
+		if err := app.FromApp(msg, quickfix.SessionID{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The view sees each report before the tracker does, and the resent
+	// duplicate is still dropped by the pipeline
+	if strings.Join(viewed, " ") != "0/PendingNew 0/New" {
+		t.Errorf("viewed %v", viewed)
+	}
+	if order, _ := app.Tracker.Get("1700000000000000000"); order.State != OrderNew || order.OrderID != "order-1" {
+		t.Errorf("tracked order = %+v", order)
+	}
+}
+
+func TestReportsCarryOrderMetadata(t *testing.T) {
+	app := &FixApplication{Tracker: NewOrderTracker(time.Minute)}
+	metadata := map[string]string{"strategy": "mean-revert", "ticket": "T-42"}
+	app.Tracker.Add(Order{ClOrdID: "1", Symbol: "ETH-USD", Side: "BUY", State: OrderNew, Metadata: metadata})
+
+	tests := []struct {
+		name   string
+		report ExecutionReport
+		want   string
+	}{
+		{"fill of the order", ExecutionReport{ClOrdID: "1", ExecType: "F"}, "T-42"},
+		{"cancel of the order", ExecutionReport{ClOrdID: "2", OrigClOrdID: "1", ExecType: "4"}, "T-42"},
+		{"untracked order", ExecutionReport{ClOrdID: "3", ExecType: "F"}, ""},
+	}
+	for _, tt := range tests {
+		report := tt.report
+		app.annotateFromOrder(&report)
+		if got := report.Metadata["ticket"]; got != tt.want {
+			t.Errorf("%s: ticket = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// Metadata stays client-side
+	msg := newOrderBuilder("SENDER", "COIN").build(OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "10", Metadata: metadata}, "portfolio", time.Now())
+	if text := msg.String(); strings.Contains(text, "T-42") || strings.Contains(text, "mean-revert") {
+		t.Errorf("order message carries metadata: %s", text)
+	}
+}
+
+func TestExecutionReportTimestamps(t *testing.T) {
+	sent := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		transactTime string // empty leaves TransactTime off
+		sendingTime  string // empty leaves SendingTime off
+		received     time.Time
+		wantTransact time.Time
+		wantDelta    time.Duration
+	}{
+		{"both stamps", "20241231-23:59:59.750", "20250101-00:00:00.000", sent.Add(40 * time.Millisecond),
+			sent.Add(-250 * time.Millisecond), 40 * time.Millisecond},
+		{"venue clock ahead", "", "20250101-00:00:00.000", sent.Add(-5 * time.Millisecond), time.Time{}, -5 * time.Millisecond},
+		{"no SendingTime", "", "", sent, time.Time{}, 0},
+		{"unparsable TransactTime", "yesterday", "20250101-00:00:00.000", sent, time.Time{}, 0},
+	}
+	for _, tt := range tests {
+		msg := parsedExecutionReport(t)
+		msg.Header.Remove(quickfix.Tag(52))
+		if tt.sendingTime != "" {
+			msg.Header.SetString(quickfix.Tag(52), tt.sendingTime)
+		}
+		if tt.transactTime != "" {
+			msg.Body.SetString(quickfix.Tag(60), tt.transactTime)
+		}
+		msg.ReceiveTime = tt.received
+
+		var report ExecutionReport
+		parseExecutionReport(msg, &report)
+		if !report.TransactedAt.Equal(tt.wantTransact) {
+			t.Errorf("%s: TransactedAt = %s, want %s", tt.name, report.TransactedAt, tt.wantTransact)
+		}
+		if got := report.ClockDelta(); got != tt.wantDelta {
+			t.Errorf("%s: ClockDelta = %s, want %s", tt.name, got, tt.wantDelta)
+		}
+	}
+}
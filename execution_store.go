@@ -0,0 +1,126 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// ExecutionStore persists ExecutionReports as JSON lines and indexes them by
+// ExecID, so executions replayed after a restart can be recognised
+type ExecutionStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	execIds map[string]struct{}
+}
+
+// OpenExecutionStore opens or creates the store at path, loading the ExecIDs
+// of every execution already persisted
+func OpenExecutionStore(path string) (*ExecutionStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ExecutionStore{path: path, file: file, execIds: make(map[string]struct{})}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var report ExecutionReport
+		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil {
+			file.Close()
+			return nil, err
+		}
+		s.execIds[report.ExecID] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Has reports whether an execution with execId has already been persisted
+func (s *ExecutionStore) Has(execId string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.execIds[execId]
+	return ok
+}
+
+// Append persists report
+func (s *ExecutionStore) Append(report ExecutionReport) error {
+	line, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	s.execIds[report.ExecID] = struct{}{}
+	return nil
+}
+
+// LoadExecutions reads every execution persisted in the store at path
+func LoadExecutions(path string) ([]ExecutionReport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reports []ExecutionReport
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var report ExecutionReport
+		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, scanner.Err()
+}
+
+// rewrite closes the file for f to replace it and reopens it after. ExecIDs
+// of executions f drops are still recognised until the next restart.
+func (s *ExecutionStore) rewrite(f func(path string) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	rewriteErr := f(s.path)
+	file, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	s.file = file
+	return rewriteErr
+}
+
+// Close closes the underlying file
+func (s *ExecutionStore) Close() error {
+	return s.file.Close()
+}
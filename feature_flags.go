@@ -0,0 +1,118 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Feature names an experimental subsystem that can be rolled out with a
+// Feature<Name>=Y or N setting
+type Feature string
+
+const (
+	// FeatureAsyncDispatch handles ExecutionReports on a worker pool, see
+	// AsyncDispatchWorkers
+	FeatureAsyncDispatch Feature = "AsyncDispatch"
+	// FeatureRestFallback cancels over the Prime REST API while the FIX
+	// session is down, see RestCancelFallback
+	FeatureRestFallback Feature = "RestFallback"
+	// FeatureMarketData reprices pegged orders from the quotes fed to the Repricer
+	FeatureMarketData Feature = "MarketData"
+	// FeatureBackfill requests a resend of recent messages on each logon, see
+	// BackfillResendWindow
+	FeatureBackfill Feature = "Backfill"
+)
+
+var features = []Feature{FeatureAsyncDispatch, FeatureRestFallback, FeatureMarketData, FeatureBackfill}
+
+// FeatureFlags holds the features explicitly switched on (true) or off
+// (false). A feature without a flag keeps its legacy behaviour: it runs when
+// its own settings configure it.
+type FeatureFlags map[Feature]bool
+
+// Enabled reports whether feature was switched on
+func (f FeatureFlags) Enabled(feature Feature) bool {
+	return f[feature]
+}
+
+// Disabled reports whether feature was switched off
+func (f FeatureFlags) Disabled(feature Feature) bool {
+	on, ok := f[feature]
+	return ok && !on
+}
+
+// active reports whether feature will run: switched on, or configured by
+// setting and not switched off
+func (f FeatureFlags) active(feature Feature, settings *quickfix.SessionSettings, setting string) bool {
+	return f.Enabled(feature) || !f.Disabled(feature) && settings.HasSetting(setting)
+}
+
+// LoadFeatureFlags reads the Feature<Name> settings of config, rejecting
+// unknown features and combinations that cannot work together
+func LoadFeatureFlags(config TenantConfig) (FeatureFlags, error) {
+	flags := make(FeatureFlags)
+	for _, line := range slices.Concat(config.defaults, config.session) {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		name, isFeature := strings.CutPrefix(key, "Feature")
+		if !ok || !isFeature {
+			continue
+		}
+		feature := Feature(name)
+		if !slices.Contains(features, feature) {
+			return nil, fmt.Errorf("unknown feature flag %s", key)
+		}
+		switch value {
+		case "Y":
+			flags[feature] = true
+		case "N":
+			flags[feature] = false
+		default:
+			return nil, fmt.Errorf("%s must be Y or N, got %q", key, value)
+		}
+	}
+	return flags, flags.validate(config.Settings.GlobalSettings())
+}
+
+// validate rejects combinations of features and settings that conflict
+func (f FeatureFlags) validate(settings *quickfix.SessionSettings) error {
+	var errs []error
+
+	if f.active(FeatureBackfill, settings, "BackfillResendWindow") {
+		if !settings.HasSetting("BackfillResendWindow") {
+			errs = append(errs, errors.New("FeatureBackfill requires BackfillResendWindow"))
+		}
+		if !settings.HasSetting("ExecutionStorePath") {
+			errs = append(errs, errors.New("FeatureBackfill requires ExecutionStorePath"))
+		}
+		// Resent executions carry PossResend, which the skip policy drops
+		if policy, _ := settings.Setting("ResendPolicy"); policy == "skip" {
+			errs = append(errs, errors.New("FeatureBackfill cannot be used with ResendPolicy=skip"))
+		}
+		// The rewind is from the persisted sequence numbers, which a reset discards
+		for _, reset := range []string{"ResetOnLogon", "ResetOnLogout", "ResetOnDisconnect"} {
+			if on, _ := settings.BoolSetting(reset); on {
+				errs = append(errs, fmt.Errorf("FeatureBackfill requires %s=N", reset))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadFeatureFlags(t *testing.T) {
+	tests := []struct {
+		settings string
+		err      string
+	}{
+		{"FeatureAsyncDispatch=Y\nFeatureMarketData=N", ""},
+		{"FeatureMarketdata=Y", "unknown feature flag"},
+		{"FeatureAsyncDispatch=yes", "must be Y or N"},
+		{"FeatureAsyncDispatch=Y\nExecutionReportFastPathTags=11,39", ""},
+		{"BackfillResendWindow=50", "requires ExecutionStorePath"},
+		{"BackfillResendWindow=50\nFeatureBackfill=N", ""},
+		{"FeatureBackfill=Y\nExecutionStorePath=x", "requires BackfillResendWindow"},
+		{"BackfillResendWindow=50\nExecutionStorePath=x\nResendPolicy=skip", "ResendPolicy=skip"},
+		{"BackfillResendWindow=50\nExecutionStorePath=x\nResetOnLogon=Y", "requires ResetOnLogon=N"},
+		{"BackfillResendWindow=50\nExecutionStorePath=x\nResetOnLogon=N\nResetOnDisconnect=Y", "requires ResetOnDisconnect=N"},
+		{"BackfillResendWindow=50\nExecutionStorePath=x\nResetOnLogon=N\nResetOnLogout=N\nResetOnDisconnect=N", ""},
+	}
+	for _, test := range tests {
+		tenants, err := splitTenantConfigs("[DEFAULT]\nBeginString=FIX.4.2\nTargetCompID=COIN\n" + test.settings + "\n[SESSION]\nSenderCompID=A\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		flags, err := LoadFeatureFlags(tenants[0])
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%q: %v", test.settings, err)
+		case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+			t.Errorf("%q: got %v, want %q", test.settings, err, test.err)
+		}
+		if test.err == "" && strings.Contains(test.settings, "FeatureMarketData=N") && !flags.Disabled(FeatureMarketData) {
+			t.Errorf("%q: MarketData not disabled", test.settings)
+		}
+	}
+}
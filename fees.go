@@ -0,0 +1,138 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+// MiscFee is one entry of the NoMiscFees (136) group
+type MiscFee struct {
+	Amount   string // MiscFeeAmt (137)
+	Currency string // MiscFeeCurr (138)
+	Type     string // MiscFeeType (139)
+}
+
+var miscFeesGroup = quickfix.GroupTemplate{
+	quickfix.GroupElement(quickfix.Tag(137)),
+	quickfix.GroupElement(quickfix.Tag(138)),
+	quickfix.GroupElement(quickfix.Tag(139)),
+}
+
+// parseMiscFees returns the misc fees carried by msg, or nil if it has none
+func parseMiscFees(msg *quickfix.Message) []MiscFee {
+	if !msg.Body.Has(quickfix.Tag(136)) {
+		return nil
+	}
+	group := quickfix.NewRepeatingGroup(quickfix.Tag(136), miscFeesGroup)
+	if err := msg.Body.GetGroup(group); err != nil {
+		return nil
+	}
+
+	fees := make([]MiscFee, 0, group.Len())
+	for i := 0; i < group.Len(); i++ {
+		entry := group.Get(i)
+		var fee MiscFee
+		fee.Amount, _ = entry.GetString(quickfix.Tag(137))
+		fee.Currency, _ = entry.GetString(quickfix.Tag(138))
+		fee.Type, _ = entry.GetString(quickfix.Tag(139))
+		fees = append(fees, fee)
+	}
+	return fees
+}
+
+// Fee returns the total fee charged on this execution: the commission,
+// converted to an amount according to CommType, plus any misc fees. Fees are
+// assumed to be in the quote currency of the symbol.
+func (r ExecutionReport) Fee() decimal.Decimal {
+	total := decimal.Zero
+
+	if commission, err := decimal.NewFromString(r.Commission); err == nil {
+		switch r.CommType {
+		case "1": // Per unit
+			qty, _ := decimal.NewFromString(r.LastShares)
+			commission = commission.Mul(qty)
+		case "2": // Percent of the fill notional
+			qty, _ := decimal.NewFromString(r.LastShares)
+			px, _ := decimal.NewFromString(r.LastPx)
+			commission = commission.Mul(qty).Mul(px).Div(decimal.NewFromInt(100))
+		}
+		total = total.Add(commission)
+	}
+
+	for _, fee := range r.MiscFees {
+		if amount, err := decimal.NewFromString(fee.Amount); err == nil {
+			total = total.Add(amount)
+		}
+	}
+	return total
+}
+
+// FeeTracker aggregates execution fees per order and per UTC day
+type FeeTracker struct {
+	mu       sync.Mutex
+	perOrder map[string]decimal.Decimal
+	perDay   map[string]decimal.Decimal
+}
+
+// NewFeeTracker creates an empty FeeTracker
+func NewFeeTracker() *FeeTracker {
+	return &FeeTracker{
+		perOrder: make(map[string]decimal.Decimal),
+		perDay:   make(map[string]decimal.Decimal),
+	}
+}
+
+// OnExecutionReport adds the fee of report to its order and day. Orders are
+// keyed by OrderID, which survives replaces, falling back to ClOrdID.
+func (t *FeeTracker) OnExecutionReport(report ExecutionReport, now time.Time) {
+	fee := report.Fee()
+	if fee.IsZero() {
+		return
+	}
+
+	key := report.OrderID
+	if key == "" {
+		key = report.ClOrdID
+	}
+	if !report.TransactedAt.IsZero() {
+		now = report.TransactedAt
+	}
+	day := now.UTC().Format("2006-01-02")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.perOrder[key] = t.perOrder[key].Add(fee)
+	t.perDay[day] = t.perDay[day].Add(fee)
+}
+
+// OrderFees returns the fees charged so far on the order with orderId (or the
+// ClOrdID of an order not yet acknowledged)
+func (t *FeeTracker) OrderFees(orderId string) decimal.Decimal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.perOrder[orderId]
+}
+
+// DayFees returns the fees charged on the UTC day of day
+func (t *FeeTracker) DayFees(day time.Time) decimal.Decimal {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.perDay[day.UTC().Format("2006-01-02")]
+}
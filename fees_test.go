@@ -0,0 +1,84 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+func TestExecutionReportFee(t *testing.T) {
+	tests := []struct {
+		name   string
+		report ExecutionReport
+		want   string
+	}{
+		{"absolute commission", ExecutionReport{Commission: "1.5", CommType: "3", LastShares: "2", LastPx: "100"}, "1.5"},
+		{"per unit", ExecutionReport{Commission: "0.1", CommType: "1", LastShares: "2", LastPx: "100"}, "0.2"},
+		{"percent of notional", ExecutionReport{Commission: "0.5", CommType: "2", LastShares: "2", LastPx: "100"}, "1"},
+		{"misc fees", ExecutionReport{Commission: "1", MiscFees: []MiscFee{{Amount: "0.25"}, {Amount: "0.25"}}}, "1.5"},
+		{"unparsable amounts", ExecutionReport{Commission: "n/a", MiscFees: []MiscFee{{Amount: ""}}}, "0"},
+		{"no fees", ExecutionReport{}, "0"},
+	}
+	for _, tt := range tests {
+		if got := tt.report.Fee(); !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("%s: Fee = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseMiscFees(t *testing.T) {
+	msg := quickfix.NewMessage()
+	if fees := parseMiscFees(msg); fees != nil {
+		t.Fatalf("fees %+v of a message without NoMiscFees", fees)
+	}
+
+	group := quickfix.NewRepeatingGroup(quickfix.Tag(136), miscFeesGroup)
+	entry := group.Add()
+	entry.SetString(quickfix.Tag(137), "0.3")
+	entry.SetString(quickfix.Tag(138), "USD")
+	entry.SetString(quickfix.Tag(139), "4")
+	msg.Body.SetGroup(group)
+	if fees := parseMiscFees(msg); len(fees) != 1 || fees[0] != (MiscFee{Amount: "0.3", Currency: "USD", Type: "4"}) {
+		t.Fatalf("fees %+v", fees)
+	}
+}
+
+func TestFeeTracker(t *testing.T) {
+	now := time.Date(2025, 6, 2, 23, 0, 0, 0, time.UTC)
+	fees := NewFeeTracker()
+	fees.OnExecutionReport(ExecutionReport{OrderID: "o-1", ClOrdID: "1", Commission: "1"}, now)
+	fees.OnExecutionReport(ExecutionReport{OrderID: "o-1", ClOrdID: "2", Commission: "2"}, now) // after a replace
+	fees.OnExecutionReport(ExecutionReport{ClOrdID: "3", Commission: "4", TransactedAt: now.Add(2 * time.Hour)}, now)
+
+	tests := []struct {
+		name string
+		got  decimal.Decimal
+		want int64
+	}{
+		{"order across a replace", fees.OrderFees("o-1"), 3},
+		{"unacknowledged order", fees.OrderFees("3"), 4},
+		{"day of receipt", fees.DayFees(now), 3},
+		{"day of the transaction", fees.DayFees(now.Add(2 * time.Hour)), 4},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equal(decimal.NewFromInt(tt.want)) {
+			t.Errorf("%s: fees %s, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -15,199 +15,1453 @@
 package main
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"prime-fix-go/auth"
+
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/datadictionary"
+	"github.com/shopspring/decimal"
 )
 
 type FixApplication struct {
+	// Tenant names the session in a multi-tenant process; it labels the
+	// application's logs, alerts and ExecutionReports. Logger, when set,
+	// is used for the application's logs instead of the standard logger.
+	Tenant string
+	Logger *log.Logger
+
+	// LogSampler, when set, limits which received and sent messages are
+	// logged by MsgType
+	LogSampler *LogSampler
+
+	// Redaction is how order memos appear in the message logs
+	Redaction TextRedaction
+
 	ApiKey       string
-	ApiSecret    string
-	Passphrase   string
+	Passphrase   *auth.Secret
 	TargetCompId string
 	PortfolioId  string
-	SessionId    quickfix.SessionID
+
+	// Signer signs the logon and REST requests, and holds the signing key
+	// when it is in process; nil signs with an empty key
+	Signer auth.Signer
+
+	session sessionState
+	stats   sessionCounters
+	latency latencyRecorder
+
+	statusQueries statusQueries
+	logout        logoutState
+	draining      atomic.Bool
+
+	// SlowHandlerThreshold, when positive, logs a warning whenever handling a
+	// received message holds up the session for longer
+	SlowHandlerThreshold time.Duration
+
+	// Clock is the time source for signing, message timestamps and ClOrdIDs;
+	// nil uses the wall clock
+	Clock Clock
+
+	// CorrelationTag, when set, carries each order's CorrelationId on the
+	// wire and is read back from its ExecutionReports
+	CorrelationTag quickfix.Tag
+
+	// Tracker, when set, follows every order placed through PlaceOrder and is
+	// required by CancelOrder and ReplaceOrder
+	Tracker   *OrderTracker
+	builder   *orderBuilder
+	builderMu sync.Mutex
+
+	// Algos, when set, aggregates the slice executions of TWAP and VWAP orders
+	Algos *AlgoTracker
+
+	// Scheduler holds orders queued for submission at a future time
+	Scheduler *OrderScheduler
+
+	// Brackets works entry/take-profit/stop bracket orders client-side
+	Brackets *BracketManager
+
+	// Icebergs slices large orders into smaller child limit orders
+	Icebergs *IcebergManager
+
+	// Baskets submits groups of orders together, see SubmitBasket
+	Baskets *BasketManager
+
+	// Repricer follows pegged limit orders as quotes are fed to it through OnQuote
+	Repricer *Repricer
+
+	// Mids, when set, keeps the mids of the quotes fed to OnQuote, and
+	// PlaceOrder stamps orders placed without an ArrivalMid with the current one
+	Mids *MidBook
+
+	// Quality follows the day's slippage against arrival mids
+	Quality *ExecutionQuality
+
+	// PriceBreakers, when set, block a symbol after a fill far from its
+	// reference price
+	PriceBreakers *PriceBreakers
+
+	// Paper, when set, takes the place of Prime: orders are filled by the
+	// simulated venue and never sent over the session
+	Paper *PaperVenue
+
+	// SendQueue, when set, holds outbound messages to the configured rate,
+	// letting cancels overtake waiting orders
+	SendQueue *SendQueue
+
+	// Strict halts the client on any Anomaly in what the venue reports
+	// instead of logging a warning and carrying on
+	Strict bool
+
+	// Strategy, when set, is fed every quote passed to OnQuote and every
+	// ExecutionReport, and trades through the application
+	Strategy Strategy
+
+	// Canary, when set, round trips a minimal order on a schedule in sandbox
+	Canary *Canary
+
+	// Messages registers the custom messages SendCustom may send, checked
+	// against Dictionary when it is set
+	Messages   MessageDescriptors
+	Dictionary *datadictionary.DataDictionary
+
+	// Shadow, when set, mirrors every order to a sandbox tenant or paper
+	// venue and compares the outcomes
+	Shadow *Shadow
+
+	// Positions nets fills per symbol; Risk blocks orders that would breach
+	// exposure limits on those positions
+	Positions *PositionTracker
+	Risk      *RiskChecker
+
+	// OrderIDs cross-references venue OrderIDs with REST order ids
+	OrderIDs *OrderIDMap
+
+	// RESTCancel, when set, sends cancels over the Prime REST API while the
+	// FIX session is down
+	RESTCancel *RESTCancelFallback
+
+	// Balances, when set, checks orders against the portfolio balance over REST
+	Balances *BalanceChecker
+
+	// Fees totals the commissions and fees reported on executions
+	Fees *FeeTracker
+
+	// Alerts, when set, is notified of session loss, logon failures, reject
+	// storms (RejectStormCount rejects within the rejects window) and stuck orders
+	Alerts           *Alerter
+	RejectStormCount int
+	rejects          *eventWindow
+
+	// Maintenance, when set, is the venue's maintenance calendar; sessions
+	// lost and logons rejected during a window are expected, not alerted
+	Maintenance *MaintenanceCalendar
+
+	// Retrier, when set, resubmits orders rejected for transient venue issues
+	Retrier *RejectRetrier
+
+	// Breaker, when set, blocks PlaceOrder after a burst of rejects until reset
+	Breaker *CircuitBreaker
+
+	// Venue, when set, tracks halted symbols and blocks orders in them
+	Venue *VenueStatus
+
+	// OnExecutionReport is called with every parsed ExecutionReport, on the
+	// Dispatcher's workers when one is set or inline on the session goroutine
+	OnExecutionReport func(report ExecutionReport)
+	Dispatcher        *Dispatcher
+
+	// Handlers, when set, receive every ExecutionReport by ExecType, after
+	// OnExecutionReport and on the same goroutine
+	Handlers *ExecutionHandlers
+
+	// OMS, when set, receives every ExecutionReport and the tracked order it
+	// updated through the FIX-agnostic OMS interfaces, alongside OnExecutionReport
+	OMS OMSAdapter
+
+	// Outbox, when set, durably records every new ExecutionReport before the
+	// message is acknowledged and delivers it to its sink exactly once by ExecID
+	Outbox *Outbox
+
+	// Sinks each receive every ExecutionReport through their own bounded
+	// queue, so a slow sink is handled by its overflow policy
+	Sinks []*EventQueue
+
+	// Defaults, when set, fill in the order parameters requests leave unset
+	// and cap the order quantity, per symbol
+	Defaults SymbolDefaults
+
+	// Rules, when set, are config-defined checks every order must pass before it is sent
+	Rules *OrderRules
+
+	// Features are the feature flags the application was configured with
+	Features FeatureFlags
+
+	// Quotas, when set, limits the orders each gateway client may place
+	// through POST /admin/orders
+	Quotas *GatewayQuotas
+
+	// Archive, when set, records every message sent and received
+	Archive *MessageArchive
+
+	// Journal, when set, records session lifecycle events
+	Journal *SessionJournal
+
+	// Executions, when set, persists every ExecutionReport and drops any whose
+	// ExecID has already been seen. BackfillWindow > 0 rewinds the inbound
+	// sequence number by that many messages on each logon, so the venue resends
+	// them and executions missed while down are recovered.
+	Executions     *ExecutionStore
+	BackfillWindow int
+
+	// Dedup drops ExecutionReports whose ExecID/ExecType was recently processed
+	Dedup *ExecDedup
+
+	// ResendPolicy decides what happens to app messages flagged PossDupFlag or PossResend
+	ResendPolicy ResendPolicy
+
+	// ExecutionView, when set, adds an allocation free fast path: the view's
+	// tags are extracted and handed to OnExecutionView before the report goes
+	// through the usual pipeline, so tracking, dedup, positions, risk, the
+	// outbox and sinks still see every ExecutionReport
+	ExecutionView   *ExecutionReportView
+	OnExecutionView func(view *ExecutionReportView)
+
+	// LogonGuard backs off between rejected logons and gives up before Prime
+	// locks the credentials out
+	LogonGuard *LogonGuard
+
+	// Sessions stops the reconnect loop of another instance sharing the session
+	Sessions *ConcurrentSessionDetector
+
+	// Symbols translates internal instrument identifiers to Prime symbols on
+	// the wire; nil sends symbols unchanged
+	Symbols *SymbolMapper
+
+	// UnknownPolicy decides what happens to app messages of unhandled types,
+	// which are handed to UnknownMessage under UnknownHandle
+	UnknownPolicy  UnknownMessagePolicy
+	UnknownMessage func(msgType string, msg *quickfix.Message)
+	unknown        unknownMessageCounts
 }
 
 func (a *FixApplication) OnCreate(sessionId quickfix.SessionID) {
-	log.Println("Session created:", sessionId)
-	a.SessionId = sessionId
+	a.logger().Println("Session created:", sessionId)
+	a.session.setId(sessionId)
+	a.journal(sessionId, SessionCreated, "")
 }
 
 func (a *FixApplication) OnLogon(sessionId quickfix.SessionID) {
-	log.Println(" Logged in:", sessionId)
-	a.SessionId = sessionId
+	a.logger().Println(" Logged in:", sessionId, "running", BuildInfo())
+	a.session.setLoggedOn(sessionId, true)
+	if a.stats.loggedOn(a.now()) > 1 {
+		a.journal(sessionId, SessionReconnect, "")
+	} else {
+		a.journal(sessionId, SessionLogon, "")
+	}
+	if a.LogonGuard != nil {
+		a.LogonGuard.Succeeded()
+	}
+	if a.Sessions != nil {
+		a.Sessions.LoggedOn(a.now())
+	}
 
-	order := createOrderMessage("ETH-USD", "LIMIT", "BUY", "0.0015", "1001", a.PortfolioId)
-	log.Println("Raw FIX Message:", order.String())
+	if a.RESTCancel != nil && a.Tracker != nil {
+		go a.RESTCancel.reconcile(a.Tracker)
+	}
 
-	// Send using session ID
-	err := quickfix.SendToTarget(order, sessionId)
+	// Settle orders left in an unknown state before anything is resubmitted
+	if a.Tracker != nil {
+		for _, order := range a.Tracker.Unknown() {
+			a.RequestStatus(order.ClOrdID)
+		}
+	}
+	// Submit scheduled orders that came due while logged out
+	if a.Scheduler != nil {
+		go a.Scheduler.Resume()
+	}
+
+	_, err := a.PlaceOrder(OrderRequest{
+		Symbol:     "ETH-USD",
+		OrdType:    "LIMIT",
+		Side:       "BUY",
+		Quantity:   "0.0015",
+		LimitPrice: "1001",
+	})
 	if err != nil {
-		log.Println("Failed to send order:", err)
+		a.logger().Println("Failed to send order:", err)
 	} else {
-		log.Println("Order sent successfully!")
+		a.logger().Println("Order sent successfully!")
 	}
 }
 
 func (a *FixApplication) OnLogout(sessionId quickfix.SessionID) {
-	log.Println("Logged out:", sessionId)
+	a.logger().Println("Logged out:", sessionId)
+	a.session.setLoggedOn(sessionId, false)
+	a.stats.loggedOut()
+	reason, initiated := a.logout.initiated()
+	switch {
+	case initiated:
+		a.journal(sessionId, SessionLogout, strings.TrimSuffix("initiated: "+reason, ": "))
+	case a.inMaintenance():
+		a.journal(sessionId, SessionLogout, "maintenance")
+	default:
+		a.journal(sessionId, SessionLogout, "")
+	}
+	if initiated || a.inMaintenance() {
+		return
+	}
+	a.alert("session-lost", "critical", "FIX session logged out: "+sessionId.String())
+	if a.Sessions != nil {
+		a.Sessions.LoggedOut(a.now())
+	}
 }
 
 func (a *FixApplication) ToAdmin(msg *quickfix.Message, sessionId quickfix.SessionID) {
-	log.Println("Sending Admin:", msg)
-
 	msgType, _ := msg.Header.GetString(quickfix.Tag(35))
+	switch msgType {
+	case "5": // Logout
+		a.stampLogoutReason(msg)
+	case "2": // ResendRequest for a gap in what Prime sent
+		a.journal(sessionId, SessionGapDetected, resendRange(msg))
+	}
+	a.logMessage("Sending Admin", msg)
+	a.stats.sent()
+	a.archive("out", msg) // before a Logon gets its credentials
+
 	if msgType == "A" { // Logon Message
-		timestamp := time.Now().UTC().Format("20060102-15:04:05.000")
-		seqNum := "1"
+		// Have the venue's Logon open a gap reaching back over the backfill window
+		if reset, _ := msg.Body.GetBool(quickfix.Tag(141)); a.BackfillWindow > 0 && !reset {
+			if begin, err := rewindForBackfill(sessionId, a.BackfillWindow); err != nil {
+				a.logger().Println("Failed to request backfill:", err)
+			} else {
+				a.logger().Println("Backfilling drop copy from message", begin)
+			}
+		}
 
-		// Generate HMAC signature for authentication
-		signature := sign(timestamp, "A", seqNum, a.ApiKey, a.TargetCompId, a.Passphrase, a.ApiSecret)
+		// Sign the SendingTime and MsgSeqNum the Logon goes out with
+		timestamp, _ := msg.Header.GetString(quickfix.Tag(52))
+		if timestamp == "" {
+			timestamp = a.now().UTC().Format(fixTimestampFormat)
+			msg.Header.SetString(quickfix.Tag(52), timestamp)
+		}
+		seqNum := logonSeqNum(msg)
+		passphrase, err := a.Passphrase.Reveal() // signed and sent as Password (554)
+		if err != nil {
+			a.logger().Println("Failed to read passphrase:", err)
+		}
+
+		// Generate HMAC signature for authentication; a remote signer must
+		// answer well within the logon timeout
+		ctx, cancel := context.WithTimeout(context.Background(), logonSigningTimeout)
+		signature, err := auth.SignLogon(ctx, a.signer(), auth.Logon{
+			SendingTime:  timestamp,
+			MsgType:      "A",
+			MsgSeqNum:    seqNum,
+			AccessKey:    a.ApiKey,
+			TargetCompID: a.TargetCompId,
+			Passphrase:   passphrase,
+		})
+		cancel()
+		if err != nil {
+			// Prime rejects the unsigned logon and the session retries
+			a.logger().Println("Failed to sign logon:", err)
+			a.alert("logon-signing", "critical", "Failed to sign FIX logon: "+err.Error())
+		}
 
 		// Add all required authentication fields
-		msg.Body.SetField(quickfix.Tag(1), quickfix.FIXString(a.PortfolioId))  // Account (Portfolio ID)
-		msg.Body.SetField(quickfix.Tag(96), quickfix.FIXString(signature))     // RawData (HMAC Signature)
-		msg.Body.SetField(quickfix.Tag(554), quickfix.FIXString(a.Passphrase)) // Password
-		msg.Body.SetField(quickfix.Tag(9406), quickfix.FIXString("Y"))         // DropCopyFlag (default "Y")
-		msg.Body.SetField(quickfix.Tag(9407), quickfix.FIXString(a.ApiKey))    // Access Key (API Key)
+		msg.Body.SetField(quickfix.Tag(1), quickfix.FIXString(a.PortfolioId)) // Account (Portfolio ID)
+		msg.Body.SetField(quickfix.Tag(96), quickfix.FIXString(signature))    // RawData (HMAC Signature)
+		msg.Body.SetField(quickfix.Tag(554), quickfix.FIXString(passphrase))  // Password
+		msg.Body.SetField(quickfix.Tag(9406), quickfix.FIXString("Y"))        // DropCopyFlag (default "Y")
+		msg.Body.SetField(quickfix.Tag(9407), quickfix.FIXString(a.ApiKey))   // Access Key (API Key)
 	}
 }
 
 func (a *FixApplication) FromAdmin(msg *quickfix.Message, sessionId quickfix.SessionID) quickfix.MessageRejectError {
-	log.Println("Received Admin:", msg)
+	defer a.observeLatency(msg, time.Now())
+	a.logMessage("Received Admin", msg)
+	a.archive("in", msg)
+
+	msgType, _ := msg.Header.GetString(quickfix.Tag(35))
+	a.stats.received(msgType, a.now())
+	if msgType == "5" && a.logout.onLogout() {
+		a.logger().Println("Logout confirmed:", bodyString(msg, quickfix.Tag(58)))
+	} else if msgType == "5" && a.inMaintenance() {
+		a.logger().Println("Logged out during Prime maintenance:", bodyString(msg, quickfix.Tag(58)))
+	} else if msgType == "5" && !a.IsLoggedOn() { // Logout before logon completed
+		a.journal(sessionId, SessionLogonRejected, bodyString(msg, quickfix.Tag(58)))
+		a.alert("logon-failure", "critical", "FIX logon rejected: "+bodyString(msg, quickfix.Tag(58)))
+		if a.LogonGuard != nil {
+			a.LogonGuard.Rejected()
+		}
+	} else if msgType == "2" {
+		a.journal(sessionId, SessionResendRequest, resendRange(msg))
+	} else if msgType == "4" {
+		a.journal(sessionId, SessionSequenceReset, "NewSeqNo="+bodyString(msg, quickfix.Tag(36)))
+	}
 	return nil
 }
 
 func (a *FixApplication) ToApp(msg *quickfix.Message, sessionId quickfix.SessionID) error {
-	log.Println("Sending App:", msg)
+	stampTransmitTime(msg, a.now())
+	a.logMessage("Sending App", msg)
+	a.stats.sent()
+	a.archive("out", msg)
 	return nil
 }
 
+// logMessage logs msg under label unless the LogSampler skips its MsgType
+func (a *FixApplication) logMessage(label string, msg *quickfix.Message) {
+	msgType, _ := msg.Header.GetString(quickfix.Tag(35))
+	if a.LogSampler.Sample(msgType) {
+		a.logger().Println(label+":", string(redactFIX([]byte(a.Redaction.Apply(msg.String())))))
+	}
+}
+
+// archive records msg in the message archive, if there is one
+func (a *FixApplication) archive(direction string, msg *quickfix.Message) {
+	if a.Archive == nil {
+		return
+	}
+	if err := a.Archive.Record(direction, msg, a.now()); err != nil {
+		a.logger().Println("Failed to archive message:", err)
+	}
+}
+
+// stampTransmitTime sets the time-sensitive fields of an outbound message at
+// the moment it is transmitted, so messages built ahead of time, queued or
+// retried never carry a stale SendingTime. A resent message keeps its original
+// TransactTime; the session records its original SendingTime itself.
+func stampTransmitTime(msg *quickfix.Message, now time.Time) {
+	timestamp := now.UTC().Format(fixTimestampFormat)
+	msg.Header.SetString(quickfix.Tag(52), timestamp) // SendingTime
+
+	if possDup, _ := resendFlags(msg); !possDup && msg.Body.Has(quickfix.Tag(60)) {
+		msg.Body.SetString(quickfix.Tag(60), timestamp) // TransactTime
+	}
+}
+
 func (a *FixApplication) FromApp(msg *quickfix.Message, sessionId quickfix.SessionID) quickfix.MessageRejectError {
-	log.Println("Received App:", msg)
+	defer a.observeLatency(msg, time.Now())
+	a.logMessage("Received App", msg)
+	a.stats.received("", a.now())
+	a.archive("in", msg)
+
+	if possDup, possResend := resendFlags(msg); (possDup || possResend) && a.ResendPolicy == ResendSkip {
+		a.logger().Println("Skipping possible duplicate app message")
+		return nil
+	}
 
 	msgType, _ := msg.Header.GetString(quickfix.Tag(35))
-	if msgType == "8" { // Execution Report
+	switch msgType {
+	case "9": // Order Cancel Reject
+		a.processCancelReject(msg)
+
+	case "f": // Security Status
+		if a.Venue != nil {
+			symbol := a.Symbols.ToInternal(bodyString(msg, quickfix.Tag(55)))
+			a.Venue.OnSecurityStatus(symbol, bodyString(msg, quickfix.Tag(326))) // SecurityTradingStatus
+		}
+
+	case "8": // Execution Report
+		if a.ExecutionView != nil {
+			a.ExecutionView.Load(msg)
+			if a.OnExecutionView != nil {
+				a.OnExecutionView(a.ExecutionView)
+			}
+		}
 		a.processExecutionReport(msg)
+
+	default:
+		return a.processUnknownMessage(msgType, msg)
 	}
 
 	return nil
 }
 
 func (a *FixApplication) processExecutionReport(msg *quickfix.Message) {
-	var execType, orderID, clOrdID, side, quantity quickfix.FIXString
+	var report ExecutionReport
+	parseExecutionReport(msg, &report)
+	if report.ReceivedAt.IsZero() {
+		report.ReceivedAt = a.now()
+	}
+	report.Symbol = a.Symbols.ToInternal(report.Symbol)
+	report.Tenant = a.Tenant
+	if a.CorrelationTag != 0 {
+		report.CorrelationId = parseCorrelationId(a.CorrelationTag, bodyString(msg, a.CorrelationTag))
+	}
+	if a.OrderIDs != nil {
+		a.OrderIDs.onExecutionReport(report.OrderID, msg)
+	}
+	a.annotateFromOrder(&report)
+	massStatus := a.statusQueries.offer(report, msg)
+	if an := decimalAnomaly(report); an != nil {
+		a.anomaly(an)
+	}
+
+	if a.Dedup != nil && report.ExecID != "" && a.Dedup.Seen(report.ExecID, report.ExecType) {
+		if report.PossDup || report.PossResend {
+			a.logger().Println("Skipping resent execution:", report.ExecID)
+		} else {
+			a.logger().Println("Skipping duplicate execution not flagged PossDup:", report.ExecID)
+		}
+		return
+	}
+
+	if a.Executions != nil && report.ExecID != "" {
+		if a.Executions.Has(report.ExecID) {
+			a.logger().Println("Skipping already persisted execution:", report.ExecID)
+			return
+		}
+		if err := a.Executions.Append(report); err != nil {
+			a.logger().Println("Failed to persist execution:", err)
+		}
+	}
+	if a.Outbox != nil {
+		if err := a.Outbox.Add(report); err != nil {
+			a.logger().Println("Failed to record execution in outbox:", err)
+		}
+	}
+
+	if a.ResendPolicy == ResendProcess {
+		report.PossDup, report.PossResend = false, false
+	}
+
+	if report.ExecType == "8" { // Rejected
+		a.logger().Printf("Order %s rejected: %s: %s", report.ClOrdID, report.RejectReason(), report.Text)
+		now := a.now()
+		if a.rejects != nil && a.rejects.add(now) >= a.RejectStormCount {
+			a.alert("reject-storm", "error", fmt.Sprintf("%d order rejects within %s", a.RejectStormCount, a.rejects.window))
+		}
+		if a.Breaker != nil {
+			a.Breaker.RecordReject(now)
+		}
+	}
+
+	if a.Tracker != nil {
+		// Mass status answers are expected for orders placed elsewhere
+		if _, tracked := a.Tracker.Get(report.ClOrdID); tracked || !massStatus {
+			a.Tracker.OnExecutionReport(report)
+		}
+	}
+	if a.Positions != nil {
+		a.Positions.OnExecutionReport(report)
+	}
+	if a.Venue != nil {
+		a.Venue.OnExecutionReport(report)
+	}
+	if a.Risk != nil {
+		a.Risk.OnExecutionReport(report, a.now())
+	}
+	if a.Fees != nil {
+		a.Fees.OnExecutionReport(report, a.now())
+	}
+	if a.Algos != nil {
+		a.Algos.OnExecutionReport(report, a.now())
+	}
+	if a.Brackets != nil {
+		a.Brackets.OnExecutionReport(report)
+	}
+	if a.Icebergs != nil {
+		a.Icebergs.OnExecutionReport(report)
+	}
+	if a.Baskets != nil {
+		a.Baskets.OnExecutionReport(report)
+	}
+	if a.Canary != nil {
+		a.Canary.OnExecutionReport(report)
+	}
+	if a.Shadow != nil {
+		a.Shadow.OnExecutionReport(report)
+	}
+	if a.Quality != nil {
+		a.Quality.OnExecutionReport(report, a.now())
+	}
+	if a.PriceBreakers != nil {
+		a.PriceBreakers.OnExecutionReport(report, a.now())
+	}
 
-	// Extract values from the message body
-	msg.Body.GetField(quickfix.Tag(150), &execType) // ExecType
-	msg.Body.GetField(quickfix.Tag(37), &orderID)   // OrderID
-	msg.Body.GetField(quickfix.Tag(11), &clOrdID)   // Client Order ID
-	msg.Body.GetField(quickfix.Tag(54), &side)      // Side (Buy/Sell)
-	msg.Body.GetField(quickfix.Tag(38), &quantity)  // Order Quantity
+	if a.Retrier != nil {
+		if retry, backoff, ok := a.Retrier.OnExecutionReport(report); ok {
+			clOrdID := report.ClOrdID
+			clockOrSystem(a.Clock).AfterFunc(backoff, func() { a.retryRejected(clOrdID, retry) })
+		}
+	}
 
+	for _, sink := range a.Sinks {
+		sink.Push(report)
+	}
+
+	if a.Dispatcher != nil {
+		a.Dispatcher.Dispatch(report)
+	} else {
+		a.handleExecutionReport(report)
+	}
+}
+
+// annotateFromOrder copies the client-side context of the tracked order report
+// refers to onto it
+func (a *FixApplication) annotateFromOrder(report *ExecutionReport) {
+	if a.Tracker == nil {
+		return
+	}
+	order, ok := a.Tracker.Get(report.ClOrdID)
+	if !ok {
+		order, ok = a.Tracker.Get(report.OrigClOrdID)
+	}
+	if !ok {
+		return
+	}
+	report.Metadata = order.Metadata
+	report.ArrivalMid = order.ArrivalMid
+	if report.CorrelationId == "" {
+		report.CorrelationId = order.CorrelationId
+	}
+}
+
+func (a *FixApplication) handleExecutionReport(report ExecutionReport) {
 	// Log execution report details
-	log.Printf("Execution Report: OrderID=%s ClOrdID=%s Side=%s Quantity=%s ExecType=%s",
-		orderID, clOrdID, side, quantity, execType)
+	a.logger().Printf("Execution Report: OrderID=%s ClOrdID=%s CorrelationId=%s Side=%s Quantity=%s ExecType=%s PossDup=%t ClockDelta=%s",
+		report.OrderID, report.ClOrdID, report.CorrelationId, SideName(report.Side), report.Quantity, ExecTypeName(report.ExecType), report.PossDup || report.PossResend, report.ClockDelta())
+
+	if a.OnExecutionReport != nil {
+		a.OnExecutionReport(report)
+	}
+	if a.Handlers != nil {
+		a.Handlers.Handle(report)
+	}
+	if a.Strategy != nil {
+		a.Strategy.OnExecution(a, report)
+	}
+	if a.OMS != nil {
+		a.OMS.OnExecution(AsOMSExecution(report))
+		if a.Tracker != nil {
+			if order, ok := a.Tracker.Get(report.ClOrdID); ok {
+				a.OMS.OnOrder(AsOMSOrder(order))
+			}
+		}
+	}
 }
 
-// LoadFIXConfig loads the FIX configuration file
+// logonSeqNum returns the MsgSeqNum (34) Logon msg goes out with. quickfix
+// numbers it before ToAdmin, then renumbers it 1 if it carries ResetSeqNumFlag
+// (141=Y).
+func logonSeqNum(msg *quickfix.Message) string {
+	if reset, err := msg.Body.GetBool(quickfix.Tag(141)); err == nil && reset {
+		return "1"
+	}
+	if seqNum, err := msg.Header.GetString(quickfix.Tag(34)); err == nil {
+		return seqNum
+	}
+	return "1"
+}
+
+// now returns the current time of the application's clock
+func (a *FixApplication) now() time.Time {
+	return clockOrSystem(a.Clock).Now()
+}
+
+// alert raises an alert when alerting is configured
+func (a *FixApplication) alert(key, severity, summary string) {
+	if a.Alerts != nil {
+		a.Alerts.Alert(Alert{Key: key, Severity: severity, Summary: summary, Tenant: a.Tenant})
+	}
+}
+
+// logger returns the tenant's logger, or the standard logger without tenants
+func (a *FixApplication) logger() *log.Logger {
+	if a.Logger == nil {
+		return log.Default()
+	}
+	return a.Logger
+}
+
+// LoadFIXConfig loads the FIX configuration file, applying any PRIMEFIX_*
+// environment and command line overrides, see resolveConfig
 func LoadFIXConfig(path string) (*quickfix.Settings, error) {
-	file, err := os.Open(path)
+	config, err := resolveConfig(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	if config, err = decryptConfigSecrets(config); err != nil {
+		return nil, err
+	}
+	return quickfix.ParseSettings(strings.NewReader(config))
+}
 
-	return quickfix.ParseSettings(file)
+// primeREST returns a Prime REST client using the session's credentials and
+// PrimeRestURL when set
+func (a *FixApplication) primeREST(settings *quickfix.SessionSettings) *PrimeREST {
+	baseURL, _ := settings.Setting("PrimeRestURL")
+	return &PrimeREST{
+		BaseURL:     baseURL,
+		AccessKey:   a.ApiKey,
+		Signer:      a.signer(),
+		Passphrase:  a.Passphrase,
+		PortfolioId: a.PortfolioId,
+		Clock:       a.Clock,
+	}
 }
 
-// sign generates a FIX authentication signature
-func sign(timestamp, msgType, seqNum, accessKey, targetCompID, passphrase, secret string) string {
-	message := timestamp + msgType + seqNum + accessKey + targetCompID + passphrase
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(message))
-	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+// credentialSetting returns the credential in the env variable, falling back to
+// setting; either may hold an encrypted secret
+func credentialSetting(settings *quickfix.SessionSettings, setting, env string) string {
+	value, ok := os.LookupEnv(env)
+	if !ok {
+		value, _ = settings.Setting(setting)
+	}
+	value, err := resolveSecret(value)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", setting, err)
+	}
+	return value
 }
 
-func createOrderMessage(symbol, ordType, side, quantity, limitPrice, portfolioId string) *quickfix.Message {
-	order := quickfix.NewMessage()
+// credentialBytes returns the credential like credentialSetting, decrypting
+// an encrypted one without passing its plaintext through a string. The caller
+// owns the result and should move it into an auth.Secret.
+func credentialBytes(settings *quickfix.SessionSettings, setting, env string) []byte {
+	value, ok := os.LookupEnv(env)
+	if !ok {
+		value, _ = settings.Setting(setting)
+	}
+	plaintext, err := resolveSecretBytes(value)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", setting, err)
+	}
+	return plaintext
+}
 
-	// Header fields (standard FIX header)
-	order.Header.SetField(quickfix.Tag(35), quickfix.FIXString("D"))                                              // MsgType = 'D'
-	order.Header.SetField(quickfix.Tag(49), quickfix.FIXString(os.Getenv("SVC_ACCOUNTID")))                       // SenderCompID
-	order.Header.SetField(quickfix.Tag(56), quickfix.FIXString("COIN"))                                           // TargetCompID
-	order.Header.SetField(quickfix.Tag(52), quickfix.FIXString(time.Now().UTC().Format("20060102-15:04:05.000"))) // SendingTime
+// parseTagList parses a comma separated list of FIX tag numbers
+func parseTagList(value string) ([]quickfix.Tag, error) {
+	var tags []quickfix.Tag
+	for _, field := range strings.Split(value, ",") {
+		tag, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, quickfix.Tag(tag))
+	}
+	return tags, nil
+}
 
-	// Body fields (order data)
-	clientOrderId := fmt.Sprintf("%d", time.Now().UnixNano())
-	order.Body.SetField(quickfix.Tag(1), quickfix.FIXString(portfolioId))    // Account (Portfolio ID)
-	order.Body.SetField(quickfix.Tag(11), quickfix.FIXString(clientOrderId)) // ClOrdID
-	order.Body.SetField(quickfix.Tag(55), quickfix.FIXString(symbol))        // Symbol
+// decimalSetting returns the decimal value of setting, or zero when it is unset or invalid
+func decimalSetting(settings *quickfix.SessionSettings, setting string) decimal.Decimal {
+	value, err := settings.Setting(setting)
+	if err != nil {
+		return decimal.Zero
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		log.Printf("Ignoring invalid %s: %v", setting, err)
+		return decimal.Zero
+	}
+	return d
+}
 
-	// Order Type, TimeInForce, Price, TargetStrategy
-	if ordType == "LIMIT" {
-		order.Body.SetField(quickfix.Tag(40), quickfix.FIXString("2")) // OrdType = Limit
-		order.Body.SetField(quickfix.Tag(59), quickfix.FIXString("1")) // TimeInForce = GTC (example)
-		order.Body.SetField(quickfix.Tag(44), quickfix.FIXString(limitPrice))
-		order.Body.SetField(quickfix.Tag(847), quickfix.FIXString("L")) // TargetStrategy = Limit
-	} else if ordType == "MARKET" {
-		order.Body.SetField(quickfix.Tag(40), quickfix.FIXString("1"))  // OrdType = Market
-		order.Body.SetField(quickfix.Tag(59), quickfix.FIXString("3"))  // TimeInForce = IOC
-		order.Body.SetField(quickfix.Tag(847), quickfix.FIXString("M")) // TargetStrategy = Market
+// signer returns the Signer of the logon signature
+func (a *FixApplication) signer() auth.Signer {
+	if a.Signer != nil {
+		return a.Signer
 	}
+	return auth.NewHMACSigner(nil)
+}
 
-	// Side
-	if side == "BUY" {
-		order.Body.SetField(quickfix.Tag(54), quickfix.FIXString("1")) // Side = Buy
-	} else {
-		order.Body.SetField(quickfix.Tag(54), quickfix.FIXString("2")) // Side = Sell
+func main() {
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
+	// Flags override the config file and environment, see resolveConfig
+	configPath, err := parseConfigFlags(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	logToJournal()
+	log.Println("Starting", BuildInfo())
+
+	// Load FIX configuration (ensure the config file exists)
+	settings, err := LoadFIXConfig(configPath)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	// Each [SESSION] is a tenant with its own credentials, application and
+	// initiator; a single session config runs as the only tenant
+	configs, err := LoadTenantConfigs(configPath)
+	if err != nil {
+		log.Fatal("Failed to load tenants:", err)
+	}
+	manager := NewManager()
+
+	// The blotter takes over the terminal, so the log goes to its log pane
+	// instead, and on to stderr only when that is redirected
+	var blotter *Blotter
+	if enabled, _ := settings.GlobalSettings().BoolSetting("Blotter"); enabled {
+		blotter = NewBlotter(manager, os.Stdin, os.Stdout)
+		if info, err := os.Stderr.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+			blotter.Log = os.Stderr
+		}
+		log.SetOutput(blotter)
+	}
+
+	for _, config := range configs {
+		if err := manager.Add(newTenant(config)); err != nil {
+			log.Fatal("Failed to add tenant:", err)
+		}
+	}
+	if err := linkShadows(manager); err != nil {
+		log.Fatal("Invalid shadow settings:", err)
+	}
+
+	quit := make(chan struct{})
+	if blotter != nil {
+		for _, tenant := range manager.Tenants() {
+			fills, err := NewEventQueue(100, OverflowDropOldest, "", blotter.OnExecutionReport)
+			if err != nil {
+				log.Fatal("Failed to create blotter queue:", err)
+			}
+			tenant.App.Sinks = append(tenant.App.Sinks, fills)
+		}
+		go func() {
+			blotter.Run()
+			close(quit)
+		}()
+	}
+
+	// Serve pprof, goroutine dumps and queue depths to diagnose the process in place
+	if addr, err := settings.GlobalSettings().Setting("DebugListenAddr"); err == nil {
+		debug, admin := NewDebugHandler(manager), NewAdminHandler(manager)
+
+		// Require a read token or client certificate, and a trade one for the
+		// admin actions, which are not served at all without authentication
+		auth, err := gatewayAuthSetting(settings.GlobalSettings())
+		if err != nil {
+			log.Fatal("Invalid gateway authentication:", err)
+		}
+		mux, err := GatewayHandler(addr, debug, admin, auth)
+		if err != nil {
+			log.Fatal("Refusing to serve debug endpoints without authentication:", err)
+		}
+		server := &http.Server{Addr: addr, Handler: mux}
+		if certFile, err := settings.GlobalSettings().Setting("GatewayCertFile"); err == nil {
+			keyFile, _ := settings.GlobalSettings().Setting("GatewayKeyFile")
+			clientCAFile, _ := settings.GlobalSettings().Setting("GatewayClientCAFile")
+			if server.TLSConfig, err = GatewayTLSConfig(certFile, keyFile, clientCAFile); err != nil {
+				log.Fatal("Invalid gateway TLS:", err)
+			}
+		}
+
+		go func() {
+			log.Println("Serving debug endpoints on", addr)
+			var err error
+			if server.TLSConfig != nil {
+				err = server.ListenAndServeTLS("", "")
+			} else {
+				err = server.ListenAndServe()
+			}
+			log.Println("Debug listener failed:", err)
+		}()
+	}
+
+	// Apply risk and alerting changes on SIGHUP without dropping the session.
+	// Reloads read the shared defaults, so they are only offered to a single tenant.
+	if tenants := manager.Tenants(); len(tenants) == 1 {
+		go NewConfigReloader(tenants[0].App, configPath, settings).WatchSIGHUP(tenants[0].stop)
 	}
 
-	// Order Quantity
-	order.Body.SetField(quickfix.Tag(38), quickfix.FIXString(quantity))
+	// In primary/standby mode only the lock holder connects; sequence numbers
+	// are shared through a file store on the same volume as the lock
+	if path, err := settings.GlobalSettings().Setting("LeaderLockPath"); err == nil {
+		lock, err := NewFileLock(path)
+		if err != nil {
+			log.Fatal("Failed to create leader lock:", err)
+		}
+		interval, err := settings.GlobalSettings().DurationSetting("LeaderPollInterval")
+		if err != nil {
+			interval = time.Second
+		}
+		if err := AwaitLeadership(lock, interval, make(chan struct{})); err != nil {
+			log.Fatal("Leader election failed:", err)
+		}
+		go func() {
+			<-lock.Lost()
+			log.Fatal("Lost leadership, exiting so a standby can take over")
+		}()
+		for _, tenant := range manager.Tenants() {
+			if tenant.StoreFactory, err = fileStoreFactory(tenant.Settings); err != nil {
+				log.Fatal("Failed to create message store:", err)
+			}
+		}
+	}
 
-	// Additional logging
-	log.Printf("Order Message: ClOrdID=%s Symbol=%s Side=%s Quantity=%s Price=%s",
-		clientOrderId, symbol, side, quantity, limitPrice)
+	// Drain ahead of Prime's scheduled maintenance and stay disconnected
+	// through it rather than retrying logons against a venue that is down
+	calendar, err := maintenanceSetting(settings.GlobalSettings())
+	if err != nil {
+		log.Fatal("Invalid MaintenanceWindows:", err)
+	}
+	if calendar != nil {
+		lead, err := settings.GlobalSettings().DurationSetting("MaintenanceDrainLead")
+		if err != nil {
+			lead = 5 * time.Minute
+		}
+		cancel, _ := settings.GlobalSettings().BoolSetting("MaintenanceCancelOrders")
+		manager.Calendar = calendar
+		for _, tenant := range manager.Tenants() {
+			tenant.App.Maintenance = calendar
+		}
+		go manager.RunMaintenance(DrainOptions{Cancel: cancel, Pace: 100 * time.Millisecond, Wait: lead, Reason: "scheduled maintenance"}, lead, nil, quit)
+	}
 
-	log.Println("Full FIX Message:", order.String())
-	return order
+	// Run until SIGTERM, stopping the sessions cleanly and saving snapshots,
+	// and report readiness and liveness to systemd when run by it
+	err = RunService(manager, quit)
+	manager.Close()
+	manager.ScrubSecrets()
+	if err != nil {
+		log.Fatal("FIX session failed:", err)
+	}
 }
 
-func main() {
-	// Load FIX configuration (ensure 'fix.cfg' exists)
-	settings, err := LoadFIXConfig("fix.cfg")
+// newTenant builds the application of one tenant from its settings
+func newTenant(config TenantConfig) *Tenant {
+	// Refuse CompIDs and hosts of another environment than the declared one
+	if err := ValidateEnvironment(config.Settings.GlobalSettings()); err != nil {
+		log.Fatalf("Tenant %s is misconfigured for its Environment: %v", config.Name, err)
+	}
+
+	// Route the connection through a wire tap when one is configured
+	if path, err := config.Settings.GlobalSettings().Setting("WireTapPath"); err == nil {
+		config, err = wireTapConfig(config, path)
+		if err != nil {
+			log.Fatal("Failed to start wire tap:", err)
+		}
+	}
+	name, settings := config.Name, config.Settings
+
+	// Experimental subsystems are switched on and off by Feature<Name> flags
+	features, err := LoadFeatureFlags(config)
 	if err != nil {
-		log.Fatal("Failed to load config:", err)
+		log.Fatal("Invalid feature flags:", err)
 	}
 
+	// stop ends the tenant's watchers once it is removed, see Manager.Remove
+	stop := make(chan struct{})
+
 	app := &FixApplication{
-		ApiKey:       os.Getenv("ACCESS_KEY"),
-		ApiSecret:    os.Getenv("SIGNING_KEY"),
-		Passphrase:   os.Getenv("PASSPHRASE"),
+		Tenant:       name,
+		Logger:       log.New(log.Writer(), "tenant="+name+" ", log.Flags()|log.Lmsgprefix),
+		ApiKey:       credentialSetting(settings.GlobalSettings(), "AccessKey", "ACCESS_KEY"),
+		Passphrase:   auth.NewSecret(credentialBytes(settings.GlobalSettings(), "Passphrase", "PASSPHRASE")),
 		TargetCompId: "COIN",
-		PortfolioId:  os.Getenv("PORTFOLIO_ID"),
+		PortfolioId:  credentialSetting(settings.GlobalSettings(), "PortfolioId", "PORTFOLIO_ID"),
+	}
+	app.stats.startedAt = app.now()
+	app.Features = features
+
+	// Sign in process with SigningKey, or in AWS KMS or an HSM
+	signingKey := credentialBytes(settings.GlobalSettings(), "SigningKey", "SIGNING_KEY")
+	if app.Signer, err = newSigner(settings.GlobalSettings(), signingKey); err != nil {
+		log.Fatal("Invalid SigningBackend:", err)
+	}
+	if app.Passphrase.Len() > 0 && !app.Passphrase.Locked() {
+		app.logger().Println("Credentials are not locked in memory and may be swapped out; raise RLIMIT_MEMLOCK")
+	}
+
+	// Log only a sample of high-volume message types, e.g. heartbeats
+	var logSampling map[string]int
+	if value, err := settings.GlobalSettings().Setting("LogSampling"); err == nil {
+		if logSampling, err = ParseLogSampling(value); err != nil {
+			log.Fatal("Invalid LogSampling:", err)
+		}
+	}
+	// LogLevel=info logs no FIX messages at all, debug (the default) logs them
+	switch level, _ := settings.GlobalSettings().Setting("LogLevel"); level {
+	case "", "debug":
+	case "info":
+		logSampling = map[string]int{"*": 0}
+	default:
+		log.Fatalf("Invalid LogLevel %q, want debug or info", level)
+	}
+	if logSampling != nil {
+		app.LogSampler = NewLogSampler(logSampling)
 	}
 
-	storeFactory := quickfix.NewMemoryStoreFactory()
-	logFactory := quickfix.NewScreenLogFactory()
-	initiator, err := quickfix.NewInitiator(app, storeFactory, settings, logFactory)
+	// Order memos are redacted from the logs unless TextLogRedaction says otherwise
+	if value, err := settings.GlobalSettings().Setting("TextLogRedaction"); err == nil {
+		if app.Redaction, err = ParseTextRedaction(value); err != nil {
+			log.Fatal("Invalid TextLogRedaction:", err)
+		}
+	}
+
+	// Warn when a handler holds up the session; 0 turns the warning off
+	slowHandler, err := settings.GlobalSettings().DurationSetting("SlowHandlerThreshold")
 	if err != nil {
-		log.Fatal("Failed to create initiator:", err)
+		slowHandler = 100 * time.Millisecond
 	}
+	app.SlowHandlerThreshold = slowHandler
 
-	// Start FIX session
-	err = initiator.Start()
+	// Deduplicate resent executions in memory
+	dedupCapacity, err := settings.GlobalSettings().IntSetting("ExecDedupCapacity")
 	if err != nil {
-		log.Fatal("Failed to start FIX session:", err)
+		dedupCapacity = 10000
 	}
+	app.Dedup = NewExecDedup(dedupCapacity)
 
-	// Keep the application running
-	select {}
+	if value, err := settings.GlobalSettings().Setting("ResendPolicy"); err == nil {
+		app.ResendPolicy, err = ParseResendPolicy(value)
+		if err != nil {
+			log.Fatal("Invalid ResendPolicy:", err)
+		}
+	}
+
+	if path, err := settings.GlobalSettings().Setting("SymbolMapPath"); err == nil {
+		app.Symbols, err = LoadSymbolMapper(path)
+		if err != nil {
+			log.Fatal("Failed to load symbol map:", err)
+		}
+	}
+
+	if path, err := settings.GlobalSettings().Setting("SymbolDefaultsPath"); err == nil {
+		app.Defaults, err = LoadSymbolDefaults(path)
+		if err != nil {
+			log.Fatal("Failed to load symbol defaults:", err)
+		}
+	}
+
+	// Register the messages SendCustom may send, validated against the
+	// session's data dictionary when it uses one
+	if path, err := settings.GlobalSettings().Setting("MessageDescriptorsPath"); err == nil {
+		app.Messages, err = LoadMessageDescriptors(path)
+		if err != nil {
+			log.Fatal("Failed to load message descriptors:", err)
+		}
+		if use, err := settings.GlobalSettings().BoolSetting("UseDataDictionary"); err == nil && use {
+			if path, err := settings.GlobalSettings().Setting("DataDictionary"); err == nil {
+				if app.Dictionary, err = datadictionary.Parse(path); err != nil {
+					log.Fatal("Failed to load data dictionary:", err)
+				}
+			}
+		}
+	}
+
+	// Check orders against the symbol, side and trading hour rules, auditing every hit
+	if path, err := settings.GlobalSettings().Setting("OrderRulesPath"); err == nil {
+		app.Rules, err = LoadOrderRules(path)
+		if err != nil {
+			log.Fatal("Failed to load order rules:", err)
+		}
+		app.Rules.OnRuleHit = func(hit RuleHit) {
+			app.logger().Printf("AUDIT: order blocked by rule %s: %s %s %s %s portfolio=%s: %s",
+				hit.Err.Rule, hit.Request.OrdType, hit.Request.Side, hit.Request.Quantity, hit.Request.Symbol,
+				hit.Portfolio, hit.Err.Reason)
+		}
+	}
+
+	if value, err := settings.GlobalSettings().Setting("UnknownMessagePolicy"); err == nil {
+		app.UnknownPolicy, err = ParseUnknownMessagePolicy(value)
+		if err != nil {
+			log.Fatal("Invalid UnknownMessagePolicy:", err)
+		}
+	}
+	app.UnknownMessage = func(msgType string, msg *quickfix.Message) {
+		log.Printf("Unknown message %s: %s", msgType, msg)
+	}
+
+	// Opt into the ExecutionReport fast path when a tag subset is configured
+	if settings.GlobalSettings().HasSetting("ExecutionReportFastPathTags") {
+		value, _ := settings.GlobalSettings().Setting("ExecutionReportFastPathTags")
+		tags, err := parseTagList(value)
+		if err != nil {
+			log.Fatal("Invalid ExecutionReportFastPathTags:", err)
+		}
+		app.ExecutionView = NewExecutionReportView(tags...)
+		app.OnExecutionView = func(view *ExecutionReportView) {
+			for _, tag := range tags {
+				log.Printf("Execution Report: %d=%s", tag, view.Get(tag))
+			}
+		}
+	}
+
+	// Hand ExecutionReports to a worker pool instead of handling them inline
+	if features.active(FeatureAsyncDispatch, settings.GlobalSettings(), "AsyncDispatchWorkers") {
+		workers, err := settings.GlobalSettings().IntSetting("AsyncDispatchWorkers")
+		if err != nil {
+			workers = 4
+		}
+		queueSize, err := settings.GlobalSettings().IntSetting("AsyncDispatchQueueSize")
+		if err != nil {
+			queueSize = 1024
+		} else if queueSize < 0 {
+			log.Fatalf("Invalid AsyncDispatchQueueSize %d, want 0 or more", queueSize)
+		}
+		app.Dispatcher = NewDispatcher(workers, queueSize, app.handleExecutionReport)
+	}
+
+	// Persist executions so replays can be deduplicated across restarts
+	if path, err := settings.GlobalSettings().Setting("ExecutionStorePath"); err == nil {
+		app.Executions, err = OpenExecutionStore(path)
+		if err != nil {
+			log.Fatal("Failed to open execution store:", err)
+		}
+	}
+
+	// Limit the orders each gateway client may place
+	if value, err := settings.GlobalSettings().Setting("GatewayQuotas"); err == nil {
+		quotas, err := ParseGatewayQuotas(value)
+		if err != nil {
+			log.Fatal("Invalid GatewayQuotas:", err)
+		}
+		app.Quotas = NewGatewayQuotas(quotas)
+	}
+
+	// Archive raw messages for `messages search`
+	if path, err := settings.GlobalSettings().Setting("MessageArchivePath"); err == nil {
+		app.Archive, err = OpenMessageArchive(path)
+		if err != nil {
+			log.Fatal("Failed to open message archive:", err)
+		}
+	}
+
+	// Journal session lifecycle events for `session timeline`
+	if path, err := settings.GlobalSettings().Setting("SessionJournalPath"); err == nil {
+		app.Journal, err = OpenSessionJournal(path)
+		if err != nil {
+			log.Fatal("Failed to open session journal:", err)
+		}
+	}
+
+	// Deliver executions to a webhook through a persistent outbox
+	if url, err := settings.GlobalSettings().Setting("ExecutionWebhookURL"); err == nil {
+		path, err := settings.GlobalSettings().Setting("ExecutionOutboxPath")
+		if err != nil {
+			path = "./Sessions/outbox.jsonl"
+		}
+		sink := &WebhookSink{URL: url}
+		if name, err := settings.GlobalSettings().Setting("ExecutionWebhookCodec"); err == nil {
+			if sink.Codec, err = CodecByName(name); err != nil {
+				log.Fatal("Invalid ExecutionWebhookCodec:", err)
+			}
+		}
+		app.Outbox, err = OpenOutbox(path, sink)
+		if err != nil {
+			log.Fatal("Failed to open execution outbox:", err)
+		}
+	}
+
+	// Alert on session and order anomalies to Slack and/or PagerDuty. The
+	// alerter always exists so sinks can be added by a config reload.
+	app.Alerts = NewAlerter(alertWindowSetting(settings.GlobalSettings()), alertSinksSetting(settings.GlobalSettings())...)
+	app.RejectStormCount, err = settings.GlobalSettings().IntSetting("RejectStormCount")
+	if err != nil {
+		app.RejectStormCount = 5
+	}
+	stormWindow, err := settings.GlobalSettings().DurationSetting("RejectStormWindow")
+	if err != nil {
+		stormWindow = 10 * time.Second
+	}
+	app.rejects = newEventWindow(stormWindow)
+
+	// Stop submitting after a burst of rejects
+	if threshold, err := settings.GlobalSettings().IntSetting("CircuitBreakerRejects"); err == nil {
+		window, err := settings.GlobalSettings().DurationSetting("CircuitBreakerWindow")
+		if err != nil {
+			window = 30 * time.Second
+		}
+		app.Breaker = NewCircuitBreaker(threshold, window)
+		app.Breaker.OnTrip = func(rejects int) {
+			app.alert("circuit-breaker", "critical",
+				fmt.Sprintf("Order circuit breaker tripped after %d rejects, submissions blocked until reset", rejects))
+		}
+	}
+
+	app.Venue = NewVenueStatus()
+	app.Venue.OnStatusChange = app.onSymbolStatus
+
+	// Back off between rejected logons and stop before the credentials get locked out
+	maxLogonFailures, err := settings.GlobalSettings().IntSetting("LogonMaxFailures")
+	if err != nil {
+		maxLogonFailures = 5
+	}
+	logonBackoff, err := settings.GlobalSettings().DurationSetting("LogonBackoffBase")
+	if err != nil {
+		logonBackoff = 10 * time.Second
+	}
+	logonBackoffMax, err := settings.GlobalSettings().DurationSetting("LogonBackoffMax")
+	if err != nil {
+		logonBackoffMax = 5 * time.Minute
+	}
+	app.LogonGuard = NewLogonGuard(maxLogonFailures, logonBackoff, logonBackoffMax)
+	app.LogonGuard.OnLockout = func(err error) {
+		app.alert("logon-lockout", "critical", err.Error())
+		app.Alerts.Flush()
+		fatalUnrecoverable(err)
+	}
+
+	// Give up when another instance with the same CompIDs keeps bouncing the session
+	maxBounces, err := settings.GlobalSettings().IntSetting("ConcurrentSessionMaxBounces")
+	if err != nil {
+		maxBounces = 3
+	}
+	bounceWindow, err := settings.GlobalSettings().DurationSetting("ConcurrentSessionWindow")
+	if err != nil {
+		bounceWindow = 5 * time.Second
+	}
+	app.Sessions = NewConcurrentSessionDetector(maxBounces, bounceWindow)
+	app.Sessions.OnDetected = func(err error) {
+		app.alert("concurrent-session", "critical", err.Error())
+		app.Alerts.Flush()
+		fatalUnrecoverable(err)
+	}
+
+	// Track orders, giving up on cancels and replaces that never get a response
+	pendingTimeout, err := settings.GlobalSettings().DurationSetting("PendingRequestTimeout")
+	if err != nil {
+		pendingTimeout = 30 * time.Second
+	}
+	app.Tracker = NewOrderTracker(pendingTimeout)
+	app.Tracker.Clock = app.Clock
+	app.Tracker.OnUnknownState = func(order Order) {
+		summary := fmt.Sprintf("%s for order %s got no response, manual intervention required", order.Pending, order.ClOrdID)
+		if order.Pending == "" {
+			summary = fmt.Sprintf("Order %s may or may not have reached Prime, status requested", order.ClOrdID)
+		}
+		app.alert("stuck-order:"+order.ClOrdID, "error", summary)
+	}
+	app.Strict, _ = settings.GlobalSettings().BoolSetting("StrictMode")
+	app.Tracker.OnAnomaly = app.anomaly
+
+	// Move orders that have been terminal for TrackerRetention out of memory
+	if retention, err := settings.GlobalSettings().DurationSetting("TrackerRetention"); err == nil {
+		path, err := settings.GlobalSettings().Setting("TrackerArchivePath")
+		if err != nil {
+			path = "./Sessions/orders.jsonl"
+		}
+		cacheSize, err := settings.GlobalSettings().IntSetting("TrackerCacheSize")
+		if err != nil {
+			cacheSize = 1000
+		}
+		archive, err := OpenFileOrderArchive(path)
+		if err != nil {
+			log.Fatal("Failed to open order archive:", err)
+		}
+		app.Tracker.ArchiveTerminal(archive, retention, cacheSize)
+	}
+	go app.Tracker.WatchTimeouts(time.Second, stop)
+
+	// Purge executions, archived messages and archived orders past their retention
+	retention, err := retentionSetting(settings.GlobalSettings(), app)
+	if err != nil {
+		log.Fatal("Invalid retention settings:", err)
+	}
+	if retention.Policy.Executions > 0 || retention.Policy.Messages > 0 || retention.Policy.Orders > 0 {
+		interval, err := settings.GlobalSettings().DurationSetting("RetentionPurgeInterval")
+		if err != nil {
+			interval = 24 * time.Hour
+		}
+		go retention.Watch(interval, stop)
+	}
+
+	app.Algos = NewAlgoTracker()
+	app.Algos.Clock = app.Clock
+	if app.Algos.Retention, err = settings.GlobalSettings().DurationSetting("AlgoRetention"); err != nil {
+		app.Algos.Retention = time.Hour
+	}
+	app.Algos.OnParentComplete = func(order AlgoOrder) {
+		log.Printf("Algo order complete: ClOrdID=%s Strategy=%s State=%s Filled=%s/%s (%.1f%%) AvgPx=%s Slices=%d",
+			order.ClOrdID, order.Strategy, order.State, order.FilledQty, order.Quantity, order.PercentComplete(), order.AvgPrice(), len(order.Slices))
+	}
+	go app.Algos.WatchEvictions(time.Minute, stop)
+
+	// Enforce exposure limits against live positions
+	app.Positions = NewPositionTracker()
+	app.Fees = NewFeeTracker()
+	app.Risk = NewRiskChecker(riskLimitsSetting(settings.GlobalSettings()), app.Positions)
+	app.Risk.Clock = app.Clock
+	app.Risk.Reference = func(symbol string) (decimal.Decimal, bool) {
+		if app.Mids == nil {
+			return decimal.Zero, false
+		}
+		return app.Mids.Mid(symbol)
+	}
+	app.Risk.Working = app.Tracker.WorkingQty
+	app.Risk.OnRiskEvent = func(event RiskEvent) {
+		app.alert("risk:"+event.Err.Rule+":"+event.Err.Symbol, "warning", "Order blocked: "+event.Err.Error())
+	}
+
+	// Stamp correlation IDs on orders so logs across systems can be joined
+	correlationTag, _ := settings.GlobalSettings().IntSetting("CorrelationIdTag")
+	app.CorrelationTag = quickfix.Tag(correlationTag)
+
+	// Cross-reference OrderIDs with REST order ids, read from RestOrderIdTag
+	// when Prime reports them separately
+	restIdTag, _ := settings.GlobalSettings().IntSetting("RestOrderIdTag")
+	app.OrderIDs = NewOrderIDMap(quickfix.Tag(restIdTag))
+
+	// Cancel over the Prime REST API while the FIX session is down
+	fallback, _ := settings.GlobalSettings().BoolSetting("RestCancelFallback")
+	if features.Enabled(FeatureRestFallback) || fallback && !features.Disabled(FeatureRestFallback) {
+		app.RESTCancel = NewRESTCancelFallback(app.primeREST(settings.GlobalSettings()))
+		app.RESTCancel.IDs = app.OrderIDs
+	}
+
+	// Check balances over the Prime REST API before sending orders
+	if preflight, err := settings.GlobalSettings().BoolSetting("BalancePreflight"); err == nil && preflight {
+		app.Balances = &BalanceChecker{REST: app.primeREST(settings.GlobalSettings()), Positions: app.Positions}
+	}
+
+	// Cumulative daily limits, persisted so a restart keeps the day's totals
+	maxDailyOrders, _ := settings.GlobalSettings().IntSetting("MaxDailyOrders")
+	resetTime, _ := settings.GlobalSettings().Setting("DailyResetTime")
+	location := time.UTC
+	if zone, err := settings.GlobalSettings().Setting("DailyResetTimezone"); err == nil {
+		if location, err = time.LoadLocation(zone); err != nil {
+			log.Fatal("Invalid DailyResetTimezone:", err)
+		}
+	}
+	countersPath, _ := settings.GlobalSettings().Setting("DailyCountersPath")
+	app.Risk.Daily, err = NewDailyLimiter(DailyLimits{
+		MaxNotional: decimalSetting(settings.GlobalSettings(), "MaxDailyNotional"),
+		MaxOrders:   maxDailyOrders,
+		ResetTime:   resetTime,
+		Location:    location,
+	}, countersPath)
+	if err != nil {
+		log.Fatal("Failed to load daily limits:", err)
+	}
+
+	app.Brackets = NewBracketManager(app.placeChildOrder, app.CancelOrder, app.ReplaceOrder)
+	app.Brackets.OnUnprotected = func(bracket Bracket, err error) {
+		app.alert("bracket-unprotected:"+bracket.EntryClOrdID, "critical",
+			fmt.Sprintf("Bracket %s entry filled but its exits could not be placed: %v", bracket.EntryClOrdID, err))
+	}
+	app.Icebergs = NewIcebergManager(app.placeChildOrder, app.CancelOrder)
+	app.Icebergs.Clock = app.Clock
+	app.Baskets = NewBasketManager(app.validateOrder, app.placeChildOrder, app.CancelOrder)
+	if !features.Disabled(FeatureMarketData) {
+		app.Repricer = NewRepricer(app.Tracker, app.ReplaceOrder)
+		app.Mids = NewMidBook()
+	}
+	app.Quality = NewExecutionQuality(location)
+
+	// Stop trading a symbol after a fill far from its mid or previous fill
+	if percent := decimalSetting(settings.GlobalSettings(), "FillPriceBreakerPercent"); percent.IsPositive() {
+		app.PriceBreakers = NewPriceBreakers(percent.Div(decimal.NewFromInt(100)))
+		app.PriceBreakers.Reference = func(symbol string) (decimal.Decimal, bool) {
+			if app.Mids == nil {
+				return decimal.Zero, false
+			}
+			return app.Mids.Mid(symbol)
+		}
+		app.PriceBreakers.OnTrip = func(trip PriceBreakerTrip) {
+			app.alert("price-breaker:"+trip.Symbol, "critical",
+				fmt.Sprintf("Fill %s in %s at %s is %s%% from reference %s, orders in %s blocked until reset",
+					trip.ExecID, trip.Symbol, trip.Price, trip.Deviation.Mul(decimal.NewFromInt(100)).Round(2), trip.Reference, trip.Symbol))
+		}
+	}
+
+	// Resubmit orders rejected for rate limits or venue errors
+	app.Retrier = rejectRetrySetting(settings.GlobalSettings())
+
+	// Paper trading fills orders against quotes fed to OnQuote instead of Prime
+	if paper, err := settings.GlobalSettings().BoolSetting("PaperTrading"); err == nil && paper {
+		app.Paper, err = paperVenueSetting(settings.GlobalSettings(), app.Clock)
+		if err != nil {
+			log.Fatal("Invalid paper trading settings:", err)
+		}
+		app.Paper.Deliver = func(msg *quickfix.Message) { app.FromApp(msg, quickfix.SessionID{}) }
+	}
+
+	// Dual-run against an in-process paper venue; shadow tenants are linked
+	// once every tenant exists
+	if target, err := settings.GlobalSettings().Setting("ShadowTenant"); err == nil && target == "paper" {
+		shadow, err := paperShadow(settings.GlobalSettings(), app)
+		if err != nil {
+			log.Fatal("Invalid paper trading settings:", err)
+		}
+		if app.Shadow, err = newShadow(target, app, shadow); err != nil {
+			log.Fatal("Failed to create shadow:", err)
+		}
+	}
+
+	// Shape outbound messages to the venue's rate limit
+	if settings.GlobalSettings().HasSetting("MaxMessagesPerSecond") {
+		app.SendQueue, err = sendQueueSetting(settings.GlobalSettings(), app.Clock)
+		if err != nil {
+			log.Fatal("Invalid send queue settings:", err)
+		}
+	}
+
+	// Sandbox canary orders validate the full order path on a schedule
+	if settings.GlobalSettings().HasSetting("CanaryInterval") {
+		app.Canary, err = canarySetting(settings.GlobalSettings(), app)
+		if err != nil {
+			log.Fatal("Invalid canary settings:", err)
+		}
+		app.Canary.Start()
+	}
+
+	// Queue timed orders, persisting them when a path is configured
+	scheduledPath, _ := settings.GlobalSettings().Setting("ScheduledOrdersPath")
+	app.Scheduler, err = NewOrderScheduler(scheduledPath, time.Minute, app.Clock, app.PlaceOrder)
+	if err != nil {
+		log.Fatal("Failed to load scheduled orders:", err)
+	}
+
+	tenant := &Tenant{Name: name, App: app, Settings: settings, stop: stop}
+	tenant.StoreFactory = quickfix.NewMemoryStoreFactory()
+
+	// Backfill needs sequence numbers that survive a restart
+	if features.active(FeatureBackfill, settings.GlobalSettings(), "BackfillResendWindow") {
+		app.BackfillWindow, _ = settings.GlobalSettings().IntSetting("BackfillResendWindow")
+		if tenant.StoreFactory, err = fileStoreFactory(settings); err != nil {
+			log.Fatal("Failed to create message store:", err)
+		}
+	}
+	tenant.LogFactory = quickfix.NewScreenLogFactory()
+	if blotter, _ := settings.GlobalSettings().BoolSetting("Blotter"); blotter {
+		// Keep the screen log from scrolling the blotter away
+		tenant.LogFactory = quickfix.NewNullLogFactory()
+	}
+	if settings.GlobalSettings().HasSetting("FileLogPath") {
+		// Log messages to files, which a support bundle can pick up
+		tenant.LogFactory, err = quickfix.NewFileLogFactory(settings)
+		if err != nil {
+			log.Fatal("Failed to create file log:", err)
+		}
+	}
+	if logSampling != nil {
+		tenant.LogFactory = NewSampledLogFactory(tenant.LogFactory, logSampling)
+	}
+	tenant.LogFactory = NewRedactedLogFactory(tenant.LogFactory, app.Redaction)
+
+	tenant.SnapshotPath, _ = settings.GlobalSettings().Setting("SnapshotPath")
+
+	// Save session stats for `session stats`
+	if path, err := settings.GlobalSettings().Setting("SessionStatsPath"); err == nil {
+		interval, err := settings.GlobalSettings().DurationSetting("SessionStatsInterval")
+		if err != nil {
+			interval = 10 * time.Second
+		}
+		go app.WriteSessionStats(path, interval, stop)
+	}
+	return tenant
 }
@@ -0,0 +1,275 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Role is what an authenticated gateway client may do
+type Role string
+
+const (
+	// RoleRead may query orders, executions and diagnostics
+	RoleRead Role = "read"
+	// RoleTrade may also place and cancel orders
+	RoleTrade Role = "trade"
+)
+
+// Allows reports whether r grants required; trade includes read
+func (r Role) Allows(required Role) bool {
+	return r == required || r == RoleTrade && required == RoleRead
+}
+
+func parseRole(value string) (Role, error) {
+	switch Role(value) {
+	case RoleRead, RoleTrade:
+		return Role(value), nil
+	}
+	return "", fmt.Errorf("unknown role %q", value)
+}
+
+// Principal is an authenticated gateway client: an API token or a client
+// certificate, identified by Name
+type Principal struct {
+	Name string
+	Role Role
+}
+
+var (
+	// ErrUnauthenticated is returned for requests with no valid token or certificate
+	ErrUnauthenticated = errors.New("gateway: authentication required")
+	// ErrForbidden is returned for clients whose role does not allow the request
+	ErrForbidden = errors.New("gateway: forbidden")
+	// ErrAdminDisabled is returned for admin requests when no gateway
+	// authentication is configured
+	ErrAdminDisabled = errors.New("gateway: admin endpoints need GatewayTokensPath or GatewayClientRoles")
+	// ErrTokenNeedsTLS is returned for bearer tokens sent in clear text from
+	// another host
+	ErrTokenNeedsTLS = errors.New("gateway: bearer tokens need TLS off loopback")
+)
+
+// GatewayAuth authenticates gateway requests by bearer API token or by
+// verified client certificate (mTLS). Tokens are only held as SHA-256 hashes.
+type GatewayAuth struct {
+	tokens      map[string]Principal // hex SHA-256 of the token -> principal
+	clientRoles map[string]Role      // certificate CommonName -> role
+}
+
+// NewGatewayAuth creates an authenticator from tokens keyed by their hash,
+// see LoadGatewayTokens, and the roles of client certificate CommonNames
+func NewGatewayAuth(tokens map[string]Principal, clientRoles map[string]Role) *GatewayAuth {
+	return &GatewayAuth{tokens: tokens, clientRoles: clientRoles}
+}
+
+// HashGatewayToken returns the hex SHA-256 under which a token is stored
+func HashGatewayToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadGatewayTokens reads a token file of "name role sha256" lines, as
+// printed by `gateway token`. Blank lines and # comments are ignored.
+func LoadGatewayTokens(path string) (map[string]Principal, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tokens := make(map[string]Principal)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%s:%d: want name, role and token hash", path, line)
+		}
+		role, err := parseRole(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		tokens[strings.ToLower(fields[2])] = Principal{Name: fields[0], Role: role}
+	}
+	return tokens, scanner.Err()
+}
+
+// ParseGatewayClientRoles parses comma separated CommonName:role pairs
+func ParseGatewayClientRoles(value string) (map[string]Role, error) {
+	roles := make(map[string]Role)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, ":")
+		role, err := parseRole(strings.TrimSpace(value))
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid client role %q, want CommonName:read|trade", pair)
+		}
+		roles[strings.TrimSpace(name)] = role
+	}
+	return roles, nil
+}
+
+// Authenticate returns the principal making r, preferring a verified client
+// certificate over a bearer token. Tokens are only accepted over TLS or from
+// a loopback client, where they cannot be sniffed.
+func (a *GatewayAuth) Authenticate(r *http.Request) (Principal, error) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		name := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		if role, ok := a.clientRoles[name]; ok {
+			return Principal{Name: "cert:" + name, Role: role}, nil
+		}
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		if r.TLS == nil && !isLoopbackAddr(r.RemoteAddr) {
+			return Principal{}, ErrTokenNeedsTLS
+		}
+		if principal, ok := a.tokens[HashGatewayToken(strings.TrimSpace(token))]; ok {
+			return principal, nil
+		}
+	}
+	return Principal{}, ErrUnauthenticated
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal of a request passed by Require
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Require only passes requests from principals whose role allows role to
+// next, answering 401 or 403 otherwise
+func (a *GatewayAuth) Require(role Role, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, err := a.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !principal.Role.Allows(role) {
+			http.Error(w, ErrForbidden.Error(), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+	})
+}
+
+// GatewayTLSConfig serves certFile/keyFile and, with clientCAFile, asks for
+// client certificates signed by it. Clients without one can still use a token.
+func GatewayTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
+
+// gatewayAuthSetting creates the GatewayAuth configured by GatewayTokensPath
+// and GatewayClientRoles, or nil when neither is set
+func gatewayAuthSetting(settings *quickfix.SessionSettings) (*GatewayAuth, error) {
+	tokens := make(map[string]Principal)
+	clientRoles := make(map[string]Role)
+	configured := false
+	if path, err := settings.Setting("GatewayTokensPath"); err == nil {
+		if tokens, err = LoadGatewayTokens(path); err != nil {
+			return nil, err
+		}
+		configured = true
+	}
+	if value, err := settings.Setting("GatewayClientRoles"); err == nil {
+		if clientRoles, err = ParseGatewayClientRoles(value); err != nil {
+			return nil, err
+		}
+		configured = true
+	}
+	if !configured {
+		return nil, nil
+	}
+	return NewGatewayAuth(tokens, clientRoles), nil
+}
+
+// GatewayHandler serves debug and the GET requests of admin to read clients,
+// and the other admin requests, which place and cancel orders, to trade
+// clients. Without auth, only debug is served, and only on a loopback address.
+func GatewayHandler(addr string, debug, admin http.Handler, auth *GatewayAuth) (http.Handler, error) {
+	mux := http.NewServeMux()
+	if auth == nil {
+		if !isLoopbackAddr(addr) {
+			return nil, fmt.Errorf("%s is not a loopback address; set GatewayTokensPath or GatewayClientRoles", addr)
+		}
+		mux.Handle("/", debug)
+		mux.HandleFunc("/admin/", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, ErrAdminDisabled.Error(), http.StatusForbidden)
+		})
+		return mux, nil
+	}
+	mux.Handle("/", auth.Require(RoleRead, debug))
+	readAdmin, tradeAdmin := auth.Require(RoleRead, admin), auth.Require(RoleTrade, admin)
+	mux.HandleFunc("/admin/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			readAdmin.ServeHTTP(w, r)
+		} else {
+			tradeAdmin.ServeHTTP(w, r)
+		}
+	})
+	return mux, nil
+}
+
+// isLoopbackAddr reports whether a listen address only binds loopback
+// interfaces; an empty host binds every interface
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,179 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGatewayAuthRequire(t *testing.T) {
+	auth := NewGatewayAuth(map[string]Principal{
+		HashGatewayToken("read-token"):  {Name: "monitoring", Role: RoleRead},
+		HashGatewayToken("trade-token"): {Name: "desk-oms", Role: RoleTrade},
+	}, nil)
+
+	var seen Principal
+	handler := auth.Require(RoleTrade, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = PrincipalFromContext(r.Context())
+	}))
+
+	tests := []struct {
+		token  string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"wrong", http.StatusUnauthorized},
+		{"read-token", http.StatusForbidden},
+		{"trade-token", http.StatusOK},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.RemoteAddr = "127.0.0.1:50000"
+		if test.token != "" {
+			req.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != test.status {
+			t.Errorf("token %q: status %d, want %d", test.token, rec.Code, test.status)
+		}
+	}
+	if seen.Name != "desk-oms" {
+		t.Errorf("handler saw principal %+v", seen)
+	}
+}
+
+func TestGatewayHandler(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	if _, err := GatewayHandler(":6060", ok, ok, nil); err == nil {
+		t.Error("served every interface without authentication")
+	}
+	if _, err := GatewayHandler("10.0.0.1:6060", ok, ok, nil); err == nil {
+		t.Error("served a non-loopback address without authentication")
+	}
+
+	// On loopback without authentication, admin is not served at all
+	for _, addr := range []string{"127.0.0.1:6060", "localhost:6060", "[::1]:6060"} {
+		handler, err := GatewayHandler(addr, ok, ok, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", addr, err)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/drain", nil))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: unauthenticated admin status %d, want %d", addr, rec.Code, http.StatusForbidden)
+		}
+	}
+
+	auth := NewGatewayAuth(map[string]Principal{
+		HashGatewayToken("read-token"): {Name: "monitoring", Role: RoleRead},
+	}, nil)
+	handler, err := GatewayHandler(":6060", ok, ok, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Read clients may look at admin state but not change it, and tokens
+	// in clear text are refused from other hosts
+	for _, tt := range []struct {
+		method, path, remote string
+		tls                  bool
+		status               int
+	}{
+		{http.MethodGet, "/debug/queues", "127.0.0.1:50000", false, http.StatusOK},
+		{http.MethodGet, "/admin/orders", "127.0.0.1:50000", false, http.StatusOK},
+		{http.MethodGet, "/admin/scheduled", "10.0.0.2:50000", true, http.StatusOK},
+		{http.MethodPost, "/admin/drain", "127.0.0.1:50000", false, http.StatusForbidden},
+		{http.MethodGet, "/admin/orders", "10.0.0.2:50000", false, http.StatusUnauthorized},
+	} {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.RemoteAddr = tt.remote
+		if tt.tls {
+			req.TLS = &tls.ConnectionState{}
+		}
+		req.Header.Set("Authorization", "Bearer read-token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s %s from %s (TLS %t) with a read token: status %d, want %d", tt.method, tt.path, tt.remote, tt.tls, rec.Code, tt.status)
+		}
+	}
+}
+
+func TestGatewayQuotas(t *testing.T) {
+	quotas, err := ParseGatewayQuotas("desk-oms:2/1m,*:1/1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := NewGatewayQuotas(quotas)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, want := range []bool{true, true, false} {
+		if got := q.Allow("desk-oms", OrderRequest{}, now) == nil; got != want {
+			t.Errorf("desk-oms order %d allowed=%t, want %t", i+1, got, want)
+		}
+	}
+	if q.Allow("desk-oms", OrderRequest{}, now.Add(time.Minute)) != nil {
+		t.Error("desk-oms quota did not free up after the window")
+	}
+	if q.Allow("other", OrderRequest{}, now) != nil || q.Allow("other", OrderRequest{}, now) == nil {
+		t.Error("default quota not applied to other clients")
+	}
+}
+
+func TestAdminPlaceOrder(t *testing.T) {
+	app := &FixApplication{Quotas: NewGatewayQuotas(map[string]GatewayQuota{"desk-oms": {Orders: 0, Window: time.Minute}})}
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app}); err != nil {
+		t.Fatal(err)
+	}
+	auth := NewGatewayAuth(map[string]Principal{HashGatewayToken("trade-token"): {Name: "desk-oms", Role: RoleTrade}}, nil)
+	handler, err := GatewayHandler("127.0.0.1:6060", http.NotFoundHandler(), NewAdminHandler(manager), auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := `{"Symbol":"BTC-USD","OrdType":"LIMIT","Side":"BUY","Quantity":"1","LimitPrice":"100"}`
+	for _, tt := range []struct {
+		token, body string
+		status      int
+	}{
+		{"", order, http.StatusUnauthorized},
+		{"trade-token", "{", http.StatusBadRequest},
+		{"trade-token", order, http.StatusTooManyRequests},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/admin/orders", strings.NewReader(tt.body))
+		req.RemoteAddr = "127.0.0.1:50000"
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("token %q body %q: status %d, want %d", tt.token, tt.body, rec.Code, tt.status)
+		}
+	}
+
+	// Without a principal, as when mounted without auth, no order is placed
+	rec := httptest.NewRecorder()
+	NewAdminHandler(manager).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/orders", strings.NewReader(order)))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("order without a principal: status %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
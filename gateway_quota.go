@@ -0,0 +1,135 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// GatewayClientKey is the order Metadata key recording which gateway client
+// placed the order
+const GatewayClientKey = "gateway_client"
+
+// GatewayQuota limits a gateway client to Orders orders per Window
+type GatewayQuota struct {
+	Orders int
+	Window time.Duration
+}
+
+// ParseGatewayQuotas parses comma separated name:orders/window pairs, e.g.
+// "desk-oms:100/1m,*:10/1s". The name is a token name, or cert:CommonName
+// for a client certificate, and * applies to clients without their own quota.
+func ParseGatewayQuotas(value string) (map[string]GatewayQuota, error) {
+	quotas := make(map[string]GatewayQuota)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		invalid := fmt.Errorf("invalid gateway quota %q, want name:orders/window", pair)
+		i := strings.LastIndex(pair, ":")
+		if i <= 0 {
+			return nil, invalid
+		}
+		orders, window, ok := strings.Cut(pair[i+1:], "/")
+		if !ok {
+			return nil, invalid
+		}
+		n, err := strconv.Atoi(orders)
+		if err != nil || n < 0 {
+			return nil, invalid
+		}
+		d, err := time.ParseDuration(window)
+		if err != nil || d <= 0 {
+			return nil, invalid
+		}
+		quotas[pair[:i]] = GatewayQuota{Orders: n, Window: d}
+	}
+	return quotas, nil
+}
+
+// GatewayQuotas enforces per-client order quotas over a sliding window
+type GatewayQuotas struct {
+	mu     sync.Mutex
+	quotas map[string]GatewayQuota
+	sent   map[string][]time.Time
+}
+
+// NewGatewayQuotas creates quotas keyed by client name, see ParseGatewayQuotas
+func NewGatewayQuotas(quotas map[string]GatewayQuota) *GatewayQuotas {
+	return &GatewayQuotas{quotas: quotas, sent: make(map[string][]time.Time)}
+}
+
+// Allow counts an order req by client at now against its quota, returning
+// a *RiskError instead when the quota is used up
+func (q *GatewayQuotas) Allow(client string, req OrderRequest, now time.Time) *RiskError {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	quota, ok := q.quotas[client]
+	if !ok {
+		if quota, ok = q.quotas["*"]; !ok {
+			return nil
+		}
+	}
+
+	sent := q.sent[client]
+	for len(sent) > 0 && now.Sub(sent[0]) >= quota.Window {
+		sent = sent[1:]
+	}
+	if len(sent) >= quota.Orders {
+		q.sent[client] = sent
+		return &RiskError{
+			Rule:      "GatewayQuota " + client,
+			Symbol:    req.Symbol,
+			Limit:     decimal.NewFromInt(int64(quota.Orders)),
+			Projected: decimal.NewFromInt(int64(len(sent) + 1)),
+		}
+	}
+	q.sent[client] = append(sent, now)
+	return nil
+}
+
+// PlaceOrderAs places req on behalf of an authenticated gateway client: the
+// client needs the trade role and room in its quota, and is recorded in the
+// order's Metadata under GatewayClientKey so its executions carry it too
+func (a *FixApplication) PlaceOrderAs(principal Principal, req OrderRequest) (string, error) {
+	if !principal.Role.Allows(RoleTrade) {
+		return "", ErrForbidden
+	}
+	if a.Quotas != nil {
+		if err := a.Quotas.Allow(principal.Name, req, a.now()); err != nil {
+			a.logger().Printf("Order from gateway client %s blocked: %v", principal.Name, err)
+			return "", err
+		}
+	}
+
+	metadata := maps.Clone(req.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	metadata[GatewayClientKey] = principal.Name
+	req.Metadata = metadata
+
+	clOrdId, err := a.PlaceOrder(req)
+	a.logger().Printf("Gateway client %s placed order %s: %v", principal.Name, clOrdId, err)
+	return clOrdId, err
+}
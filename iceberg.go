@@ -0,0 +1,242 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// IcebergRequest works Quantity as a sequence of child limit orders of at most
+// DisplaySize, waiting Interval after each child completes before the next
+type IcebergRequest struct {
+	Symbol      string
+	Side        string
+	Quantity    string
+	LimitPrice  string
+	DisplaySize string
+	Interval    time.Duration
+	Metadata    map[string]string
+}
+
+// Iceberg is the aggregate progress of an iceberg parent
+type Iceberg struct {
+	Id        string
+	Quantity  decimal.Decimal
+	FilledQty decimal.Decimal
+	Children  []string // ClOrdIDs of the child orders, oldest first
+	Done      bool
+
+	request IcebergRequest
+	display decimal.Decimal
+	active  string
+}
+
+// PercentComplete returns the filled share of the parent quantity, 0-100
+func (i Iceberg) PercentComplete() float64 {
+	if i.Quantity.IsZero() {
+		return 0
+	}
+	pct, _ := i.FilledQty.Div(i.Quantity).Mul(decimal.NewFromInt(100)).Float64()
+	return pct
+}
+
+// IcebergManager slices iceberg parents into child orders client-side
+type IcebergManager struct {
+	mu       sync.Mutex
+	icebergs map[string]*Iceberg
+	children map[string]*Iceberg // child ClOrdID -> parent
+	held     heldReports
+
+	place  func(req OrderRequest) (string, error)
+	cancel func(clOrdID string) error
+
+	// Clock times the interval between children; nil uses the wall clock
+	Clock Clock
+}
+
+// NewIcebergManager creates a manager placing and cancelling children through place and cancel
+func NewIcebergManager(place func(req OrderRequest) (string, error), cancel func(clOrdID string) error) *IcebergManager {
+	return &IcebergManager{
+		icebergs: make(map[string]*Iceberg),
+		children: make(map[string]*Iceberg),
+		place:    place,
+		cancel:   cancel,
+	}
+}
+
+// Submit starts working req, placing its first child immediately
+func (m *IcebergManager) Submit(req IcebergRequest) (string, error) {
+	quantity, err := decimal.NewFromString(req.Quantity)
+	if err != nil || !quantity.IsPositive() {
+		return "", errors.New("iceberg quantity must be a positive decimal")
+	}
+	display, err := decimal.NewFromString(req.DisplaySize)
+	if err != nil || !display.IsPositive() {
+		return "", errors.New("iceberg display size must be a positive decimal")
+	}
+
+	iceberg := &Iceberg{
+		Id:       "ice-" + strconv.FormatInt(clockOrSystem(m.Clock).Now().UnixNano(), 10),
+		Quantity: quantity,
+		request:  req,
+		display:  display,
+	}
+
+	m.mu.Lock()
+	m.held.placing++
+	m.mu.Unlock()
+
+	if err := m.placeChild(iceberg); err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	m.icebergs[iceberg.Id] = iceberg
+	m.mu.Unlock()
+	return iceberg.Id, nil
+}
+
+// Get returns a copy of the iceberg id
+func (m *IcebergManager) Get(id string) (Iceberg, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	iceberg, ok := m.icebergs[id]
+	if !ok {
+		return Iceberg{}, false
+	}
+	copied := *iceberg
+	copied.Children = append([]string(nil), iceberg.Children...)
+	return copied, true
+}
+
+// Cancel stops working the iceberg and cancels its active child
+func (m *IcebergManager) Cancel(id string) error {
+	m.mu.Lock()
+	iceberg, ok := m.icebergs[id]
+	if !ok || iceberg.Done {
+		m.mu.Unlock()
+		return errors.New("no working iceberg " + id)
+	}
+	iceberg.Done = true
+	active := iceberg.active
+	m.mu.Unlock()
+
+	// A child still being placed is cancelled by placeChild once it is known
+	if active != "" {
+		return m.cancel(active)
+	}
+	return nil
+}
+
+// OnExecutionReport aggregates child fills and schedules the next child once
+// the active one completes. A rejected child stops the iceberg.
+func (m *IcebergManager) OnExecutionReport(report ExecutionReport) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.children[report.ClOrdID]; ok || !m.held.hold(report) {
+		m.apply(report)
+	}
+}
+
+// apply adds report to its iceberg; callers must hold mu
+func (m *IcebergManager) apply(report ExecutionReport) {
+	iceberg, ok := m.children[report.ClOrdID]
+	if !ok {
+		return
+	}
+
+	switch report.ExecType {
+	case "1", "2", "F": // Partial fill, Fill, Trade
+		if qty, err := decimal.NewFromString(report.LastShares); err == nil {
+			iceberg.FilledQty = iceberg.FilledQty.Add(qty)
+		}
+	}
+
+	if !orderStateFromOrdStatus[report.OrdStatus].Terminal() || report.ClOrdID != iceberg.active {
+		return
+	}
+	iceberg.active = ""
+
+	if iceberg.Done || !iceberg.FilledQty.LessThan(iceberg.Quantity) {
+		iceberg.Done = true
+		log.Printf("Iceberg %s complete: Filled=%s/%s", iceberg.Id, iceberg.FilledQty, iceberg.Quantity)
+		return
+	}
+	// A reject, such as insufficient funds or a halted symbol, would only be
+	// repeated by the next child
+	if report.OrdStatus == "8" {
+		iceberg.Done = true
+		log.Printf("Iceberg %s stopped, child %s rejected: %s Filled=%s/%s", iceberg.Id, report.ClOrdID, report.Text, iceberg.FilledQty, iceberg.Quantity)
+		return
+	}
+
+	clockOrSystem(m.Clock).AfterFunc(iceberg.request.Interval, func() {
+		m.mu.Lock()
+		if iceberg.Done {
+			m.mu.Unlock()
+			return
+		}
+		m.held.placing++
+		m.mu.Unlock()
+
+		if err := m.placeChild(iceberg); err != nil {
+			log.Printf("Failed to place next child of iceberg %s: %v", iceberg.Id, err)
+		}
+	})
+}
+
+// placeChild places the next child for the remaining quantity without mu,
+// which the caller has counted it as a placement in flight under
+func (m *IcebergManager) placeChild(iceberg *Iceberg) error {
+	m.mu.Lock()
+	size := decimal.Min(iceberg.display, iceberg.Quantity.Sub(iceberg.FilledQty))
+	m.mu.Unlock()
+
+	clOrdId, err := m.place(OrderRequest{
+		Symbol:     iceberg.request.Symbol,
+		OrdType:    "LIMIT",
+		Side:       iceberg.request.Side,
+		Quantity:   size.String(),
+		LimitPrice: iceberg.request.LimitPrice,
+		Metadata:   iceberg.request.Metadata,
+	})
+
+	m.mu.Lock()
+	canceled := false
+	if err == nil {
+		iceberg.active = clOrdId
+		iceberg.Children = append(iceberg.Children, clOrdId)
+		m.children[clOrdId] = iceberg
+		canceled = iceberg.Done
+	}
+	for _, report := range m.held.release(func(clOrdID string) bool { _, ok := m.children[clOrdID]; return ok }) {
+		m.apply(report)
+	}
+	m.mu.Unlock()
+
+	if canceled {
+		if err := m.cancel(clOrdId); err != nil {
+			log.Printf("Failed to cancel child %s of canceled iceberg %s: %v", clOrdId, iceberg.Id, err)
+		}
+	}
+	return err
+}
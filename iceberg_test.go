@@ -0,0 +1,151 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestIcebergSlicesParent(t *testing.T) {
+	legs := &legRecorder{}
+	clock := NewFakeClock(time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC))
+	icebergs := NewIcebergManager(legs.place, legs.cancel)
+	icebergs.Clock = clock
+	id, err := icebergs.Submit(IcebergRequest{Symbol: "BTC-USD", Side: "BUY", Quantity: "5", LimitPrice: "100", DisplaySize: "2", Interval: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each child fills completely; the next waits for the interval
+	for _, size := range []string{"2", "2", "1"} {
+		child := legs.placed[len(legs.placed)-1]
+		if child.Quantity != size || child.OrdType != "LIMIT" || child.LimitPrice != "100" {
+			t.Fatalf("child %+v, want %s", child, size)
+		}
+		placed := len(legs.placed)
+		icebergs.OnExecutionReport(ExecutionReport{ClOrdID: strconv.Itoa(placed), ExecType: "2", OrdStatus: "2", LastShares: size})
+		if len(legs.placed) != placed {
+			t.Fatal("next child placed before the interval")
+		}
+		clock.Advance(time.Second)
+	}
+
+	iceberg, _ := icebergs.Get(id)
+	if !iceberg.Done || !slices.Equal(iceberg.Children, []string{"1", "2", "3"}) || iceberg.PercentComplete() != 100 {
+		t.Fatalf("iceberg %+v, %.0f%% complete", iceberg, iceberg.PercentComplete())
+	}
+	if len(legs.placed) != 3 {
+		t.Fatalf("placed %d children, want 3", len(legs.placed))
+	}
+}
+
+func TestIcebergCancel(t *testing.T) {
+	legs := &legRecorder{}
+	clock := NewFakeClock(time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC))
+	icebergs := NewIcebergManager(legs.place, legs.cancel)
+	icebergs.Clock = clock
+	id, _ := icebergs.Submit(IcebergRequest{Quantity: "4", DisplaySize: "2", Interval: time.Second})
+
+	icebergs.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "1", OrdStatus: "1", LastShares: "1"})
+	if err := icebergs.Cancel(id); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(legs.canceled, []string{"1"}) {
+		t.Fatalf("canceled %v, want the active child", legs.canceled)
+	}
+	icebergs.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "4", OrdStatus: "4"})
+	clock.Advance(time.Minute)
+
+	iceberg, _ := icebergs.Get(id)
+	if len(legs.placed) != 1 || !iceberg.Done || iceberg.PercentComplete() != 25 {
+		t.Fatalf("iceberg %+v after cancel, placed %d children", iceberg, len(legs.placed))
+	}
+	if err := icebergs.Cancel(id); err == nil {
+		t.Fatal("canceled a finished iceberg")
+	}
+}
+
+func TestIcebergStopsOnRejectedChild(t *testing.T) {
+	legs := &legRecorder{}
+	clock := NewFakeClock(time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC))
+	icebergs := NewIcebergManager(legs.place, legs.cancel)
+	icebergs.Clock = clock
+	id, _ := icebergs.Submit(IcebergRequest{Quantity: "4", DisplaySize: "2", Interval: time.Second})
+
+	icebergs.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "8", OrdStatus: "8", Text: "Insufficient funds"})
+	clock.Advance(time.Minute)
+
+	iceberg, _ := icebergs.Get(id)
+	if len(legs.placed) != 1 || !iceberg.Done {
+		t.Fatalf("iceberg %+v after a rejected child, placed %d children", iceberg, len(legs.placed))
+	}
+}
+
+func TestIcebergSubmitValidates(t *testing.T) {
+	legs := &legRecorder{}
+	icebergs := NewIcebergManager(legs.place, legs.cancel)
+	for _, req := range []IcebergRequest{
+		{Quantity: "0", DisplaySize: "1"},
+		{Quantity: "1", DisplaySize: ""},
+		{Quantity: "abc", DisplaySize: "1"},
+	} {
+		if _, err := icebergs.Submit(req); err == nil {
+			t.Errorf("Submit(%+v) succeeded", req)
+		}
+	}
+	if len(legs.placed) != 0 {
+		t.Fatalf("placed %+v", legs.placed)
+	}
+}
+
+func TestIcebergSendsOutsideItsLock(t *testing.T) {
+	legs := &legRecorder{}
+	clock := NewFakeClock(time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC))
+	var icebergs *IcebergManager
+	var id string
+	place := func(req OrderRequest) (string, error) {
+		clOrdId, err := legs.place(req)
+		switch clOrdId {
+		case "1":
+			// The first child fills before its send returns
+			icebergs.OnExecutionReport(ExecutionReport{ClOrdID: clOrdId, ExecType: "2", OrdStatus: "2", LastShares: req.Quantity})
+		case "2":
+			// The iceberg is canceled while its second child is in flight
+			if err := icebergs.Cancel(id); err != nil {
+				t.Error(err)
+			}
+		}
+		return clOrdId, err
+	}
+	icebergs = NewIcebergManager(place, legs.cancel)
+	icebergs.Clock = clock
+
+	var err error
+	if id, err = icebergs.Submit(IcebergRequest{Quantity: "4", DisplaySize: "2", Interval: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Second)
+
+	iceberg, _ := icebergs.Get(id)
+	if !iceberg.Done || !slices.Equal(iceberg.Children, []string{"1", "2"}) {
+		t.Fatalf("iceberg %+v", iceberg)
+	}
+	if !slices.Equal(legs.canceled, []string{"2"}) {
+		t.Fatalf("canceled %v, want the child placed after the cancel", legs.canceled)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// initAnswers are what `init` asks for to write a config
+type initAnswers struct {
+	SenderCompID string
+	AccessKey    string
+	Passphrase   string
+	SigningKey   string
+	PortfolioId  string
+	Environment  string // sandbox or production
+	Host         string
+	Port         string
+	TLS          bool
+	DataDir      string // parent of the Sessions and Logs directories
+}
+
+// initConfig renders the config for answers, passing the secrets through
+// encrypt when it is not nil
+func initConfig(answers initAnswers, now time.Time, encrypt func(secret string) (string, error)) (string, error) {
+	secrets := map[string]string{
+		"AccessKey":  answers.AccessKey,
+		"Passphrase": answers.Passphrase,
+		"SigningKey": answers.SigningKey,
+	}
+	if encrypt != nil {
+		for setting, value := range secrets {
+			encrypted, err := encrypt(value)
+			if err != nil {
+				return "", fmt.Errorf("encrypt %s: %w", setting, err)
+			}
+			secrets[setting] = encrypted
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by prime-fix-go init on %s\n", now.Format(time.DateOnly))
+	b.WriteString("[DEFAULT]\n")
+	for _, line := range envDefaults {
+		if strings.HasPrefix(line, "SSLEnable=") && !answers.TLS {
+			line = "SSLEnable=N"
+		}
+		b.WriteString(line + "\n")
+	}
+	sessions := filepath.Join(answers.DataDir, "Sessions")
+	fmt.Fprintf(&b, "FileStorePath=%s\n", sessions+string(filepath.Separator))
+	fmt.Fprintf(&b, "FileLogPath=%s\n", filepath.Join(answers.DataDir, "Logs")+string(filepath.Separator))
+	fmt.Fprintf(&b, "ExecutionStorePath=%s\n", filepath.Join(sessions, "executions.jsonl"))
+	fmt.Fprintf(&b, "Environment=%s\n", answers.Environment)
+	for _, setting := range []string{"AccessKey", "Passphrase", "SigningKey"} {
+		fmt.Fprintf(&b, "%s=%s\n", setting, secrets[setting])
+	}
+	fmt.Fprintf(&b, "PortfolioId=%s\n", answers.PortfolioId)
+	b.WriteString("\n[SESSION]\n")
+	fmt.Fprintf(&b, "SenderCompID=%s\n", answers.SenderCompID)
+	fmt.Fprintf(&b, "SocketConnectHost=%s\n", answers.Host)
+	fmt.Fprintf(&b, "SocketConnectPort=%s\n", answers.Port)
+	return b.String(), nil
+}
+
+// initWizard asks questions on out and reads the answers from in
+type initWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask returns the answer to question, def when it is left empty; without a
+// default an answer is required
+func (w initWizard) ask(question, def string) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(w.out, "%s: ", question)
+		}
+		line, err := w.in.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if answer != "" {
+			return answer, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintln(w.out, "An answer is required.")
+	}
+}
+
+// confirm asks a yes/no question, defaulting to no
+func (w initWizard) confirm(question string) bool {
+	answer, _ := w.ask(question+" (y/N)", "N")
+	return answer == "y" || answer == "Y" || answer == "yes"
+}
+
+// answers collects the config answers
+func (w initWizard) answers() (initAnswers, error) {
+	var a initAnswers
+	var err error
+	questions := []struct {
+		question, def string
+		answer        *string
+	}{
+		{"Service account ID (SenderCompID)", "", &a.SenderCompID},
+		{"API access key", "", &a.AccessKey},
+		{"API passphrase", "", &a.Passphrase},
+		{"API signing key", "", &a.SigningKey},
+		{"Portfolio ID", "", &a.PortfolioId},
+		{"Environment, sandbox or production", "sandbox", &a.Environment},
+		{"FIX host", "", &a.Host},
+		{"FIX port", "4198", &a.Port},
+		{"Directory for the message store and logs", ".", &a.DataDir},
+	}
+	for _, q := range questions {
+		if *q.answer, err = w.ask(q.question, q.def); err != nil {
+			return a, err
+		}
+	}
+	if a.Environment != "sandbox" && a.Environment != "production" {
+		return a, fmt.Errorf("unknown environment %q", a.Environment)
+	}
+	tls, err := w.ask("Connect with TLS? N when going through stunnel (Y/n)", "Y")
+	a.TLS = tls != "n" && tls != "N" && tls != "no"
+	return a, err
+}
+
+// runInit implements `init`, the guided first-time setup: it asks for the
+// credentials and connection details, writes the config and its directories,
+// runs the connectivity doctor and offers to round trip a sandbox test order
+func runInit(args []string) int {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	config := flags.String("config", "fix.cfg", "config file to write")
+	force := flags.Bool("force", false, "overwrite an existing config file")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout of the connectivity checks")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if _, err := os.Stat(*config); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "%s already exists; pass -force to overwrite it\n", *config)
+		return 2
+	}
+
+	wizard := initWizard{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	fmt.Println("Setting up prime-fix-go. Answers are echoed as you type them.")
+	answers, err := wizard.answers()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "\nSetup aborted:", err)
+		return 1
+	}
+
+	// Encrypt the secrets when a secret key is configured
+	var encrypt func(string) (string, error)
+	if key, err := secretKeyFromEnv(); err == nil {
+		encrypt = func(secret string) (string, error) { return EncryptSecret(key, []byte(secret)) }
+		fmt.Println("Encrypting the secrets under", envPrefix+"SECRET_KEY")
+	} else {
+		fmt.Println("Secrets are stored in the clear; see `secret keygen` to encrypt them")
+	}
+	text, err := initConfig(answers, time.Now(), encrypt)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to generate config:", err)
+		return 1
+	}
+	for _, dir := range []string{"Sessions", "Logs"} {
+		if err := os.MkdirAll(filepath.Join(answers.DataDir, dir), 0700); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to create directory:", err)
+			return 1
+		}
+	}
+	if err := os.WriteFile(*config, []byte(text), 0600); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to write config:", err)
+		return 1
+	}
+	fmt.Println("Wrote", *config)
+
+	tenants, err := LoadTenantConfigs(*config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load the new config:", err)
+		return 1
+	}
+	fmt.Println("\nChecking connectivity:")
+	if !printDoctorChecks(os.Stdout, RunDoctor(tenants[0], *timeout)) {
+		fmt.Printf("\nFix the failures above, then rerun `prime-fix-go doctor -config %s`\n", *config)
+		return 1
+	}
+
+	if answers.Environment == "sandbox" && wizard.confirm("\nPlace a sandbox test order and cancel it?") {
+		req := OrderRequest{OrdType: "LIMIT", Side: "BUY"}
+		for _, q := range []struct {
+			question, def string
+			answer        *string
+		}{
+			{"Symbol", "BTC-USD", &req.Symbol},
+			{"Quantity", "0.0001", &req.Quantity},
+			{"Limit price, far enough from the market not to fill", "1", &req.LimitPrice},
+		} {
+			if *q.answer, err = wizard.ask(q.question, q.def); err != nil {
+				fmt.Fprintln(os.Stderr, "\nSetup aborted:", err)
+				return 1
+			}
+		}
+		if err := placeTestOrder(tenants[0], req, 30*time.Second); err != nil {
+			fmt.Fprintln(os.Stderr, "Test order failed:", err)
+			return 1
+		}
+		fmt.Println("Test order placed and cancelled")
+	}
+
+	fmt.Printf("\nSetup complete. Start the client with `prime-fix-go -config %s`\n", *config)
+	return 0
+}
+
+// placeTestOrder logs the tenant on, places req and cancels it once it is
+// acknowledged, waiting up to timeout for each step
+func placeTestOrder(config TenantConfig, req OrderRequest, timeout time.Duration) error {
+	tenant := newTenant(config)
+	reports := make(chan ExecutionReport, 64)
+	tenant.App.OnExecutionReport = func(report ExecutionReport) {
+		select {
+		case reports <- report:
+		default:
+		}
+	}
+
+	manager := NewManager()
+	if err := manager.Add(tenant); err != nil {
+		return err
+	}
+	if err := manager.Start(tenant.Name); err != nil {
+		return err
+	}
+	defer manager.Stop(tenant.Name)
+
+	fmt.Println("Logging on...")
+	deadline := time.Now().Add(timeout)
+	for !tenant.App.IsLoggedOn() {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("not logged on within %s, see the FIX logs", timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	clOrdID, err := tenant.App.PlaceOrder(req)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Placed order", clOrdID)
+	report, err := awaitReport(reports, clOrdID, timeout)
+	if err != nil {
+		return err
+	}
+	if report.ExecType != "0" {
+		return fmt.Errorf("order %s: %s %s", clOrdID, ExecTypeName(report.ExecType), report.Text)
+	}
+
+	if err := tenant.App.CancelOrder(clOrdID); err != nil {
+		return err
+	}
+	fmt.Println("Acknowledged, cancelling")
+	if report, err = awaitReport(reports, clOrdID, timeout); err != nil {
+		return err
+	}
+	if report.ExecType != "4" {
+		return fmt.Errorf("order %s not cancelled: %s %s", clOrdID, ExecTypeName(report.ExecType), report.Text)
+	}
+	return nil
+}
+
+// awaitReport returns the next report for the order clOrdID that is not a
+// pending status
+func awaitReport(reports <-chan ExecutionReport, clOrdID string, timeout time.Duration) (ExecutionReport, error) {
+	expired := time.After(timeout)
+	for {
+		select {
+		case report := <-reports:
+			pending := report.ExecType == "A" || report.ExecType == "6" // PendingNew, PendingCancel
+			if (report.ClOrdID == clOrdID || report.OrigClOrdID == clOrdID) && !pending {
+				return report, nil
+			}
+		case <-expired:
+			return ExecutionReport{}, errors.New("no ExecutionReport within " + timeout.String())
+		}
+	}
+}
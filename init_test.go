@@ -0,0 +1,68 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInitConfigPassesDoctor(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	dir := t.TempDir()
+	text, err := initConfig(initAnswers{
+		SenderCompID: "SENDER",
+		AccessKey:    "key",
+		Passphrase:   "passphrase",
+		SigningKey:   "c2lnbmluZw==",
+		PortfolioId:  "portfolio",
+		Environment:  "sandbox",
+		Host:         host,
+		Port:         port,
+		DataDir:      dir,
+	}, time.Now(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "fix.cfg")
+	if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tenants, err := LoadTenantConfigs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tenants) != 1 || tenants[0].Name != "SENDER" {
+		t.Fatalf("unexpected tenants %+v", tenants)
+	}
+	for _, check := range RunDoctor(tenants[0], time.Second) {
+		if check.Err != nil {
+			t.Errorf("%s: %v", check.Name, check.Err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Sessions")); err != nil {
+		t.Fatalf("doctor did not create the store directory: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestStderrIsJournal(t *testing.T) {
+	var stat syscall.Stat_t
+	if err := syscall.Fstat(int(os.Stderr.Fd()), &stat); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		stream string
+		want   bool
+	}{
+		{"stderr is the stream", fmt.Sprintf("%d:%d", stat.Dev, stat.Ino), true},
+		{"another stream", fmt.Sprintf("%d:%d", stat.Dev, stat.Ino+1), false},
+		{"not under systemd", "", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("JOURNAL_STREAM", tt.stream)
+		if got := stderrIsJournal(); got != tt.want {
+			t.Errorf("%s: stderrIsJournal = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
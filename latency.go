@@ -0,0 +1,151 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// latencyBuckets are the upper bounds of the LatencyHistogram buckets
+var latencyBuckets = []time.Duration{
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+}
+
+// LatencyHistogram counts how long received messages took to handle, from
+// the session handing them over to the handler returning. Counts[i] is the
+// number at or under Buckets[i]; the last count holds everything slower.
+type LatencyHistogram struct {
+	Buckets []time.Duration
+	Counts  []uint64
+	Count   uint64
+	Sum     time.Duration
+	Max     time.Duration
+}
+
+func newLatencyHistogram() *LatencyHistogram {
+	return &LatencyHistogram{Buckets: latencyBuckets, Counts: make([]uint64, len(latencyBuckets)+1)}
+}
+
+func (h *LatencyHistogram) observe(d time.Duration) {
+	i := sort.Search(len(h.Buckets), func(i int) bool { return d <= h.Buckets[i] })
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+	h.Max = max(h.Max, d)
+}
+
+// Mean returns the average latency
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns the upper bound of the bucket holding quantile q, e.g. 0.99,
+// or Max when it falls in the overflow bucket
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	rank := max(uint64(math.Ceil(q*float64(h.Count))), 1)
+	var seen uint64
+	for i, count := range h.Counts {
+		seen += count
+		if seen >= rank && i < len(h.Buckets) {
+			return min(h.Buckets[i], h.Max)
+		}
+	}
+	return h.Max
+}
+
+// latencyRecorder keeps a LatencyHistogram per MsgType
+type latencyRecorder struct {
+	mu     sync.Mutex
+	byType map[string]*LatencyHistogram
+}
+
+func (r *latencyRecorder) observe(msgType string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.byType == nil {
+		r.byType = make(map[string]*LatencyHistogram)
+	}
+	h, ok := r.byType[msgType]
+	if !ok {
+		h = newLatencyHistogram()
+		r.byType[msgType] = h
+	}
+	h.observe(d)
+}
+
+func (r *latencyRecorder) snapshot() map[string]LatencyHistogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	histograms := make(map[string]LatencyHistogram, len(r.byType))
+	for msgType, h := range r.byType {
+		copied := *h
+		copied.Counts = append([]uint64(nil), h.Counts...)
+		histograms[msgType] = copied
+	}
+	return histograms
+}
+
+// Latencies returns the handling latency histogram of each MsgType received
+func (a *FixApplication) Latencies() map[string]LatencyHistogram {
+	return a.latency.snapshot()
+}
+
+// observeLatency records the time since start spent handling msg, warning
+// when the handler held up the session for longer than SlowHandlerThreshold
+func (a *FixApplication) observeLatency(msg *quickfix.Message, start time.Time) {
+	d := time.Since(start)
+	msgType, _ := msg.Header.GetString(quickfix.Tag(35))
+	a.latency.observe(msgType, d)
+
+	if a.SlowHandlerThreshold > 0 && d > a.SlowHandlerThreshold {
+		a.logger().Printf("Slow handler: MsgType %s took %s, holding up the session (threshold %s)",
+			msgType, d.Round(time.Microsecond), a.SlowHandlerThreshold)
+	}
+}
+
+// writeLatencyReport prints a line per MsgType with its count and latencies
+func writeLatencyReport(w io.Writer, histograms map[string]LatencyHistogram) {
+	msgTypes := make([]string, 0, len(histograms))
+	for msgType := range histograms {
+		msgTypes = append(msgTypes, msgType)
+	}
+	sort.Strings(msgTypes)
+
+	fmt.Fprintf(w, "%-8s %10s %10s %10s %10s %10s\n", "MsgType", "Count", "Mean", "p50", "p99", "Max")
+	for _, msgType := range msgTypes {
+		h := histograms[msgType]
+		fmt.Fprintf(w, "%-8s %10d %10s %10s %10s %10s\n", msgType, h.Count,
+			h.Mean().Round(time.Microsecond), h.Quantile(0.5), h.Quantile(0.99), h.Max.Round(time.Microsecond))
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyHistogramQuantile(t *testing.T) {
+	h := newLatencyHistogram()
+	for i := 0; i < 98; i++ {
+		h.observe(200 * time.Microsecond)
+	}
+	h.observe(20 * time.Millisecond)
+	h.observe(7 * time.Second)
+
+	if got := h.Quantile(0.5); got != 500*time.Microsecond {
+		t.Errorf("p50 = %s, want 500µs", got)
+	}
+	if got := h.Quantile(0.99); got != 50*time.Millisecond {
+		t.Errorf("p99 = %s, want 50ms", got)
+	}
+	if got := h.Quantile(1); got != 7*time.Second {
+		t.Errorf("p100 = %s, want 7s", got)
+	}
+	if h.Count != 100 || h.Max != 7*time.Second {
+		t.Errorf("count %d max %s", h.Count, h.Max)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+// ErrStopped is returned by AwaitLeadership when stop is closed first
+var ErrStopped = errors.New("stopped before acquiring leadership")
+
+// LeaderLock elects one of several connector instances to hold the FIX
+// session. Implementations may be backed by a file lock on a shared volume or
+// by an external coordinator such as Redis or etcd.
+type LeaderLock interface {
+	// TryAcquire takes leadership if no other instance holds it, without blocking
+	TryAcquire() (bool, error)
+	// Release gives up leadership
+	Release() error
+	// Lost is closed if leadership is lost after being acquired, e.g. when a
+	// lease expires. A nil channel means leadership is held until Release.
+	Lost() <-chan struct{}
+}
+
+// AwaitLeadership blocks as a standby, polling lock every interval until it
+// is acquired or stop is closed
+func AwaitLeadership(lock LeaderLock, interval time.Duration, stop <-chan struct{}) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logged := false
+	for {
+		acquired, err := lock.TryAcquire()
+		if err != nil {
+			return err
+		}
+		if acquired {
+			log.Println("Acquired leadership")
+			return nil
+		}
+		if !logged {
+			log.Println("Another instance is leader, running as standby")
+			logged = true
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return ErrStopped
+		}
+	}
+}
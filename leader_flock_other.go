@@ -0,0 +1,31 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !unix
+
+package main
+
+import "errors"
+
+// FileLock is only available on unix platforms
+type FileLock struct{}
+
+// NewFileLock returns an error on platforms without flock
+func NewFileLock(path string) (*FileLock, error) {
+	return nil, errors.New("file lock leader election requires a unix platform")
+}
+
+func (l *FileLock) TryAcquire() (bool, error) { return false, nil }
+func (l *FileLock) Release() error            { return nil }
+func (l *FileLock) Lost() <-chan struct{}     { return nil }
@@ -0,0 +1,59 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "leader.lock")
+	leader, _ := NewFileLock(path)
+	standby, _ := NewFileLock(path)
+	missing, _ := NewFileLock(filepath.Join(dir, "missing", "leader.lock"))
+
+	tests := []struct {
+		name    string
+		step    func() (bool, error)
+		want    bool
+		wantErr bool
+	}{
+		{"leader acquires", leader.TryAcquire, true, false},
+		{"leader reacquires", leader.TryAcquire, true, false},
+		{"standby blocked", standby.TryAcquire, false, false},
+		{"leader releases", func() (bool, error) { return false, leader.Release() }, false, false},
+		{"standby takes over", standby.TryAcquire, true, false},
+		{"unopenable path", missing.TryAcquire, false, true},
+	}
+	for _, tt := range tests {
+		got, err := tt.step()
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: err = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("%s: acquired = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+	if err := standby.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := standby.Release(); err != nil {
+		t.Errorf("second Release = %v", err)
+	}
+}
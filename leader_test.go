@@ -0,0 +1,73 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type scriptedLock struct {
+	results []bool
+	err     error
+	calls   int
+}
+
+func (l *scriptedLock) TryAcquire() (bool, error) {
+	l.calls++
+	if l.err != nil {
+		return false, l.err
+	}
+	if l.calls > len(l.results) {
+		return false, nil
+	}
+	return l.results[l.calls-1], nil
+}
+
+func (l *scriptedLock) Release() error        { return nil }
+func (l *scriptedLock) Lost() <-chan struct{} { return nil }
+
+func TestAwaitLeadership(t *testing.T) {
+	lockErr := errors.New("lock unavailable")
+	tests := []struct {
+		name      string
+		lock      *scriptedLock
+		stop      bool
+		wantErr   error
+		wantCalls int
+	}{
+		{"acquired at once", &scriptedLock{results: []bool{true}}, false, nil, 1},
+		{"acquired after standby", &scriptedLock{results: []bool{false, false, true}}, false, nil, 3},
+		{"lock error", &scriptedLock{err: lockErr}, false, lockErr, 1},
+		{"stopped as standby", &scriptedLock{}, true, ErrStopped, 1},
+	}
+	for _, tt := range tests {
+		stop := make(chan struct{})
+		if tt.stop {
+			close(stop)
+		}
+		interval := time.Millisecond
+		if tt.stop {
+			interval = time.Hour
+		}
+		if err := AwaitLeadership(tt.lock, interval, stop); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: AwaitLeadership = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if tt.lock.calls != tt.wantCalls {
+			t.Errorf("%s: TryAcquire called %d times, want %d", tt.name, tt.lock.calls, tt.wantCalls)
+		}
+	}
+}
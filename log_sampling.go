@@ -0,0 +1,136 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// LogSampler decides which messages get logged, per MsgType: a rate of N
+// logs one message in N and 0 logs none. MsgTypes without a rate take the
+// rate of "*", and are all logged when there is none.
+type LogSampler struct {
+	mu     sync.Mutex
+	rates  map[string]int
+	counts map[string]uint64
+}
+
+// NewLogSampler creates a sampler applying rates by MsgType
+func NewLogSampler(rates map[string]int) *LogSampler {
+	return &LogSampler{rates: rates, counts: make(map[string]uint64)}
+}
+
+// ParseLogSampling parses comma separated MsgType:rate pairs, e.g.
+// "0:0,W:1000,X:1000" to log no heartbeats and one market data message in 1000
+func ParseLogSampling(value string) (map[string]int, error) {
+	rates := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		msgType, rate, ok := strings.Cut(pair, ":")
+		n, err := strconv.Atoi(strings.TrimSpace(rate))
+		if !ok || msgType == "" || err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid log sampling %q, want MsgType:rate", pair)
+		}
+		rates[strings.TrimSpace(msgType)] = n
+	}
+	return rates, nil
+}
+
+// Sample reports whether the next message of msgType should be logged. A nil
+// sampler logs everything.
+func (s *LogSampler) Sample(msgType string) bool {
+	if s == nil {
+		return true
+	}
+	rate, ok := s.rates[msgType]
+	if !ok {
+		rate, ok = s.rates["*"]
+	}
+	if !ok || rate == 1 {
+		return true
+	}
+	if rate == 0 {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := s.counts[msgType]
+	s.counts[msgType]++
+	return count%uint64(rate) == 0
+}
+
+// rawMsgType returns the MsgType (35) of a raw FIX message
+func rawMsgType(raw []byte) string {
+	_, value, ok := bytes.Cut(raw, []byte("\x0135="))
+	if !ok {
+		return ""
+	}
+	msgType, _, _ := bytes.Cut(value, []byte("\x01"))
+	return string(msgType)
+}
+
+// sampledLogFactory wraps a quickfix LogFactory so the message logs it
+// creates only record the messages their LogSampler picks; events are kept
+type sampledLogFactory struct {
+	quickfix.LogFactory
+	rates map[string]int
+}
+
+// NewSampledLogFactory wraps factory to sample its message logs by rates
+func NewSampledLogFactory(factory quickfix.LogFactory, rates map[string]int) quickfix.LogFactory {
+	return sampledLogFactory{LogFactory: factory, rates: rates}
+}
+
+func (f sampledLogFactory) Create() (quickfix.Log, error) {
+	log, err := f.LogFactory.Create()
+	if err != nil {
+		return nil, err
+	}
+	return sampledLog{Log: log, sampler: NewLogSampler(f.rates)}, nil
+}
+
+func (f sampledLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	log, err := f.LogFactory.CreateSessionLog(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return sampledLog{Log: log, sampler: NewLogSampler(f.rates)}, nil
+}
+
+type sampledLog struct {
+	quickfix.Log
+	sampler *LogSampler
+}
+
+func (l sampledLog) OnIncoming(raw []byte) {
+	if l.sampler.Sample(rawMsgType(raw)) {
+		l.Log.OnIncoming(raw)
+	}
+}
+
+func (l sampledLog) OnOutgoing(raw []byte) {
+	if l.sampler.Sample(rawMsgType(raw)) {
+		l.Log.OnOutgoing(raw)
+	}
+}
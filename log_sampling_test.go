@@ -0,0 +1,92 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestParseLogSampling(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    map[string]int
+		wantErr bool
+	}{
+		{"0:0, W:1000,X:1000", map[string]int{"0": 0, "W": 1000, "X": 1000}, false},
+		{"", map[string]int{}, false},
+		{"*:10,", map[string]int{"*": 10}, false},
+		{"W", nil, true},
+		{":5", nil, true},
+		{"W:often", nil, true},
+		{"W:-1", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLogSampling(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: err = %v, want error %t", tt.value, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: rates %v, want %v", tt.value, got, tt.want)
+		}
+		for msgType, rate := range tt.want {
+			if got[msgType] != rate {
+				t.Errorf("%q: rates %v, want %v", tt.value, got, tt.want)
+			}
+		}
+	}
+}
+
+type countingLog struct {
+	quickfix.Log
+	incoming []string
+}
+
+func (l *countingLog) OnIncoming(raw []byte) { l.incoming = append(l.incoming, rawMsgType(raw)) }
+
+func TestLogSampler(t *testing.T) {
+	tests := []struct {
+		name    string
+		rates   map[string]int
+		msgType string
+		want    int // logged of 10
+	}{
+		{"unsampled", map[string]int{"W": 5}, "8", 10},
+		{"one in five", map[string]int{"W": 5}, "W", 2},
+		{"dropped", map[string]int{"0": 0}, "0", 0},
+		{"wildcard", map[string]int{"*": 3, "8": 1}, "X", 4},
+		{"overrides wildcard", map[string]int{"*": 3, "8": 1}, "8", 10},
+	}
+	for _, tt := range tests {
+		log := &countingLog{}
+		sampled := sampledLog{Log: log, sampler: NewLogSampler(tt.rates)}
+		for i := 0; i < 10; i++ {
+			sampled.OnIncoming([]byte("8=FIX.4.2\x019=5\x0135=" + tt.msgType + "\x0110=000\x01"))
+		}
+		if len(log.incoming) != tt.want {
+			t.Errorf("%s: logged %d of 10, want %d", tt.name, len(log.incoming), tt.want)
+		}
+	}
+
+	var sampler *LogSampler
+	if !sampler.Sample("0") {
+		t.Error("nil sampler skipped a message")
+	}
+	if got := rawMsgType([]byte("8=FIX.4.2\x019=5\x01")); got != "" {
+		t.Errorf("MsgType of a message without one = %q", got)
+	}
+}
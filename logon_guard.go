@@ -0,0 +1,97 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLogonLockout is reported once consecutive logon rejects reach the limit.
+// Retrying further risks Prime locking the credentials out.
+var ErrLogonLockout = errors.New("too many rejected logons, giving up to avoid a credential lockout; check the API key, passphrase and signing key")
+
+// LogonGuard backs off exponentially between rejected logons and gives up
+// after MaxFailures consecutive rejects. The backoff happens while
+// disconnected: OnBackoff is asked to reconnect only after the delay.
+type LogonGuard struct {
+	mu       sync.Mutex
+	failures int
+
+	MaxFailures int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+
+	// OnLockout is called once when MaxFailures is reached
+	OnLockout func(err error)
+
+	// OnBackoff is called after every other rejected logon with the delay
+	// before the next attempt, see Manager.Backoff
+	OnBackoff func(delay time.Duration)
+}
+
+// NewLogonGuard creates a guard giving up after maxFailures rejects, waiting
+// base, 2*base, 4*base... up to max between attempts
+func NewLogonGuard(maxFailures int, base, max time.Duration) *LogonGuard {
+	return &LogonGuard{MaxFailures: maxFailures, BaseDelay: base, MaxDelay: max}
+}
+
+// Rejected records a rejected logon
+func (g *LogonGuard) Rejected() {
+	g.mu.Lock()
+	g.failures++
+	lockedOut := g.MaxFailures > 0 && g.failures == g.MaxFailures
+	g.mu.Unlock()
+
+	if lockedOut {
+		if g.OnLockout != nil {
+			g.OnLockout(ErrLogonLockout)
+		}
+		return
+	}
+	if g.OnBackoff != nil {
+		g.OnBackoff(g.Delay())
+	}
+}
+
+// Succeeded resets the failure count after a successful logon
+func (g *LogonGuard) Succeeded() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.failures = 0
+}
+
+// Delay returns how long to wait before the next logon attempt
+func (g *LogonGuard) Delay() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.failures == 0 {
+		return 0
+	}
+	delay := g.BaseDelay
+	for i := 1; i < g.failures && delay < g.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, g.MaxDelay)
+}
+
+// LockedOut reports whether the guard has given up
+func (g *LogonGuard) LockedOut() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.MaxFailures > 0 && g.failures >= g.MaxFailures
+}
@@ -0,0 +1,96 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLogonGuardBacksOffThenLocksOut(t *testing.T) {
+	guard := NewLogonGuard(4, time.Second, 3*time.Second)
+	var delays []time.Duration
+	var lockout error
+	guard.OnBackoff = func(delay time.Duration) { delays = append(delays, delay) }
+	guard.OnLockout = func(err error) { lockout = err }
+
+	if guard.Delay() != 0 {
+		t.Fatalf("delay %s before any reject", guard.Delay())
+	}
+	for i := 0; i < 3; i++ {
+		guard.Rejected()
+	}
+	if want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}; fmt.Sprint(delays) != fmt.Sprint(want) {
+		t.Errorf("delays %v, want %v", delays, want)
+	}
+	if guard.LockedOut() || lockout != nil {
+		t.Fatal("locked out before MaxFailures")
+	}
+
+	guard.Rejected()
+	if !guard.LockedOut() || !errors.Is(lockout, ErrLogonLockout) || len(delays) != 3 {
+		t.Errorf("after MaxFailures: locked out %t, err %v, %d backoffs", guard.LockedOut(), lockout, len(delays))
+	}
+
+	guard.Succeeded()
+	if guard.LockedOut() || guard.Delay() != 0 {
+		t.Error("logon did not reset the guard")
+	}
+}
+
+func TestManagerBackoffReconnectsAfterDelay(t *testing.T) {
+	venue, manager, app := startLogoutVenue(t)
+	if app.LogonGuard.OnBackoff == nil {
+		t.Fatal("Add did not route the guard's backoff to the manager")
+	}
+
+	start := time.Now()
+	if err := manager.Backoff("desk", 300*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if manager.Running("desk") {
+		t.Fatal("still connected while backing off")
+	}
+	select {
+	case <-venue.logon:
+		if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+			t.Errorf("reconnected after %s, before the delay", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reconnect after the delay")
+	}
+}
+
+func TestManagerStopCancelsBackoff(t *testing.T) {
+	venue, manager, _ := startLogoutVenue(t)
+
+	if err := manager.Backoff("desk", 200*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	manager.Stop("desk")
+	select {
+	case <-venue.logon:
+		t.Fatal("reconnected after Stop")
+	case <-ctx.Done():
+	}
+	if manager.Running("desk") {
+		t.Error("tenant running after Stop")
+	}
+}
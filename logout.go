@@ -0,0 +1,112 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ErrLogoutUnconfirmed is returned by Logout when the session ended without
+// Prime answering the Logout within LogoutTimeout
+var ErrLogoutUnconfirmed = errors.New("logout was not confirmed by the venue")
+
+// logoutState tracks a Logout this side initiated
+type logoutState struct {
+	mu        sync.Mutex
+	reason    string
+	active    bool
+	confirmed bool
+}
+
+func (s *logoutState) start(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reason, s.active, s.confirmed = reason, true, false
+}
+
+func (s *logoutState) end() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active = false
+	return s.confirmed
+}
+
+// initiated returns the reason of the Logout in progress, if there is one
+func (s *logoutState) initiated() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reason, s.active
+}
+
+// onLogout records the counter-Logout of the venue
+func (s *logoutState) onLogout() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active {
+		s.confirmed = true
+	}
+	return s.active
+}
+
+// stampLogoutReason sets the reason of the Logout in progress as the Text
+// (58) of an outbound Logout
+func (a *FixApplication) stampLogoutReason(msg *quickfix.Message) {
+	if reason, ok := a.logout.initiated(); ok && reason != "" && !msg.Body.Has(quickfix.Tag(58)) {
+		msg.Body.SetString(quickfix.Tag(58), reason) // Text
+	}
+}
+
+// Logout logs the tenant name out with reason as the Text (58) of its
+// Logout, waits for Prime's counter-Logout and leaves the tenant stopped,
+// saving its snapshot as Stop does, to be started again with Start. The
+// process and the tenant's application state are kept, and the logout is
+// not alerted as a lost session. It returns ErrLogoutUnconfirmed when Prime
+// did not answer within LogoutTimeout, and ctx's error when ctx is done
+// first, in which case the session is still stopped in the background.
+func (m *Manager) Logout(ctx context.Context, name, reason string) error {
+	tenant, ok := m.Get(name)
+	if !ok {
+		return fmt.Errorf("unknown tenant %q", name)
+	}
+	if !tenant.App.IsLoggedOn() {
+		return m.Stop(name)
+	}
+
+	tenant.App.logger().Printf("Logging out: %s", reason)
+	tenant.App.logout.start(reason)
+	stopped := make(chan error, 1)
+	go func() {
+		err := m.Stop(name)
+		if confirmed := tenant.App.logout.end(); err == nil && !confirmed {
+			err = ErrLogoutUnconfirmed
+		}
+		stopped <- err
+	}()
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			return fmt.Errorf("tenant %s: %w", name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tenant %s: %w", name, ctx.Err())
+	}
+}
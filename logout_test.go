@@ -0,0 +1,180 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// logoutAcceptor is a venue that accepts any logon and records the Text of
+// the Logouts and the BeginSeqNo of the ResendRequests it receives
+type logoutAcceptor struct {
+	mu      sync.Mutex
+	logon   chan struct{}
+	reasons []string
+	resends []string
+}
+
+func (v *logoutAcceptor) OnCreate(quickfix.SessionID)                   {}
+func (v *logoutAcceptor) OnLogon(quickfix.SessionID)                    { v.logon <- struct{}{} }
+func (v *logoutAcceptor) OnLogout(quickfix.SessionID)                   {}
+func (v *logoutAcceptor) ToAdmin(*quickfix.Message, quickfix.SessionID) {}
+func (v *logoutAcceptor) ToApp(*quickfix.Message, quickfix.SessionID) error {
+	return nil
+}
+func (v *logoutAcceptor) FromApp(*quickfix.Message, quickfix.SessionID) quickfix.MessageRejectError {
+	return nil
+}
+func (v *logoutAcceptor) FromAdmin(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	switch msgType, _ := msg.Header.GetString(quickfix.Tag(35)); msgType {
+	case "5":
+		v.reasons = append(v.reasons, bodyString(msg, quickfix.Tag(58)))
+	case "2":
+		v.resends = append(v.resends, bodyString(msg, quickfix.Tag(7)))
+	}
+	return nil
+}
+
+func TestManagerLogoutSendsReasonAndStops(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	session := "[SESSION]\nBeginString=FIXT.1.1\nDefaultApplVerID=FIX.5.0SP2\nHeartBtInt=30\nUseDataDictionary=N\n"
+	acceptorSettings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(
+		"[DEFAULT]\nConnectionType=acceptor\nSocketAcceptPort=%d\nSenderCompID=PRIME\nTargetCompID=CLIENT\n%s", port, session)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	venue := &logoutAcceptor{logon: make(chan struct{}, 1)}
+	acceptor, err := quickfix.NewAcceptor(venue, quickfix.NewMemoryStoreFactory(), acceptorSettings, quickfix.NewNullLogFactory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := acceptor.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer acceptor.Stop()
+
+	initiatorSettings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(
+		"[DEFAULT]\nConnectionType=initiator\nSocketConnectHost=127.0.0.1\nSocketConnectPort=%d\nSenderCompID=CLIENT\nTargetCompID=PRIME\nReconnectInterval=1\nLogoutTimeout=2\n%s", port, session)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &FixApplication{TargetCompId: "PRIME"}
+	manager := NewManager()
+	snapshotPath := t.TempDir() + "/snapshot.json"
+	manager.Add(&Tenant{Name: "desk", App: app, Settings: initiatorSettings, StoreFactory: quickfix.NewMemoryStoreFactory(), LogFactory: quickfix.NewNullLogFactory(), SnapshotPath: snapshotPath})
+	if err := manager.Start("desk"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-venue.logon:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no logon")
+	}
+	for !app.IsLoggedOn() {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.Logout(ctx, "desk", "maintenance window"); err != nil {
+		t.Fatal(err)
+	}
+	if manager.Running("desk") || app.IsLoggedOn() {
+		t.Error("tenant still running after logout")
+	}
+	venue.mu.Lock()
+	defer venue.mu.Unlock()
+	if len(venue.reasons) != 1 || venue.reasons[0] != "maintenance window" {
+		t.Errorf("venue received logouts %q", venue.reasons)
+	}
+
+	// The snapshot taken on stop has the sequence numbers after the Logon and Logout
+	snapshot, err := LoadSnapshot(snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.NextSenderMsgSeqNum < 3 || snapshot.NextTargetMsgSeqNum < 2 {
+		t.Errorf("snapshot sequence numbers %d/%d", snapshot.NextSenderMsgSeqNum, snapshot.NextTargetMsgSeqNum)
+	}
+}
+
+// startLogoutVenue starts a venue accepting any logon and a tenant "desk"
+// connecting to it
+func startLogoutVenue(t *testing.T) (*logoutAcceptor, *Manager, *FixApplication) {
+	app := &FixApplication{TargetCompId: "PRIME", LogonGuard: NewLogonGuard(5, time.Second, time.Minute)}
+	venue, manager := startVenueWith(t, app, quickfix.NewMemoryStoreFactory(), quickfix.NewMemoryStoreFactory())
+	return venue, manager, app
+}
+
+// startVenueWith starts a venue accepting any logon with venueStores and a
+// tenant "desk" running app with stores connecting to it
+func startVenueWith(t *testing.T, app *FixApplication, stores, venueStores quickfix.MessageStoreFactory) (*logoutAcceptor, *Manager) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	session := "[SESSION]\nBeginString=FIXT.1.1\nDefaultApplVerID=FIX.5.0SP2\nHeartBtInt=30\nUseDataDictionary=N\n"
+	acceptorSettings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(
+		"[DEFAULT]\nConnectionType=acceptor\nSocketAcceptPort=%d\nSenderCompID=PRIME\nTargetCompID=CLIENT\n%s", port, session)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	venue := &logoutAcceptor{logon: make(chan struct{}, 4)}
+	acceptor, err := quickfix.NewAcceptor(venue, venueStores, acceptorSettings, quickfix.NewNullLogFactory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := acceptor.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(acceptor.Stop)
+
+	initiatorSettings, err := quickfix.ParseSettings(strings.NewReader(fmt.Sprintf(
+		"[DEFAULT]\nConnectionType=initiator\nSocketConnectHost=127.0.0.1\nSocketConnectPort=%d\nSenderCompID=CLIENT\nTargetCompID=PRIME\nReconnectInterval=1\nLogoutTimeout=2\n%s", port, session)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager()
+	manager.Add(&Tenant{Name: "desk", App: app, Settings: initiatorSettings, StoreFactory: stores, LogFactory: quickfix.NewNullLogFactory()})
+	t.Cleanup(func() { manager.Stop("desk") })
+	if err := manager.Start("desk"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-venue.logon:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no logon")
+	}
+	return venue, manager
+}
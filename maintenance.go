@@ -0,0 +1,194 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// MaintenanceWindow is a recurring venue maintenance window, on Day or
+// every day when Daily is set. Start and End are minutes after midnight; a
+// window ending before it starts runs past midnight.
+type MaintenanceWindow struct {
+	Day        time.Weekday
+	Daily      bool
+	Start, End int
+}
+
+// MaintenanceCalendar is the venue's scheduled maintenance windows, in Location
+type MaintenanceCalendar struct {
+	Windows  []MaintenanceWindow
+	Location *time.Location
+}
+
+// ParseMaintenanceWindows parses comma separated windows such as
+// "Sat 12:00-14:00" or, daily, "21:55-22:05"
+func ParseMaintenanceWindows(value string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		window := MaintenanceWindow{Daily: true}
+		if day, span, ok := strings.Cut(field, " "); ok {
+			weekday, err := parseWeekday(day)
+			if err != nil {
+				return nil, err
+			}
+			window.Day, window.Daily, field = weekday, false, strings.TrimSpace(span)
+		}
+		from, to, ok := strings.Cut(field, "-")
+		if !ok {
+			return nil, fmt.Errorf("maintenance window %q: want [day] HH:MM-HH:MM", field)
+		}
+		var err error
+		if window.Start, err = parseMinuteOfDay(from); err != nil {
+			return nil, err
+		}
+		if window.End, err = parseMinuteOfDay(to); err != nil {
+			return nil, err
+		}
+		if window.Start == window.End {
+			return nil, fmt.Errorf("maintenance window %q is empty", field)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(value, day.String()[:3]) || strings.EqualFold(value, day.String()) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", value)
+}
+
+func parseMinuteOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: want HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Next returns the window in progress at t, or else the next one to start.
+// ok is false when the calendar has no windows.
+func (c *MaintenanceCalendar) Next(t time.Time) (start, end time.Time, ok bool) {
+	local := t.In(c.Location)
+	for offset := -1; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, offset)
+		for _, window := range c.Windows {
+			if !window.Daily && day.Weekday() != window.Day {
+				continue
+			}
+			from := time.Date(day.Year(), day.Month(), day.Day(), window.Start/60, window.Start%60, 0, 0, c.Location)
+			to := time.Date(day.Year(), day.Month(), day.Day(), window.End/60, window.End%60, 0, 0, c.Location)
+			if window.End < window.Start {
+				to = to.AddDate(0, 0, 1)
+			}
+			if to.After(t) && (!ok || from.Before(start)) {
+				start, end, ok = from, to, true
+			}
+		}
+	}
+	return start, end, ok
+}
+
+// In reports whether t falls in a window, returning its end
+func (c *MaintenanceCalendar) In(t time.Time) (time.Time, bool) {
+	if c == nil {
+		return time.Time{}, false
+	}
+	start, end, ok := c.Next(t)
+	return end, ok && !t.Before(start)
+}
+
+func (a *FixApplication) inMaintenance() bool {
+	_, in := a.Maintenance.In(a.now())
+	return in
+}
+
+// maintenanceSetting returns the calendar of MaintenanceWindows in
+// MaintenanceTimezone, or nil when no windows are configured
+func maintenanceSetting(settings *quickfix.SessionSettings) (*MaintenanceCalendar, error) {
+	value, err := settings.Setting("MaintenanceWindows")
+	if err != nil {
+		return nil, nil
+	}
+	calendar := &MaintenanceCalendar{Location: time.UTC}
+	if calendar.Windows, err = ParseMaintenanceWindows(value); err != nil {
+		return nil, err
+	}
+	if zone, err := settings.Setting("MaintenanceTimezone"); err == nil {
+		if calendar.Location, err = time.LoadLocation(zone); err != nil {
+			return nil, err
+		}
+	}
+	return calendar, nil
+}
+
+// RunMaintenance drains every tenant lead before each window of the
+// manager's Calendar and starts them again once it is over, until quit is
+// closed. StartAll keeps tenants disconnected in between, so a restart
+// during a window does not connect either.
+func (m *Manager) RunMaintenance(opts DrainOptions, lead time.Duration, clock Clock, quit <-chan struct{}) {
+	clock = clockOrSystem(clock)
+	for {
+		now := clock.Now()
+		start, end, ok := m.Calendar.Next(now)
+		if !ok {
+			return
+		}
+		if now.Before(start) {
+			if !sleepUntil(clock, start.Add(-lead), quit) {
+				return
+			}
+			log.Printf("Prime maintenance from %s to %s, draining", start.Format(time.RFC3339), end.Format(time.RFC3339))
+			for _, result := range m.DrainAll(context.Background(), opts) {
+				if result.Error != "" {
+					log.Printf("Tenant %s did not drain cleanly: %s", result.Tenant, result.Error)
+				}
+			}
+		}
+		if !sleepUntil(clock, end, quit) {
+			return
+		}
+		log.Println("Prime maintenance over, reconnecting")
+		for _, tenant := range m.Tenants() {
+			if err := m.Start(tenant.Name); err != nil {
+				log.Println("Failed to reconnect after maintenance:", err)
+			}
+		}
+	}
+}
+
+// sleepUntil waits for clock to reach t, returning false if quit is closed first
+func sleepUntil(clock Clock, t time.Time, quit <-chan struct{}) bool {
+	due := make(chan struct{})
+	timer := clock.AfterFunc(t.Sub(clock.Now()), func() { close(due) })
+	defer timer.Stop()
+	select {
+	case <-due:
+		return true
+	case <-quit:
+		return false
+	}
+}
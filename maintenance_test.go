@@ -0,0 +1,137 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceCalendar(t *testing.T) {
+	windows, err := ParseMaintenanceWindows("Sat 23:00-01:00, 12:00-12:05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, _ := time.LoadLocation("America/New_York")
+	calendar := &MaintenanceCalendar{Windows: windows, Location: newYork}
+
+	for _, tc := range []struct {
+		at         time.Time
+		start, end time.Time
+		in         bool
+	}{
+		// Friday morning: the daily window comes next
+		{time.Date(2024, 1, 5, 9, 0, 0, 0, newYork), time.Date(2024, 1, 5, 12, 0, 0, 0, newYork), time.Date(2024, 1, 5, 12, 5, 0, 0, newYork), false},
+		{time.Date(2024, 1, 5, 12, 3, 0, 0, newYork), time.Date(2024, 1, 5, 12, 0, 0, 0, newYork), time.Date(2024, 1, 5, 12, 5, 0, 0, newYork), true},
+		// The weekly window runs from Saturday night into Sunday
+		{time.Date(2024, 1, 7, 0, 30, 0, 0, newYork), time.Date(2024, 1, 6, 23, 0, 0, 0, newYork), time.Date(2024, 1, 7, 1, 0, 0, 0, newYork), true},
+		{time.Date(2024, 1, 6, 12, 5, 0, 0, newYork), time.Date(2024, 1, 6, 23, 0, 0, 0, newYork), time.Date(2024, 1, 7, 1, 0, 0, 0, newYork), false},
+	} {
+		start, end, ok := calendar.Next(tc.at)
+		if !ok || !start.Equal(tc.start) || !end.Equal(tc.end) {
+			t.Errorf("Next(%s) = %s-%s, want %s-%s", tc.at, start, end, tc.start, tc.end)
+		}
+		if _, in := calendar.In(tc.at); in != tc.in {
+			t.Errorf("In(%s) = %v", tc.at, in)
+		}
+	}
+
+	for _, invalid := range []string{"Sat", "Xyz 10:00-11:00", "10:00-10:00", "25:00-26:00"} {
+		if _, err := ParseMaintenanceWindows(invalid); err == nil {
+			t.Errorf("parsed %q", invalid)
+		}
+	}
+}
+
+func TestRunMaintenanceDrainsAndReconnects(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 6, 11, 0, 0, 0, time.UTC))
+	windows, _ := ParseMaintenanceWindows("Sat 12:00-14:00")
+	app := &FixApplication{Tracker: NewOrderTracker(time.Minute), Paper: NewPaperVenue(clock, 1)}
+	manager := NewManager()
+	manager.Calendar = &MaintenanceCalendar{Windows: windows, Location: time.UTC}
+	manager.Add(&Tenant{Name: "desk", App: app})
+
+	quit := make(chan struct{})
+	defer close(quit)
+	go manager.RunMaintenance(DrainOptions{}, 5*time.Minute, clock, quit)
+
+	// The scheduler sets its timers asynchronously, so keep setting the
+	// clock until it has caught up
+	at := func(hour, min int, draining bool) {
+		t.Helper()
+		now := time.Date(2024, 1, 6, hour, min, 0, 0, time.UTC)
+		for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+			clock.Set(now)
+			if app.Draining() == draining {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("draining = %v at %s", !draining, now)
+			}
+		}
+	}
+	at(11, 54, false)
+	at(11, 55, true)
+	at(14, 0, false)
+}
+
+func TestStartAllUsesClock(t *testing.T) {
+	windows, _ := ParseMaintenanceWindows("Sat 12:00-14:00")
+	tests := []struct {
+		name     string
+		now      time.Time
+		draining bool
+	}{
+		{"in maintenance", time.Date(2024, 1, 6, 13, 0, 0, 0, time.UTC), true},
+		{"after maintenance", time.Date(2024, 1, 6, 15, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		clock := NewFakeClock(tt.now)
+		app := &FixApplication{Paper: NewPaperVenue(clock, 1)}
+		app.draining.Store(true)
+		manager := NewManager()
+		manager.Clock = clock
+		manager.Calendar = &MaintenanceCalendar{Windows: windows, Location: time.UTC}
+		manager.Add(&Tenant{Name: "desk", App: app})
+
+		if err := manager.StartAll(); err != nil {
+			t.Fatal(err)
+		}
+		if app.Draining() != tt.draining {
+			t.Errorf("%s: draining = %v, want %v", tt.name, app.Draining(), tt.draining)
+		}
+	}
+}
+
+func TestBackoffUsesClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 6, 15, 0, 0, 0, time.UTC))
+	app := &FixApplication{Paper: NewPaperVenue(clock, 1)}
+	manager := NewManager()
+	manager.Clock = clock
+	manager.Add(&Tenant{Name: "desk", App: app})
+
+	if err := manager.Backoff("desk", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	app.draining.Store(true)
+	clock.Advance(59 * time.Second)
+	if !app.Draining() {
+		t.Fatal("reconnected before the backoff elapsed")
+	}
+	clock.Advance(time.Second)
+	if app.Draining() {
+		t.Error("did not reconnect once the backoff elapsed on the clock")
+	}
+}
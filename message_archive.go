@@ -0,0 +1,150 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ArchivedMessage is one raw FIX message in a MessageArchive, stored with the
+// fields it is searched on
+type ArchivedMessage struct {
+	Time        time.Time `json:"time"`
+	Direction   string    `json:"direction"` // "in" from Prime or "out" to Prime
+	MsgType     string    `json:"msg_type"`
+	ClOrdID     string    `json:"clordid,omitempty"`
+	OrigClOrdID string    `json:"orig_clordid,omitempty"`
+	OrderID     string    `json:"order_id,omitempty"`
+	Raw         string    `json:"raw"`
+}
+
+// MessageArchive appends every message sent and received to a JSON lines
+// file for later investigation with `messages search`
+type MessageArchive struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// OpenMessageArchive opens or creates the archive at path
+func OpenMessageArchive(path string) (*MessageArchive, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageArchive{path: path, file: file}, nil
+}
+
+// Record archives msg, sent or received at now
+func (a *MessageArchive) Record(direction string, msg *quickfix.Message, now time.Time) error {
+	entry := ArchivedMessage{
+		Time:        now.UTC(),
+		Direction:   direction,
+		ClOrdID:     bodyString(msg, quickfix.Tag(11)),
+		OrigClOrdID: bodyString(msg, quickfix.Tag(41)),
+		OrderID:     bodyString(msg, quickfix.Tag(37)),
+		Raw:         msg.String(),
+	}
+	entry.MsgType, _ = msg.Header.GetString(quickfix.Tag(35))
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.file.Write(append(line, '\n'))
+	return err
+}
+
+// rewrite closes the file for f to replace it and reopens it after
+func (a *MessageArchive) rewrite(f func(path string) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.file.Close(); err != nil {
+		return err
+	}
+	rewriteErr := f(a.path)
+	file, err := os.OpenFile(a.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	a.file = file
+	return rewriteErr
+}
+
+// Close closes the underlying file
+func (a *MessageArchive) Close() error {
+	return a.file.Close()
+}
+
+// MessageQuery selects archived messages; empty fields match everything.
+// ClOrdID also matches the OrigClOrdID of cancels and replaces.
+type MessageQuery struct {
+	MsgType string
+	ClOrdID string
+	OrderID string
+	Since   time.Time
+	Until   time.Time
+}
+
+// Matches reports whether message is selected by q
+func (q MessageQuery) Matches(message ArchivedMessage) bool {
+	switch {
+	case q.MsgType != "" && message.MsgType != q.MsgType:
+		return false
+	case q.ClOrdID != "" && message.ClOrdID != q.ClOrdID && message.OrigClOrdID != q.ClOrdID:
+		return false
+	case q.OrderID != "" && message.OrderID != q.OrderID:
+		return false
+	case !q.Since.IsZero() && message.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !message.Time.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+// SearchMessages returns the messages in the archive at path matching query,
+// oldest first
+func SearchMessages(path string, query MessageQuery) ([]ArchivedMessage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var messages []ArchivedMessage
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var message ArchivedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &message); err != nil {
+			return nil, err
+		}
+		if query.Matches(message) {
+			messages = append(messages, message)
+		}
+	}
+	return messages, scanner.Err()
+}
@@ -0,0 +1,71 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestMessageArchiveSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.jsonl")
+	archive, err := OpenMessageArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	order := quickfix.NewMessage()
+	order.Header.SetString(quickfix.Tag(35), "D")
+	order.Body.SetString(quickfix.Tag(11), "clordid-1")
+	cancel := quickfix.NewMessage()
+	cancel.Header.SetString(quickfix.Tag(35), "F")
+	cancel.Body.SetString(quickfix.Tag(11), "clordid-2")
+	cancel.Body.SetString(quickfix.Tag(41), "clordid-1")
+
+	archive.Record("out", order, start)
+	archive.Record("in", parsedExecutionReport(t), start.Add(time.Minute))
+	archive.Record("out", cancel, start.Add(2*time.Hour))
+	archive.Close()
+
+	tests := []struct {
+		query MessageQuery
+		types []string
+	}{
+		{MessageQuery{}, []string{"D", "8", "F"}},
+		{MessageQuery{ClOrdID: "clordid-1"}, []string{"D", "F"}},
+		{MessageQuery{OrderID: "order-1"}, []string{"8"}},
+		{MessageQuery{MsgType: "8"}, []string{"8"}},
+		{MessageQuery{Since: start.Add(time.Hour)}, []string{"F"}},
+		{MessageQuery{ClOrdID: "clordid-1", Until: start.Add(time.Minute)}, []string{"D"}},
+	}
+	for _, test := range tests {
+		messages, err := SearchMessages(path, test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var types []string
+		for _, message := range messages {
+			types = append(types, message.MsgType)
+		}
+		if !slices.Equal(types, test.types) {
+			t.Errorf("%+v matched %v, want %v", test.query, types, test.types)
+		}
+	}
+}
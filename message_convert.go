@@ -0,0 +1,194 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"slices"
+	"strconv"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// headerTags and trailerTags place converted fields in their message section
+var (
+	headerTags  = map[int]bool{8: true, 9: true, 34: true, 35: true, 43: true, 49: true, 50: true, 52: true, 56: true, 57: true, 97: true, 115: true, 122: true, 128: true}
+	trailerTags = map[int]bool{10: true, 89: true, 93: true}
+)
+
+// msgTypeNames names the message types Prime FIX uses
+var msgTypeNames = map[string]string{
+	"0": "Heartbeat", "1": "TestRequest", "2": "ResendRequest", "3": "Reject",
+	"4": "SequenceReset", "5": "Logout", "8": "ExecutionReport", "9": "OrderCancelReject",
+	"A": "Logon", "D": "NewOrderSingle", "F": "OrderCancelRequest",
+	"G": "OrderCancelReplaceRequest", "H": "OrderStatusRequest", "j": "BusinessMessageReject",
+}
+
+// fixmlElements are the FIXML element names of the application messages
+var fixmlElements = map[string]string{
+	"8": "ExecRpt", "9": "OrdCxlRej", "D": "Order", "F": "OrdCxlReq",
+	"G": "OrdCxlRplcReq", "H": "OrdStatReq", "j": "BizMsgRej",
+}
+
+// enumNames name the coded values of the enumerated fields
+var enumNames = map[int]func(code string) string{
+	39:  OrdStatusName,
+	54:  SideName,
+	59:  func(code string) string { return timeInForceEnum.name(code) },
+	102: CxlRejReasonName,
+	150: ExecTypeName,
+}
+
+// ConvertedField is one field of a converted message, in wire order
+type ConvertedField struct {
+	Tag   int    `json:"tag"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+	Enum  string `json:"enum,omitempty"` // name of a coded value
+}
+
+// ConvertedMessage is a FIX message split into sections of named fields,
+// the JSON form used by the audit trail and debugging commands
+type ConvertedMessage struct {
+	MsgType     string           `json:"msgType"`
+	MsgTypeName string           `json:"msgTypeName,omitempty"`
+	Header      []ConvertedField `json:"header"`
+	Body        []ConvertedField `json:"body"`
+	Trailer     []ConvertedField `json:"trailer"`
+}
+
+// ConvertMessage converts msg to a ConvertedMessage
+func ConvertMessage(msg *quickfix.Message) (ConvertedMessage, error) {
+	return ConvertRawFIX(msg.String())
+}
+
+// ConvertRawFIX converts a raw message, delimited by SOH or '|', to a ConvertedMessage
+func ConvertRawFIX(raw string) (ConvertedMessage, error) {
+	fields, err := parseRawFIX(raw)
+	if err != nil {
+		return ConvertedMessage{}, err
+	}
+
+	var converted ConvertedMessage
+	for _, f := range fields {
+		field := ConvertedField{Tag: f.tag, Name: tagNames[f.tag], Value: f.value}
+		if name, ok := enumNames[f.tag]; ok {
+			if enum := name(f.value); enum != f.value {
+				field.Enum = enum
+			}
+		}
+
+		switch {
+		case headerTags[f.tag]:
+			converted.Header = append(converted.Header, field)
+		case trailerTags[f.tag]:
+			converted.Trailer = append(converted.Trailer, field)
+		default:
+			converted.Body = append(converted.Body, field)
+		}
+		if f.tag == 35 {
+			converted.MsgType = f.value
+			converted.MsgTypeName = msgTypeNames[f.value]
+		}
+	}
+	return converted, nil
+}
+
+// MessageToJSON serializes msg as JSON, see ConvertedMessage
+func MessageToJSON(msg *quickfix.Message) ([]byte, error) {
+	converted, err := ConvertMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(converted)
+}
+
+// MessageToFIXML serializes msg as FIXML
+func MessageToFIXML(msg *quickfix.Message) ([]byte, error) {
+	converted, err := ConvertMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return converted.FIXML()
+}
+
+// FIXML renders the message as a FIXML document. Fields become attributes
+// named after the field, or T<tag> when the name is unknown, rather than
+// the abbreviated FIXML names and components; repeated fields, which belong
+// to repeating groups, are written as Fld elements in wire order.
+func (m ConvertedMessage) FIXML() ([]byte, error) {
+	name := fixmlElements[m.MsgType]
+	if name == "" {
+		name = m.MsgTypeName
+	}
+	if name == "" {
+		name = "Msg" + m.MsgType
+	}
+
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	root := xml.StartElement{Name: xml.Name{Local: "FIXML"}}
+	message := fixmlElement(name, m.Body)
+	// Length, checksum and message type are implied by the document
+	header := fixmlElement("Hdr", m.Header, 8, 9, 35)
+
+	tokens := []xml.Token{root, message.start, header.start}
+	tokens = append(tokens, header.children...)
+	tokens = append(tokens, header.start.End())
+	tokens = append(tokens, message.children...)
+	tokens = append(tokens, message.start.End(), root.End())
+	for _, token := range tokens {
+		if err := encoder.EncodeToken(token); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type fixmlNode struct {
+	start    xml.StartElement
+	children []xml.Token
+}
+
+// fixmlElement builds an element carrying fields as attributes, skipping the omitted tags
+func fixmlElement(name string, fields []ConvertedField, omit ...int) fixmlNode {
+	node := fixmlNode{start: xml.StartElement{Name: xml.Name{Local: name}}}
+	seen := make(map[int]bool)
+	for _, field := range fields {
+		if slices.Contains(omit, field.Tag) {
+			continue
+		}
+		attr := field.Name
+		if attr == "" {
+			attr = "T" + strconv.Itoa(field.Tag)
+		}
+		if !seen[field.Tag] {
+			seen[field.Tag] = true
+			node.start.Attr = append(node.start.Attr, xml.Attr{Name: xml.Name{Local: attr}, Value: field.Value})
+			continue
+		}
+		fld := xml.StartElement{Name: xml.Name{Local: "Fld"}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: "Tag"}, Value: strconv.Itoa(field.Tag)},
+			{Name: xml.Name{Local: "Val"}, Value: field.Value},
+		}}
+		node.children = append(node.children, fld, fld.End())
+	}
+	return node
+}
@@ -0,0 +1,71 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertRawFIX(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantErr   bool
+		msgType   string
+		header    int
+		body      int
+		trailer   int
+		wantFIXML []string
+	}{
+		{"execution report", "8=FIX.4.2|9=60|35=8|49=COIN|56=SENDER|39=2|54=1|150=F|10=123|",
+			false, "ExecutionReport", 5, 3, 1,
+			[]string{`<ExecRpt OrdStatus="2" Side="1" ExecType="F">`, `<Hdr SenderCompID="COIN" TargetCompID="SENDER"></Hdr>`}},
+		{"repeated and unknown tags", "8=FIX.4.2\x0135=UZ\x0120001=a\x01137=1.5\x01137=2.5\x01",
+			false, "", 2, 3, 0,
+			[]string{`<MsgUZ T20001="a" T137="1.5"><Hdr></Hdr><Fld Tag="137" Val="2.5"></Fld></MsgUZ>`}},
+		{"malformed field", "8=FIX.4.2|35", true, "", 0, 0, 0, nil},
+		{"malformed tag", "8=FIX.4.2|x=1|", true, "", 0, 0, 0, nil},
+		{"empty", "  ", true, "", 0, 0, 0, nil},
+	}
+	for _, tt := range tests {
+		converted, err := ConvertRawFIX(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if converted.MsgTypeName != tt.msgType || len(converted.Header) != tt.header || len(converted.Body) != tt.body || len(converted.Trailer) != tt.trailer {
+			t.Errorf("%s: %s with %d/%d/%d fields, want %s with %d/%d/%d", tt.name, converted.MsgTypeName,
+				len(converted.Header), len(converted.Body), len(converted.Trailer), tt.msgType, tt.header, tt.body, tt.trailer)
+		}
+		fixml, err := converted.FIXML()
+		if err != nil {
+			t.Fatalf("%s: FIXML: %v", tt.name, err)
+		}
+		for _, want := range tt.wantFIXML {
+			if !strings.Contains(string(fixml), want) {
+				t.Errorf("%s: FIXML %s, want it to contain %s", tt.name, fixml, want)
+			}
+		}
+	}
+
+	converted, _ := ConvertRawFIX("35=8|150=F|39=Z|")
+	if converted.Body[0].Enum != "TRADE" || converted.Body[1].Enum != "" {
+		t.Errorf("enums %q and %q, want TRADE and none for an unknown code", converted.Body[0].Enum, converted.Body[1].Enum)
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Metric is one metric served on /metrics. The registry of them, metrics, is
+// also what the Grafana dashboards are generated from, see GrafanaDashboard,
+// so a metric added here shows up on the dashboards too.
+//
+// Names follow the Prometheus conventions: a primefix_ prefix, _total on
+// counters and the base unit as a suffix. Every metric is labelled with the
+// tenant, and Labels lists any further labels.
+type Metric struct {
+	Name   string
+	Help   string
+	Type   string // counter, gauge or histogram
+	Labels []string
+
+	// Row is the dashboard row the metric is shown in, and Unit its Grafana
+	// unit, "short" when unset
+	Row  string
+	Unit string
+
+	collect func(app *FixApplication) []metricSample
+}
+
+// metricSample is one value of a metric, with the values of its Labels
+type metricSample struct {
+	labels    []string
+	value     float64
+	histogram *LatencyHistogram
+}
+
+func sample(value float64, labels ...string) metricSample {
+	return metricSample{labels: labels, value: value}
+}
+
+// metrics is the registry of every metric the client serves
+var metrics = []Metric{
+	{
+		Name: "primefix_session_logged_on", Type: "gauge", Row: "Session",
+		Help: "Whether the FIX session is logged on",
+		collect: func(app *FixApplication) []metricSample {
+			return []metricSample{sample(boolValue(app.IsLoggedOn()))}
+		},
+	},
+	{
+		Name: "primefix_messages_received_total", Type: "counter", Row: "Session",
+		Help: "FIX messages received",
+		collect: func(app *FixApplication) []metricSample {
+			return []metricSample{sample(float64(app.SessionStats().MessagesIn))}
+		},
+	},
+	{
+		Name: "primefix_messages_sent_total", Type: "counter", Row: "Session",
+		Help: "FIX messages sent",
+		collect: func(app *FixApplication) []metricSample {
+			return []metricSample{sample(float64(app.SessionStats().MessagesOut))}
+		},
+	},
+	{
+		Name: "primefix_reconnects_total", Type: "counter", Row: "Session",
+		Help: "Logons after the first",
+		collect: func(app *FixApplication) []metricSample {
+			return []metricSample{sample(float64(app.SessionStats().Reconnects))}
+		},
+	},
+	{
+		Name: "primefix_next_sender_seq_num", Type: "gauge", Row: "Session",
+		Help: "Next MsgSeqNum the session will send",
+		collect: func(app *FixApplication) []metricSample {
+			return []metricSample{sample(float64(app.SessionStats().NextSenderMsgSeqNum))}
+		},
+	},
+	{
+		Name: "primefix_next_target_seq_num", Type: "gauge", Row: "Session",
+		Help: "Next MsgSeqNum the session expects to receive",
+		collect: func(app *FixApplication) []metricSample {
+			return []metricSample{sample(float64(app.SessionStats().NextTargetMsgSeqNum))}
+		},
+	},
+	{
+		Name: "primefix_message_handling_seconds", Type: "histogram", Labels: []string{"msg_type"}, Row: "Session", Unit: "s",
+		Help: "Time taken to handle a received message",
+		collect: func(app *FixApplication) []metricSample {
+			var samples []metricSample
+			for msgType, histogram := range app.SessionStats().Latency {
+				samples = append(samples, metricSample{labels: []string{msgType}, histogram: &histogram})
+			}
+			return samples
+		},
+	},
+	{
+		Name: "primefix_open_orders", Type: "gauge", Row: "Orders",
+		Help: "Orders not yet filled, canceled, rejected or expired",
+		collect: func(app *FixApplication) []metricSample {
+			return []metricSample{sample(float64(len(openOrders(app))))}
+		},
+	},
+	{
+		Name: "primefix_send_queue_pending", Type: "gauge", Labels: []string{"tier"}, Row: "Orders",
+		Help: "Messages waiting for the send rate limit",
+		collect: func(app *FixApplication) []metricSample {
+			if app.SendQueue == nil {
+				return nil
+			}
+			cancels, orders := app.SendQueue.Pending()
+			return []metricSample{sample(float64(cancels), "cancels"), sample(float64(orders), "orders")}
+		},
+	},
+	{
+		Name: "primefix_position_exposure", Type: "gauge", Labels: []string{"symbol"}, Row: "Orders",
+		Help: "Net position valued at the last fill price, in the quote currency",
+		collect: func(app *FixApplication) []metricSample {
+			if app.Positions == nil {
+				return nil
+			}
+			var samples []metricSample
+			for _, position := range app.Positions.All() {
+				samples = append(samples, sample(position.Exposure().InexactFloat64(), position.Symbol))
+			}
+			return samples
+		},
+	},
+	{
+		Name: "primefix_price_breaker_open", Type: "gauge", Labels: []string{"symbol"}, Row: "Orders",
+		Help: "Symbols blocked by a fill far from the reference price",
+		collect: func(app *FixApplication) []metricSample {
+			if app.PriceBreakers == nil {
+				return nil
+			}
+			var samples []metricSample
+			for _, trip := range app.PriceBreakers.Open() {
+				samples = append(samples, sample(1, trip.Symbol))
+			}
+			return samples
+		},
+	},
+	{
+		Name: "primefix_queue_depth", Type: "gauge", Labels: []string{"queue"}, Row: "Queues",
+		Help: "Items waiting in the internal queues",
+		collect: func(app *FixApplication) []metricSample {
+			depths := app.QueueDepths()
+			dispatcher, sinks := 0, 0
+			for _, depth := range depths.Dispatcher {
+				dispatcher += depth
+			}
+			for _, depth := range depths.Sinks {
+				sinks += depth
+			}
+			return []metricSample{
+				sample(float64(dispatcher), "dispatcher"),
+				sample(float64(sinks), "sinks"),
+				sample(float64(depths.Outbox), "outbox"),
+				sample(float64(depths.Scheduled), "scheduled"),
+			}
+		},
+	},
+	{
+		Name: "primefix_sink_dropped_total", Type: "counter", Row: "Queues",
+		Help: "ExecutionReports dropped by sinks that fell behind",
+		collect: func(app *FixApplication) []metricSample {
+			var dropped uint64
+			for _, sink := range app.Sinks {
+				dropped += sink.Dropped()
+			}
+			return []metricSample{sample(float64(dropped))}
+		},
+	},
+	{
+		Name: "primefix_implementation_shortfall", Type: "gauge", Labels: []string{"symbol"}, Row: "Execution quality",
+		Help: "Cost of the day's fills over their arrival mids, in the quote currency",
+		collect: func(app *FixApplication) []metricSample {
+			if app.Quality == nil {
+				return nil
+			}
+			var samples []metricSample
+			for symbol, shortfall := range app.Quality.Report().Shortfall {
+				samples = append(samples, sample(shortfall.InexactFloat64(), symbol))
+			}
+			return samples
+		},
+	},
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// WriteMetrics writes every metric of every tenant of manager in the
+// Prometheus text format
+func WriteMetrics(w io.Writer, manager *Manager) error {
+	out := bufio.NewWriter(w)
+	tenants := manager.Tenants()
+	for _, metric := range metrics {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", metric.Name, metric.Help, metric.Name, metric.Type)
+		for _, tenant := range tenants {
+			for _, s := range metric.collect(tenant.App) {
+				labels := metricLabels(metric.Labels, tenant.Name, s.labels)
+				if s.histogram == nil {
+					fmt.Fprintf(out, "%s{%s} %s\n", metric.Name, labels, formatMetricValue(s.value))
+					continue
+				}
+				writeHistogram(out, metric.Name, labels, s.histogram)
+			}
+		}
+	}
+	return out.Flush()
+}
+
+// writeHistogram writes h as cumulative buckets in seconds
+func writeHistogram(w io.Writer, name, labels string, h *LatencyHistogram) {
+	var cumulative uint64
+	for i, bucket := range h.Buckets {
+		cumulative += h.Counts[i]
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatMetricValue(bucket.Seconds()), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.Count)
+	fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, formatMetricValue(h.Sum.Seconds()))
+	fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.Count)
+}
+
+func metricLabels(names []string, tenant string, values []string) string {
+	labels := []string{`tenant="` + escapeLabel(tenant) + `"`}
+	for i, name := range names {
+		labels = append(labels, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	return strings.Join(labels, ",")
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,70 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	app := &FixApplication{Tracker: NewOrderTracker(time.Minute), Positions: NewPositionTracker()}
+	app.Tracker.Add(Order{ClOrdID: "1"})
+	app.Positions.OnExecutionReport(ExecutionReport{ExecType: "F", Symbol: "BTC-USD", Side: "1", LastShares: "2", LastPx: "100"})
+	app.latency.observe("8", 2*time.Millisecond)
+	app.latency.observe("8", time.Second)
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: `desk "a"`, App: app}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := WriteMetrics(&out, manager); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# TYPE primefix_messages_received_total counter\n",
+		`primefix_session_logged_on{tenant="desk \"a\""} 0` + "\n",
+		`primefix_open_orders{tenant="desk \"a\""} 1` + "\n",
+		`primefix_position_exposure{tenant="desk \"a\"",symbol="BTC-USD"} 200` + "\n",
+		`primefix_message_handling_seconds_bucket{tenant="desk \"a\"",msg_type="8",le="0.005"} 1` + "\n",
+		`primefix_message_handling_seconds_bucket{tenant="desk \"a\"",msg_type="8",le="+Inf"} 2` + "\n",
+		`primefix_message_handling_seconds_count{tenant="desk \"a\"",msg_type="8"} 2` + "\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics are missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestGrafanaDashboardCoversEveryMetric(t *testing.T) {
+	dashboard := GrafanaDashboard("Prime FIX", "prime-fix-go")
+	var exprs []string
+	for _, panel := range dashboard["panels"].([]map[string]any) {
+		if targets, ok := panel["targets"].([]map[string]any); ok {
+			exprs = append(exprs, targets[0]["expr"].(string))
+		}
+	}
+	if len(exprs) != len(metrics) {
+		t.Fatalf("%d panels for %d metrics", len(exprs), len(metrics))
+	}
+	for i, metric := range metrics {
+		if !strings.Contains(exprs[i], metric.Name) {
+			t.Errorf("panel %d queries %s, want %s", i, exprs[i], metric.Name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OMSExecution is a FIX-agnostic view of an ExecutionReport for existing OMS
+// code: names are spelled out, sides and types are names rather than FIX
+// codes, and quantities and prices are decimals, zero when absent
+type OMSExecution interface {
+	ExecutionID() string
+	ClientOrderID() string
+	VenueOrderID() string
+	Symbol() string
+	Side() string // BUY or SELL
+	Type() string // the ExecType name, e.g. FILL or CANCELED
+	Status() OrderState
+	LastQuantity() decimal.Decimal
+	LastPrice() decimal.Decimal
+	CumulativeQuantity() decimal.Decimal
+	AveragePrice() decimal.Decimal
+	Time() time.Time
+	Metadata() map[string]string
+}
+
+// OMSOrder is a FIX-agnostic view of an order, tracked by the client or
+// coming from an OMS to be placed
+type OMSOrder interface {
+	ClientOrderID() string
+	VenueOrderID() string // empty until the venue acknowledges the order
+	Symbol() string
+	Side() string // BUY or SELL
+	Type() string // LIMIT, MARKET, STOP_LIMIT, TWAP or VWAP
+	Quantity() decimal.Decimal
+	LimitPrice() decimal.Decimal
+	Status() OrderState
+	Metadata() map[string]string
+}
+
+// OMSAdapter receives executions and the order updates they cause, set as
+// FixApplication.OMS to feed an existing OMS
+type OMSAdapter interface {
+	OnExecution(execution OMSExecution)
+	OnOrder(order OMSOrder)
+}
+
+// AsOMSExecution adapts report to OMSExecution
+func AsOMSExecution(report ExecutionReport) OMSExecution {
+	return omsExecution{report}
+}
+
+// AsOMSOrder adapts a tracked order to OMSOrder
+func AsOMSOrder(order Order) OMSOrder {
+	return omsOrder{order}
+}
+
+// OrderRequestFromOMS maps an OMS order onto an OrderRequest for PlaceOrder
+func OrderRequestFromOMS(order OMSOrder) OrderRequest {
+	req := OrderRequest{
+		Symbol:   order.Symbol(),
+		OrdType:  order.Type(),
+		Side:     order.Side(),
+		Quantity: order.Quantity().String(),
+		Metadata: order.Metadata(),
+	}
+	if price := order.LimitPrice(); !price.IsZero() {
+		req.LimitPrice = price.String()
+	}
+	return req
+}
+
+type omsExecution struct {
+	report ExecutionReport
+}
+
+func (e omsExecution) ExecutionID() string                 { return e.report.ExecID }
+func (e omsExecution) ClientOrderID() string               { return e.report.ClOrdID }
+func (e omsExecution) VenueOrderID() string                { return e.report.OrderID }
+func (e omsExecution) Symbol() string                      { return e.report.Symbol }
+func (e omsExecution) Side() string                        { return SideName(e.report.Side) }
+func (e omsExecution) Type() string                        { return ExecTypeName(e.report.ExecType) }
+func (e omsExecution) Status() OrderState                  { return orderStateFromOrdStatus[e.report.OrdStatus] }
+func (e omsExecution) LastQuantity() decimal.Decimal       { return omsDecimal(e.report.LastShares) }
+func (e omsExecution) LastPrice() decimal.Decimal          { return omsDecimal(e.report.LastPx) }
+func (e omsExecution) CumulativeQuantity() decimal.Decimal { return omsDecimal(e.report.CumQty) }
+func (e omsExecution) AveragePrice() decimal.Decimal       { return omsDecimal(e.report.AvgPx) }
+func (e omsExecution) Metadata() map[string]string         { return e.report.Metadata }
+
+// Time is the venue's TransactTime, falling back to when the report arrived
+func (e omsExecution) Time() time.Time {
+	if !e.report.TransactedAt.IsZero() {
+		return e.report.TransactedAt
+	}
+	return e.report.ReceivedAt
+}
+
+type omsOrder struct {
+	order Order
+}
+
+func (o omsOrder) ClientOrderID() string       { return o.order.ClOrdID }
+func (o omsOrder) VenueOrderID() string        { return o.order.OrderID }
+func (o omsOrder) Symbol() string              { return o.order.Symbol }
+func (o omsOrder) Side() string                { return o.order.Side }
+func (o omsOrder) Type() string                { return o.order.OrdType }
+func (o omsOrder) Quantity() decimal.Decimal   { return omsDecimal(o.order.Quantity) }
+func (o omsOrder) LimitPrice() decimal.Decimal { return omsDecimal(o.order.LimitPrice) }
+func (o omsOrder) Status() OrderState          { return o.order.State }
+func (o omsOrder) Metadata() map[string]string { return o.order.Metadata }
+
+// omsDecimal parses value, returning zero when it is empty or invalid
+func omsDecimal(value string) decimal.Decimal {
+	d, _ := decimal.NewFromString(value)
+	return d
+}
@@ -0,0 +1,105 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestOMSExecution(t *testing.T) {
+	transacted := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	received := transacted.Add(time.Second)
+	tests := []struct {
+		name      string
+		report    ExecutionReport
+		wantType  string
+		wantState OrderState
+		wantLast  string
+		wantTime  time.Time
+	}{
+		{"fill", ExecutionReport{ExecType: "F", OrdStatus: "1", Side: "1", LastShares: "0.5", TransactedAt: transacted, ReceivedAt: received},
+			"TRADE", OrderPartiallyFilled, "0.5", transacted},
+		{"cancel without TransactTime", ExecutionReport{ExecType: "4", OrdStatus: "4", Side: "2", ReceivedAt: received},
+			"CANCELED", OrderCanceled, "0", received},
+		{"invalid quantity", ExecutionReport{ExecType: "F", OrdStatus: "2", Side: "1", LastShares: "lots", ReceivedAt: received},
+			"TRADE", OrderFilled, "0", received},
+	}
+	for _, tt := range tests {
+		execution := AsOMSExecution(tt.report)
+		if execution.Type() != tt.wantType || execution.Status() != tt.wantState {
+			t.Errorf("%s: %s %s, want %s %s", tt.name, execution.Type(), execution.Status(), tt.wantType, tt.wantState)
+		}
+		if !execution.LastQuantity().Equal(decimal.RequireFromString(tt.wantLast)) {
+			t.Errorf("%s: LastQuantity = %s, want %s", tt.name, execution.LastQuantity(), tt.wantLast)
+		}
+		if !execution.Time().Equal(tt.wantTime) {
+			t.Errorf("%s: Time = %s, want %s", tt.name, execution.Time(), tt.wantTime)
+		}
+	}
+	if side := AsOMSExecution(tests[1].report).Side(); side != "SELL" {
+		t.Errorf("Side = %q, want SELL", side)
+	}
+}
+
+func TestOrderRequestFromOMS(t *testing.T) {
+	tests := []struct {
+		name  string
+		order Order
+		want  OrderRequest
+	}{
+		{"limit", Order{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1.50", LimitPrice: "2500"},
+			OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1.5", LimitPrice: "2500"}},
+		{"market", Order{Symbol: "ETH-USD", OrdType: "MARKET", Side: "SELL", Quantity: "2"},
+			OrderRequest{Symbol: "ETH-USD", OrdType: "MARKET", Side: "SELL", Quantity: "2"}},
+		{"invalid quantity", Order{Symbol: "ETH-USD", OrdType: "MARKET", Side: "SELL", Quantity: "two"},
+			OrderRequest{Symbol: "ETH-USD", OrdType: "MARKET", Side: "SELL", Quantity: "0"}},
+	}
+	for _, tt := range tests {
+		got := OrderRequestFromOMS(AsOMSOrder(tt.order))
+		if got.Symbol != tt.want.Symbol || got.OrdType != tt.want.OrdType || got.Side != tt.want.Side ||
+			got.Quantity != tt.want.Quantity || got.LimitPrice != tt.want.LimitPrice {
+			t.Errorf("%s: %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+type omsRecorder struct {
+	executions []OMSExecution
+	orders     []OMSOrder
+}
+
+func (r *omsRecorder) OnExecution(execution OMSExecution) {
+	r.executions = append(r.executions, execution)
+}
+func (r *omsRecorder) OnOrder(order OMSOrder) { r.orders = append(r.orders, order) }
+
+func TestOMSAdapterFed(t *testing.T) {
+	oms := &omsRecorder{}
+	app := &FixApplication{OMS: oms, Tracker: NewOrderTracker(time.Minute)}
+	app.Tracker.Add(Order{ClOrdID: "tracked", Quantity: "1", State: OrderNew})
+
+	app.handleExecutionReport(ExecutionReport{ClOrdID: "tracked", ExecType: "F", OrdStatus: "2", LastShares: "1", CumQty: "1"})
+	app.handleExecutionReport(ExecutionReport{ClOrdID: "untracked", ExecType: "F", OrdStatus: "2"})
+
+	if len(oms.executions) != 2 {
+		t.Fatalf("OMS got %d executions, want 2", len(oms.executions))
+	}
+	if len(oms.orders) != 1 || oms.orders[0].ClientOrderID() != "tracked" {
+		t.Fatalf("OMS got order updates %v, want one for the tracked order", oms.orders)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"container/list"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// OrderArchive stores terminal orders evicted from an OrderTracker, so they
+// can still be looked up without being held in memory
+type OrderArchive interface {
+	// Put archives order, also findable by the request ClOrdIDs in aliases
+	Put(order Order, aliases []string) error
+	// Get returns the archived order known by clOrdID
+	Get(clOrdID string) (Order, bool, error)
+}
+
+// archivedOrder is one line of a FileOrderArchive
+type archivedOrder struct {
+	Order   Order
+	Aliases []string `json:",omitempty"`
+}
+
+// FileOrderArchive is an OrderArchive appending orders to a JSON lines file.
+// Only the file offset of each ClOrdID is kept in memory; lookups read the
+// order back from disk.
+type FileOrderArchive struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	size    int64
+	offsets map[string]int64
+}
+
+// OpenFileOrderArchive opens or creates the archive at path, indexing the
+// orders already in it
+func OpenFileOrderArchive(path string) (*FileOrderArchive, error) {
+	a := &FileOrderArchive{path: path}
+	if err := a.open(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// open opens the file and indexes the orders in it
+func (a *FileOrderArchive) open() error {
+	file, err := os.OpenFile(a.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	a.file, a.size, a.offsets = file, 0, make(map[string]int64)
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break // a torn last line is overwritten by the next Put
+		}
+		if err != nil {
+			file.Close()
+			return err
+		}
+		var record archivedOrder
+		if err := json.Unmarshal(line, &record); err != nil {
+			file.Close()
+			return err
+		}
+		a.index(record, a.size)
+		a.size += int64(len(line))
+	}
+	return nil
+}
+
+func (a *FileOrderArchive) index(record archivedOrder, offset int64) {
+	a.offsets[record.Order.ClOrdID] = offset
+	for _, alias := range record.Aliases {
+		a.offsets[alias] = offset
+	}
+}
+
+func (a *FileOrderArchive) Put(order Order, aliases []string) error {
+	record := archivedOrder{Order: order, Aliases: aliases}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.WriteAt(line, a.size); err != nil {
+		return err
+	}
+	a.index(record, a.size)
+	a.size += int64(len(line))
+	return nil
+}
+
+func (a *FileOrderArchive) Get(clOrdID string) (Order, bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	offset, ok := a.offsets[clOrdID]
+	if !ok {
+		return Order{}, false, nil
+	}
+	line, err := bufio.NewReader(io.NewSectionReader(a.file, offset, a.size-offset)).ReadBytes('\n')
+	if err != nil {
+		return Order{}, false, err
+	}
+	var record archivedOrder
+	if err := json.Unmarshal(line, &record); err != nil {
+		return Order{}, false, err
+	}
+	return record.Order, true, nil
+}
+
+// rewrite closes the file for f to replace it, then reopens and reindexes it
+func (a *FileOrderArchive) rewrite(f func(path string) error) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.file.Close(); err != nil {
+		return err
+	}
+	rewriteErr := f(a.path)
+	if err := a.open(); err != nil {
+		return err
+	}
+	return rewriteErr
+}
+
+// Close closes the underlying file
+func (a *FileOrderArchive) Close() error {
+	return a.file.Close()
+}
+
+// orderCache is a fixed size LRU of archived orders looked up recently
+type orderCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // most recently used first
+}
+
+type orderCacheEntry struct {
+	key   string
+	order Order
+}
+
+func newOrderCache(capacity int) *orderCache {
+	return &orderCache{capacity: capacity, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (c *orderCache) get(key string) (Order, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return Order{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*orderCacheEntry).order, true
+}
+
+func (c *orderCache) put(key string, order Order) {
+	if c.capacity < 1 {
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*orderCacheEntry).order = order
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&orderCacheEntry{key: key, order: order})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*orderCacheEntry).key)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// fixTimestampFormat is the UTCTimestamp layout used by Prime FIX
+const fixTimestampFormat = "20060102-15:04:05.000"
+
+// OrderRequest describes a new order to place
+type OrderRequest struct {
+	Symbol     string
+	OrdType    string // LIMIT, MARKET, STOP_LIMIT, TWAP or VWAP
+	Side       string // BUY or SELL
+	Quantity   string
+	LimitPrice string
+	StopPrice  string // STOP_LIMIT only
+
+	// TimeInForce is DAY, GTC, IOC, FOK or GTD; empty uses the default of
+	// the OrdType
+	TimeInForce string
+
+	// StartTime (optional) and ExpireTime bound the schedule of TWAP and VWAP
+	// orders; ExpireTime is also when a GTD order expires
+	StartTime  time.Time
+	ExpireTime time.Time
+
+	// Force confirms an order whose notional exceeds the fat-finger threshold
+	Force bool
+
+	// Metadata is opaque caller context (strategy, trader, ticket...) that is
+	// carried through tracking, events and persisted records but never sent
+	Metadata map[string]string
+
+	// Text is a free-text memo sent in Text (58) that shows up in Prime's
+	// records, at most MaxOrderTextLength bytes; logs redact it
+	Text string
+
+	// ExecInst lists execution instructions such as ExecInstPostOnly
+	ExecInst []ExecInst
+
+	// Algo holds the optional TWAP and VWAP strategy parameters
+	Algo AlgoParams
+
+	// ArrivalMid is the mid price when the order was decided on, recorded
+	// with its executions for slippage reporting but never sent
+	ArrivalMid string
+
+	// CorrelationId joins the order's logs across systems; PlaceOrder
+	// generates one when empty
+	CorrelationId string
+}
+
+// isAlgo reports whether ordType is a scheduled algo strategy
+func isAlgo(ordType string) bool {
+	return ordType == "TWAP" || ordType == "VWAP"
+}
+
+// orderBuilder builds NewOrderSingle messages, reusing the same message and
+// scratch buffers on every call so the hot path avoids fmt and map allocations.
+// The returned message is only valid until the next call to build, and an
+// orderBuilder must not be shared between goroutines.
+type orderBuilder struct {
+	senderCompId string
+	targetCompId string
+
+	// correlationTag carries req.CorrelationId when set
+	correlationTag quickfix.Tag
+
+	msg     *quickfix.Message
+	ordType string
+	clOrdId []byte
+}
+
+func newOrderBuilder(senderCompId, targetCompId string) *orderBuilder {
+	return &orderBuilder{
+		senderCompId: senderCompId,
+		targetCompId: targetCompId,
+		msg:          quickfix.NewMessage(),
+		clOrdId:      make([]byte, 0, 20),
+	}
+}
+
+// build populates the reusable message with a NewOrderSingle for req
+func (b *orderBuilder) build(req OrderRequest, portfolioId string, now time.Time) *quickfix.Message {
+	order := b.msg
+
+	// Fields are overwritten in place; the body only needs clearing when the
+	// order type changes the set of tags present. Algo orders carry optional
+	// schedule tags, so the body is always cleared around them.
+	if req.OrdType != b.ordType || isAlgo(req.OrdType) {
+		order.Body.Clear()
+		b.ordType = req.OrdType
+	}
+
+	b.clOrdId = strconv.AppendInt(b.clOrdId[:0], nextClOrdID(now), 10)
+
+	// Header fields (standard FIX header); SendingTime is stamped by ToApp at transmit
+	order.Header.SetField(quickfix.Tag(35), quickfix.FIXString("D")) // MsgType = 'D'
+	order.Header.SetString(quickfix.Tag(49), b.senderCompId)         // SenderCompID
+	order.Header.SetString(quickfix.Tag(56), b.targetCompId)         // TargetCompID
+
+	// Body fields (order data)
+	order.Body.SetString(quickfix.Tag(1), portfolioId) // Account (Portfolio ID)
+	order.Body.SetBytes(quickfix.Tag(11), b.clOrdId)   // ClOrdID
+	order.Body.SetString(quickfix.Tag(55), req.Symbol) // Symbol
+
+	// Order Type, TimeInForce, Price, TargetStrategy
+	switch req.OrdType {
+	case "LIMIT":
+		order.Body.SetField(quickfix.Tag(40), quickfix.FIXString("2")) // OrdType = Limit
+		order.Body.SetField(quickfix.Tag(59), quickfix.FIXString("1")) // TimeInForce = GTC (example)
+		order.Body.SetString(quickfix.Tag(44), req.LimitPrice)
+		order.Body.SetField(quickfix.Tag(847), quickfix.FIXString("L")) // TargetStrategy = Limit
+	case "MARKET":
+		order.Body.SetField(quickfix.Tag(40), quickfix.FIXString("1"))  // OrdType = Market
+		order.Body.SetField(quickfix.Tag(59), quickfix.FIXString("3"))  // TimeInForce = IOC
+		order.Body.SetField(quickfix.Tag(847), quickfix.FIXString("M")) // TargetStrategy = Market
+	case "STOP_LIMIT":
+		order.Body.SetField(quickfix.Tag(40), quickfix.FIXString("4")) // OrdType = Stop Limit
+		order.Body.SetField(quickfix.Tag(59), quickfix.FIXString("1")) // TimeInForce = GTC
+		order.Body.SetString(quickfix.Tag(44), req.LimitPrice)
+		order.Body.SetString(quickfix.Tag(99), req.StopPrice)            // StopPx
+		order.Body.SetField(quickfix.Tag(847), quickfix.FIXString("SL")) // TargetStrategy = Stop Limit
+	case "TWAP", "VWAP":
+		order.Body.SetField(quickfix.Tag(40), quickfix.FIXString("2")) // OrdType = Limit
+		order.Body.SetField(quickfix.Tag(59), quickfix.FIXString("6")) // TimeInForce = GTD
+		order.Body.SetString(quickfix.Tag(44), req.LimitPrice)
+		if req.OrdType == "TWAP" {
+			order.Body.SetField(quickfix.Tag(847), quickfix.FIXString("T")) // TargetStrategy = TWAP
+		} else {
+			order.Body.SetField(quickfix.Tag(847), quickfix.FIXString("V")) // TargetStrategy = VWAP
+		}
+		if !req.StartTime.IsZero() {
+			order.Body.SetString(quickfix.Tag(168), req.StartTime.UTC().Format(fixTimestampFormat)) // EffectiveTime
+		}
+		order.Body.SetString(quickfix.Tag(126), req.ExpireTime.UTC().Format(fixTimestampFormat)) // ExpireTime
+		if req.Algo.MaxParticipationRate.IsPositive() {
+			order.Body.SetString(quickfix.Tag(849), req.Algo.MaxParticipationRate.String()) // ParticipationRate
+		}
+	}
+
+	// Algos run until their ExpireTime, so keep GTD for them
+	if !isAlgo(req.OrdType) {
+		if code, ok := TimeInForceCode(req.TimeInForce); ok {
+			order.Body.SetString(quickfix.Tag(59), code) // TimeInForce
+		}
+		if req.TimeInForce == "GTD" {
+			order.Body.SetString(quickfix.Tag(126), req.ExpireTime.UTC().Format(fixTimestampFormat)) // ExpireTime
+		} else {
+			order.Body.Remove(quickfix.Tag(126))
+		}
+	}
+
+	// Side
+	if req.Side == "BUY" {
+		order.Body.SetField(quickfix.Tag(54), quickfix.FIXString("1")) // Side = Buy
+	} else {
+		order.Body.SetField(quickfix.Tag(54), quickfix.FIXString("2")) // Side = Sell
+	}
+
+	// Order Quantity
+	order.Body.SetString(quickfix.Tag(38), req.Quantity)
+
+	// Execution instructions; the body is not always cleared between orders
+	if len(req.ExecInst) > 0 {
+		order.Body.SetString(quickfix.Tag(18), formatExecInst(req.ExecInst)) // ExecInst
+	} else {
+		order.Body.Remove(quickfix.Tag(18))
+	}
+
+	if req.Text != "" {
+		order.Body.SetString(quickfix.Tag(58), req.Text) // Text
+	} else {
+		order.Body.Remove(quickfix.Tag(58))
+	}
+
+	if b.correlationTag != 0 {
+		if req.CorrelationId != "" {
+			order.Body.SetString(b.correlationTag, formatCorrelationId(b.correlationTag, req.CorrelationId))
+		} else {
+			order.Body.Remove(b.correlationTag)
+		}
+	}
+
+	return order
+}
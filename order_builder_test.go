@@ -0,0 +1,182 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func BenchmarkOrderBuilderLimit(b *testing.B) {
+	builder := newOrderBuilder("SENDER", "COIN")
+	req := OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "0.0015", LimitPrice: "1001"}
+	now := time.Now()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		builder.build(req, "portfolio", now)
+	}
+}
+
+func BenchmarkOrderBuilderMarket(b *testing.B) {
+	builder := newOrderBuilder("SENDER", "COIN")
+	req := OrderRequest{Symbol: "ETH-USD", OrdType: "MARKET", Side: "SELL", Quantity: "0.0015"}
+	now := time.Now()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		builder.build(req, "portfolio", now)
+	}
+}
+
+func TestSymbolDefaultsFillUnsetParameters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "symbol_defaults.json")
+	config := `{"ETH-USD": {"ord_type": "LIMIT", "time_in_force": "IOC", "max_quantity": "10"}, "*": {"time_in_force": "FOK"}}`
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defaults, err := LoadSymbolDefaults(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := defaults.Apply(OrderRequest{Symbol: "ETH-USD", Side: "BUY", Quantity: "1", LimitPrice: "100"})
+	if req.OrdType != "LIMIT" || req.TimeInForce != "IOC" {
+		t.Errorf("defaults not applied: %+v", req)
+	}
+	if req := defaults.Apply(OrderRequest{Symbol: "ETH-USD", OrdType: "MARKET", TimeInForce: "FOK"}); req.OrdType != "MARKET" || req.TimeInForce != "FOK" {
+		t.Errorf("defaults overrode the request: %+v", req)
+	}
+	if req := defaults.Apply(OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT"}); req.TimeInForce != "FOK" {
+		t.Errorf("* defaults not applied: %+v", req)
+	}
+	if req := defaults.Apply(OrderRequest{Symbol: "BTC-USD", OrdType: "TWAP"}); req.TimeInForce != "" {
+		t.Errorf("algo given a time in force: %+v", req)
+	}
+	if err := defaults.Check(OrderRequest{Symbol: "ETH-USD", Quantity: "10.5"}); err == nil {
+		t.Error("order above max_quantity passed")
+	}
+	if err := defaults.Check(OrderRequest{Symbol: "ETH-USD", Quantity: "10"}); err != nil {
+		t.Error(err)
+	}
+
+	builder := newOrderBuilder("SENDER", "COIN")
+	order := builder.build(OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "100", TimeInForce: "GTD", ExpireTime: time.Now().Add(time.Hour)}, "portfolio", time.Now())
+	if tif := bodyString(order, quickfix.Tag(59)); tif != "6" || !order.Body.Has(quickfix.Tag(126)) {
+		t.Errorf("GTD order has TimeInForce %s and ExpireTime %v", tif, order.Body.Has(quickfix.Tag(126)))
+	}
+	order = builder.build(req, "portfolio", time.Now())
+	if tif := bodyString(order, quickfix.Tag(59)); tif != "3" || order.Body.Has(quickfix.Tag(126)) {
+		t.Errorf("IOC order has TimeInForce %s and ExpireTime %v", tif, order.Body.Has(quickfix.Tag(126)))
+	}
+
+	for _, invalid := range []string{`{"ETH-USD": {"ord_type": "PEG"}}`, `{"ETH-USD": {"time_in_force": "GTX"}}`, `{"ETH-USD": {"max_quantity": "-1"}}`} {
+		os.WriteFile(path, []byte(invalid), 0644)
+		if _, err := LoadSymbolDefaults(path); err == nil {
+			t.Errorf("loaded %s", invalid)
+		}
+	}
+}
+
+func TestOrderTextMemo(t *testing.T) {
+	builder := newOrderBuilder("SENDER", "COIN")
+	req := OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "1000", Text: "desk 4 hedge"}
+	order := builder.build(req, "portfolio", time.Now())
+	if text := bodyString(order, quickfix.Tag(58)); text != "desk 4 hedge" {
+		t.Fatalf("Text = %q", text)
+	}
+	raw := order.String()
+
+	// The builder reuses its message, so a memo must not leak into the next order
+	req.Text = ""
+	if order := builder.build(req, "portfolio", time.Now()); order.Body.Has(quickfix.Tag(58)) {
+		t.Fatal("Text kept from the previous order")
+	}
+
+	for _, tc := range []struct{ rule, want string }{
+		{"redact", "\x0158=REDACTED\x01"},
+		{"prefix:4", "\x0158=desk...\x01"},
+		{"show", "\x0158=desk 4 hedge\x01"},
+	} {
+		redaction, err := ParseTextRedaction(tc.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := redaction.Apply(raw); !strings.Contains(got, tc.want) {
+			t.Errorf("%s: %q", tc.rule, got)
+		}
+	}
+	if got := (TextRedaction{}).Apply(raw); !strings.Contains(got, "58=REDACTED") {
+		t.Errorf("default redaction: %q", got)
+	}
+	reject := "8=FIX.4.2\x0135=8\x0158=Insufficient funds\x01"
+	if got := (TextRedaction{}).Apply(reject); got != reject {
+		t.Errorf("redacted an ExecutionReport: %q", got)
+	}
+
+	if err := validateOrderText(strings.Repeat("x", MaxOrderTextLength+1)); err == nil {
+		t.Error("accepted an over-long memo")
+	}
+	if err := validateOrderText("a\x01b"); err == nil {
+		t.Error("accepted a memo with a field delimiter")
+	}
+}
+
+func TestStampTransmitTime(t *testing.T) {
+	built := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	sent := built.Add(time.Minute)
+	builtStamp := built.Format(fixTimestampFormat)
+	sentStamp := sent.Format(fixTimestampFormat)
+
+	tests := []struct {
+		name         string
+		transactTime bool
+		possDup      bool
+		wantTransact string // empty means absent
+	}{
+		{"new order", true, false, sentStamp},
+		{"resend keeps TransactTime", true, true, builtStamp},
+		{"no TransactTime", false, false, ""},
+	}
+	for _, tt := range tests {
+		msg := quickfix.NewMessage()
+		if tt.transactTime {
+			msg.Body.SetString(quickfix.Tag(60), builtStamp)
+		}
+		if tt.possDup {
+			msg.Header.SetBool(quickfix.Tag(43), true)
+		}
+		stampTransmitTime(msg, sent)
+
+		if got, _ := msg.Header.GetString(quickfix.Tag(52)); got != sentStamp {
+			t.Errorf("%s: SendingTime = %q, want %q", tt.name, got, sentStamp)
+		}
+		if got := bodyString(msg, quickfix.Tag(60)); got != tt.wantTransact {
+			t.Errorf("%s: TransactTime = %q, want %q", tt.name, got, tt.wantTransact)
+		}
+	}
+
+	req := OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "1000"}
+	if order := newOrderBuilder("SENDER", "COIN").build(req, "portfolio", built); order.Header.Has(quickfix.Tag(52)) {
+		t.Error("build set SendingTime before transmit")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// lastClOrdID is the last ClOrdID issued by nextClOrdID in this process
+var lastClOrdID atomic.Int64
+
+// nextClOrdID returns the ClOrdID of an order, cancel or replace sent at now:
+// its UnixNano, moved past the last one issued so that messages at the same
+// instant, e.g. under a FakeClock, never share an id
+func nextClOrdID(now time.Time) int64 {
+	for {
+		last := lastClOrdID.Load()
+		id := max(now.UnixNano(), last+1)
+		if lastClOrdID.CompareAndSwap(last, id) {
+			return id
+		}
+	}
+}
+
+// OrderIDMap cross-references venue OrderIDs (37) with the order ids used by
+// the Prime REST API and portal, learned from ExecutionReports
+type OrderIDMap struct {
+	mu     sync.RWMutex
+	toREST map[string]string
+	toFIX  map[string]string
+
+	// tag is the ExecutionReport tag carrying the REST order id; zero means
+	// Prime's OrderID is itself the REST order id
+	tag quickfix.Tag
+}
+
+// NewOrderIDMap creates a map reading REST order ids from tag, or taking the
+// OrderID as the REST id when tag is zero
+func NewOrderIDMap(tag quickfix.Tag) *OrderIDMap {
+	return &OrderIDMap{
+		toREST: make(map[string]string),
+		toFIX:  make(map[string]string),
+		tag:    tag,
+	}
+}
+
+// Add records that orderId and restId identify the same order
+func (m *OrderIDMap) Add(orderId, restId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.toREST[orderId] = restId
+	m.toFIX[restId] = orderId
+}
+
+// RESTOrderID returns the REST order id of the venue orderId
+func (m *OrderIDMap) RESTOrderID(orderId string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	restId, ok := m.toREST[orderId]
+	return restId, ok
+}
+
+// OrderID returns the venue OrderID of the REST order restId
+func (m *OrderIDMap) OrderID(restId string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	orderId, ok := m.toFIX[restId]
+	return orderId, ok
+}
+
+// onExecutionReport records the ids carried by an ExecutionReport
+func (m *OrderIDMap) onExecutionReport(orderId string, msg *quickfix.Message) {
+	if orderId == "" {
+		return
+	}
+	restId := orderId
+	if m.tag != 0 {
+		if restId = bodyString(msg, m.tag); restId == "" {
+			return
+		}
+	}
+	if known, ok := m.RESTOrderID(orderId); !ok || known != restId {
+		m.Add(orderId, restId)
+	}
+}
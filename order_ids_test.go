@@ -0,0 +1,66 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestOrderIDMap(t *testing.T) {
+	report := func(restId string) *quickfix.Message {
+		msg := quickfix.NewMessage()
+		if restId != "" {
+			msg.Body.SetString(quickfix.Tag(9999), restId)
+		}
+		return msg
+	}
+
+	tests := []struct {
+		name     string
+		tag      quickfix.Tag
+		orderId  string
+		msg      *quickfix.Message
+		wantREST string // empty means nothing is recorded
+	}{
+		{"OrderID is the REST id", 0, "venue-1", report(""), "venue-1"},
+		{"REST id from tag", 9999, "venue-1", report("rest-1"), "rest-1"},
+		{"tag missing", 9999, "venue-1", report(""), ""},
+		{"no OrderID", 9999, "", report("rest-1"), ""},
+	}
+	for _, tt := range tests {
+		ids := NewOrderIDMap(tt.tag)
+		ids.onExecutionReport(tt.orderId, tt.msg)
+
+		restId, ok := ids.RESTOrderID(tt.orderId)
+		if ok != (tt.wantREST != "") || restId != tt.wantREST {
+			t.Errorf("%s: RESTOrderID = %q, %t, want %q", tt.name, restId, ok, tt.wantREST)
+		}
+		if tt.wantREST != "" {
+			if orderId, _ := ids.OrderID(tt.wantREST); orderId != tt.orderId {
+				t.Errorf("%s: OrderID = %q, want %q", tt.name, orderId, tt.orderId)
+			}
+		}
+	}
+
+	var ids *OrderIDMap
+	if _, ok := ids.RESTOrderID("venue-1"); ok {
+		t.Error("nil map found a REST id")
+	}
+	if _, ok := ids.OrderID("rest-1"); ok {
+		t.Error("nil map found an OrderID")
+	}
+}
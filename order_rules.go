@@ -0,0 +1,210 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// OrderRule is one config-defined predicate every order must satisfy before
+// it is sent. Symbols and Portfolios narrow the orders a rule applies to,
+// except for "symbols" rules where Symbols is the allow-list itself.
+type OrderRule struct {
+	Name string `json:"name"`
+	// Type is "symbols" (only Symbols may be traded), "sides" (only Sides
+	// may be sent) or "hours" (orders only between Start and End on Days)
+	Type       string   `json:"type"`
+	Symbols    []string `json:"symbols,omitempty"`
+	Portfolios []string `json:"portfolios,omitempty"`
+	Sides      []string `json:"sides,omitempty"`
+
+	// Start and End are "15:04" times in Timezone (UTC by default); End
+	// before Start wraps past midnight. Days are "Mon" to "Sun", every day when empty.
+	Start    string   `json:"start,omitempty"`
+	End      string   `json:"end,omitempty"`
+	Days     []string `json:"days,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+
+	location   *time.Location
+	start, end time.Duration // since midnight
+}
+
+// RuleError is returned when an order breaks an OrderRule
+type RuleError struct {
+	Rule   string
+	Type   string
+	Reason string
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("order rule %s (%s): %s", e.Rule, e.Type, e.Reason)
+}
+
+// RuleHit records an order blocked by a rule, for the audit trail
+type RuleHit struct {
+	Time      time.Time
+	Request   OrderRequest
+	Portfolio string
+	Err       *RuleError
+}
+
+// OrderRules evaluates OrderRules in order, blocking an order on the first
+// rule it breaks
+type OrderRules struct {
+	Rules []OrderRule
+
+	// OnRuleHit is called for every order a rule blocks
+	OnRuleHit func(hit RuleHit)
+}
+
+// LoadOrderRules reads a JSON array of OrderRules
+func LoadOrderRules(path string) (*OrderRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []OrderRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid order rules %s: %w", path, err)
+	}
+	return NewOrderRules(rules)
+}
+
+// NewOrderRules validates rules and prepares them for evaluation
+func NewOrderRules(rules []OrderRule) (*OrderRules, error) {
+	for i := range rules {
+		if err := rules[i].compile(); err != nil {
+			return nil, fmt.Errorf("order rule %q: %w", rules[i].Name, err)
+		}
+	}
+	return &OrderRules{Rules: rules}, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"Sun": time.Sunday, "Mon": time.Monday, "Tue": time.Tuesday, "Wed": time.Wednesday,
+	"Thu": time.Thursday, "Fri": time.Friday, "Sat": time.Saturday,
+}
+
+func (r *OrderRule) compile() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule has no name")
+	}
+	switch r.Type {
+	case "symbols":
+		if len(r.Symbols) == 0 {
+			return fmt.Errorf("symbols rule lists no symbols")
+		}
+	case "sides":
+		if len(r.Sides) == 0 {
+			return fmt.Errorf("sides rule lists no sides")
+		}
+	case "hours":
+		var err error
+		if r.location, err = time.LoadLocation(r.Timezone); err != nil {
+			return err
+		}
+		if r.start, err = parseClock(r.Start); err != nil {
+			return fmt.Errorf("start: %w", err)
+		}
+		if r.end, err = parseClock(r.End); err != nil {
+			return fmt.Errorf("end: %w", err)
+		}
+		for _, day := range r.Days {
+			if _, ok := weekdays[day]; !ok {
+				return fmt.Errorf("unknown day %q", day)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return nil
+}
+
+// parseClock parses a "15:04" time of day
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Check returns a *RuleError for the first rule req breaks when sent for
+// portfolio at now
+func (o *OrderRules) Check(req OrderRequest, portfolio string, now time.Time) error {
+	for _, rule := range o.Rules {
+		if err := rule.check(req, portfolio, now); err != nil {
+			if o.OnRuleHit != nil {
+				o.OnRuleHit(RuleHit{Time: now, Request: req, Portfolio: portfolio, Err: err})
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *OrderRule) check(req OrderRequest, portfolio string, now time.Time) *RuleError {
+	if len(r.Portfolios) > 0 && !slices.Contains(r.Portfolios, portfolio) {
+		return nil
+	}
+	if r.Type == "symbols" {
+		if !slices.Contains(r.Symbols, req.Symbol) {
+			return &RuleError{Rule: r.Name, Type: r.Type, Reason: req.Symbol + " is not an allowed symbol"}
+		}
+		return nil
+	}
+	if len(r.Symbols) > 0 && !slices.Contains(r.Symbols, req.Symbol) {
+		return nil
+	}
+
+	switch r.Type {
+	case "sides":
+		if !slices.Contains(r.Sides, req.Side) {
+			return &RuleError{Rule: r.Name, Type: r.Type,
+				Reason: fmt.Sprintf("%s orders are not allowed on %s, only %s", req.Side, req.Symbol, strings.Join(r.Sides, ", "))}
+		}
+	case "hours":
+		if !r.open(now) {
+			return &RuleError{Rule: r.Name, Type: r.Type,
+				Reason: fmt.Sprintf("%s only trades %s-%s %s", req.Symbol, r.Start, r.End, r.location)}
+		}
+	}
+	return nil
+}
+
+// open reports whether now falls within the rule's trading window
+func (r *OrderRule) open(now time.Time) bool {
+	local := now.In(r.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, r.location)
+	sinceMidnight := local.Sub(midnight)
+
+	day := local.Weekday()
+	var within bool
+	if r.start <= r.end {
+		within = sinceMidnight >= r.start && sinceMidnight < r.end
+	} else {
+		// Wraps past midnight: the early part belongs to the window opened the day before
+		within = sinceMidnight >= r.start || sinceMidnight < r.end
+		if sinceMidnight < r.end {
+			day = (day + 6) % 7
+		}
+	}
+	return within && (len(r.Days) == 0 || slices.ContainsFunc(r.Days, func(d string) bool { return weekdays[d] == day }))
+}
@@ -0,0 +1,63 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrderRules(t *testing.T) {
+	rules, err := NewOrderRules([]OrderRule{
+		{Name: "allowed", Type: "symbols", Symbols: []string{"BTC-USD", "ETH-USD"}},
+		{Name: "long-only", Type: "sides", Portfolios: []string{"pf-long"}, Sides: []string{"BUY"}},
+		{Name: "eth-hours", Type: "hours", Symbols: []string{"ETH-USD"}, Start: "22:00", End: "02:00", Days: []string{"Mon"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hits []string
+	rules.OnRuleHit = func(hit RuleHit) { hits = append(hits, hit.Err.Rule) }
+
+	monday := time.Date(2025, 1, 6, 23, 0, 0, 0, time.UTC)
+	tests := []struct {
+		req       OrderRequest
+		portfolio string
+		now       time.Time
+		rule      string
+	}{
+		{OrderRequest{Symbol: "SOL-USD", Side: "BUY"}, "pf", monday, "allowed"},
+		{OrderRequest{Symbol: "BTC-USD", Side: "SELL"}, "pf", monday, ""},
+		{OrderRequest{Symbol: "BTC-USD", Side: "SELL"}, "pf-long", monday, "long-only"},
+		{OrderRequest{Symbol: "ETH-USD", Side: "BUY"}, "pf", monday, ""},
+		{OrderRequest{Symbol: "ETH-USD", Side: "BUY"}, "pf", monday.Add(2 * time.Hour), ""}, // Tuesday 01:00, Monday's window
+		{OrderRequest{Symbol: "ETH-USD", Side: "BUY"}, "pf", monday.Add(4 * time.Hour), "eth-hours"},
+		{OrderRequest{Symbol: "ETH-USD", Side: "BUY"}, "pf", monday.Add(24 * time.Hour), "eth-hours"}, // Tuesday 23:00
+	}
+	for _, test := range tests {
+		err := rules.Check(test.req, test.portfolio, test.now)
+		var ruleErr *RuleError
+		switch {
+		case test.rule == "" && err != nil:
+			t.Errorf("%+v at %s: unexpected %v", test.req, test.now, err)
+		case test.rule != "" && (!errors.As(err, &ruleErr) || ruleErr.Rule != test.rule):
+			t.Errorf("%+v at %s: got %v, want rule %s", test.req, test.now, err, test.rule)
+		}
+	}
+	if len(hits) != 4 {
+		t.Errorf("rule hits %v, want 4", hits)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "fmt"
+
+// OrderTransitions is the order lifecycle state machine: the states an order
+// may move to from each state. Staying in the same state is always allowed,
+// as the venue may repeat a status. Terminal states have no way out.
+var OrderTransitions = map[OrderState][]OrderState{
+	// Any open order becomes unknown when a send or request is ambiguous
+	OrderPendingNew: {
+		OrderNew, OrderPartiallyFilled, OrderFilled, OrderRejected,
+		OrderCanceled, OrderExpired, OrderDoneForDay, OrderUnknownState,
+	},
+	OrderNew: {
+		OrderPartiallyFilled, OrderFilled, OrderCanceled, OrderExpired,
+		OrderDoneForDay, OrderPendingCancel, OrderPendingReplace, OrderUnknownState,
+	},
+	OrderPartiallyFilled: {
+		OrderFilled, OrderCanceled, OrderExpired, OrderDoneForDay,
+		OrderPendingCancel, OrderPendingReplace, OrderUnknownState,
+	},
+	OrderPendingCancel: {
+		OrderNew, OrderPartiallyFilled, OrderFilled, OrderCanceled,
+		OrderExpired, OrderDoneForDay, OrderUnknownState,
+	},
+	OrderPendingReplace: {
+		OrderNew, OrderPartiallyFilled, OrderFilled, OrderCanceled,
+		OrderExpired, OrderDoneForDay, OrderUnknownState,
+	},
+	// The venue's next report resolves an unknown state, whatever it says
+	OrderUnknownState: {
+		OrderPendingNew, OrderNew, OrderPartiallyFilled, OrderFilled,
+		OrderCanceled, OrderRejected, OrderExpired, OrderDoneForDay,
+		OrderPendingCancel, OrderPendingReplace,
+	},
+}
+
+// InvalidTransitionError is returned for a state change the lifecycle does not allow
+type InvalidTransitionError struct {
+	From OrderState
+	To   OrderState
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("invalid order state transition %s -> %s", e.From, e.To)
+}
+
+// ValidateTransition returns an *InvalidTransitionError if an order cannot
+// move from one state to the other
+func ValidateTransition(from, to OrderState) error {
+	if from == to {
+		return nil
+	}
+	for _, allowed := range OrderTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return &InvalidTransitionError{From: from, To: to}
+}
@@ -0,0 +1,184 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to OrderState
+		valid    bool
+	}{
+		{OrderPendingNew, OrderNew, true},
+		{OrderPendingNew, OrderRejected, true},
+		{OrderPendingNew, OrderFilled, true}, // marketable order filled before the ack
+		{OrderNew, OrderNew, true},
+		{OrderNew, OrderPartiallyFilled, true},
+		{OrderPartiallyFilled, OrderPartiallyFilled, true},
+		{OrderPartiallyFilled, OrderFilled, true},
+		{OrderPartiallyFilled, OrderCanceled, true},
+		{OrderUnknownState, OrderCanceled, true},
+		{OrderNew, OrderUnknownState, true},
+		{OrderPendingReplace, OrderUnknownState, true},
+		{OrderFilled, OrderUnknownState, false},
+		{OrderNew, OrderRejected, false},
+		{OrderNew, OrderPendingNew, false},
+		{OrderPartiallyFilled, OrderNew, false},
+		{OrderFilled, OrderCanceled, false},
+		{OrderCanceled, OrderPartiallyFilled, false},
+		{OrderRejected, OrderNew, false},
+	}
+	for _, tt := range tests {
+		err := ValidateTransition(tt.from, tt.to)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateTransition(%s, %s) = %v, want valid=%v", tt.from, tt.to, err, tt.valid)
+		}
+		var invalid *InvalidTransitionError
+		if err != nil && !errors.As(err, &invalid) {
+			t.Errorf("ValidateTransition(%s, %s) returned %T", tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestTrackerIgnoresInvalidTransition(t *testing.T) {
+	tracker := NewOrderTracker(time.Minute)
+	tracker.Add(Order{ClOrdID: "1"})
+
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "2", OrdStatus: "2"}) // Filled
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "4", OrdStatus: "4"}) // late Canceled
+
+	order, _ := tracker.Get("1")
+	if order.State != OrderFilled {
+		t.Fatalf("state = %s, want %s", order.State, OrderFilled)
+	}
+}
+
+func TestTrackerEvictsTerminalOrders(t *testing.T) {
+	archive, err := OpenFileOrderArchive(filepath.Join(t.TempDir(), "orders.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	tracker := NewOrderTracker(time.Minute)
+	tracker.ArchiveTerminal(archive, time.Hour, 10)
+	tracker.Add(Order{ClOrdID: "1", Symbol: "ETH-USD"})
+	tracker.Add(Order{ClOrdID: "2"})
+	if err := tracker.MarkPending("1", "1-cancel", OrderPendingCancel, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1-cancel", OrigClOrdID: "1", ExecType: "4", OrdStatus: "4"})
+
+	if n := tracker.Evict(time.Now()); n != 0 {
+		t.Fatalf("evicted %d orders before the retention", n)
+	}
+	if n := tracker.Evict(time.Now().Add(2 * time.Hour)); n != 1 {
+		t.Fatalf("evicted %d orders, want 1", n)
+	}
+	if orders, _ := tracker.Snapshot(); len(orders) != 1 || orders[0].ClOrdID != "2" {
+		t.Fatalf("live orders %+v, want only 2", orders)
+	}
+
+	order, ok := tracker.Get("1")
+	if !ok || order.State != OrderCanceled || order.Symbol != "ETH-USD" {
+		t.Fatalf("Get(1) = %+v, %t", order, ok)
+	}
+
+	reopened, err := OpenFileOrderArchive(archive.file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	if _, ok, err := reopened.Get("1"); !ok || err != nil {
+		t.Fatalf("reopened archive lost order 1: %v", err)
+	}
+}
+
+func TestTrackerLineageAggregatesFills(t *testing.T) {
+	tracker := NewOrderTracker(time.Minute)
+	tracker.Add(Order{ClOrdID: "1", Quantity: "3"})
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "0", OrdStatus: "0"})
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "1", OrdStatus: "1", LastShares: "1", LastPx: "100"})
+
+	// Two replaces, the venue restarting CumQty with each
+	for _, replace := range []struct{ from, to string }{{"1", "2"}, {"2", "3"}} {
+		if err := tracker.MarkPending(replace.from, replace.to, OrderPendingReplace, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		tracker.OnExecutionReport(ExecutionReport{ClOrdID: replace.to, OrigClOrdID: replace.from, ExecType: "5", OrdStatus: "1", CumQty: "0"})
+	}
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "3", ExecType: "2", OrdStatus: "2", LastShares: "2", LastPx: "103", CumQty: "2"})
+
+	for _, clOrdID := range []string{"1", "2", "3"} {
+		if lineage := tracker.Lineage(clOrdID); !slices.Equal(lineage, []string{"1", "2", "3"}) {
+			t.Errorf("Lineage(%s) = %v, want [1 2 3]", clOrdID, lineage)
+		}
+	}
+	order, _ := tracker.Get("1")
+	if order.CumQty != "3" || order.AvgPx != "102" {
+		t.Fatalf("CumQty %s AvgPx %s, want 3 at 102", order.CumQty, order.AvgPx)
+	}
+	if lineage := tracker.Lineage("unknown"); lineage != nil {
+		t.Fatalf("Lineage(unknown) = %v", lineage)
+	}
+}
+
+func TestTrackerStatusSettlesUnknownOrder(t *testing.T) {
+	tracker := NewOrderTracker(time.Minute)
+	var alerted []string
+	tracker.OnUnknownState = func(order Order) { alerted = append(alerted, order.ClOrdID) }
+	tracker.Add(Order{ClOrdID: "1"})
+	tracker.MarkUnknown("1")
+
+	if unknown := tracker.Unknown(); len(unknown) != 1 || unknown[0].ClOrdID != "1" || !slices.Equal(alerted, []string{"1"}) {
+		t.Fatalf("unknown orders %+v, alerted %v", unknown, alerted)
+	}
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "I", OrdStatus: "0"}) // Order status: New
+	if order, _ := tracker.Get("1"); order.State != OrderNew || len(tracker.Unknown()) != 0 {
+		t.Fatalf("state = %s after the status report, want %s", order.State, OrderNew)
+	}
+
+	// An order the venue already settled stays settled
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "2", OrdStatus: "2"})
+	tracker.MarkUnknown("1")
+	if order, _ := tracker.Get("1"); order.State != OrderFilled || len(alerted) != 1 {
+		t.Fatalf("state = %s after MarkUnknown of a filled order, alerted %v", order.State, alerted)
+	}
+}
+
+func TestTrackerReportsAnomalies(t *testing.T) {
+	tracker := NewOrderTracker(time.Minute)
+	var anomalies []string
+	tracker.OnAnomaly = func(an *Anomaly) { anomalies = append(anomalies, an.Kind) }
+	tracker.Add(Order{ClOrdID: "1"})
+
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "2", OrdStatus: "2"})                // Filled
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "0", OrdStatus: "0"})                // New after Filled
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "2", ExecType: "0", OrdStatus: "0"})                // never placed
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "3", ExecType: "0", OrdStatus: "0", PossDup: true}) // replayed
+
+	if want := []string{"invalid-transition", "untracked-order"}; !slices.Equal(anomalies, want) {
+		t.Fatalf("anomalies %v, want %v", anomalies, want)
+	}
+	if an := decimalAnomaly(ExecutionReport{ExecID: "x", LastShares: "1", LastPx: "1,000.5"}); an == nil || an.Kind != "invalid-decimal" {
+		t.Fatalf("decimalAnomaly = %v, want invalid-decimal", an)
+	}
+}
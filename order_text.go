@@ -0,0 +1,123 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// MaxOrderTextLength is the longest memo an order can carry in Text (58)
+const MaxOrderTextLength = 128
+
+// validateOrderText checks an order memo fits in Text (58)
+func validateOrderText(text string) error {
+	if len(text) > MaxOrderTextLength {
+		return fmt.Errorf("order text is %d bytes, at most %d are allowed", len(text), MaxOrderTextLength)
+	}
+	if strings.IndexFunc(text, unicode.IsControl) >= 0 {
+		return errors.New("order text contains control characters")
+	}
+	return nil
+}
+
+// orderTextPattern matches Text (58) in a raw New, Cancel or Replace message
+var (
+	orderTextMsgTypes = regexp.MustCompile(`(^|[\x01|])35=[DFG][\x01|]`)
+	orderTextPattern  = regexp.MustCompile(`(^|[\x01|])58=[^\x01|]*`)
+)
+
+// TextRedaction is how the memos of orders appear in logs: "redact" (the
+// default) replaces them, "show" logs them as sent and "prefix:N" keeps
+// their first N characters. Text received from Prime is never redacted.
+type TextRedaction struct {
+	Mode string // "redact", "show" or "prefix"
+	Keep int    // characters kept by "prefix"
+}
+
+// ParseTextRedaction parses "redact", "show" or "prefix:N"
+func ParseTextRedaction(value string) (TextRedaction, error) {
+	switch {
+	case value == "redact" || value == "show":
+		return TextRedaction{Mode: value}, nil
+	case strings.HasPrefix(value, "prefix:"):
+		keep, err := strconv.Atoi(strings.TrimPrefix(value, "prefix:"))
+		if err != nil || keep < 0 {
+			break
+		}
+		return TextRedaction{Mode: "prefix", Keep: keep}, nil
+	}
+	return TextRedaction{}, fmt.Errorf("invalid text redaction %q, want redact, show or prefix:N", value)
+}
+
+// Apply redacts the memo of raw when it is an order message
+func (r TextRedaction) Apply(raw string) string {
+	if r.Mode == "show" || !orderTextMsgTypes.MatchString(raw) {
+		return raw
+	}
+	return orderTextPattern.ReplaceAllStringFunc(raw, func(field string) string {
+		prefix, text, _ := strings.Cut(field, "58=")
+		switch {
+		case r.Mode != "prefix":
+			return prefix + "58=" + redacted
+		case len(text) > r.Keep:
+			return prefix + "58=" + text[:r.Keep] + "..."
+		}
+		return field
+	})
+}
+
+// redactedLogFactory wraps a quickfix LogFactory so the messages it logs have
+// their order memos and logon credentials redacted; events are kept
+type redactedLogFactory struct {
+	quickfix.LogFactory
+	redaction TextRedaction
+}
+
+// NewRedactedLogFactory wraps factory to apply redaction to its message logs
+func NewRedactedLogFactory(factory quickfix.LogFactory, redaction TextRedaction) quickfix.LogFactory {
+	return redactedLogFactory{LogFactory: factory, redaction: redaction}
+}
+
+func (f redactedLogFactory) Create() (quickfix.Log, error) {
+	log, err := f.LogFactory.Create()
+	if err != nil {
+		return nil, err
+	}
+	return redactedLog{Log: log, redaction: f.redaction}, nil
+}
+
+func (f redactedLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	log, err := f.LogFactory.CreateSessionLog(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return redactedLog{Log: log, redaction: f.redaction}, nil
+}
+
+type redactedLog struct {
+	quickfix.Log
+	redaction TextRedaction
+}
+
+func (l redactedLog) OnOutgoing(raw []byte) {
+	l.Log.OnOutgoing(redactFIX([]byte(l.redaction.Apply(string(raw)))))
+}
@@ -0,0 +1,615 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderState is the client's view of an order's lifecycle
+type OrderState string
+
+const (
+	OrderPendingNew      OrderState = "PendingNew"
+	OrderNew             OrderState = "New"
+	OrderPartiallyFilled OrderState = "PartiallyFilled"
+	OrderFilled          OrderState = "Filled"
+	OrderDoneForDay      OrderState = "DoneForDay"
+	OrderCanceled        OrderState = "Canceled"
+	OrderRejected        OrderState = "Rejected"
+	OrderExpired         OrderState = "Expired"
+	OrderPendingCancel   OrderState = "PendingCancel"
+	OrderPendingReplace  OrderState = "PendingReplace"
+
+	// OrderUnknownState means a cancel or replace never got a response, or a
+	// new order may or may not have reached the venue, and the order cannot
+	// be trusted until the venue reports it again
+	OrderUnknownState OrderState = "UnknownOrderState"
+)
+
+// orderStateFromOrdStatus maps OrdStatus (39) onto an OrderState
+var orderStateFromOrdStatus = map[string]OrderState{
+	"0": OrderNew,
+	"1": OrderPartiallyFilled,
+	"2": OrderFilled,
+	"3": OrderDoneForDay,
+	"4": OrderCanceled,
+	"6": OrderPendingCancel,
+	"8": OrderRejected,
+	"A": OrderPendingNew,
+	"C": OrderExpired,
+	"E": OrderPendingReplace,
+}
+
+// Terminal reports whether no further executions are expected for the order
+func (s OrderState) Terminal() bool {
+	switch s {
+	case OrderFilled, OrderCanceled, OrderRejected, OrderExpired, OrderDoneForDay:
+		return true
+	}
+	return false
+}
+
+// Order is a tracked order
+type Order struct {
+	ClOrdID     string
+	OrderID     string
+	Symbol      string
+	Side        string
+	OrdType     string
+	Quantity    string
+	LimitPrice  string
+	StopPrice   string // STOP_LIMIT only
+	PortfolioId string
+	State       OrderState
+	Metadata    map[string]string
+	ArrivalMid  string
+
+	// CorrelationId is the correlation ID the order was placed with
+	CorrelationId string
+
+	// Pending is OrderPendingCancel or OrderPendingReplace while a request is
+	// outstanding, with PendingClOrdID the ClOrdID of that request
+	Pending        OrderState
+	PendingClOrdID string
+	PendingSince   time.Time
+
+	// PendingQuantity is the quantity an outstanding replace asks for, which
+	// counts as working until the venue answers
+	PendingQuantity string
+
+	// PlacedAt is when the order was sent
+	PlacedAt time.Time
+	// TerminalAt is when the order reached a terminal state
+	TerminalAt time.Time
+
+	// Lineage holds the ClOrdIDs the order went by before each accepted
+	// replace, oldest first
+	Lineage []string
+
+	// CumQty and AvgPx aggregate the fills across the whole replace chain
+	CumQty string
+	AvgPx  string
+}
+
+// OrderTracker follows orders through their lifecycle from the client's
+// outbound requests and the venue's ExecutionReports and cancel rejects
+type OrderTracker struct {
+	mu      sync.Mutex
+	orders  map[string]*Order
+	aliases map[string]string // request ClOrdID -> tracked order ClOrdID
+
+	pendingTimeout time.Duration
+
+	// Terminal orders move to archive once retention has passed, see ArchiveTerminal
+	archive   OrderArchive
+	retention time.Duration
+	cache     *orderCache
+
+	// Clock stamps TerminalAt and times WatchTimeouts; nil uses the wall clock
+	Clock Clock
+
+	// OnUnknownState is called when an order enters OrderUnknownState
+	OnUnknownState func(order Order)
+
+	// OnAnomaly, when set, is called instead of logging a warning for an
+	// ExecutionReport, cancel reject or reconciliation the tracker cannot apply
+	OnAnomaly func(an *Anomaly)
+}
+
+// NewOrderTracker creates a tracker that gives up on cancel and replace
+// requests which receive no response within pendingTimeout
+func NewOrderTracker(pendingTimeout time.Duration) *OrderTracker {
+	return &OrderTracker{
+		orders:         make(map[string]*Order),
+		aliases:        make(map[string]string),
+		pendingTimeout: pendingTimeout,
+	}
+}
+
+// Add starts tracking a newly submitted order
+func (t *OrderTracker) Add(order Order) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if order.State == "" {
+		order.State = OrderPendingNew
+	}
+	t.orders[order.ClOrdID] = &order
+}
+
+// Remove stops tracking clOrdID, e.g. when it could not be sent
+func (t *OrderTracker) Remove(clOrdID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, clOrdID)
+}
+
+// ArchiveTerminal bounds the tracker's memory by moving orders to archive
+// once they have been terminal for retention, see Evict. Get still finds
+// archived orders, keeping the last cacheSize looked up in memory.
+func (t *OrderTracker) ArchiveTerminal(archive OrderArchive, retention time.Duration, cacheSize int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.archive = archive
+	t.retention = retention
+	t.cache = newOrderCache(cacheSize)
+}
+
+// Get returns a copy of the order known by clOrdID, which may be the ClOrdID
+// of the order itself or of a cancel or replace request against it
+func (t *OrderTracker) Get(clOrdID string) (Order, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.lookup(clOrdID)
+	if ok {
+		return *order, true
+	}
+	if t.archive == nil {
+		return Order{}, false
+	}
+
+	if archived, ok := t.cache.get(clOrdID); ok {
+		return archived, true
+	}
+	archived, ok, err := t.archive.Get(clOrdID)
+	if err != nil {
+		log.Printf("Failed to read archived order %s: %v", clOrdID, err)
+		return Order{}, false
+	}
+	if ok {
+		t.cache.put(clOrdID, archived)
+	}
+	return archived, ok
+}
+
+// Evict moves the orders that have been terminal for longer than the
+// retention, and the request ClOrdIDs aliasing them, to the archive. It
+// returns the number of orders evicted and does nothing without an archive.
+func (t *OrderTracker) Evict(now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.archive == nil {
+		return 0
+	}
+
+	aliases := make(map[string][]string)
+	for from := range t.aliases {
+		if order, ok := t.lookup(from); ok {
+			aliases[order.ClOrdID] = append(aliases[order.ClOrdID], from)
+		}
+	}
+
+	evicted := 0
+	for clOrdID, order := range t.orders {
+		if !order.State.Terminal() || order.Pending != "" || order.TerminalAt.IsZero() || now.Sub(order.TerminalAt) < t.retention {
+			continue
+		}
+		if err := t.archive.Put(*order, aliases[clOrdID]); err != nil {
+			log.Printf("Failed to archive order %s, keeping it in memory: %v", clOrdID, err)
+			break
+		}
+		delete(t.orders, clOrdID)
+		for _, from := range aliases[clOrdID] {
+			delete(t.aliases, from)
+		}
+		evicted++
+	}
+	return evicted
+}
+
+func (t *OrderTracker) lookup(clOrdID string) (*Order, bool) {
+	// Aliases chain through successive replaces, each pointing at the next ClOrdID
+	for hops := 0; hops <= len(t.aliases); hops++ {
+		if order, ok := t.orders[clOrdID]; ok {
+			return order, true
+		}
+		next, ok := t.aliases[clOrdID]
+		if !ok {
+			break
+		}
+		clOrdID = next
+	}
+	return nil, false
+}
+
+// Snapshot returns copies of the orders that are not yet terminal, with the
+// request ClOrdIDs that alias them
+func (t *OrderTracker) Snapshot() ([]Order, map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var orders []Order
+	for _, order := range t.orders {
+		if !order.State.Terminal() {
+			orders = append(orders, *order)
+		}
+	}
+	aliases := make(map[string]string)
+	for from := range t.aliases {
+		if order, ok := t.lookup(from); ok && !order.State.Terminal() {
+			aliases[from] = order.ClOrdID
+		}
+	}
+	return orders, aliases
+}
+
+// WorkingQty returns the unfilled quantity of the open buy and sell orders in
+// symbol
+func (t *OrderTracker) WorkingQty(symbol string) (buy, sell decimal.Decimal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, order := range t.orders {
+		if order.Symbol != symbol || order.State.Terminal() {
+			continue
+		}
+		qty, err := decimal.NewFromString(order.Quantity)
+		if err != nil {
+			continue
+		}
+		// Until the venue answers, a replace may be working either quantity
+		if pending, err := decimal.NewFromString(order.PendingQuantity); err == nil {
+			qty = decimal.Max(qty, pending)
+		}
+		cumQty, _ := decimal.NewFromString(order.CumQty)
+		open := qty.Sub(cumQty)
+		if !open.IsPositive() {
+			continue
+		}
+		if order.Side == "SELL" {
+			sell = sell.Add(open)
+		} else {
+			buy = buy.Add(open)
+		}
+	}
+	return buy, sell
+}
+
+// Restore starts tracking orders and aliases taken by Snapshot, alongside
+// any orders already tracked
+func (t *OrderTracker) Restore(orders []Order, aliases map[string]string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, order := range orders {
+		order := order
+		t.orders[order.ClOrdID] = &order
+	}
+	for from, to := range aliases {
+		t.aliases[from] = to
+	}
+}
+
+// MarkPending records an outstanding cancel (OrderPendingCancel) or replace
+// (OrderPendingReplace) request, identified by requestClOrdID, against clOrdID
+func (t *OrderTracker) MarkPending(clOrdID, requestClOrdID string, pending OrderState, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, err := t.markPending(clOrdID, requestClOrdID, pending, now)
+	return err
+}
+
+// MarkPendingReplace is MarkPending for a replace asking for quantity
+func (t *OrderTracker) MarkPendingReplace(clOrdID, requestClOrdID, quantity string, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, err := t.markPending(clOrdID, requestClOrdID, OrderPendingReplace, now)
+	if err == nil {
+		order.PendingQuantity = quantity
+	}
+	return err
+}
+
+// markPending is MarkPending; callers must hold mu
+func (t *OrderTracker) markPending(clOrdID, requestClOrdID string, pending OrderState, now time.Time) (*Order, error) {
+	order, ok := t.lookup(clOrdID)
+	if !ok {
+		return nil, fmt.Errorf("unknown order %s", clOrdID)
+	}
+	if order.State.Terminal() {
+		return nil, fmt.Errorf("order %s is %s", clOrdID, order.State)
+	}
+	if order.Pending != "" {
+		return nil, fmt.Errorf("order %s already has a %s request outstanding", clOrdID, order.Pending)
+	}
+
+	order.Pending = pending
+	order.PendingClOrdID = requestClOrdID
+	order.PendingSince = now
+	t.aliases[requestClOrdID] = order.ClOrdID
+	return order, nil
+}
+
+// OnExecutionReport applies an ExecutionReport to the tracked order it
+// refers to, passing a report for an unknown order or one the lifecycle does
+// not allow to OnAnomaly
+func (t *OrderTracker) OnExecutionReport(report ExecutionReport) {
+	t.anomaly(t.applyExecutionReport(report))
+}
+
+// anomaly passes an, when there is one, to OnAnomaly; callers must not hold mu
+func (t *OrderTracker) anomaly(an *Anomaly) {
+	if an == nil {
+		return
+	}
+	if t.OnAnomaly != nil {
+		t.OnAnomaly(an)
+	} else {
+		log.Println("ANOMALY:", an)
+	}
+}
+
+func (t *OrderTracker) applyExecutionReport(report ExecutionReport) *Anomaly {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.lookup(report.ClOrdID)
+	if !ok {
+		order, ok = t.lookup(report.OrigClOrdID)
+	}
+	if !ok && (report.PossDup || report.PossResend) {
+		// Replays may well be of orders from before a restart
+		log.Println("Resent Execution Report for untracked order:", report.ClOrdID)
+		return nil
+	}
+	if !ok {
+		return &Anomaly{Kind: "untracked-order", Detail: "Execution Report for untracked order " + report.ClOrdID}
+	}
+
+	if report.OrderID != "" {
+		order.OrderID = report.OrderID
+	}
+
+	switch report.ExecType {
+	case "6", "E": // Pending Cancel, Pending Replace
+		// The venue has acknowledged the request; keep waiting for the outcome
+		return nil
+	case "5": // Replace
+		t.completeReplace(order, report)
+	case "1", "2", "F": // Partial fill, Fill, Trade
+		addFill(order, report)
+	}
+
+	timedOut := order.State == OrderUnknownState && order.Pending != ""
+	var an *Anomaly
+	if state, ok := orderStateFromOrdStatus[report.OrdStatus]; ok && state != OrderPendingCancel && state != OrderPendingReplace {
+		an = t.setState(order, state, "ExecID "+report.ExecID)
+	}
+	// A request that timed out is settled by whatever the venue reports next,
+	// after which the order can be cancelled or replaced again
+	if (order.Pending == OrderPendingCancel && order.State.Terminal()) || (timedOut && order.State != OrderUnknownState) {
+		t.clearPending(order)
+	}
+	return an
+}
+
+// completeReplace re-keys order under the ClOrdID of the accepted replace
+func (t *OrderTracker) completeReplace(order *Order, report ExecutionReport) {
+	if order.Pending != OrderPendingReplace || report.ClOrdID != order.PendingClOrdID {
+		return
+	}
+
+	delete(t.orders, order.ClOrdID)
+	t.aliases[order.ClOrdID] = report.ClOrdID
+	delete(t.aliases, report.ClOrdID)
+	order.Lineage = append(order.Lineage, order.ClOrdID)
+	order.ClOrdID = report.ClOrdID
+	if report.Quantity != "" {
+		order.Quantity = report.Quantity
+	}
+	if report.Price != "" {
+		order.LimitPrice = report.Price
+	}
+	t.orders[order.ClOrdID] = order
+	t.clearPending(order)
+}
+
+// addFill adds the fill carried by report to the order's CumQty and AvgPx.
+// The venue's own CumQty and AvgPx may restart with each replace, so they are
+// built up from LastShares and LastPx instead.
+func addFill(order *Order, report ExecutionReport) {
+	qty, err := decimal.NewFromString(report.LastShares)
+	if err != nil || !qty.IsPositive() {
+		return
+	}
+	px, err := decimal.NewFromString(report.LastPx)
+	if err != nil {
+		return
+	}
+	cumQty, _ := decimal.NewFromString(order.CumQty)
+	avgPx, _ := decimal.NewFromString(order.AvgPx)
+	notional := avgPx.Mul(cumQty).Add(qty.Mul(px))
+	cumQty = cumQty.Add(qty)
+	order.CumQty = cumQty.String()
+	order.AvgPx = notional.Div(cumQty).String()
+}
+
+// Lineage returns every ClOrdID of the replace chain clOrdID belongs to,
+// oldest first and ending with the order's current ClOrdID, or nil when the
+// order is unknown
+func (t *OrderTracker) Lineage(clOrdID string) []string {
+	order, ok := t.Get(clOrdID)
+	if !ok {
+		return nil
+	}
+	return append(slices.Clone(order.Lineage), order.ClOrdID)
+}
+
+// OnCancelReject applies an OrderCancelReject (35=9) for the request clOrdID
+func (t *OrderTracker) OnCancelReject(clOrdID, ordStatus string) {
+	t.anomaly(t.applyCancelReject(clOrdID, ordStatus))
+}
+
+func (t *OrderTracker) applyCancelReject(clOrdID, ordStatus string) *Anomaly {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.lookup(clOrdID)
+	if !ok {
+		log.Println("Cancel Reject for untracked order:", clOrdID)
+		return nil
+	}
+
+	t.clearPending(order)
+	if state, ok := orderStateFromOrdStatus[ordStatus]; ok {
+		return t.setState(order, state, "cancel reject "+clOrdID)
+	}
+	return nil
+}
+
+// setState moves order to state, logging and ignoring a transition the
+// lifecycle does not allow, such as a late report reopening a filled order
+func (t *OrderTracker) setState(order *Order, state OrderState, source string) *Anomaly {
+	if err := ValidateTransition(order.State, state); err != nil {
+		return &Anomaly{Kind: "invalid-transition", Detail: fmt.Sprintf("order %s: %v from %s, keeping %s", order.ClOrdID, err, source, order.State)}
+	}
+	if state.Terminal() && !order.State.Terminal() {
+		order.TerminalAt = clockOrSystem(t.Clock).Now()
+	}
+	order.State = state
+	return nil
+}
+
+// Reconcile applies the state of clOrdID learned outside the FIX session,
+// e.g. from the REST API, clearing any outstanding request
+func (t *OrderTracker) Reconcile(clOrdID string, state OrderState) {
+	t.anomaly(t.reconcile(clOrdID, state))
+}
+
+func (t *OrderTracker) reconcile(clOrdID string, state OrderState) *Anomaly {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.lookup(clOrdID)
+	if !ok {
+		return nil
+	}
+	if order.Pending != "" && state != order.State {
+		t.clearPending(order)
+	}
+	return t.setState(order, state, "reconciliation")
+}
+
+func (t *OrderTracker) clearPending(order *Order) {
+	delete(t.aliases, order.PendingClOrdID)
+	order.Pending = ""
+	order.PendingClOrdID = ""
+	order.PendingSince = time.Time{}
+	order.PendingQuantity = ""
+}
+
+// MarkUnknown moves clOrdID into OrderUnknownState, e.g. when its send failed
+// after it may have reached the venue
+func (t *OrderTracker) MarkUnknown(clOrdID string) {
+	t.mu.Lock()
+	order, ok := t.lookup(clOrdID)
+	var unknown Order
+	var an *Anomaly
+	if ok {
+		// A report may have settled the order while its send was failing
+		if ok = !order.State.Terminal(); ok {
+			an = t.setState(order, OrderUnknownState, "ambiguous send")
+			unknown = *order
+		}
+	}
+	t.mu.Unlock()
+
+	t.anomaly(an)
+	if ok && an == nil && t.OnUnknownState != nil {
+		t.OnUnknownState(unknown)
+	}
+}
+
+// Unknown returns copies of the orders in OrderUnknownState
+func (t *OrderTracker) Unknown() []Order {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var orders []Order
+	for _, order := range t.orders {
+		if order.State == OrderUnknownState {
+			orders = append(orders, *order)
+		}
+	}
+	return orders
+}
+
+// CheckTimeouts moves orders whose cancel or replace has been outstanding for
+// longer than the pending timeout into OrderUnknownState
+func (t *OrderTracker) CheckTimeouts(now time.Time) {
+	var stuck []Order
+
+	t.mu.Lock()
+	for _, order := range t.orders {
+		if order.Pending == "" || order.State == OrderUnknownState || now.Sub(order.PendingSince) < t.pendingTimeout {
+			continue
+		}
+		if t.setState(order, OrderUnknownState, "pending timeout") != nil {
+			continue
+		}
+		stuck = append(stuck, *order)
+	}
+	t.mu.Unlock()
+
+	for _, order := range stuck {
+		log.Printf("ALERT: %s for order %s timed out after %s, order is in %s and needs manual intervention",
+			order.Pending, order.ClOrdID, t.pendingTimeout, OrderUnknownState)
+		if t.OnUnknownState != nil {
+			t.OnUnknownState(order)
+		}
+	}
+}
+
+// WatchTimeouts calls CheckTimeouts and Evict every interval until stop is closed
+func (t *OrderTracker) WatchTimeouts(interval time.Duration, stop <-chan struct{}) {
+	clock := clockOrSystem(t.Clock)
+	for sleepUntil(clock, clock.Now().Add(interval), stop) {
+		now := clock.Now()
+		t.CheckTimeouts(now)
+		t.Evict(now)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTrackerLifecycle(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		reports  []ExecutionReport
+		want     OrderState
+		terminal bool
+	}{
+		{"acknowledged", []ExecutionReport{{ExecType: "0", OrdStatus: "0"}}, OrderNew, false},
+		{"partially filled", []ExecutionReport{
+			{ExecType: "0", OrdStatus: "0"},
+			{ExecType: "1", OrdStatus: "1", LastShares: "1", LastPx: "100"},
+		}, OrderPartiallyFilled, false},
+		{"filled", []ExecutionReport{
+			{ExecType: "0", OrdStatus: "0"},
+			{ExecType: "2", OrdStatus: "2", LastShares: "2", LastPx: "100"},
+		}, OrderFilled, true},
+		{"rejected", []ExecutionReport{{ExecType: "8", OrdStatus: "8"}}, OrderRejected, true},
+		{"late cancel after fill", []ExecutionReport{
+			{ExecType: "2", OrdStatus: "2", LastShares: "2", LastPx: "100"},
+			{ExecType: "4", OrdStatus: "4"},
+		}, OrderFilled, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(start)
+			tracker := NewOrderTracker(time.Minute)
+			tracker.Clock = clock
+			tracker.OnAnomaly = func(*Anomaly) {}
+			tracker.Add(Order{ClOrdID: "1", Quantity: "2"})
+
+			for _, report := range tt.reports {
+				report.ClOrdID = "1"
+				tracker.OnExecutionReport(report)
+				clock.Advance(time.Second)
+			}
+
+			order, _ := tracker.Get("1")
+			if order.State != tt.want {
+				t.Fatalf("state = %s, want %s", order.State, tt.want)
+			}
+			// TerminalAt comes from the tracker's clock and is not moved by a later report
+			if want := start.Add(time.Duration(firstTerminal(tt.reports)) * time.Second); tt.terminal && !order.TerminalAt.Equal(want) {
+				t.Fatalf("TerminalAt = %s, want %s", order.TerminalAt, want)
+			}
+			if !tt.terminal && !order.TerminalAt.IsZero() {
+				t.Fatalf("TerminalAt = %s for a working order", order.TerminalAt)
+			}
+		})
+	}
+}
+
+// firstTerminal returns the index of the first report moving an order into a
+// terminal state
+func firstTerminal(reports []ExecutionReport) int {
+	for i, report := range reports {
+		if orderStateFromOrdStatus[report.OrdStatus].Terminal() {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestTrackerEvictsByClock(t *testing.T) {
+	archive, err := OpenFileOrderArchive(filepath.Join(t.TempDir(), "orders.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	clock := NewFakeClock(time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC))
+	tracker := NewOrderTracker(time.Minute)
+	tracker.Clock = clock
+	tracker.ArchiveTerminal(archive, time.Hour, 10)
+	tracker.Add(Order{ClOrdID: "1"})
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "2", OrdStatus: "2"})
+
+	// The wall clock is well past the fake one, so eviction must be measured
+	// from the fake TerminalAt
+	if n := tracker.Evict(clock.Now()); n != 0 {
+		t.Fatalf("evicted %d orders at their terminal time", n)
+	}
+	if n := tracker.Evict(clock.Now().Add(2 * time.Hour)); n != 1 {
+		t.Fatalf("evicted %d orders, want 1", n)
+	}
+}
+
+func TestTrackerMarkPending(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   OrderState
+		pending OrderState
+		wantErr bool
+	}{
+		{"working order", OrderNew, "", false},
+		{"filled order", OrderFilled, "", true},
+		{"request outstanding", OrderNew, OrderPendingCancel, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewOrderTracker(time.Minute)
+			tracker.Add(Order{ClOrdID: "1", State: tt.state, Pending: tt.pending})
+			err := tracker.MarkPending("1", "1-cancel", OrderPendingCancel, time.Now())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MarkPending = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+	if err := NewOrderTracker(time.Minute).MarkPending("1", "1-cancel", OrderPendingCancel, time.Now()); err == nil {
+		t.Fatal("MarkPending accepted an unknown order")
+	}
+}
+
+func TestTrackerReplaceAliases(t *testing.T) {
+	tracker := NewOrderTracker(time.Minute)
+	tracker.Add(Order{ClOrdID: "1", Quantity: "2", LimitPrice: "100"})
+	if err := tracker.MarkPending("1", "2", OrderPendingReplace, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	// The replace request resolves to the original order while outstanding
+	if order, ok := tracker.Get("2"); !ok || order.ClOrdID != "1" || order.Pending != OrderPendingReplace {
+		t.Fatalf("Get(2) = %+v, %t", order, ok)
+	}
+
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "2", OrigClOrdID: "1", ExecType: "5", OrdStatus: "0", Quantity: "3", Price: "101"})
+
+	for _, clOrdID := range []string{"1", "2"} {
+		order, ok := tracker.Get(clOrdID)
+		if !ok || order.ClOrdID != "2" || order.Quantity != "3" || order.LimitPrice != "101" || order.Pending != "" {
+			t.Fatalf("Get(%s) = %+v, %t", clOrdID, order, ok)
+		}
+	}
+}
+
+func TestTrackerCancelReject(t *testing.T) {
+	tests := []struct {
+		name      string
+		ordStatus string
+		want      OrderState
+	}{
+		{"still working", "0", OrderNew},
+		{"already filled", "2", OrderFilled},
+		{"no status", "", OrderNew},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewOrderTracker(time.Minute)
+			tracker.Add(Order{ClOrdID: "1", State: OrderNew})
+			if err := tracker.MarkPending("1", "1-cancel", OrderPendingCancel, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+
+			tracker.OnCancelReject("1-cancel", tt.ordStatus)
+
+			order, _ := tracker.Get("1")
+			if order.State != tt.want || order.Pending != "" || order.PendingClOrdID != "" {
+				t.Fatalf("order %+v, want %s with nothing pending", order, tt.want)
+			}
+			if _, ok := tracker.Get("1-cancel"); ok {
+				t.Fatal("the rejected request still resolves to the order")
+			}
+		})
+	}
+}
+
+func TestTrackerCheckTimeouts(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    OrderState
+	}{
+		{"within the timeout", 59 * time.Second, OrderNew},
+		{"at the timeout", time.Minute, OrderUnknownState},
+		{"past the timeout", time.Hour, OrderUnknownState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewOrderTracker(time.Minute)
+			var alerted []string
+			tracker.OnUnknownState = func(order Order) { alerted = append(alerted, order.ClOrdID) }
+			tracker.Add(Order{ClOrdID: "1", State: OrderNew})
+			tracker.Add(Order{ClOrdID: "2", State: OrderNew}) // nothing outstanding
+			if err := tracker.MarkPending("1", "1-cancel", OrderPendingCancel, start); err != nil {
+				t.Fatal(err)
+			}
+
+			tracker.CheckTimeouts(start.Add(tt.elapsed))
+			tracker.CheckTimeouts(start.Add(tt.elapsed)) // alerts once
+
+			if order, _ := tracker.Get("1"); order.State != tt.want {
+				t.Fatalf("state = %s, want %s", order.State, tt.want)
+			}
+			if order, _ := tracker.Get("2"); order.State != OrderNew {
+				t.Fatalf("order without a request moved to %s", order.State)
+			}
+			if wantAlerts := tt.want == OrderUnknownState; (len(alerted) == 1) != wantAlerts || len(alerted) > 1 {
+				t.Fatalf("alerted %v", alerted)
+			}
+		})
+	}
+}
+
+func TestTrackerWatchTimeoutsUsesClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC))
+	tracker := NewOrderTracker(time.Minute)
+	tracker.Clock = clock
+	alerted := make(chan string, 1)
+	tracker.OnUnknownState = func(order Order) { alerted <- order.ClOrdID }
+	tracker.Add(Order{ClOrdID: "1", State: OrderNew})
+	if err := tracker.MarkPending("1", "1-cancel", OrderPendingCancel, clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go tracker.WatchTimeouts(time.Second, stop)
+
+	// Only the fake clock moves, so the timeout can only come from it
+	deadline := time.After(5 * time.Second)
+	for {
+		clock.Advance(time.Second)
+		select {
+		case clOrdID := <-alerted:
+			if clOrdID != "1" || clock.Now().Sub(time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)) < time.Minute {
+				t.Fatalf("order %s timed out at %s", clOrdID, clock.Now())
+			}
+			return
+		case <-deadline:
+			t.Fatal("the pending cancel never timed out on the fake clock")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestTrackerSettlesTimedOutRequest(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tracker := NewOrderTracker(time.Minute)
+	var alerted []string
+	tracker.OnUnknownState = func(order Order) { alerted = append(alerted, order.ClOrdID) }
+	tracker.Add(Order{ClOrdID: "1", State: OrderNew})
+	if err := tracker.MarkPending("1", "1-cancel", OrderPendingCancel, start); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker.CheckTimeouts(start.Add(time.Minute))
+	// The status request answers that the cancel never took effect
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "1", ExecType: "I", OrdStatus: "0"})
+	tracker.CheckTimeouts(start.Add(2 * time.Minute))
+
+	order, _ := tracker.Get("1")
+	if order.State != OrderNew || order.Pending != "" || len(alerted) != 1 {
+		t.Fatalf("order %+v, alerted %v", order, alerted)
+	}
+	if err := tracker.MarkPending("1", "1-cancel-2", OrderPendingCancel, start.Add(2*time.Minute)); err != nil {
+		t.Fatalf("cancel after the timed out one settled: %v", err)
+	}
+}
+
+func TestTrackerReportsAnomaliesOutsideExecutionReports(t *testing.T) {
+	tracker := NewOrderTracker(time.Minute)
+	var anomalies []string
+	tracker.OnAnomaly = func(an *Anomaly) { anomalies = append(anomalies, an.Kind) }
+	tracker.Add(Order{ClOrdID: "1", State: OrderFilled})
+	tracker.Add(Order{ClOrdID: "2", State: OrderCanceled})
+
+	tracker.OnCancelReject("1", "0")             // New after Filled
+	tracker.Reconcile("2", OrderPartiallyFilled) // PartiallyFilled after Canceled
+
+	if len(anomalies) != 2 || anomalies[0] != "invalid-transition" || anomalies[1] != "invalid-transition" {
+		t.Fatalf("anomalies %v, want two invalid transitions", anomalies)
+	}
+}
@@ -0,0 +1,459 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ErrNoTracker is returned by operations that need an OrderTracker when none is configured
+var ErrNoTracker = errors.New("order tracker is not configured")
+
+// PlaceOrder sends a NewOrderSingle and starts tracking it, returning its ClOrdID
+func (a *FixApplication) PlaceOrder(req OrderRequest) (string, error) {
+	return a.placeOrder(req, true)
+}
+
+// placeChildOrder places an order owned by a parent manager, such as an
+// iceberg child or a bracket exit. The manager reacts to its rejects itself,
+// so the Retrier does not resubmit it behind the manager's back.
+func (a *FixApplication) placeChildOrder(req OrderRequest) (string, error) {
+	return a.placeOrder(req, false)
+}
+
+// placeOrder places req, which the Retrier may resubmit when retry is set
+func (a *FixApplication) placeOrder(req OrderRequest, retry bool) (string, error) {
+	req = a.Defaults.Apply(req)
+	if err := a.validateOrder(req); err != nil {
+		return "", err
+	}
+	req, _ = applyAlgoParams(req)
+	var reservation RiskReservation
+	if a.Risk != nil {
+		var err error
+		if reservation, err = a.Risk.Reserve(req, a.now()); err != nil {
+			return "", err
+		}
+	}
+	if req.CorrelationId == "" {
+		req.CorrelationId = newCorrelationId()
+	}
+	if req.ArrivalMid == "" && a.Mids != nil {
+		if mid, ok := a.Mids.Mid(req.Symbol); ok {
+			req.ArrivalMid = mid.String()
+		}
+	}
+
+	a.builderMu.Lock()
+	if a.builder == nil {
+		a.builder = newOrderBuilder(os.Getenv("SVC_ACCOUNTID"), a.TargetCompId)
+		a.builder.correlationTag = a.CorrelationTag
+	}
+	wireReq := req
+	wireReq.Symbol = a.Symbols.ToPrime(req.Symbol)
+	// The builder reuses its message, so a copy is sent, and the lock is not
+	// held while the order waits its turn in the SendQueue
+	order := quickfix.NewMessage()
+	a.builder.build(wireReq, a.PortfolioId, a.now()).CopyInto(order)
+	clOrdId := string(a.builder.clOrdId)
+	a.builderMu.Unlock()
+	if a.LogSampler.Sample("D") {
+		a.logger().Printf("Raw FIX Message (CorrelationId=%s): %s", req.CorrelationId, a.Redaction.Apply(order.String()))
+	}
+
+	if a.Tracker != nil {
+		a.Tracker.Add(Order{
+			ClOrdID:     clOrdId,
+			Symbol:      req.Symbol,
+			Side:        req.Side,
+			OrdType:     req.OrdType,
+			Quantity:    req.Quantity,
+			LimitPrice:  req.LimitPrice,
+			StopPrice:   req.StopPrice,
+			PortfolioId: a.PortfolioId,
+			Metadata:    req.Metadata,
+			ArrivalMid:  req.ArrivalMid,
+			PlacedAt:    a.now(),
+
+			CorrelationId: req.CorrelationId,
+		})
+	}
+	if a.Algos != nil && isAlgo(req.OrdType) {
+		a.Algos.Add(clOrdId, req)
+	}
+	if a.Retrier != nil && retry {
+		a.Retrier.Add(clOrdId, req)
+	}
+	if a.Shadow != nil {
+		a.Shadow.Add(clOrdId, req, a.now())
+	}
+
+	err := a.Send(order)
+	if a.Risk != nil {
+		// An ambiguous send may have reached the venue, so it stays counted
+		a.Risk.Release(reservation, err == nil || errors.Is(err, ErrAmbiguousSend))
+	}
+	// Mirrored only once production is sent, so the shadow session cannot
+	// hold up production orders
+	if a.Shadow != nil {
+		a.Shadow.Sent(clOrdId, err == nil || a.Tracker != nil && errors.Is(err, ErrAmbiguousSend))
+	}
+	if err != nil {
+		if a.Tracker == nil || !errors.Is(err, ErrAmbiguousSend) {
+			if a.Tracker != nil {
+				a.Tracker.Remove(clOrdId)
+			}
+			if a.Retrier != nil {
+				a.Retrier.Remove(clOrdId)
+			}
+			return clOrdId, err
+		}
+		// The order may be live: keep it as unknown and ask the venue
+		a.logger().Printf("Send of order %s failed after it may have been sent: %v", clOrdId, err)
+		a.Tracker.MarkUnknown(clOrdId)
+		a.RequestStatus(clOrdId)
+		return clOrdId, err
+	}
+	return clOrdId, nil
+}
+
+// RequestStatus sends an OrderStatusRequest for the tracked order clOrdID.
+// The venue answers with an ExecutionReport (ExecType I) that the tracker
+// applies like any other.
+func (a *FixApplication) RequestStatus(clOrdID string) {
+	if a.Tracker == nil {
+		return
+	}
+	order, ok := a.Tracker.Get(clOrdID)
+	if !ok {
+		return
+	}
+	order.Symbol = a.Symbols.ToPrime(order.Symbol)
+	if err := a.Send(createStatusRequestMessage(order)); err != nil {
+		a.logger().Printf("Failed to request status of order %s, retrying on next logon: %v", clOrdID, err)
+	}
+}
+
+// validateOrder runs the pre-trade checks on req without sending it
+func (a *FixApplication) validateOrder(req OrderRequest) error {
+	if err := a.tradable(req.Symbol); err != nil {
+		return err
+	}
+	req = a.Defaults.Apply(req)
+	if err := a.Defaults.Check(req); err != nil {
+		return err
+	}
+	req, err := applyAlgoParams(req)
+	if err != nil {
+		return err
+	}
+	if err := validateExecInst(req.OrdType, req.ExecInst); err != nil {
+		return err
+	}
+	if err := validateOrderText(req.Text); err != nil {
+		return err
+	}
+	if a.Rules != nil {
+		if err := a.Rules.Check(req, a.PortfolioId, a.now()); err != nil {
+			return err
+		}
+	}
+	if isAlgo(req.OrdType) && req.ExpireTime.IsZero() {
+		return errors.New(req.OrdType + " orders require an ExpireTime")
+	}
+	if _, ok := TimeInForceCode(req.TimeInForce); req.TimeInForce != "" && !ok {
+		return errors.New("unknown time in force " + req.TimeInForce)
+	}
+	if req.TimeInForce == "GTD" && req.ExpireTime.IsZero() {
+		return errors.New("GTD orders require an ExpireTime")
+	}
+	if a.Risk != nil {
+		if err := a.Risk.Check(req); err != nil {
+			return err
+		}
+	}
+	if a.Balances != nil {
+		return a.Balances.Check(req, a.Symbols.ToPrime(req.Symbol))
+	}
+	return nil
+}
+
+// validateReplace runs the pre-trade checks that apply to replacing order
+// with req, which carries its new quantity and price
+func (a *FixApplication) validateReplace(order Order, req OrderRequest) error {
+	if err := a.tradable(req.Symbol); err != nil {
+		return err
+	}
+	if err := a.Defaults.Check(req); err != nil {
+		return err
+	}
+	if a.Rules != nil {
+		return a.Rules.Check(req, a.PortfolioId, a.now())
+	}
+	return nil
+}
+
+// tradable returns why orders in symbol cannot be sent right now, if at all
+func (a *FixApplication) tradable(symbol string) error {
+	if a.Draining() {
+		return ErrDraining
+	}
+	if a.Breaker != nil {
+		if err := a.Breaker.Allow(); err != nil {
+			return err
+		}
+	}
+	if a.PriceBreakers != nil {
+		if err := a.PriceBreakers.Allow(symbol); err != nil {
+			return err
+		}
+	}
+	if a.Venue != nil && !a.Venue.Tradable(symbol) {
+		return fmt.Errorf("%w: %s", ErrSymbolHalted, symbol)
+	}
+	return nil
+}
+
+// SubmitBasket validates every order of the basket before placing any, see
+// BasketManager.Submit, returning the basket id
+func (a *FixApplication) SubmitBasket(reqs []OrderRequest) (string, error) {
+	if a.Baskets == nil {
+		return "", ErrNoBaskets
+	}
+	return a.Baskets.Submit(reqs)
+}
+
+// CancelBasket cancels every working order of the basket id
+func (a *FixApplication) CancelBasket(id string) error {
+	if a.Baskets == nil {
+		return ErrNoBaskets
+	}
+	return a.Baskets.Cancel(id)
+}
+
+// CancelOrder sends an OrderCancelRequest for the tracked order clOrdID and
+// marks it PendingCancel until the venue responds. While the session is down
+// the cancel goes over REST when RESTCancel is set.
+func (a *FixApplication) CancelOrder(clOrdID string) error {
+	if a.Tracker == nil {
+		return ErrNoTracker
+	}
+	order, ok := a.Tracker.Get(clOrdID)
+	if !ok {
+		return errors.New("unknown order " + clOrdID)
+	}
+	if a.RESTCancel != nil && !a.IsLoggedOn() {
+		if err := a.RESTCancel.cancel(a.Tracker, order); err != nil {
+			return err
+		}
+		a.mirrorCancel(clOrdID)
+		return nil
+	}
+
+	now := a.now()
+	cancelClOrdId := strconv.FormatInt(nextClOrdID(now), 10)
+	if err := a.Tracker.MarkPending(order.ClOrdID, cancelClOrdId, OrderPendingCancel, now); err != nil {
+		return err
+	}
+
+	order.Symbol = a.Symbols.ToPrime(order.Symbol)
+	if err := a.Send(createCancelMessage(order, cancelClOrdId)); err != nil {
+		a.requestSendFailed("cancel", clOrdID, cancelClOrdId, err)
+		return err
+	}
+	a.mirrorCancel(clOrdID)
+	return nil
+}
+
+// requestSendFailed settles the order clOrdID after its cancel or replace
+// requestClOrdId failed to send with err. A request that may still reach the
+// venue stays pending while the order is unknown and its status requested;
+// any other failure drops the request.
+func (a *FixApplication) requestSendFailed(kind, clOrdID, requestClOrdId string, err error) {
+	if errors.Is(err, ErrAmbiguousSend) {
+		a.logger().Printf("Send of %s %s of order %s failed after it may have been sent: %v", kind, requestClOrdId, clOrdID, err)
+		a.Tracker.MarkUnknown(clOrdID)
+		a.RequestStatus(clOrdID)
+		return
+	}
+	a.Tracker.OnCancelReject(requestClOrdId, "")
+}
+
+// mirrorCancel cancels the shadow of the order clOrdID in a dual run
+func (a *FixApplication) mirrorCancel(clOrdID string) {
+	if a.Shadow != nil {
+		a.Shadow.Canceled(clOrdID)
+	}
+}
+
+// CancelAlgo cancels the TWAP or VWAP strategy of the algo parent clOrdID,
+// returning a channel that receives the parent with its partial fill
+// statistics once Prime reports it canceled (or filled, if that came first)
+func (a *FixApplication) CancelAlgo(clOrdID string) (<-chan AlgoOrder, error) {
+	if a.Algos == nil {
+		return nil, errors.New("algo tracker is not configured")
+	}
+	done, ok := a.Algos.Wait(clOrdID)
+	if !ok {
+		return nil, errors.New("no algo parent order " + clOrdID)
+	}
+	if err := a.Algos.RequestCancel(clOrdID, a.now()); err != nil {
+		return nil, err
+	}
+	if err := a.CancelOrder(clOrdID); err != nil {
+		a.Algos.OnCancelReject(clOrdID)
+		return nil, err
+	}
+	return done, nil
+}
+
+// ReplaceOrder sends an OrderCancelReplaceRequest changing the quantity and
+// limit price of the tracked order clOrdID, marking it PendingReplace until the
+// venue responds. The new quantity and price go through the same pre-trade
+// checks and risk limits as a new order.
+func (a *FixApplication) ReplaceOrder(clOrdID, quantity, limitPrice string) error {
+	if a.Tracker == nil {
+		return ErrNoTracker
+	}
+	order, ok := a.Tracker.Get(clOrdID)
+	if !ok {
+		return errors.New("unknown order " + clOrdID)
+	}
+
+	// The order passed the fat-finger check when placed, so it only applies
+	// again when the replace raises its notional
+	req := OrderRequest{
+		Symbol:     order.Symbol,
+		OrdType:    order.OrdType,
+		Side:       order.Side,
+		Quantity:   quantity,
+		LimitPrice: limitPrice,
+		StopPrice:  order.StopPrice,
+		Metadata:   order.Metadata,
+	}
+	req.Force = !orderNotional(req).GreaterThan(orderNotional(OrderRequest{Quantity: order.Quantity, LimitPrice: order.LimitPrice}))
+	if err := a.validateReplace(order, req); err != nil {
+		return err
+	}
+	var reservation RiskReservation
+	if a.Risk != nil {
+		var err error
+		if reservation, err = a.Risk.ReserveReplace(order, req, a.now()); err != nil {
+			return err
+		}
+	}
+
+	now := a.now()
+	replaceClOrdId := strconv.FormatInt(nextClOrdID(now), 10)
+	if err := a.Tracker.MarkPendingReplace(order.ClOrdID, replaceClOrdId, quantity, now); err != nil {
+		if a.Risk != nil {
+			a.Risk.Release(reservation, false)
+		}
+		return err
+	}
+
+	order.Symbol = a.Symbols.ToPrime(order.Symbol)
+	err := a.Send(createReplaceMessage(order, replaceClOrdId, quantity, limitPrice))
+	if a.Risk != nil {
+		a.Risk.Release(reservation, err == nil || errors.Is(err, ErrAmbiguousSend))
+	}
+	if err != nil {
+		a.requestSendFailed("replace", clOrdID, replaceClOrdId, err)
+		return err
+	}
+	return nil
+}
+
+// createCancelMessage builds an OrderCancelRequest (35=F) for order
+func createCancelMessage(order Order, clOrdId string) *quickfix.Message {
+	side, _ := SideCode(order.Side)
+	cancel := quickfix.NewMessage()
+	cancel.Header.SetField(quickfix.Tag(35), quickfix.FIXString("F")) // MsgType = OrderCancelRequest
+
+	cancel.Body.SetString(quickfix.Tag(1), order.PortfolioId) // Account (Portfolio ID)
+	cancel.Body.SetString(quickfix.Tag(11), clOrdId)          // ClOrdID
+	cancel.Body.SetString(quickfix.Tag(41), order.ClOrdID)    // OrigClOrdID
+	if order.OrderID != "" {
+		cancel.Body.SetString(quickfix.Tag(37), order.OrderID) // OrderID
+	}
+	cancel.Body.SetString(quickfix.Tag(55), order.Symbol) // Symbol
+	cancel.Body.SetString(quickfix.Tag(54), side)
+	cancel.Body.SetString(quickfix.Tag(38), order.Quantity) // Order Quantity
+	return cancel
+}
+
+// createReplaceMessage builds an OrderCancelReplaceRequest (35=G) for order
+func createReplaceMessage(order Order, clOrdId, quantity, limitPrice string) *quickfix.Message {
+	side, _ := SideCode(order.Side)
+	replace := quickfix.NewMessage()
+	replace.Header.SetField(quickfix.Tag(35), quickfix.FIXString("G")) // MsgType = OrderCancelReplaceRequest
+
+	replace.Body.SetString(quickfix.Tag(1), order.PortfolioId) // Account (Portfolio ID)
+	replace.Body.SetString(quickfix.Tag(11), clOrdId)          // ClOrdID
+	replace.Body.SetString(quickfix.Tag(41), order.ClOrdID)    // OrigClOrdID
+	if order.OrderID != "" {
+		replace.Body.SetString(quickfix.Tag(37), order.OrderID) // OrderID
+	}
+	replace.Body.SetString(quickfix.Tag(55), order.Symbol) // Symbol
+	replace.Body.SetString(quickfix.Tag(54), side)
+	if order.OrdType == "STOP_LIMIT" {
+		replace.Body.SetField(quickfix.Tag(40), quickfix.FIXString("4")) // OrdType = Stop Limit
+		replace.Body.SetString(quickfix.Tag(99), order.StopPrice)        // StopPx
+	} else {
+		replace.Body.SetField(quickfix.Tag(40), quickfix.FIXString("2")) // OrdType = Limit
+	}
+	replace.Body.SetString(quickfix.Tag(38), quantity)   // Order Quantity
+	replace.Body.SetString(quickfix.Tag(44), limitPrice) // Price
+	return replace
+}
+
+// createStatusRequestMessage builds an OrderStatusRequest (35=H) for order
+func createStatusRequestMessage(order Order) *quickfix.Message {
+	side, _ := SideCode(order.Side)
+	status := quickfix.NewMessage()
+	status.Header.SetField(quickfix.Tag(35), quickfix.FIXString("H")) // MsgType = OrderStatusRequest
+
+	status.Body.SetString(quickfix.Tag(11), order.ClOrdID) // ClOrdID
+	if order.OrderID != "" {
+		status.Body.SetString(quickfix.Tag(37), order.OrderID) // OrderID
+	}
+	status.Body.SetString(quickfix.Tag(55), order.Symbol) // Symbol
+	status.Body.SetString(quickfix.Tag(54), side)
+	return status
+}
+
+// processCancelReject applies an OrderCancelReject (35=9) to the tracker
+func (a *FixApplication) processCancelReject(msg *quickfix.Message) {
+	clOrdID := bodyString(msg, quickfix.Tag(11))     // ClOrdID of the rejected request
+	origClOrdID := bodyString(msg, quickfix.Tag(41)) // OrigClOrdID of the order
+	ordStatus := bodyString(msg, quickfix.Tag(39))   // OrdStatus of the order
+	reason := bodyString(msg, quickfix.Tag(102))     // CxlRejReason
+	text := bodyString(msg, quickfix.Tag(58))        // Text
+
+	a.logger().Printf("Cancel Reject: ClOrdID=%s OrdStatus=%s Reason=%s Text=%s",
+		clOrdID, OrdStatusName(ordStatus), CxlRejReasonName(reason), text)
+
+	if a.Tracker != nil {
+		a.Tracker.OnCancelReject(clOrdID, ordStatus)
+	}
+	if a.Algos != nil {
+		a.Algos.OnCancelReject(origClOrdID)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestCancelAndReplaceOrderID(t *testing.T) {
+	build := map[string]func(order Order) *quickfix.Message{
+		"cancel":  func(order Order) *quickfix.Message { return createCancelMessage(order, "2") },
+		"replace": func(order Order) *quickfix.Message { return createReplaceMessage(order, "2", "1", "100") },
+	}
+	tests := []struct {
+		name    string
+		orderID string
+	}{
+		{"acknowledged", "order-1"},
+		{"not yet acknowledged", ""},
+	}
+	for kind, create := range build {
+		for _, tt := range tests {
+			msg := create(Order{ClOrdID: "1", OrderID: tt.orderID, Side: "BUY"})
+			if got := msg.Body.Has(quickfix.Tag(37)); got != (tt.orderID != "") {
+				t.Errorf("%s of %s order: has OrderID (37) = %t", kind, tt.name, got)
+			}
+			if got := bodyString(msg, quickfix.Tag(37)); got != tt.orderID {
+				t.Errorf("%s of %s order: OrderID (37) = %q, want %q", kind, tt.name, got, tt.orderID)
+			}
+		}
+	}
+}
+
+func TestReplaceKeepsStopLimit(t *testing.T) {
+	tests := []struct {
+		ordType, stopPrice string
+		wantType           string
+	}{
+		{"LIMIT", "", "2"},
+		{"STOP_LIMIT", "95", "4"},
+	}
+	for _, tt := range tests {
+		msg := createReplaceMessage(Order{ClOrdID: "1", OrdType: tt.ordType, StopPrice: tt.stopPrice, Side: "SELL"}, "2", "1", "94")
+		if got := bodyString(msg, quickfix.Tag(40)); got != tt.wantType {
+			t.Errorf("%s: OrdType (40) = %s, want %s", tt.ordType, got, tt.wantType)
+		}
+		if got := bodyString(msg, quickfix.Tag(99)); got != tt.stopPrice {
+			t.Errorf("%s: StopPx (99) = %q, want %q", tt.ordType, got, tt.stopPrice)
+		}
+	}
+}
+
+func TestCancelSendFailure(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantState   OrderState
+		wantPending OrderState
+	}{
+		{"may have been sent", fmt.Errorf("%w: disk full", ErrAmbiguousSend), OrderUnknownState, OrderPendingCancel},
+		{"never sent", ErrNotLoggedOn, OrderNew, ""},
+	}
+	for _, tt := range tests {
+		app := &FixApplication{Tracker: NewOrderTracker(time.Minute)}
+		app.Tracker.Add(Order{ClOrdID: "1", Symbol: "BTC-USD", Side: "BUY", State: OrderNew})
+		if err := app.Tracker.MarkPending("1", "2", OrderPendingCancel, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+
+		app.requestSendFailed("cancel", "1", "2", tt.err)
+		order, _ := app.Tracker.Get("1")
+		if order.State != tt.wantState || order.Pending != tt.wantPending {
+			t.Errorf("%s: state %s pending %q, want %s pending %q", tt.name, order.State, order.Pending, tt.wantState, tt.wantPending)
+		}
+	}
+
+	// CancelOrder drops a cancel that could not be sent at all
+	app := &FixApplication{Tracker: NewOrderTracker(time.Minute)}
+	app.Tracker.Add(Order{ClOrdID: "1", Symbol: "BTC-USD", Side: "BUY", State: OrderNew})
+	if err := app.CancelOrder("1"); !errors.Is(err, ErrNotLoggedOn) {
+		t.Fatalf("CancelOrder = %v, want ErrNotLoggedOn", err)
+	}
+	if order, _ := app.Tracker.Get("1"); order.State != OrderNew || order.Pending != "" {
+		t.Errorf("order %+v after an unsent cancel", order)
+	}
+}
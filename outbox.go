@@ -0,0 +1,298 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// EventSink delivers ExecutionReports to a downstream system such as a
+// webhook or a Kafka producer. The outbox retries a report until Deliver
+// succeeds, so sinks should pass the ExecID on for downstream deduplication.
+type EventSink interface {
+	Deliver(report ExecutionReport) error
+}
+
+// WebhookSink posts each ExecutionReport encoded by Codec (JSON when nil),
+// with its ExecID as the Idempotency-Key header
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+	Codec  Codec
+}
+
+func (s *WebhookSink) Deliver(report ExecutionReport) error {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	codec := s.Codec
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	data, err := codec.Encode(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", codec.ContentType())
+	req.Header.Set("Idempotency-Key", report.ExecID)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("execution post to %s failed: %s", s.URL, resp.Status)
+	}
+	return nil
+}
+
+// outboxRecord is one line of the outbox file: a report to deliver or the
+// ExecID of a delivered one
+type outboxRecord struct {
+	Report *ExecutionReport `json:"report,omitempty"`
+	Acked  string           `json:"acked,omitempty"`
+}
+
+// Outbox records every ExecutionReport on disk before it is acknowledged to
+// the session and delivers it to a sink in the background, retrying with
+// backoff until the sink accepts it. Reports not yet delivered are picked up
+// again after a restart, and a report whose ExecID the outbox has seen before
+// is never recorded twice, so the sink sees every fill exactly once by ExecID.
+type Outbox struct {
+	mu      sync.Mutex
+	file    *os.File
+	sink    EventSink
+	pending []ExecutionReport
+	seen    map[string]struct{}
+	wake    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// outboxRetryBase and outboxRetryMax bound the delay between failed deliveries
+var (
+	outboxRetryBase = time.Second
+	outboxRetryMax  = time.Minute
+)
+
+// OpenOutbox opens or creates the outbox at path, compacts it and starts
+// delivering the reports left pending by a previous run to sink
+func OpenOutbox(path string, sink EventSink) (*Outbox, error) {
+	o := &Outbox{
+		sink: sink,
+		seen: make(map[string]struct{}),
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	var acked []string
+	if file, err := os.Open(path); err == nil {
+		pending := make(map[string]int)
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			var record outboxRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				// A torn last line from a crash mid-write is skipped
+				log.Println("Skipping unreadable outbox record:", err)
+				continue
+			}
+			switch {
+			case record.Report != nil:
+				pending[record.Report.ExecID] = len(o.pending)
+				o.pending = append(o.pending, *record.Report)
+			case record.Acked != "":
+				acked = append(acked, record.Acked)
+				if i, ok := pending[record.Acked]; ok {
+					o.pending[i].ExecID = ""
+				}
+			}
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// Rewrite the file with the acked ExecIDs and the still pending reports
+	var records []outboxRecord
+	for _, execId := range acked {
+		o.seen[execId] = struct{}{}
+		records = append(records, outboxRecord{Acked: execId})
+	}
+	pending := o.pending[:0]
+	for _, report := range o.pending {
+		if report.ExecID == "" {
+			continue
+		}
+		o.seen[report.ExecID] = struct{}{}
+		pending = append(pending, report)
+		records = append(records, outboxRecord{Report: &report})
+	}
+	o.pending = pending
+
+	tmp := path + ".tmp"
+	if err := writeOutbox(tmp, records); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	o.file = file
+
+	if len(o.pending) > 0 {
+		log.Printf("Outbox resuming delivery of %d pending executions", len(o.pending))
+	}
+	go o.run()
+	return o, nil
+}
+
+func writeOutbox(path string, records []outboxRecord) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Add durably records report for delivery. Reports without an ExecID or
+// whose ExecID was already recorded are ignored.
+func (o *Outbox) Add(report ExecutionReport) error {
+	if report.ExecID == "" {
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.seen[report.ExecID]; ok {
+		return nil
+	}
+	if err := o.write(outboxRecord{Report: &report}); err != nil {
+		return err
+	}
+	o.seen[report.ExecID] = struct{}{}
+	o.pending = append(o.pending, report)
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Pending returns the number of reports not yet delivered
+func (o *Outbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.pending)
+}
+
+// Close stops delivery and closes the file; pending reports are delivered
+// on the next open
+func (o *Outbox) Close() error {
+	close(o.stop)
+	<-o.done
+	return o.file.Close()
+}
+
+// write appends record to the file and syncs it; callers must hold mu
+func (o *Outbox) write(record outboxRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := o.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return o.file.Sync()
+}
+
+func (o *Outbox) run() {
+	defer close(o.done)
+
+	delay := outboxRetryBase
+	for {
+		o.mu.Lock()
+		if len(o.pending) == 0 {
+			o.mu.Unlock()
+			select {
+			case <-o.wake:
+				continue
+			case <-o.stop:
+				return
+			}
+		}
+		report := o.pending[0]
+		o.mu.Unlock()
+
+		if err := o.sink.Deliver(report); err != nil {
+			log.Printf("Outbox delivery of %s failed, retrying in %s: %v", report.ExecID, delay, err)
+			select {
+			case <-time.After(delay):
+			case <-o.stop:
+				return
+			}
+			delay = min(delay*2, outboxRetryMax)
+			continue
+		}
+		delay = outboxRetryBase
+
+		o.mu.Lock()
+		o.pending = o.pending[1:]
+		if err := o.write(outboxRecord{Acked: report.ExecID}); err != nil {
+			// Without the ack the report is delivered again after a restart
+			log.Println("Failed to record outbox delivery:", err)
+		}
+		o.mu.Unlock()
+	}
+}
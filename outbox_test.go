@@ -0,0 +1,91 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu        sync.Mutex
+	delivered []string
+	fail      bool
+}
+
+func (s *recordingSink) Deliver(report ExecutionReport) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("sink down")
+	}
+	s.delivered = append(s.delivered, report.ExecID)
+	return nil
+}
+
+func (s *recordingSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.delivered)
+}
+
+func TestOutboxRedeliversAfterRestartExactlyOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	outboxRetryBase = time.Millisecond
+	defer func() { outboxRetryBase = time.Second }()
+
+	// The sink is down, so both reports stay pending when the outbox closes
+	down := &recordingSink{fail: true}
+	o, err := OpenOutbox(path, down)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Add(ExecutionReport{ExecID: "1"})
+	o.Add(ExecutionReport{ExecID: "2"})
+	o.Add(ExecutionReport{ExecID: "1"})
+	if o.Pending() != 2 {
+		t.Fatalf("expected 2 pending, got %d", o.Pending())
+	}
+	o.Close()
+
+	up := &recordingSink{}
+	o, err = OpenOutbox(path, up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); o.Pending() > 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	// A replayed execution is not delivered again, even across a restart
+	o.Add(ExecutionReport{ExecID: "2"})
+	o.Close()
+
+	o, err = OpenOutbox(path, up)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Add(ExecutionReport{ExecID: "1"})
+	if o.Pending() != 0 {
+		t.Fatalf("expected nothing pending, got %d", o.Pending())
+	}
+	o.Close()
+
+	if up.count() != 2 || up.delivered[0] != "1" || up.delivered[1] != "2" {
+		t.Fatalf("expected [1 2] delivered once each, got %v", up.delivered)
+	}
+}
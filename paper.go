@@ -0,0 +1,448 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+// LatencyDistribution draws simulated venue latencies. Kind is "fixed" (A),
+// "uniform" (between A and B), "normal" (mean A, standard deviation B) or
+// "lognormal" (median A, 99th percentile B), the last giving the long tail
+// real acknowledgements have.
+type LatencyDistribution struct {
+	Kind string
+	A, B time.Duration
+}
+
+// ParseLatencyDistribution parses kind:a[:b], e.g. "fixed:5ms",
+// "uniform:5ms:50ms" or "lognormal:20ms:250ms"
+func ParseLatencyDistribution(value string) (LatencyDistribution, error) {
+	parts := strings.Split(value, ":")
+	d := LatencyDistribution{Kind: parts[0]}
+	want := 3
+	if d.Kind == "fixed" {
+		want = 2
+	}
+	if len(parts) != want {
+		return d, fmt.Errorf("invalid latency distribution %q", value)
+	}
+	var err error
+	if d.A, err = time.ParseDuration(parts[1]); err != nil {
+		return d, err
+	}
+	if want == 3 {
+		if d.B, err = time.ParseDuration(parts[2]); err != nil {
+			return d, err
+		}
+	}
+	switch d.Kind {
+	case "fixed", "normal":
+	case "uniform", "lognormal":
+		if d.B < d.A {
+			return d, fmt.Errorf("invalid latency distribution %q: %s is below %s", value, d.B, d.A)
+		}
+	default:
+		return d, fmt.Errorf("unknown latency distribution %q", d.Kind)
+	}
+	return d, nil
+}
+
+// Sample draws a latency, never negative
+func (d LatencyDistribution) Sample(rng *rand.Rand) time.Duration {
+	var sample float64
+	switch d.Kind {
+	case "uniform":
+		sample = float64(d.A) + rng.Float64()*float64(d.B-d.A)
+	case "normal":
+		sample = float64(d.A) + rng.NormFloat64()*float64(d.B)
+	case "lognormal":
+		if d.A <= 0 {
+			return 0
+		}
+		sigma := math.Log(float64(d.B)/float64(d.A)) / 2.326 // z of the 99th percentile
+		sample = float64(d.A) * math.Exp(rng.NormFloat64()*sigma)
+	default:
+		sample = float64(d.A)
+	}
+	return time.Duration(max(sample, 0))
+}
+
+// SlippageModel prices simulated market orders off the far touch: buys pay
+// the ask and sells get the bid, moved against the order by FixedBps, by
+// ImpactBps for every Depth of quantity, and by half-normal noise of NoiseBps
+type SlippageModel struct {
+	FixedBps  float64
+	ImpactBps float64
+	Depth     decimal.Decimal
+	NoiseBps  float64
+}
+
+// Price returns the fill price of a market order for qty
+func (m SlippageModel) Price(buy bool, bid, ask, qty decimal.Decimal, rng *rand.Rand) decimal.Decimal {
+	bps := m.FixedBps + math.Abs(rng.NormFloat64())*m.NoiseBps
+	if m.Depth.IsPositive() {
+		bps += m.ImpactBps * qty.Div(m.Depth).InexactFloat64()
+	}
+	move := decimal.NewFromFloat(bps / 10000)
+	if buy {
+		return ask.Mul(decimal.NewFromInt(1).Add(move)).Round(8)
+	}
+	return bid.Mul(decimal.NewFromInt(1).Sub(move)).Round(8)
+}
+
+// PaperVenue simulates Prime for paper trading: orders sent in paper mode
+// never leave the process and are acknowledged and filled against the quotes
+// fed to OnQuote after simulated latencies, with the ExecutionReports handed
+// to Deliver as if they came from the session. Market orders fill at once
+// through the slippage model; limit orders fill at their price or better
+// once the quotes cross them, and stop limits once their stop is touched.
+type PaperVenue struct {
+	Clock       Clock
+	AckLatency  LatencyDistribution
+	FillLatency LatencyDistribution
+	Slippage    SlippageModel
+
+	// Deliver receives each simulated ExecutionReport (35=8) and
+	// OrderCancelReject (35=9)
+	Deliver func(msg *quickfix.Message)
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	quotes  map[string][2]decimal.Decimal // symbol -> bid, ask
+	orders  map[string]*paperOrder        // by current ClOrdID
+	nextId  int
+	pending int                 // reports scheduled but not generated
+	outbox  []*quickfix.Message // reports generated but not delivered
+}
+
+type paperOrder struct {
+	clOrdID, orderID, symbol, side, ordType string
+	qty, price, stopPx, cumQty, notional    decimal.Decimal
+	status                                  string // OrdStatus (39)
+	acked, triggered                        bool
+}
+
+// NewPaperVenue creates a paper venue on clock whose random draws are seeded
+// by seed, so a run can be repeated exactly
+func NewPaperVenue(clock Clock, seed int64) *PaperVenue {
+	return &PaperVenue{
+		Clock:  clockOrSystem(clock),
+		rng:    rand.New(rand.NewSource(seed)),
+		quotes: make(map[string][2]decimal.Decimal),
+		orders: make(map[string]*paperOrder),
+	}
+}
+
+// OnQuote sets the top of book of symbol and fills the resting orders it crosses
+func (v *PaperVenue) OnQuote(symbol string, bid, ask decimal.Decimal) {
+	defer v.flush()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.quotes[symbol] = [2]decimal.Decimal{bid, ask}
+	for _, order := range v.orders {
+		if order.symbol == symbol && order.acked {
+			v.tryFill(order)
+		}
+	}
+}
+
+// Submit accepts a NewOrderSingle, OrderCancelRequest or OrderCancelReplaceRequest
+func (v *PaperVenue) Submit(msg *quickfix.Message) error {
+	msgType, _ := msg.Header.GetString(quickfix.Tag(35))
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	switch msgType {
+	case "D":
+		return v.newOrder(msg)
+	case "F":
+		v.cancel(bodyString(msg, quickfix.Tag(11)), bodyString(msg, quickfix.Tag(41)))
+		return nil
+	case "G":
+		return v.replace(msg)
+	}
+	return fmt.Errorf("paper venue does not support MsgType %s", msgType)
+}
+
+// Pending returns the number of simulated reports not yet delivered
+func (v *PaperVenue) Pending() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.pending
+}
+
+func (v *PaperVenue) newOrder(msg *quickfix.Message) error {
+	v.nextId++
+	order := &paperOrder{
+		clOrdID: bodyString(msg, quickfix.Tag(11)),
+		orderID: "paper-" + strconv.Itoa(v.nextId),
+		symbol:  bodyString(msg, quickfix.Tag(55)),
+		side:    bodyString(msg, quickfix.Tag(54)),
+		ordType: bodyString(msg, quickfix.Tag(40)),
+		status:  "0",
+	}
+	var err error
+	if order.qty, err = decimal.NewFromString(bodyString(msg, quickfix.Tag(38))); err != nil {
+		return fmt.Errorf("paper venue: invalid OrderQty: %w", err)
+	}
+	if order.ordType != "1" {
+		if order.price, err = decimal.NewFromString(bodyString(msg, quickfix.Tag(44))); err != nil {
+			return fmt.Errorf("paper venue: invalid Price: %w", err)
+		}
+	}
+	if order.ordType == "4" {
+		if order.stopPx, err = decimal.NewFromString(bodyString(msg, quickfix.Tag(99))); err != nil {
+			return fmt.Errorf("paper venue: invalid StopPx: %w", err)
+		}
+	}
+	v.orders[order.clOrdID] = order
+
+	v.after(v.AckLatency, func() {
+		order.acked = true
+		v.deliver(v.report(order, "0", "", decimal.Zero, decimal.Zero))
+		v.tryFill(order)
+	})
+	return nil
+}
+
+func (v *PaperVenue) cancel(clOrdID, origClOrdID string) {
+	v.after(v.AckLatency, func() {
+		order, ok := v.orders[origClOrdID]
+		if !ok || !order.open() {
+			v.deliver(cancelReject(clOrdID, origClOrdID, "Unknown order"))
+			return
+		}
+		delete(v.orders, origClOrdID)
+		order.status = "4"
+		report := v.report(order, "4", "", decimal.Zero, decimal.Zero)
+		report.Body.SetString(quickfix.Tag(11), clOrdID)
+		report.Body.SetString(quickfix.Tag(41), origClOrdID)
+		v.deliver(report)
+	})
+}
+
+func (v *PaperVenue) replace(msg *quickfix.Message) error {
+	clOrdID, origClOrdID := bodyString(msg, quickfix.Tag(11)), bodyString(msg, quickfix.Tag(41))
+	qty, err := decimal.NewFromString(bodyString(msg, quickfix.Tag(38)))
+	if err != nil {
+		return fmt.Errorf("paper venue: invalid OrderQty: %w", err)
+	}
+	price, err := decimal.NewFromString(bodyString(msg, quickfix.Tag(44)))
+	if err != nil {
+		return fmt.Errorf("paper venue: invalid Price: %w", err)
+	}
+
+	v.after(v.AckLatency, func() {
+		order, ok := v.orders[origClOrdID]
+		if !ok || !order.open() || qty.LessThanOrEqual(order.cumQty) {
+			v.deliver(cancelReject(clOrdID, origClOrdID, "Cannot replace order"))
+			return
+		}
+		delete(v.orders, origClOrdID)
+		order.clOrdID, order.qty, order.price = clOrdID, qty, price
+		v.orders[clOrdID] = order
+		report := v.report(order, "5", "", decimal.Zero, decimal.Zero)
+		report.Body.SetString(quickfix.Tag(41), origClOrdID)
+		v.deliver(report)
+		v.tryFill(order)
+	})
+	return nil
+}
+
+func (o *paperOrder) open() bool {
+	return o.status == "0" || o.status == "1"
+}
+
+// tryFill fills order against the current quote when it is marketable,
+// after the fill latency; callers must hold mu
+func (v *PaperVenue) tryFill(order *paperOrder) {
+	quote, ok := v.quotes[order.symbol]
+	if !ok || !order.open() {
+		if ok || order.ordType != "1" {
+			return
+		}
+		// A market order with no market to fill against is rejected
+		delete(v.orders, order.clOrdID)
+		order.status = "8"
+		v.deliver(v.report(order, "8", "No market data for "+order.symbol, decimal.Zero, decimal.Zero))
+		return
+	}
+	bid, ask := quote[0], quote[1]
+	buy := order.side == "1"
+
+	var price decimal.Decimal
+	switch order.ordType {
+	case "1":
+		price = v.Slippage.Price(buy, bid, ask, order.qty.Sub(order.cumQty), v.rng)
+	case "4":
+		if !order.triggered {
+			order.triggered = buy && ask.GreaterThanOrEqual(order.stopPx) || !buy && bid.LessThanOrEqual(order.stopPx)
+		}
+		if !order.triggered {
+			return
+		}
+		fallthrough
+	default:
+		switch {
+		case buy && ask.LessThanOrEqual(order.price):
+			price = ask
+		case !buy && bid.GreaterThanOrEqual(order.price):
+			price = bid
+		default:
+			return
+		}
+	}
+
+	// Fill in full unless the order changes in the meantime
+	clOrdID, qty := order.clOrdID, order.qty
+	v.after(v.FillLatency, func() {
+		if order.clOrdID != clOrdID || !order.qty.Equal(qty) || !order.open() {
+			return
+		}
+		lastQty := order.qty.Sub(order.cumQty)
+		order.cumQty = order.qty
+		order.notional = order.notional.Add(lastQty.Mul(price))
+		order.status = "2"
+		delete(v.orders, order.clOrdID)
+		v.deliver(v.report(order, "F", "", lastQty, price))
+	})
+}
+
+// after runs f under mu once the latency drawn from d has passed
+func (v *PaperVenue) after(d LatencyDistribution, f func()) {
+	v.pending++
+	v.Clock.AfterFunc(d.Sample(v.rng), func() {
+		defer v.flush()
+		v.mu.Lock()
+		defer v.mu.Unlock()
+		v.pending--
+		f()
+	})
+}
+
+// deliver queues msg for Deliver; callers must hold mu
+func (v *PaperVenue) deliver(msg *quickfix.Message) {
+	v.outbox = append(v.outbox, msg)
+}
+
+// flush hands queued reports to Deliver outside mu, so handlers can send
+// follow-up orders
+func (v *PaperVenue) flush() {
+	v.mu.Lock()
+	outbox := v.outbox
+	v.outbox = nil
+	v.mu.Unlock()
+
+	if v.Deliver == nil {
+		return
+	}
+	for _, msg := range outbox {
+		v.Deliver(msg)
+	}
+}
+
+// report builds an ExecutionReport for order, with a fill of lastQty at lastPx
+func (v *PaperVenue) report(order *paperOrder, execType, text string, lastQty, lastPx decimal.Decimal) *quickfix.Message {
+	v.nextId++
+	now := v.Clock.Now()
+
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(35), "8")
+	msg.Header.SetString(quickfix.Tag(49), "COIN")
+	msg.Header.SetString(quickfix.Tag(52), now.UTC().Format(fixTimestampFormat))
+	msg.Body.SetString(quickfix.Tag(17), "paper-exec-"+strconv.Itoa(v.nextId))
+	msg.Body.SetString(quickfix.Tag(150), execType)
+	msg.Body.SetString(quickfix.Tag(39), order.status)
+	msg.Body.SetString(quickfix.Tag(37), order.orderID)
+	msg.Body.SetString(quickfix.Tag(11), order.clOrdID)
+	msg.Body.SetString(quickfix.Tag(55), order.symbol)
+	msg.Body.SetString(quickfix.Tag(54), order.side)
+	msg.Body.SetString(quickfix.Tag(38), order.qty.String())
+	if order.ordType != "1" {
+		msg.Body.SetString(quickfix.Tag(44), order.price.String())
+	}
+	msg.Body.SetString(quickfix.Tag(32), lastQty.String())
+	msg.Body.SetString(quickfix.Tag(31), lastPx.String())
+	msg.Body.SetString(quickfix.Tag(14), order.cumQty.String())
+	avgPx := decimal.Zero
+	if order.cumQty.IsPositive() {
+		avgPx = order.notional.Div(order.cumQty).Round(8)
+	}
+	msg.Body.SetString(quickfix.Tag(6), avgPx.String())
+	leaves := decimal.Zero
+	if order.open() {
+		leaves = order.qty.Sub(order.cumQty)
+	}
+	msg.Body.SetString(quickfix.Tag(151), leaves.String())
+	msg.Body.SetString(quickfix.Tag(60), now.UTC().Format(fixTimestampFormat))
+	if text != "" {
+		msg.Body.SetString(quickfix.Tag(58), text)
+	}
+	msg.ReceiveTime = now
+	return msg
+}
+
+// cancelReject builds an OrderCancelReject (35=9)
+func cancelReject(clOrdID, origClOrdID, text string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(35), "9")
+	msg.Header.SetString(quickfix.Tag(49), "COIN")
+	msg.Body.SetString(quickfix.Tag(11), clOrdID)
+	msg.Body.SetString(quickfix.Tag(41), origClOrdID)
+	msg.Body.SetString(quickfix.Tag(39), "8")
+	msg.Body.SetString(quickfix.Tag(58), text)
+	return msg
+}
+
+// paperVenueSetting builds the paper venue from the Paper* settings
+func paperVenueSetting(settings *quickfix.SessionSettings, clock Clock) (*PaperVenue, error) {
+	seed := time.Now().UnixNano()
+	if settings.HasSetting("PaperSeed") {
+		value, err := settings.IntSetting("PaperSeed")
+		if err != nil {
+			return nil, err
+		}
+		seed = int64(value)
+	}
+	venue := NewPaperVenue(clock, seed)
+	venue.AckLatency = LatencyDistribution{Kind: "fixed"}
+	venue.FillLatency = LatencyDistribution{Kind: "fixed"}
+	for setting, latency := range map[string]*LatencyDistribution{"PaperAckLatency": &venue.AckLatency, "PaperFillLatency": &venue.FillLatency} {
+		if value, err := settings.Setting(setting); err == nil {
+			if *latency, err = ParseLatencyDistribution(value); err != nil {
+				return nil, fmt.Errorf("%s: %w", setting, err)
+			}
+		}
+	}
+	venue.Slippage = SlippageModel{
+		FixedBps:  decimalSetting(settings, "PaperSlippageBps").InexactFloat64(),
+		ImpactBps: decimalSetting(settings, "PaperImpactBps").InexactFloat64(),
+		Depth:     decimalSetting(settings, "PaperDepth"),
+		NoiseBps:  decimalSetting(settings, "PaperNoiseBps").InexactFloat64(),
+	}
+	return venue, nil
+}
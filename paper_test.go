@@ -0,0 +1,142 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+func paperOrderMessage(msgType, clOrdID, side, ordType, qty, price string) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.SetString(quickfix.Tag(35), msgType)
+	msg.Body.SetString(quickfix.Tag(11), clOrdID)
+	msg.Body.SetString(quickfix.Tag(55), "BTC-USD")
+	msg.Body.SetString(quickfix.Tag(54), side)
+	msg.Body.SetString(quickfix.Tag(40), ordType)
+	msg.Body.SetString(quickfix.Tag(38), qty)
+	if price != "" {
+		msg.Body.SetString(quickfix.Tag(44), price)
+	}
+	return msg
+}
+
+func TestPaperVenueMarketOrderSlippage(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC))
+	venue := NewPaperVenue(clock, 1)
+	venue.AckLatency = LatencyDistribution{Kind: "fixed", A: 10 * time.Millisecond}
+	venue.FillLatency = LatencyDistribution{Kind: "fixed", A: 5 * time.Millisecond}
+	venue.Slippage = SlippageModel{FixedBps: 2, ImpactBps: 10, Depth: decimal.NewFromInt(10)}
+	var reports []*quickfix.Message
+	venue.Deliver = func(msg *quickfix.Message) { reports = append(reports, msg) }
+
+	venue.OnQuote("BTC-USD", decimal.NewFromInt(49990), decimal.NewFromInt(50000))
+	if err := venue.Submit(paperOrderMessage("D", "c1", "1", "1", "5", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(9 * time.Millisecond)
+	if len(reports) != 0 {
+		t.Fatalf("reported before the ack latency: %d reports", len(reports))
+	}
+	clock.Advance(6 * time.Millisecond)
+	if len(reports) != 2 {
+		t.Fatalf("expected ack and fill, got %d reports", len(reports))
+	}
+	if execType := bodyString(reports[0], quickfix.Tag(150)); execType != "0" {
+		t.Fatalf("first report ExecType %s, want 0", execType)
+	}
+
+	// 2bps fixed plus 10bps per 10 of depth for 5: 7bps over the ask
+	fill := reports[1]
+	if status := bodyString(fill, quickfix.Tag(39)); status != "2" {
+		t.Fatalf("fill OrdStatus %s, want 2", status)
+	}
+	if lastPx := bodyString(fill, quickfix.Tag(31)); lastPx != "50035" {
+		t.Fatalf("LastPx %s, want 50035", lastPx)
+	}
+	if venue.Pending() != 0 {
+		t.Fatalf("%d reports still pending", venue.Pending())
+	}
+}
+
+func TestPaperVenueLimitOrderRestsUntilCrossed(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC))
+	venue := NewPaperVenue(clock, 1)
+	var reports []*quickfix.Message
+	venue.Deliver = func(msg *quickfix.Message) { reports = append(reports, msg) }
+
+	venue.OnQuote("BTC-USD", decimal.NewFromInt(49990), decimal.NewFromInt(50000))
+	if err := venue.Submit(paperOrderMessage("D", "c1", "2", "2", "1", "50010")); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Millisecond)
+	if len(reports) != 1 {
+		t.Fatalf("expected only the ack, got %d reports", len(reports))
+	}
+
+	venue.OnQuote("BTC-USD", decimal.NewFromInt(50020), decimal.NewFromInt(50030))
+	clock.Advance(time.Millisecond)
+	if len(reports) != 2 || bodyString(reports[1], quickfix.Tag(31)) != "50020" {
+		t.Fatalf("expected a fill at the bid, got %d reports", len(reports))
+	}
+
+	cancel := quickfix.NewMessage()
+	cancel.Header.SetString(quickfix.Tag(35), "F")
+	cancel.Body.SetString(quickfix.Tag(11), "c2")
+	cancel.Body.SetString(quickfix.Tag(41), "c1")
+	if err := venue.Submit(cancel); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Millisecond)
+	if msgType, _ := reports[2].Header.GetString(quickfix.Tag(35)); msgType != "9" {
+		t.Fatalf("cancel of a filled order got MsgType %s, want 9", msgType)
+	}
+}
+
+func TestLatencyDistribution(t *testing.T) {
+	for _, value := range []string{"fixed", "fixed:5ms:6ms", "uniform:5ms", "uniform:9ms:5ms", "gamma:1ms:2ms", "normal:x:1ms"} {
+		if _, err := ParseLatencyDistribution(value); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	uniform, err := ParseLatencyDistribution("uniform:5ms:50ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lognormal, err := ParseLatencyDistribution("lognormal:20ms:250ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var above int
+	for i := 0; i < 10000; i++ {
+		if d := uniform.Sample(rng); d < 5*time.Millisecond || d > 50*time.Millisecond {
+			t.Fatalf("uniform sample %s out of range", d)
+		}
+		if lognormal.Sample(rng) > 250*time.Millisecond {
+			above++
+		}
+	}
+	// About 1% should land above the 99th percentile
+	if above < 50 || above > 200 {
+		t.Fatalf("%d of 10000 lognormal samples above p99", above)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PegConfig describes how a resting limit order follows the mid price
+type PegConfig struct {
+	// Offset is added to the mid to get the limit price, e.g. negative to rest below mid
+	Offset decimal.Decimal
+	// Threshold is how far the mid must move from the last reprice before repricing again
+	Threshold decimal.Decimal
+	// Tick, when positive, rounds the limit price to the instrument's price increment
+	Tick decimal.Decimal
+	// MinInterval rate limits reprices of the same order
+	MinInterval time.Duration
+}
+
+type peggedOrder struct {
+	clOrdID     string
+	symbol      string
+	config      PegConfig
+	lastMid     decimal.Decimal
+	lastReprice time.Time
+}
+
+// Repricer cancel/replaces pegged resting limit orders when the mid price
+// moves beyond their threshold. Prices are pushed in through OnQuote from
+// whatever market data source the application uses.
+type Repricer struct {
+	mu   sync.Mutex
+	pegs map[string]*peggedOrder
+
+	tracker *OrderTracker
+	replace func(clOrdID, quantity, limitPrice string) error
+}
+
+// NewRepricer creates a repricer reading order state from tracker and
+// repricing through replace
+func NewRepricer(tracker *OrderTracker, replace func(clOrdID, quantity, limitPrice string) error) *Repricer {
+	return &Repricer{
+		pegs:    make(map[string]*peggedOrder),
+		tracker: tracker,
+		replace: replace,
+	}
+}
+
+// Peg starts repricing the tracked limit order clOrdID according to config
+func (r *Repricer) Peg(clOrdID string, config PegConfig) {
+	order, ok := r.tracker.Get(clOrdID)
+	if !ok {
+		log.Println("Cannot peg untracked order:", clOrdID)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pegs[clOrdID] = &peggedOrder{clOrdID: clOrdID, symbol: order.Symbol, config: config}
+}
+
+// Unpeg stops repricing clOrdID
+func (r *Repricer) Unpeg(clOrdID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pegs, clOrdID)
+}
+
+// OnQuote reprices the orders pegged on symbol for a new top of book
+func (r *Repricer) OnQuote(symbol string, bid, ask decimal.Decimal, now time.Time) {
+	mid := bid.Add(ask).Div(decimal.NewFromInt(2))
+
+	type reprice struct {
+		clOrdID, quantity, price string
+	}
+	var reprices []reprice
+
+	r.mu.Lock()
+	for clOrdID, peg := range r.pegs {
+		if peg.symbol != symbol {
+			continue
+		}
+
+		order, ok := r.tracker.Get(clOrdID)
+		if !ok || order.State.Terminal() {
+			delete(r.pegs, clOrdID)
+			continue
+		}
+		if order.Pending != "" || order.State == OrderPendingNew {
+			continue
+		}
+		if !peg.lastMid.IsZero() && mid.Sub(peg.lastMid).Abs().LessThan(peg.config.Threshold) {
+			continue
+		}
+		if now.Sub(peg.lastReprice) < peg.config.MinInterval {
+			continue
+		}
+
+		price := mid.Add(peg.config.Offset)
+		if peg.config.Tick.IsPositive() {
+			price = price.Div(peg.config.Tick).Round(0).Mul(peg.config.Tick)
+		}
+		if price.String() == order.LimitPrice {
+			continue
+		}
+
+		peg.lastMid = mid
+		peg.lastReprice = now
+		reprices = append(reprices, reprice{clOrdID, order.Quantity, price.String()})
+	}
+	r.mu.Unlock()
+
+	for _, rp := range reprices {
+		if err := r.replace(rp.clOrdID, rp.quantity, rp.price); err != nil {
+			log.Printf("Failed to reprice pegged order %s: %v", rp.clOrdID, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// replaceRecord is a cancel/replace the Repricer sent
+type replaceRecord struct {
+	clOrdID, quantity, price string
+}
+
+func TestRepricerFollowsMid(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tracker := NewOrderTracker(time.Minute)
+	tracker.Add(Order{ClOrdID: "1", Symbol: "BTC-USD", Quantity: "2", LimitPrice: "90", State: OrderNew})
+
+	var replaces []replaceRecord
+	replace := func(clOrdID, quantity, price string) error {
+		replaces = append(replaces, replaceRecord{clOrdID, quantity, price})
+		// The venue accepts the replace straight away
+		newId := clOrdID + "r"
+		if err := tracker.MarkPending(clOrdID, newId, OrderPendingReplace, start); err != nil {
+			return err
+		}
+		tracker.OnExecutionReport(ExecutionReport{ClOrdID: newId, OrigClOrdID: clOrdID, ExecType: "5", OrdStatus: "0", Price: price})
+		return nil
+	}
+	repricer := NewRepricer(tracker, replace)
+	repricer.Peg("1", PegConfig{
+		Offset:      decimal.RequireFromString("-1"),
+		Threshold:   decimal.RequireFromString("2"),
+		Tick:        decimal.RequireFromString("0.5"),
+		MinInterval: time.Second,
+	})
+
+	quotes := []struct {
+		name     string
+		bid, ask string
+		after    time.Duration
+		want     string // price of the replace sent, empty for none
+	}{
+		{"first quote", "100", "101", 0, "99.5"},
+		{"within the threshold", "101", "102", 2 * time.Second, ""},
+		{"drift past the threshold", "103", "104", 4 * time.Second, "102.5"},
+		{"too soon after the last reprice", "110", "111", 4500 * time.Millisecond, ""},
+		{"drift once the interval passed", "110", "111", 6 * time.Second, "109.5"},
+		{"other symbol", "1", "2", 10 * time.Second, ""},
+	}
+	for _, q := range quotes {
+		sent := len(replaces)
+		symbol := "BTC-USD"
+		if q.name == "other symbol" {
+			symbol = "ETH-USD"
+		}
+		repricer.OnQuote(symbol, decimal.RequireFromString(q.bid), decimal.RequireFromString(q.ask), start.Add(q.after))
+
+		switch {
+		case q.want == "" && len(replaces) != sent:
+			t.Fatalf("%s: repriced to %+v", q.name, replaces[sent:])
+		case q.want != "" && (len(replaces) != sent+1 || replaces[sent].price != q.want || replaces[sent].quantity != "2"):
+			t.Fatalf("%s: replaces %+v, want one to %s", q.name, replaces[sent:], q.want)
+		}
+	}
+
+	// Each replace went to the order's ClOrdID of the time, resolved by the tracker
+	if order, _ := tracker.Get("1"); order.LimitPrice != "109.5" || len(order.Lineage) != 3 {
+		t.Fatalf("order %+v", order)
+	}
+}
+
+func TestRepricerSkipsBusyAndDoneOrders(t *testing.T) {
+	now := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tracker := NewOrderTracker(time.Minute)
+	tracker.Add(Order{ClOrdID: "pending", Symbol: "BTC-USD", Quantity: "1", State: OrderPendingNew})
+	tracker.Add(Order{ClOrdID: "canceling", Symbol: "BTC-USD", Quantity: "1", State: OrderNew})
+	tracker.Add(Order{ClOrdID: "filled", Symbol: "BTC-USD", Quantity: "1", State: OrderFilled})
+	if err := tracker.MarkPending("canceling", "canceling-x", OrderPendingCancel, now); err != nil {
+		t.Fatal(err)
+	}
+
+	var replaced []string
+	repricer := NewRepricer(tracker, func(clOrdID, _, _ string) error {
+		replaced = append(replaced, clOrdID)
+		return nil
+	})
+	for _, clOrdID := range []string{"pending", "canceling", "filled", "untracked"} {
+		repricer.Peg(clOrdID, PegConfig{})
+	}
+	repricer.OnQuote("BTC-USD", decimal.NewFromInt(100), decimal.NewFromInt(102), now)
+	if len(replaced) != 0 {
+		t.Fatalf("repriced %v", replaced)
+	}
+
+	// The filled order is unpegged; the new one reprices once acknowledged
+	tracker.OnExecutionReport(ExecutionReport{ClOrdID: "pending", ExecType: "0", OrdStatus: "0"})
+	repricer.OnQuote("BTC-USD", decimal.NewFromInt(100), decimal.NewFromInt(102), now)
+	if len(replaced) != 1 || replaced[0] != "pending" {
+		t.Fatalf("repriced %v, want only the acknowledged order", replaced)
+	}
+	repricer.mu.Lock()
+	_, pegged := repricer.pegs["filled"]
+	repricer.mu.Unlock()
+	if pegged {
+		t.Fatal("the filled order is still pegged")
+	}
+}
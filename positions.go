@@ -0,0 +1,111 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Position is the net filled quantity of a symbol, positive long and negative
+// short, with the last fill price used to value it
+type Position struct {
+	Symbol    string
+	NetQty    decimal.Decimal
+	LastPrice decimal.Decimal
+}
+
+// Exposure returns the position's signed value in the symbol's quote currency
+func (p Position) Exposure() decimal.Decimal {
+	return p.NetQty.Mul(p.LastPrice)
+}
+
+// PositionTracker maintains net positions from fills
+type PositionTracker struct {
+	mu        sync.Mutex
+	positions map[string]*Position
+}
+
+// NewPositionTracker creates an empty PositionTracker
+func NewPositionTracker() *PositionTracker {
+	return &PositionTracker{positions: make(map[string]*Position)}
+}
+
+// OnExecutionReport applies the fill carried by report, if any
+func (t *PositionTracker) OnExecutionReport(report ExecutionReport) {
+	switch report.ExecType {
+	case "1", "2", "F": // Partial fill, Fill, Trade
+	default:
+		return
+	}
+
+	qty, err := decimal.NewFromString(report.LastShares)
+	if err != nil {
+		return
+	}
+	px, err := decimal.NewFromString(report.LastPx)
+	if err != nil {
+		return
+	}
+	if report.Side == "2" { // Sell
+		qty = qty.Neg()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	position, ok := t.positions[report.Symbol]
+	if !ok {
+		position = &Position{Symbol: report.Symbol}
+		t.positions[report.Symbol] = position
+	}
+	position.NetQty = position.NetQty.Add(qty)
+	position.LastPrice = px
+}
+
+// Get returns the position in symbol
+func (t *PositionTracker) Get(symbol string) Position {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if position, ok := t.positions[symbol]; ok {
+		return *position
+	}
+	return Position{Symbol: symbol}
+}
+
+// Restore replaces the position in each symbol of positions
+func (t *PositionTracker) Restore(positions []Position) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, position := range positions {
+		position := position
+		t.positions[position.Symbol] = &position
+	}
+}
+
+// All returns every position held
+func (t *PositionTracker) All() []Position {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	positions := make([]Position, 0, len(t.positions))
+	for _, position := range t.positions {
+		positions = append(positions, *position)
+	}
+	return positions
+}
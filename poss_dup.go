@@ -0,0 +1,54 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ResendPolicy controls how inbound app messages flagged PossDupFlag (43) or
+// PossResend (97) are handled
+type ResendPolicy int
+
+const (
+	// ResendFlag handles the message and marks the event as a possible duplicate
+	ResendFlag ResendPolicy = iota
+	// ResendProcess handles the message exactly like an original
+	ResendProcess
+	// ResendSkip drops the message before it reaches any handler
+	ResendSkip
+)
+
+// ParseResendPolicy parses "flag", "process" or "skip"
+func ParseResendPolicy(value string) (ResendPolicy, error) {
+	switch value {
+	case "flag":
+		return ResendFlag, nil
+	case "process":
+		return ResendProcess, nil
+	case "skip":
+		return ResendSkip, nil
+	}
+	return ResendFlag, fmt.Errorf("unknown resend policy %q", value)
+}
+
+// resendFlags returns the PossDupFlag and PossResend header flags of msg
+func resendFlags(msg *quickfix.Message) (possDup, possResend bool) {
+	possDup, _ = msg.Header.GetBool(quickfix.Tag(43))    // PossDupFlag
+	possResend, _ = msg.Header.GetBool(quickfix.Tag(97)) // PossResend
+	return possDup, possResend
+}
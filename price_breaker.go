@@ -0,0 +1,148 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrPriceBreakerOpen is returned for orders in a symbol whose price breaker
+// is tripped
+var ErrPriceBreakerOpen = errors.New("price breaker is open, reset it before submitting")
+
+// PriceBreakerTrip records the fill that tripped a symbol's price breaker
+type PriceBreakerTrip struct {
+	Symbol    string
+	ExecID    string
+	Price     decimal.Decimal
+	Reference decimal.Decimal
+	// Deviation is how far Price is from Reference, as a fraction of it
+	Deviation decimal.Decimal
+	Time      time.Time
+}
+
+// PriceBreakers block orders in a symbol after a fill whose price deviates
+// from the symbol's reference price by more than MaxDeviation. The
+// reference is the current mid when Reference knows one and otherwise the
+// previous fill. A tripped symbol stays blocked until Reset, since a bad fill
+// points at bad reference data or a venue problem someone should look at.
+type PriceBreakers struct {
+	// MaxDeviation is a fraction, e.g. 0.05 for 5%
+	MaxDeviation decimal.Decimal
+
+	// Reference, when set, returns the reference price of a symbol
+	Reference func(symbol string) (decimal.Decimal, bool)
+
+	// OnTrip is called when a symbol's breaker opens
+	OnTrip func(trip PriceBreakerTrip)
+
+	mu       sync.Mutex
+	open     map[string]PriceBreakerTrip
+	lastFill map[string]decimal.Decimal
+}
+
+// NewPriceBreakers creates breakers tripping on fills more than maxDeviation
+// away from the reference price
+func NewPriceBreakers(maxDeviation decimal.Decimal) *PriceBreakers {
+	return &PriceBreakers{
+		MaxDeviation: maxDeviation,
+		open:         make(map[string]PriceBreakerTrip),
+		lastFill:     make(map[string]decimal.Decimal),
+	}
+}
+
+// Allow returns ErrPriceBreakerOpen while the breaker of symbol is tripped
+func (b *PriceBreakers) Allow(symbol string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, open := b.open[symbol]; open {
+		return fmt.Errorf("%w: %s", ErrPriceBreakerOpen, symbol)
+	}
+	return nil
+}
+
+// Open returns the trips of the symbols whose breaker is open
+func (b *PriceBreakers) Open() []PriceBreakerTrip {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var trips []PriceBreakerTrip
+	for _, trip := range b.open {
+		trips = append(trips, trip)
+	}
+	return trips
+}
+
+// OnExecutionReport checks the fill carried by report, if any, against the
+// reference price of its symbol at now
+func (b *PriceBreakers) OnExecutionReport(report ExecutionReport, now time.Time) {
+	switch report.ExecType {
+	case "1", "2", "F": // Partial fill, Fill, Trade
+	default:
+		return
+	}
+	px, err := decimal.NewFromString(report.LastPx)
+	if err != nil || !px.IsPositive() {
+		return
+	}
+
+	var reference decimal.Decimal
+	var ok bool
+	if b.Reference != nil {
+		reference, ok = b.Reference(report.Symbol)
+	}
+
+	b.mu.Lock()
+	if !ok {
+		reference, ok = b.lastFill[report.Symbol]
+	}
+	if _, open := b.open[report.Symbol]; open || !ok || !reference.IsPositive() {
+		b.lastFill[report.Symbol] = px
+		b.mu.Unlock()
+		return
+	}
+	deviation := px.Sub(reference).Abs().Div(reference)
+	if deviation.LessThanOrEqual(b.MaxDeviation) {
+		b.lastFill[report.Symbol] = px
+		b.mu.Unlock()
+		return
+	}
+	trip := PriceBreakerTrip{Symbol: report.Symbol, ExecID: report.ExecID, Price: px, Reference: reference, Deviation: deviation, Time: now}
+	b.open[report.Symbol] = trip
+	b.mu.Unlock()
+
+	log.Printf("Price breaker tripped for %s: fill %s at %s is %s%% from reference %s",
+		report.Symbol, report.ExecID, px, deviation.Mul(decimal.NewFromInt(100)).Round(2), reference)
+	if b.OnTrip != nil {
+		b.OnTrip(trip)
+	}
+}
+
+// Reset closes the breaker of symbol, reporting whether it was open. The fill
+// that tripped it does not become the reference for the next one.
+func (b *PriceBreakers) Reset(symbol string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, wasOpen := b.open[symbol]
+	delete(b.open, symbol)
+	delete(b.lastFill, symbol)
+	log.Println("Price breaker reset for", symbol)
+	return wasOpen
+}
@@ -0,0 +1,99 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPriceBreakerTripsOnOutlyingFill(t *testing.T) {
+	breakers := NewPriceBreakers(decimal.RequireFromString("0.05"))
+	mids := map[string]decimal.Decimal{"BTC-USD": decimal.NewFromInt(100)}
+	breakers.Reference = func(symbol string) (decimal.Decimal, bool) {
+		mid, ok := mids[symbol]
+		return mid, ok
+	}
+	var trips []PriceBreakerTrip
+	breakers.OnTrip = func(trip PriceBreakerTrip) { trips = append(trips, trip) }
+	fill := func(symbol, px string) ExecutionReport {
+		return ExecutionReport{ExecType: "F", ExecID: symbol + px, Symbol: symbol, LastShares: "1", LastPx: px}
+	}
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	// Against the mid, and for ETH-USD without one against the previous fill
+	breakers.OnExecutionReport(fill("BTC-USD", "104"), now)
+	breakers.OnExecutionReport(fill("ETH-USD", "10"), now)
+	breakers.OnExecutionReport(fill("ETH-USD", "10.4"), now)
+	if len(trips) != 0 {
+		t.Fatalf("tripped on fills within 5%%: %+v", trips)
+	}
+	breakers.OnExecutionReport(fill("BTC-USD", "94"), now)
+	breakers.OnExecutionReport(fill("ETH-USD", "12"), now)
+	if len(trips) != 2 || trips[0].Symbol != "BTC-USD" || !trips[0].Deviation.Equal(decimal.RequireFromString("0.06")) {
+		t.Fatalf("trips %+v, want BTC-USD at 6%% then ETH-USD", trips)
+	}
+
+	if err := breakers.Allow("BTC-USD"); !errors.Is(err, ErrPriceBreakerOpen) {
+		t.Fatalf("Allow(BTC-USD) = %v, want ErrPriceBreakerOpen", err)
+	}
+	if err := breakers.Allow("SOL-USD"); err != nil {
+		t.Fatalf("Allow(SOL-USD) = %v", err)
+	}
+	breakers.Reset("BTC-USD")
+	if err := breakers.Allow("BTC-USD"); err != nil {
+		t.Fatalf("Allow(BTC-USD) after Reset = %v", err)
+	}
+}
+
+func TestAdminPriceBreakerReset(t *testing.T) {
+	app := &FixApplication{PriceBreakers: NewPriceBreakers(decimal.RequireFromString("0.05"))}
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	for _, px := range []string{"100", "120"} {
+		app.PriceBreakers.OnExecutionReport(ExecutionReport{ExecType: "F", ExecID: px, Symbol: "BTC-USD", LastShares: "1", LastPx: px}, now)
+	}
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app}); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewAdminHandler(manager)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/price-breaker/reset", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("reset without symbol answered %d", rec.Code)
+	}
+
+	var wasOpen map[string]bool
+	for _, want := range []bool{true, false} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/price-breaker/reset?symbol=BTC-USD", nil))
+		if err := json.Unmarshal(rec.Body.Bytes(), &wasOpen); err != nil {
+			t.Fatal(err, rec.Body.String())
+		}
+		if wasOpen["desk"] != want {
+			t.Errorf("wasOpen = %v, want desk %t", wasOpen, want)
+		}
+	}
+	if err := app.PriceBreakers.Allow("BTC-USD"); err != nil {
+		t.Errorf("Allow after reset = %v", err)
+	}
+}
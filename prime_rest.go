@@ -0,0 +1,203 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"prime-fix-go/auth"
+)
+
+// defaultPrimeRESTURL is the production Prime REST API
+const defaultPrimeRESTURL = "https://api.prime.coinbase.com"
+
+// PrimeREST is a minimal client for the Prime REST API, signed with the same
+// credentials as the FIX session
+type PrimeREST struct {
+	BaseURL     string
+	AccessKey   string
+	Signer      auth.Signer
+	Passphrase  *auth.Secret
+	PortfolioId string
+	Client      *http.Client
+	Clock       Clock // signing time source, nil for the wall clock
+}
+
+// PrimeRESTError is a non-2xx response from the REST API
+type PrimeRESTError struct {
+	Status  int
+	Message string
+}
+
+func (e *PrimeRESTError) Error() string {
+	return fmt.Sprintf("prime rest: %d %s", e.Status, e.Message)
+}
+
+// Balance is the balance of one currency in the portfolio
+type Balance struct {
+	Symbol string          `json:"symbol"`
+	Amount decimal.Decimal `json:"amount"`
+	Holds  decimal.Decimal `json:"holds"`
+}
+
+// Available returns the amount not on hold
+func (b Balance) Available() decimal.Decimal {
+	return b.Amount.Sub(b.Holds)
+}
+
+// Balance returns the trading balance of currency, e.g. "USD"
+func (c *PrimeREST) Balance(ctx context.Context, currency string) (Balance, error) {
+	var resp struct {
+		Balances []Balance `json:"balances"`
+	}
+	path := "/v1/portfolios/" + url.PathEscape(c.PortfolioId) + "/balances"
+	query := url.Values{"symbols": {strings.ToLower(currency)}, "balance_type": {"TRADING_BALANCES"}}
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &resp); err != nil {
+		return Balance{}, err
+	}
+	for _, balance := range resp.Balances {
+		if strings.EqualFold(balance.Symbol, currency) {
+			return balance, nil
+		}
+	}
+	return Balance{Symbol: currency}, nil
+}
+
+// RESTOrder is an order as reported by the REST API
+type RESTOrder struct {
+	Id                 string          `json:"id"`
+	ClientOrderId      string          `json:"client_order_id"`
+	Status             string          `json:"status"` // OPEN, FILLED, CANCELLED, EXPIRED, FAILED or PENDING
+	FilledQuantity     decimal.Decimal `json:"filled_quantity"`
+	AverageFilledPrice decimal.Decimal `json:"average_filled_price"`
+}
+
+// State maps the REST status onto an OrderState
+func (o RESTOrder) State() (OrderState, bool) {
+	switch o.Status {
+	case "PENDING":
+		return OrderPendingNew, true
+	case "OPEN":
+		if o.FilledQuantity.IsPositive() {
+			return OrderPartiallyFilled, true
+		}
+		return OrderNew, true
+	case "FILLED":
+		return OrderFilled, true
+	case "CANCELLED":
+		return OrderCanceled, true
+	case "EXPIRED":
+		return OrderExpired, true
+	case "FAILED":
+		return OrderRejected, true
+	}
+	return "", false
+}
+
+// Order returns the order orderId
+func (c *PrimeREST) Order(ctx context.Context, orderId string) (RESTOrder, error) {
+	var resp struct {
+		Order RESTOrder `json:"order"`
+	}
+	path := "/v1/portfolios/" + url.PathEscape(c.PortfolioId) + "/orders/" + url.PathEscape(orderId)
+	err := c.do(ctx, http.MethodGet, path, nil, nil, &resp)
+	return resp.Order, err
+}
+
+// CancelOrder requests the cancel of the order orderId
+func (c *PrimeREST) CancelOrder(ctx context.Context, orderId string) error {
+	path := "/v1/portfolios/" + url.PathEscape(c.PortfolioId) + "/orders/" + url.PathEscape(orderId) + "/cancel"
+	return c.do(ctx, http.MethodPost, path, nil, struct{}{}, nil)
+}
+
+// do sends a signed request and decodes the JSON response into out
+func (c *PrimeREST) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	base := c.BaseURL
+	if base == "" {
+		base = defaultPrimeRESTURL
+	}
+	target := base + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	// The signature covers the path without the query string
+	timestamp := strconv.FormatInt(clockOrSystem(c.Clock).Now().Unix(), 10)
+	signature, err := c.Signer.Sign(ctx, []byte(timestamp+method+path+string(payload)))
+	if err != nil {
+		return fmt.Errorf("prime rest: signing request: %w", err)
+	}
+	passphrase, err := c.Passphrase.Reveal()
+	if err != nil {
+		return fmt.Errorf("prime rest: %w", err)
+	}
+	req.Header.Set("X-CB-ACCESS-KEY", c.AccessKey)
+	req.Header.Set("X-CB-ACCESS-PASSPHRASE", passphrase)
+	req.Header.Set("X-CB-ACCESS-SIGNATURE", base64.StdEncoding.EncodeToString(signature))
+	req.Header.Set("X-CB-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &failure) != nil || failure.Message == "" {
+			failure.Message = strings.TrimSpace(string(data))
+		}
+		return &PrimeRESTError{Status: resp.StatusCode, Message: failure.Message}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
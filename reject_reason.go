@@ -0,0 +1,139 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "strings"
+
+// RejectReason is the cause of an order reject, classified from its
+// OrdRejReason (103) and Prime's Text (58) so callers can branch on it
+type RejectReason string
+
+const (
+	// RejectUnclassified is a reject whose cause is not recognised; see its Text
+	RejectUnclassified RejectReason = "UNCLASSIFIED"
+	// RejectInsufficientFunds is a portfolio without the balance or credit for the order
+	RejectInsufficientFunds RejectReason = "INSUFFICIENT_FUNDS"
+	// RejectInvalidPriceIncrement is a price off the product's tick size
+	RejectInvalidPriceIncrement RejectReason = "INVALID_PRICE_INCREMENT"
+	// RejectInvalidQuantity is a size off the product's increment, or outside its minimum and maximum
+	RejectInvalidQuantity RejectReason = "INVALID_QUANTITY"
+	// RejectRiskBlock is an order exceeding a venue risk or trading limit
+	RejectRiskBlock RejectReason = "RISK_BLOCK"
+	// RejectDuplicateClOrdID is a ClOrdID already used by another order
+	RejectDuplicateClOrdID RejectReason = "DUPLICATE_CLORDID"
+	// RejectUnknownSymbol is a product the venue does not list
+	RejectUnknownSymbol RejectReason = "UNKNOWN_SYMBOL"
+	// RejectTradingHalted is a product or venue not accepting orders, e.g. halted or cancel-only
+	RejectTradingHalted RejectReason = "TRADING_HALTED"
+	// RejectPostOnlyWouldCross is a post-only order that would have taken liquidity
+	RejectPostOnlyWouldCross RejectReason = "POST_ONLY_WOULD_CROSS"
+	// RejectRateLimited is an order over the venue's request rate limit
+	RejectRateLimited RejectReason = "RATE_LIMITED"
+	// RejectTooLate is an order that arrived after its time in force or a stale order
+	RejectTooLate RejectReason = "TOO_LATE"
+	// RejectUnknownAccount is a portfolio the credentials may not trade
+	RejectUnknownAccount RejectReason = "UNKNOWN_ACCOUNT"
+	// RejectUnsupportedOrder is an order type, time in force or instruction the venue does not support
+	RejectUnsupportedOrder RejectReason = "UNSUPPORTED_ORDER"
+	// RejectVenueError is an internal error or timeout at the venue
+	RejectVenueError RejectReason = "VENUE_ERROR"
+)
+
+// rejectTextPatterns are lowercase fragments of Prime's reject Text, most
+// specific first, since Prime sends most rejects as OrdRejReason 99 (Other)
+var rejectTextPatterns = []struct {
+	fragment string
+	reason   RejectReason
+}{
+	{"insufficient", RejectInsufficientFunds},
+	{"not enough", RejectInsufficientFunds},
+	{"price increment", RejectInvalidPriceIncrement},
+	{"price_increment", RejectInvalidPriceIncrement},
+	{"tick size", RejectInvalidPriceIncrement},
+	{"size increment", RejectInvalidQuantity},
+	{"base_increment", RejectInvalidQuantity},
+	{"quantity increment", RejectInvalidQuantity},
+	{"min size", RejectInvalidQuantity},
+	{"max size", RejectInvalidQuantity},
+	{"minimum", RejectInvalidQuantity},
+	{"maximum", RejectInvalidQuantity},
+	{"invalid quantity", RejectInvalidQuantity},
+	{"invalid size", RejectInvalidQuantity},
+	{"duplicate", RejectDuplicateClOrdID},
+	{"post only", RejectPostOnlyWouldCross},
+	{"post-only", RejectPostOnlyWouldCross},
+	{"would cross", RejectPostOnlyWouldCross},
+	{"rate limit", RejectRateLimited},
+	{"too many requests", RejectRateLimited},
+	{"unknown product", RejectUnknownSymbol},
+	{"invalid product", RejectUnknownSymbol},
+	{"unknown symbol", RejectUnknownSymbol},
+	{"product not found", RejectUnknownSymbol},
+	{"halt", RejectTradingHalted},
+	{"cancel only", RejectTradingHalted},
+	{"cancel-only", RejectTradingHalted},
+	{"trading disabled", RejectTradingHalted},
+	{"market closed", RejectTradingHalted},
+	{"risk", RejectRiskBlock},
+	{"limit exceeded", RejectRiskBlock},
+	{"exceeds", RejectRiskBlock},
+	{"portfolio not found", RejectUnknownAccount},
+	{"unknown portfolio", RejectUnknownAccount},
+	{"not supported", RejectUnsupportedOrder},
+	{"unsupported", RejectUnsupportedOrder},
+	{"timeout", RejectVenueError},
+	{"timed out", RejectVenueError},
+	{"internal error", RejectVenueError},
+	{"unavailable", RejectVenueError},
+}
+
+// ordRejReasons classifies the standard OrdRejReason (103) codes
+var ordRejReasons = map[string]RejectReason{
+	"1":  RejectUnknownSymbol,         // Unknown symbol
+	"2":  RejectTradingHalted,         // Exchange closed
+	"3":  RejectRiskBlock,             // Order exceeds limit
+	"4":  RejectTooLate,               // Too late to enter
+	"6":  RejectDuplicateClOrdID,      // Duplicate order
+	"8":  RejectTooLate,               // Stale order
+	"11": RejectUnsupportedOrder,      // Unsupported order characteristic
+	"13": RejectInvalidQuantity,       // Incorrect quantity
+	"15": RejectUnknownAccount,        // Unknown account
+	"18": RejectInvalidPriceIncrement, // Invalid price increment
+}
+
+// ClassifyReject returns the RejectReason of a reject with the given
+// OrdRejReason (103) code and Text (58). The Text is matched first, being
+// more specific than the codes Prime uses.
+func ClassifyReject(ordRejReason, text string) RejectReason {
+	lower := strings.ToLower(text)
+	for _, pattern := range rejectTextPatterns {
+		if strings.Contains(lower, pattern.fragment) {
+			return pattern.reason
+		}
+	}
+	if reason, ok := ordRejReasons[ordRejReason]; ok {
+		return reason
+	}
+	return RejectUnclassified
+}
+
+// RejectReason classifies the cause of a rejected order, see ClassifyReject.
+// It is empty for reports that are not rejects.
+func (r ExecutionReport) RejectReason() RejectReason {
+	if r.ExecType != "8" && r.OrdStatus != "8" {
+		return ""
+	}
+	return ClassifyReject(r.OrdRejReason, r.Text)
+}
@@ -0,0 +1,114 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestClassifyReject(t *testing.T) {
+	for _, tc := range []struct {
+		code, text string
+		want       RejectReason
+	}{
+		{"99", "Insufficient funds", RejectInsufficientFunds},
+		{"99", "limit_price does not match price_increment", RejectInvalidPriceIncrement},
+		{"99", "Order size is below min size", RejectInvalidQuantity},
+		{"99", "Order exceeds maximum order size", RejectInvalidQuantity},
+		{"99", "Duplicate client order id", RejectDuplicateClOrdID},
+		{"99", "Post only order would cross", RejectPostOnlyWouldCross},
+		{"99", "Order rate limit exceeded", RejectRateLimited},
+		{"99", "Product is in cancel only mode", RejectTradingHalted},
+		{"99", "Order blocked by risk controls", RejectRiskBlock},
+		{"99", "Request timed out", RejectVenueError},
+		{"6", "", RejectDuplicateClOrdID},
+		{"18", "", RejectInvalidPriceIncrement},
+		{"1", "", RejectUnknownSymbol},
+		{"99", "something new", RejectUnclassified},
+		{"", "", RejectUnclassified},
+	} {
+		if got := ClassifyReject(tc.code, tc.text); got != tc.want {
+			t.Errorf("ClassifyReject(%q, %q) = %s, want %s", tc.code, tc.text, got, tc.want)
+		}
+	}
+
+	if reason := (ExecutionReport{ExecType: "0", Text: "insufficient"}).RejectReason(); reason != "" {
+		t.Errorf("acknowledgement classified as reject %s", reason)
+	}
+	if reason := (ExecutionReport{ExecType: "8", OrdRejReason: "3"}).RejectReason(); reason != RejectRiskBlock {
+		t.Errorf("reject classified as %s", reason)
+	}
+}
+
+func TestRejectRetrierResubmitsRetryableRejects(t *testing.T) {
+	retrier := NewRejectRetrier(2, time.Second)
+	req := OrderRequest{Symbol: "ETH-USD", Side: "BUY", OrdType: "LIMIT", Quantity: "1", LimitPrice: "100", Metadata: map[string]string{"strategy": "mm"}}
+	retrier.Add("ord-1", req)
+
+	if _, _, ok := retrier.OnExecutionReport(ExecutionReport{ClOrdID: "ord-1", ExecType: "0", OrdStatus: "0"}); ok {
+		t.Fatal("retried an acknowledged order")
+	}
+	retry, backoff, ok := retrier.OnExecutionReport(ExecutionReport{ClOrdID: "ord-1", ExecType: "8", OrdStatus: "8", Text: "Rate limit exceeded"})
+	if !ok || backoff != time.Second || retry.Metadata[RetryOfKey] != "ord-1" || retry.Metadata[RetryAttemptKey] != "1" || retry.Metadata["strategy"] != "mm" {
+		t.Fatalf("first retry = %+v after %s, %v", retry, backoff, ok)
+	}
+	if _, ok := req.Metadata[RetryOfKey]; ok {
+		t.Fatal("retry changed the metadata of the original request")
+	}
+
+	retrier.Add("ord-2", retry)
+	retry, backoff, ok = retrier.OnExecutionReport(ExecutionReport{ClOrdID: "ord-2", ExecType: "8", OrdStatus: "8", Text: "Internal error"})
+	if !ok || backoff != 2*time.Second || retry.Metadata[RetryOfKey] != "ord-1" || retry.Metadata[RetryAttemptKey] != "2" {
+		t.Fatalf("second retry = %+v after %s, %v", retry, backoff, ok)
+	}
+	retrier.Add("ord-3", retry)
+	if _, _, ok := retrier.OnExecutionReport(ExecutionReport{ClOrdID: "ord-3", ExecType: "8", OrdStatus: "8", Text: "Internal error"}); ok {
+		t.Fatal("retried past MaxAttempts")
+	}
+
+	retrier.Add("ord-4", req)
+	if _, _, ok := retrier.OnExecutionReport(ExecutionReport{ClOrdID: "ord-4", ExecType: "8", OrdStatus: "8", Text: "Insufficient funds"}); ok {
+		t.Fatal("retried a terminal reject")
+	}
+	retrier.Add("ord-5", req)
+	retrier.OnExecutionReport(ExecutionReport{ClOrdID: "ord-5", ExecType: "2", OrdStatus: "2"})
+	if len(retrier.orders) != 0 {
+		t.Errorf("retrier still holds %d orders", len(retrier.orders))
+	}
+}
+
+func TestRejectRetrierSkipsChildOrders(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC))
+	app := &FixApplication{Clock: clock, Tracker: NewOrderTracker(time.Minute), Paper: NewPaperVenue(clock, 1), Retrier: NewRejectRetrier(2, time.Second)}
+	app.Paper.Deliver = func(msg *quickfix.Message) {}
+	app.Icebergs = NewIcebergManager(app.placeChildOrder, app.CancelOrder)
+
+	if _, err := app.Icebergs.Submit(IcebergRequest{Symbol: "ETH-USD", Side: "BUY", Quantity: "10", LimitPrice: "100", DisplaySize: "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(app.Retrier.orders) != 0 {
+		t.Fatalf("retrier holds the iceberg's child: %v", app.Retrier.orders)
+	}
+	clOrdID, err := app.PlaceOrder(OrderRequest{Symbol: "ETH-USD", Side: "BUY", OrdType: "LIMIT", Quantity: "1", LimitPrice: "100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := app.Retrier.orders[clOrdID]; !ok || len(app.Retrier.orders) != 1 {
+		t.Errorf("retrier holds %v, want only %s", app.Retrier.orders, clOrdID)
+	}
+}
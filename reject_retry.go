@@ -0,0 +1,129 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Metadata keys a RejectRetrier sets on the orders it resubmits
+const (
+	// RetryOfKey is the ClOrdID of the first order of a retry chain
+	RetryOfKey = "retry_of"
+	// RetryAttemptKey counts the resubmissions, from 1
+	RetryAttemptKey = "retry_attempt"
+)
+
+// Retryable reports whether a reject was caused by a transient venue issue,
+// such that the same order may be accepted if resubmitted. Every other
+// reason is terminal: resubmitting would be rejected again.
+func (r RejectReason) Retryable() bool {
+	return r == RejectRateLimited || r == RejectVenueError
+}
+
+// RejectRetrier resubmits orders rejected for a Retryable reason with a
+// fresh ClOrdID, up to MaxAttempts times each, waiting Backoff before the
+// first retry and doubling it for every one after. Retries carry the
+// RetryOfKey and RetryAttemptKey keys.
+type RejectRetrier struct {
+	MaxAttempts int
+	Backoff     time.Duration
+
+	mu     sync.Mutex
+	orders map[string]OrderRequest // ClOrdID -> request, until terminal
+}
+
+// NewRejectRetrier creates a retrier
+func NewRejectRetrier(maxAttempts int, backoff time.Duration) *RejectRetrier {
+	return &RejectRetrier{MaxAttempts: maxAttempts, Backoff: backoff, orders: make(map[string]OrderRequest)}
+}
+
+// Add remembers the request of a placed order, to resubmit it if rejected
+func (r *RejectRetrier) Add(clOrdID string, req OrderRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[clOrdID] = req
+}
+
+// Remove forgets the order clOrdID, which never reached the venue
+func (r *RejectRetrier) Remove(clOrdID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.orders, clOrdID)
+}
+
+// OnExecutionReport forgets orders once terminal and, for a retryable reject
+// with attempts left, returns the request to resubmit and when
+func (r *RejectRetrier) OnExecutionReport(report ExecutionReport) (OrderRequest, time.Duration, bool) {
+	state, ok := orderStateFromOrdStatus[report.OrdStatus]
+	if report.ExecType != "8" && (!ok || !state.Terminal()) {
+		return OrderRequest{}, 0, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	req, ok := r.orders[report.ClOrdID]
+	if !ok {
+		return OrderRequest{}, 0, false
+	}
+	delete(r.orders, report.ClOrdID)
+	if report.ExecType != "8" || !report.RejectReason().Retryable() {
+		return OrderRequest{}, 0, false
+	}
+
+	attempt, _ := strconv.Atoi(req.Metadata[RetryAttemptKey])
+	if attempt >= r.MaxAttempts {
+		return OrderRequest{}, 0, false
+	}
+	retry := req
+	retry.Metadata = maps.Clone(req.Metadata)
+	if retry.Metadata == nil {
+		retry.Metadata = make(map[string]string)
+	}
+	if retry.Metadata[RetryOfKey] == "" {
+		retry.Metadata[RetryOfKey] = report.ClOrdID
+	}
+	retry.Metadata[RetryAttemptKey] = strconv.Itoa(attempt + 1)
+	return retry, r.Backoff << attempt, true
+}
+
+// retryRejected resubmits req, the retry of the rejected order clOrdID
+func (a *FixApplication) retryRejected(clOrdID string, req OrderRequest) {
+	retry, err := a.PlaceOrder(req)
+	if err != nil {
+		a.logger().Printf("Retry %s of rejected order %s failed: %v", req.Metadata[RetryAttemptKey], clOrdID, err)
+		return
+	}
+	a.logger().Printf("Retried rejected order %s as %s (attempt %s)", clOrdID, retry, req.Metadata[RetryAttemptKey])
+}
+
+// rejectRetrySetting returns the retrier of RejectRetryMax and
+// RejectRetryBackoff, or nil when retries are not enabled
+func rejectRetrySetting(settings *quickfix.SessionSettings) *RejectRetrier {
+	maxAttempts, err := settings.IntSetting("RejectRetryMax")
+	if err != nil || maxAttempts <= 0 {
+		return nil
+	}
+	backoff, err := settings.DurationSetting("RejectRetryBackoff")
+	if err != nil {
+		backoff = time.Second
+	}
+	return NewRejectRetrier(maxAttempts, backoff)
+}
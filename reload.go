@@ -0,0 +1,253 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// hotReloadSettings are the settings a reload applies to the running
+// application; any other changed setting only takes effect after a restart
+var hotReloadSettings = map[string]bool{
+	"MaxSymbolExposure":     true,
+	"MaxPortfolioExposure":  true,
+	"FatFingerNotional":     true,
+	"MaxDailyNotional":      true,
+	"MaxDailyOrders":        true,
+	"SlackWebhookURL":       true,
+	"PagerDutyRoutingKey":   true,
+	"AlertDedupWindow":      true,
+	"CircuitBreakerRejects": true,
+	"CircuitBreakerWindow":  true,
+}
+
+// ConfigReloader re-reads the config file and applies what it can without
+// touching the FIX session. Changes that need a reconnect are kept as pending
+// and reported on every reload until the process is restarted.
+type ConfigReloader struct {
+	mu      sync.Mutex
+	app     *FixApplication
+	path    string
+	startup *quickfix.Settings // the settings the process started with
+	keys    []string           // setting names in the startup file
+	pending map[string]string  // setting -> value waiting for a restart
+}
+
+// NewConfigReloader creates a reloader for app, which was configured from
+// settings loaded from path
+func NewConfigReloader(app *FixApplication, path string, settings *quickfix.Settings) *ConfigReloader {
+	keys, _ := configKeys(path)
+	return &ConfigReloader{
+		app:     app,
+		path:    path,
+		startup: settings,
+		keys:    keys,
+		pending: make(map[string]string),
+	}
+}
+
+// WatchSIGHUP reloads the config on every SIGHUP until stop is closed
+func (r *ConfigReloader) WatchSIGHUP(stop <-chan struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-signals:
+			if err := r.Reload(); err != nil {
+				log.Println("Config reload failed, keeping current config:", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Reload re-reads the config file, applies the hot-reloadable settings and
+// records the rest as pending
+func (r *ConfigReloader) Reload() error {
+	settings, err := LoadFIXConfig(r.path)
+	if err != nil {
+		return err
+	}
+	keys, err := configKeys(r.path)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	applied := r.app.applySettings(settings.GlobalSettings())
+
+	// Check the startup keys too so a removed setting counts as changed
+	r.pending = make(map[string]string)
+	for _, key := range append(keys, r.keys...) {
+		if applied[key] {
+			continue
+		}
+		if value, changed := settingChanged(r.startup, settings, key); changed {
+			r.pending[key] = value
+		}
+	}
+
+	log.Println("Config reloaded from", r.path)
+	for _, key := range sortedKeys(r.pending) {
+		log.Printf("Config change pending restart: %s=%s", key, r.pending[key])
+	}
+	return nil
+}
+
+// Pending returns the changed settings that take effect only after a restart
+func (r *ConfigReloader) Pending() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := make(map[string]string, len(r.pending))
+	for key, value := range r.pending {
+		pending[key] = value
+	}
+	return pending
+}
+
+// applySettings applies the hot-reloadable settings of global, returning the
+// ones it handled
+func (a *FixApplication) applySettings(global *quickfix.SessionSettings) map[string]bool {
+	applied := make(map[string]bool, len(hotReloadSettings))
+	for key := range hotReloadSettings {
+		applied[key] = true
+	}
+
+	if a.Risk != nil {
+		a.Risk.SetLimits(riskLimitsSetting(global))
+		if a.Risk.Daily != nil {
+			maxOrders, _ := global.IntSetting("MaxDailyOrders")
+			a.Risk.Daily.SetLimits(decimalSetting(global, "MaxDailyNotional"), maxOrders)
+		}
+	}
+	if a.Alerts != nil {
+		a.Alerts.Reconfigure(alertWindowSetting(global), alertSinksSetting(global)...)
+	}
+
+	// A breaker can be retuned but not added to or removed from a running application
+	threshold, err := global.IntSetting("CircuitBreakerRejects")
+	if a.Breaker != nil && err == nil {
+		window, err := global.DurationSetting("CircuitBreakerWindow")
+		if err != nil {
+			window = 30 * time.Second
+		}
+		a.Breaker.SetThreshold(threshold, window)
+	} else {
+		delete(applied, "CircuitBreakerRejects")
+		delete(applied, "CircuitBreakerWindow")
+	}
+	return applied
+}
+
+// riskLimitsSetting reads the pre-trade RiskLimits from settings
+func riskLimitsSetting(settings *quickfix.SessionSettings) RiskLimits {
+	return RiskLimits{
+		MaxSymbolExposure:    decimalSetting(settings, "MaxSymbolExposure"),
+		MaxPortfolioExposure: decimalSetting(settings, "MaxPortfolioExposure"),
+		FatFingerNotional:    decimalSetting(settings, "FatFingerNotional"),
+	}
+}
+
+// alertSinksSetting returns the alert sinks configured in settings
+func alertSinksSetting(settings *quickfix.SessionSettings) []AlertSink {
+	var sinks []AlertSink
+	if url, err := settings.Setting("SlackWebhookURL"); err == nil {
+		sinks = append(sinks, &SlackSink{WebhookURL: url})
+	}
+	if key, err := settings.Setting("PagerDutyRoutingKey"); err == nil {
+		sinks = append(sinks, &PagerDutySink{RoutingKey: key})
+	}
+	return sinks
+}
+
+// alertWindowSetting returns the AlertDedupWindow, five minutes by default
+func alertWindowSetting(settings *quickfix.SessionSettings) time.Duration {
+	window, err := settings.DurationSetting("AlertDedupWindow")
+	if err != nil {
+		return 5 * time.Minute
+	}
+	return window
+}
+
+// settingChanged reports whether key differs between old and new, in the
+// default section or any session, returning its new value
+func settingChanged(old, new *quickfix.Settings, key string) (string, bool) {
+	oldValue, _ := old.GlobalSettings().Setting(key)
+	newValue, _ := new.GlobalSettings().Setting(key)
+	if oldValue != newValue {
+		return newValue, true
+	}
+
+	oldSessions := old.SessionSettings()
+	for id, session := range new.SessionSettings() {
+		newValue, _ := session.Setting(key)
+		oldSession, ok := oldSessions[id]
+		if !ok {
+			return newValue, true
+		}
+		oldValue, _ := oldSession.Setting(key)
+		if oldValue != newValue {
+			return newValue, true
+		}
+	}
+	return "", false
+}
+
+// configKeys returns every setting name that appears in the config file at path
+func configKeys(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	seen := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") {
+			continue
+		}
+		if key, _, ok := strings.Cut(line, "="); ok {
+			seen[strings.TrimSpace(key)] = ""
+		}
+	}
+	return sortedKeys(seen), scanner.Err()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,76 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestConfigReload(t *testing.T) {
+	const session = "[SESSION]\nBeginString=FIX.4.2\nSenderCompID=SENDER\nTargetCompID=COIN\n"
+	path := filepath.Join(t.TempDir(), "fix.cfg")
+	write := func(config string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("[DEFAULT]\nMaxSymbolExposure=10\nHeartBtInt=30\n" + session)
+	settings, err := LoadFIXConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &FixApplication{Risk: NewRiskChecker(riskLimitsSetting(settings.GlobalSettings()), NewPositionTracker())}
+	reloader := NewConfigReloader(app, path, settings)
+
+	tests := []struct {
+		name        string
+		config      string // empty removes the file
+		wantErr     bool
+		maxExposure int64
+		pending     map[string]string
+	}{
+		{"risk limit applied", "[DEFAULT]\nMaxSymbolExposure=20\nHeartBtInt=30\n" + session, false, 20, map[string]string{}},
+		{"session setting pending", "[DEFAULT]\nMaxSymbolExposure=20\nHeartBtInt=60\n" + session, false, 20, map[string]string{"HeartBtInt": "60"}},
+		{"removed setting pending", "[DEFAULT]\nMaxSymbolExposure=20\n" + session, false, 20, map[string]string{"HeartBtInt": ""}},
+		{"unreadable config kept", "", true, 20, map[string]string{"HeartBtInt": ""}},
+	}
+	for _, tt := range tests {
+		if tt.config == "" {
+			os.Remove(path)
+		} else {
+			write(tt.config)
+		}
+		if err := reloader.Reload(); (err != nil) != tt.wantErr {
+			t.Fatalf("%s: Reload = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if got := app.Risk.Limits().MaxSymbolExposure; !got.Equal(decimal.NewFromInt(tt.maxExposure)) {
+			t.Errorf("%s: MaxSymbolExposure = %s, want %d", tt.name, got, tt.maxExposure)
+		}
+		pending := reloader.Pending()
+		if len(pending) != len(tt.pending) {
+			t.Errorf("%s: pending %v, want %v", tt.name, pending, tt.pending)
+		}
+		for key, value := range tt.pending {
+			if got, ok := pending[key]; !ok || got != value {
+				t.Errorf("%s: pending %v, want %v", tt.name, pending, tt.pending)
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RESTCancelFallback cancels orders over the Prime REST API while the FIX
+// session is down, and reconciles them into the tracker once it is back
+type RESTCancelFallback struct {
+	REST    *PrimeREST
+	IDs     *OrderIDMap // translates OrderIDs to REST ids when they differ
+	Timeout time.Duration
+
+	mu      sync.Mutex
+	pending map[string]string // tracked ClOrdID -> REST order id
+}
+
+// NewRESTCancelFallback creates a fallback cancelling through rest
+func NewRESTCancelFallback(rest *PrimeREST) *RESTCancelFallback {
+	return &RESTCancelFallback{REST: rest, Timeout: 5 * time.Second, pending: make(map[string]string)}
+}
+
+// cancel sends the REST cancel of order, leaving it PendingCancel in tracker
+// until reconcile learns the outcome
+func (f *RESTCancelFallback) cancel(tracker *OrderTracker, order Order) error {
+	if order.OrderID == "" {
+		return errors.New("order " + order.ClOrdID + " has no OrderID yet, it cannot be cancelled over REST")
+	}
+
+	now := time.Now()
+	requestId := "rest-" + strconv.FormatInt(now.UnixNano(), 10)
+	if err := tracker.MarkPending(order.ClOrdID, requestId, OrderPendingCancel, now); err != nil {
+		return err
+	}
+
+	restId := order.OrderID
+	if id, ok := f.IDs.RESTOrderID(order.OrderID); ok {
+		restId = id
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.Timeout)
+	defer cancel()
+	if err := f.REST.CancelOrder(ctx, restId); err != nil {
+		tracker.OnCancelReject(requestId, "")
+		return err
+	}
+	log.Printf("FIX session down, cancel of %s (REST id %s) sent over REST", order.ClOrdID, restId)
+
+	f.mu.Lock()
+	f.pending[order.ClOrdID] = restId
+	f.mu.Unlock()
+	return nil
+}
+
+// reconcile looks up every order cancelled over REST and applies its
+// current state to tracker
+func (f *RESTCancelFallback) reconcile(tracker *OrderTracker) {
+	f.mu.Lock()
+	pending := f.pending
+	f.pending = make(map[string]string)
+	f.mu.Unlock()
+
+	for clOrdId, restId := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), f.Timeout)
+		order, err := f.REST.Order(ctx, restId)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to reconcile REST cancel of %s: %v", clOrdId, err)
+			continue
+		}
+		state, ok := order.State()
+		if !ok {
+			log.Printf("Unknown REST status %s for %s", order.Status, clOrdId)
+			continue
+		}
+		log.Printf("Reconciled REST cancel of %s: %s", clOrdId, state)
+		tracker.Reconcile(clOrdId, state)
+	}
+}
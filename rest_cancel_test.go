@@ -0,0 +1,89 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"prime-fix-go/auth"
+)
+
+func TestRESTCancelFallback(t *testing.T) {
+	var mu sync.Mutex
+	var cancelled []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/cancel") {
+			id := path.Base(path.Dir(r.URL.Path))
+			if id == "venue-bad" {
+				http.Error(w, `{"message":"order not found"}`, http.StatusNotFound)
+				return
+			}
+			mu.Lock()
+			cancelled = append(cancelled, id)
+			mu.Unlock()
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		fmt.Fprintf(w, `{"order":{"id":%q,"status":"CANCELLED"}}`, path.Base(r.URL.Path))
+	}))
+	defer server.Close()
+
+	rest := &PrimeREST{BaseURL: server.URL, PortfolioId: "portfolio", Signer: auth.NewHMACSigner([]byte("key")), Client: server.Client()}
+	fallback := NewRESTCancelFallback(rest)
+	fallback.IDs = NewOrderIDMap(0)
+	fallback.IDs.Add("venue-2", "rest-2")
+	tracker := NewOrderTracker(time.Minute)
+
+	tests := []struct {
+		name        string
+		order       Order
+		wantErr     bool
+		wantPending OrderState
+	}{
+		{"no OrderID yet", Order{ClOrdID: "1", State: OrderPendingNew}, true, ""},
+		{"cancel by OrderID", Order{ClOrdID: "2", OrderID: "venue-1", State: OrderNew}, false, OrderPendingCancel},
+		{"cancel by REST id", Order{ClOrdID: "3", OrderID: "venue-2", State: OrderNew}, false, OrderPendingCancel},
+		{"REST cancel fails", Order{ClOrdID: "4", OrderID: "venue-bad", State: OrderNew}, true, ""},
+	}
+	for _, tt := range tests {
+		tracker.Add(tt.order)
+		if err := fallback.cancel(tracker, tt.order); (err != nil) != tt.wantErr {
+			t.Errorf("%s: cancel = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if order, _ := tracker.Get(tt.order.ClOrdID); order.Pending != tt.wantPending {
+			t.Errorf("%s: pending %q, want %q", tt.name, order.Pending, tt.wantPending)
+		}
+	}
+	if len(cancelled) != 2 || cancelled[0] != "venue-1" || cancelled[1] != "rest-2" {
+		t.Errorf("cancelled REST ids %v, want [venue-1 rest-2]", cancelled)
+	}
+
+	fallback.reconcile(tracker)
+	for _, clOrdID := range []string{"2", "3"} {
+		if order, _ := tracker.Get(clOrdID); order.State != OrderCanceled || order.Pending != "" {
+			t.Errorf("order %s reconciled to %s pending %q", clOrdID, order.State, order.Pending)
+		}
+	}
+	if order, _ := tracker.Get("4"); order.State != OrderNew {
+		t.Errorf("failed cancel reconciled to %s", order.State)
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// RetentionPolicy is how long each kind of persisted data is kept, zero
+// keeping it forever
+type RetentionPolicy struct {
+	Executions time.Duration // execution store, ExecutionRetention
+	Messages   time.Duration // message archive, MessageArchiveRetention
+	Orders     time.Duration // order archive, TrackerArchiveRetention
+
+	// SettlementPeriod protects records of trades that may not have settled:
+	// nothing younger is ever purged, whatever the windows or cutoff
+	SettlementPeriod time.Duration
+}
+
+// Retention purges old records from the execution store, the message archive
+// and the order archive. Records of orders the execution store shows as still
+// open are kept whatever their age, as are records younger than the
+// settlement period.
+type Retention struct {
+	Policy         RetentionPolicy
+	ExecutionsPath string
+	MessagesPath   string
+	OrdersPath     string
+
+	// The stores a running client has open, rewritten in place; nil offline
+	executions *ExecutionStore
+	messages   *MessageArchive
+	orders     *FileOrderArchive
+}
+
+// PurgeResult counts the records kept and purged from one store. Protected
+// records were old enough to purge but belong to an open order.
+type PurgeResult struct {
+	Store     string
+	Path      string
+	Kept      int
+	Purged    int
+	Protected int
+}
+
+// retentionStore is one store as Retention purges it
+type retentionStore struct {
+	name, path string
+	cutoff     time.Time
+	rewrite    func(f func(path string) error) error // nil when not open
+
+	// record returns the time of a record and the order ids it refers to
+	record func(line []byte) (time.Time, []string, error)
+}
+
+// Purge removes the records older than before from every store
+func (r *Retention) Purge(before, now time.Time, dryRun bool) ([]PurgeResult, error) {
+	return r.purge(before, before, before, now, dryRun)
+}
+
+// Enforce purges each store by its window in Policy
+func (r *Retention) Enforce(now time.Time) ([]PurgeResult, error) {
+	cutoff := func(window time.Duration) time.Time {
+		if window <= 0 {
+			return time.Time{}
+		}
+		return now.Add(-window)
+	}
+	return r.purge(cutoff(r.Policy.Executions), cutoff(r.Policy.Messages), cutoff(r.Policy.Orders), now, false)
+}
+
+// Watch enforces the policy now and then every interval until stop is closed
+func (r *Retention) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	now := time.Now()
+	for {
+		results, err := r.Enforce(now)
+		for _, result := range results {
+			if result.Purged > 0 || result.Protected > 0 {
+				log.Printf("Retention purged %d records from the %s (%s), kept %d, %d of them protected as open",
+					result.Purged, result.Store, result.Path, result.Kept, result.Protected)
+			}
+		}
+		if err != nil {
+			log.Println("Retention purge failed:", err)
+		}
+
+		select {
+		case now = <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (r *Retention) purge(executions, messages, orders, now time.Time, dryRun bool) ([]PurgeResult, error) {
+	open, err := openOrderIDs(r.ExecutionsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	stores := []retentionStore{
+		{name: "message archive", path: r.MessagesPath, cutoff: messages, record: archivedMessageRecord},
+		{name: "order archive", path: r.OrdersPath, cutoff: orders, record: archivedOrderRecord},
+		{name: "execution store", path: r.ExecutionsPath, cutoff: executions, record: executionRecord},
+	}
+	if r.messages != nil {
+		stores[0].rewrite = r.messages.rewrite
+	}
+	if r.orders != nil {
+		stores[1].rewrite = r.orders.rewrite
+	}
+	if r.executions != nil {
+		stores[2].rewrite = r.executions.rewrite
+	}
+
+	settled := now.Add(-r.Policy.SettlementPeriod)
+	var results []PurgeResult
+	for _, store := range stores {
+		if store.path == "" || store.cutoff.IsZero() {
+			continue
+		}
+		if store.cutoff.After(settled) {
+			store.cutoff = settled
+		}
+		result, err := store.purge(open, dryRun)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// purge drops the store's records older than its cutoff that do not refer to
+// an order in open, replacing the file through rewrite when it is open
+func (s retentionStore) purge(open map[string]bool, dryRun bool) (PurgeResult, error) {
+	result := PurgeResult{Store: s.name, Path: s.path}
+	apply := func(path string) error {
+		var err error
+		result, err = purgeFile(path, dryRun, func(line []byte) (bool, bool, error) {
+			at, ids, err := s.record(line)
+			if err != nil || at.IsZero() || !at.Before(s.cutoff) {
+				return false, false, err
+			}
+			for _, id := range ids {
+				if id != "" && open[id] {
+					return false, true, nil
+				}
+			}
+			return true, false, nil
+		})
+		result.Store, result.Path = s.name, s.path
+		return err
+	}
+	if s.rewrite == nil || dryRun {
+		return result, apply(s.path)
+	}
+	return result, s.rewrite(apply)
+}
+
+// purgeFile rewrites the JSON lines file at path without the lines purge
+// selects, through a temporary file renamed over it. A torn last line is kept.
+func purgeFile(path string, dryRun bool, purge func(line []byte) (purged, protected bool, err error)) (PurgeResult, error) {
+	var result PurgeResult
+	in, err := os.Open(path)
+	if err != nil {
+		return result, err
+	}
+	defer in.Close()
+
+	var kept bytes.Buffer
+	reader := bufio.NewReader(in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			kept.Write(line)
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		purged, protected, err := purge(line)
+		if err != nil {
+			return result, err
+		}
+		if protected {
+			result.Protected++
+		}
+		if purged {
+			result.Purged++
+			continue
+		}
+		result.Kept++
+		kept.Write(line)
+	}
+	if dryRun || result.Purged == 0 {
+		return result, nil
+	}
+
+	tmp := path + ".purge"
+	if err := os.WriteFile(tmp, kept.Bytes(), 0600); err != nil {
+		return result, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return result, err
+	}
+	return result, nil
+}
+
+// openOrderIDs returns the ClOrdIDs and OrderIDs of the orders whose last
+// execution in the store at path left them open; none without a store
+func openOrderIDs(path string) (map[string]bool, error) {
+	open := make(map[string]bool)
+	if path == "" {
+		return open, nil
+	}
+	reports, err := LoadExecutions(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return open, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Replaces change the ClOrdID but keep the OrderID
+	states := make(map[string]OrderState)
+	ids := make(map[string][]string)
+	for _, report := range reports {
+		key := report.OrderID
+		if key == "" {
+			key = report.ClOrdID
+		}
+		if state, ok := orderStateFromOrdStatus[report.OrdStatus]; ok {
+			states[key] = state
+		}
+		ids[key] = append(ids[key], report.OrderID, report.ClOrdID, report.OrigClOrdID)
+	}
+	for key, state := range states {
+		if !state.Terminal() {
+			for _, id := range ids[key] {
+				open[id] = true
+			}
+		}
+	}
+	return open, nil
+}
+
+func executionRecord(line []byte) (time.Time, []string, error) {
+	var report ExecutionReport
+	if err := json.Unmarshal(line, &report); err != nil {
+		return time.Time{}, nil, err
+	}
+	at := report.TransactedAt
+	if at.IsZero() {
+		at = report.ReceivedAt
+	}
+	return at, []string{report.OrderID, report.ClOrdID, report.OrigClOrdID}, nil
+}
+
+func archivedMessageRecord(line []byte) (time.Time, []string, error) {
+	var message ArchivedMessage
+	if err := json.Unmarshal(line, &message); err != nil {
+		return time.Time{}, nil, err
+	}
+	return message.Time, []string{message.OrderID, message.ClOrdID, message.OrigClOrdID}, nil
+}
+
+func archivedOrderRecord(line []byte) (time.Time, []string, error) {
+	var record archivedOrder
+	if err := json.Unmarshal(line, &record); err != nil {
+		return time.Time{}, nil, err
+	}
+	return record.Order.TerminalAt, append([]string{record.Order.OrderID, record.Order.ClOrdID}, record.Aliases...), nil
+}
+
+// retentionSetting builds the retention of the stores configured in
+// settings, rewriting those app has open when app is not nil
+func retentionSetting(settings *quickfix.SessionSettings, app *FixApplication) (*Retention, error) {
+	r := &Retention{Policy: RetentionPolicy{SettlementPeriod: 72 * time.Hour}}
+	for setting, window := range map[string]*time.Duration{
+		"ExecutionRetention":      &r.Policy.Executions,
+		"MessageArchiveRetention": &r.Policy.Messages,
+		"TrackerArchiveRetention": &r.Policy.Orders,
+		"SettlementPeriod":        &r.Policy.SettlementPeriod,
+	} {
+		if !settings.HasSetting(setting) {
+			continue
+		}
+		var err error
+		if *window, err = settings.DurationSetting(setting); err != nil {
+			return nil, err
+		}
+		if *window < 0 {
+			return nil, errors.New(setting + " cannot be negative")
+		}
+	}
+
+	r.ExecutionsPath, _ = settings.Setting("ExecutionStorePath")
+	r.MessagesPath, _ = settings.Setting("MessageArchivePath")
+	if settings.HasSetting("TrackerRetention") {
+		r.OrdersPath = "./Sessions/orders.jsonl"
+	}
+	if path, err := settings.Setting("TrackerArchivePath"); err == nil {
+		r.OrdersPath = path
+	}
+
+	if app != nil {
+		r.executions, r.messages = app.Executions, app.Archive
+		if app.Tracker != nil {
+			r.orders, _ = app.Tracker.archive.(*FileOrderArchive)
+		}
+	}
+	return r, nil
+}
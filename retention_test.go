@@ -0,0 +1,95 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRetentionPurge(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, -2, 0)
+
+	executions, err := OpenExecutionStore(filepath.Join(dir, "executions.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer executions.Close()
+	for _, report := range []ExecutionReport{
+		{ExecID: "e1", OrderID: "o1", ClOrdID: "filled", OrdStatus: "2", ExecType: "F", TransactedAt: old},
+		{ExecID: "e2", OrderID: "o2", ClOrdID: "resting", OrdStatus: "0", ExecType: "0", TransactedAt: old},
+		{ExecID: "e3", OrderID: "o3", ClOrdID: "recent", OrdStatus: "2", ExecType: "F", TransactedAt: now.Add(-24 * time.Hour)},
+	} {
+		if err := executions.Append(report); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archive, err := OpenMessageArchive(filepath.Join(dir, "messages.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	for _, clOrdID := range []string{"filled", "resting"} {
+		if err := archive.Record("out", paperOrderMessage("D", clOrdID, "1", "2", "1", "100"), old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	retention := &Retention{
+		Policy:         RetentionPolicy{SettlementPeriod: 72 * time.Hour},
+		ExecutionsPath: executions.path,
+		MessagesPath:   archive.path,
+		executions:     executions,
+		messages:       archive,
+	}
+
+	// A cutoff inside the settlement period is pulled back to its start
+	dry, err := retention.Purge(now, now, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dry) != 2 || dry[1].Purged != 1 || dry[1].Protected != 1 || dry[1].Kept != 2 {
+		t.Fatalf("unexpected dry run %+v", dry)
+	}
+	if reports, _ := LoadExecutions(executions.path); len(reports) != 3 {
+		t.Fatalf("dry run purged executions: %d left", len(reports))
+	}
+
+	results, err := retention.Purge(now, now, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Purged != 1 || results[0].Protected != 1 {
+		t.Fatalf("unexpected message archive result %+v", results[0])
+	}
+	reports, err := LoadExecutions(executions.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 || reports[0].ClOrdID != "resting" || reports[1].ClOrdID != "recent" {
+		t.Fatalf("unexpected executions left %+v", reports)
+	}
+
+	// The open store keeps appending to the rewritten file
+	if err := executions.Append(ExecutionReport{ExecID: "e4", OrderID: "o2", ClOrdID: "resting", OrdStatus: "4", ExecType: "4", TransactedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if reports, _ := LoadExecutions(executions.path); len(reports) != 3 {
+		t.Fatalf("append after purge: %d executions", len(reports))
+	}
+}
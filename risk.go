@@ -0,0 +1,345 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RiskLimits are the pre-trade exposure limits, in quote currency. A zero
+// limit is not enforced.
+type RiskLimits struct {
+	// MaxSymbolExposure caps the absolute net exposure of any one symbol,
+	// unless overridden for that symbol in SymbolExposure
+	MaxSymbolExposure decimal.Decimal
+	SymbolExposure    map[string]decimal.Decimal
+
+	// MaxPortfolioExposure caps the absolute sum of the signed per-symbol
+	// exposures; symbols are assumed to share a quote currency
+	MaxPortfolioExposure decimal.Decimal
+
+	// FatFingerNotional is the order notional above which the request must
+	// set Force; orders without a limit price, such as MARKET orders, are
+	// valued at the reference price
+	FatFingerNotional decimal.Decimal
+}
+
+// priced reports whether any limit needs the price of an order
+func (limits RiskLimits) priced() bool {
+	if limits.MaxSymbolExposure.IsPositive() || limits.MaxPortfolioExposure.IsPositive() || limits.FatFingerNotional.IsPositive() {
+		return true
+	}
+	for _, limit := range limits.SymbolExposure {
+		if limit.IsPositive() {
+			return true
+		}
+	}
+	return false
+}
+
+// RiskError is returned when an order is blocked by a risk limit. A
+// FatFingerNotional error can be overridden by resubmitting with Force set.
+type RiskError struct {
+	Rule      string
+	Symbol    string
+	Limit     decimal.Decimal
+	Projected decimal.Decimal
+}
+
+func (e *RiskError) Error() string {
+	if e.Limit.IsZero() && e.Projected.IsZero() {
+		return fmt.Sprintf("risk check %s failed for %s", e.Rule, e.Symbol)
+	}
+	return fmt.Sprintf("risk limit %s breached for %s: projected %s exceeds %s", e.Rule, e.Symbol, e.Projected, e.Limit)
+}
+
+// RiskEvent reports an order blocked by the risk layer
+type RiskEvent struct {
+	Time    time.Time
+	Request OrderRequest
+	Err     *RiskError
+}
+
+// RiskChecker checks new orders against RiskLimits using live positions and
+// working orders, and against the daily limits of Daily when one is set.
+// Orders are valued at their limit price, or else at the Reference price or
+// the last fill; an order with no price is blocked while a limit applies.
+type RiskChecker struct {
+	mu        sync.RWMutex
+	limits    RiskLimits
+	Daily     *DailyLimiter
+	positions *PositionTracker
+
+	// reserveMu serializes Reserve, so that orders checked concurrently see
+	// each other in sending, the quantities of which count as working
+	reserveMu sync.Mutex
+	sending   map[string]workingQty
+
+	// Reference, when set, returns the mark price of a symbol, e.g. its mid
+	Reference func(symbol string) (decimal.Decimal, bool)
+
+	// Working, when set, returns the unfilled quantity of the open buy and
+	// sell orders in a symbol, which count towards exposure as if filled
+	Working func(symbol string) (buy, sell decimal.Decimal)
+
+	// OnRiskEvent is called for every order the checker blocks
+	OnRiskEvent func(event RiskEvent)
+
+	// Clock times the daily limits and risk events; nil uses the wall clock
+	Clock Clock
+}
+
+// workingQty is the unfilled buy and sell quantity of a symbol's open orders
+type workingQty struct {
+	buy, sell decimal.Decimal
+}
+
+// RiskReservation holds an order's place in the daily limits and in the
+// exposure of orders being sent, from Reserve until Release
+type RiskReservation struct {
+	daily  DailyReservation
+	symbol string
+	qty    decimal.Decimal // negative for a sell
+}
+
+// NewRiskChecker creates a checker projecting exposure from positions
+func NewRiskChecker(limits RiskLimits, positions *PositionTracker) *RiskChecker {
+	return &RiskChecker{limits: limits, positions: positions, sending: make(map[string]workingQty)}
+}
+
+// Limits returns the limits currently enforced
+func (r *RiskChecker) Limits() RiskLimits {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.limits
+}
+
+// SetLimits replaces the enforced limits, applying from the next Check
+func (r *RiskChecker) SetLimits(limits RiskLimits) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limits = limits
+}
+
+// Check returns a *RiskError if filling req in full would breach a limit
+func (r *RiskChecker) Check(req OrderRequest) error {
+	now := clockOrSystem(r.Clock).Now()
+	if r.Daily != nil {
+		if err := r.Daily.Check(req, now); err != nil {
+			return r.blocked(req, err, now)
+		}
+	}
+	r.reserveMu.Lock()
+	err := r.check(req, decimal.Zero)
+	r.reserveMu.Unlock()
+	if err != nil {
+		return r.blocked(req, err, now)
+	}
+	return nil
+}
+
+// Reserve checks req again and counts it against the limits until Release,
+// all under one lock, so that orders placed concurrently cannot each pass
+// alone and together breach a limit. The daily limits are reserved through
+// DailyLimiter.Reserve; the quantity of req counts as working.
+func (r *RiskChecker) Reserve(req OrderRequest, now time.Time) (RiskReservation, error) {
+	r.reserveMu.Lock()
+	defer r.reserveMu.Unlock()
+
+	if err := r.check(req, decimal.Zero); err != nil {
+		return RiskReservation{}, r.blocked(req, err, now)
+	}
+	var reservation RiskReservation
+	if r.Daily != nil {
+		var err *RiskError
+		if reservation.daily, err = r.Daily.Reserve(req, now); err != nil {
+			return RiskReservation{}, r.blocked(req, err, now)
+		}
+	}
+	qty, _ := decimal.NewFromString(req.Quantity)
+	r.reserveSending(&reservation, req, qty)
+	return reservation, nil
+}
+
+// ReserveReplace is Reserve for replacing the working order order with req,
+// which carries its new quantity and price. The order already counts as
+// working, so only an increase of its quantity is reserved, and it is not
+// counted as another order under the daily limits.
+func (r *RiskChecker) ReserveReplace(order Order, req OrderRequest, now time.Time) (RiskReservation, error) {
+	r.reserveMu.Lock()
+	defer r.reserveMu.Unlock()
+
+	replaced, _ := decimal.NewFromString(order.Quantity)
+	if err := r.check(req, replaced); err != nil {
+		return RiskReservation{}, r.blocked(req, err, now)
+	}
+	// What the order filled already counts towards the daily notional
+	qty, _ := decimal.NewFromString(req.Quantity)
+	cumQty, _ := decimal.NewFromString(order.CumQty)
+	var reservation RiskReservation
+	if r.Daily != nil {
+		unfilled := req
+		unfilled.Quantity = qty.Sub(cumQty).String()
+		var err *RiskError
+		if reservation.daily, err = r.Daily.ReserveReplace(unfilled, now); err != nil {
+			return RiskReservation{}, r.blocked(req, err, now)
+		}
+	}
+	r.reserveSending(&reservation, req, decimal.Max(qty.Sub(replaced), decimal.Zero))
+	return reservation, nil
+}
+
+// reserveSending counts qty of req as being sent until the reservation is
+// released; callers must hold reserveMu
+func (r *RiskChecker) reserveSending(reservation *RiskReservation, req OrderRequest, qty decimal.Decimal) {
+	working := r.sending[req.Symbol]
+	if req.Side == "SELL" {
+		working.sell = working.sell.Add(qty)
+		qty = qty.Neg()
+	} else {
+		working.buy = working.buy.Add(qty)
+	}
+	r.sending[req.Symbol] = working
+	reservation.symbol, reservation.qty = req.Symbol, qty
+}
+
+// Release ends a reservation made by Reserve once the order is tracked as
+// working or failed to send, see DailyLimiter.Release
+func (r *RiskChecker) Release(reservation RiskReservation, sent bool) {
+	r.reserveMu.Lock()
+	defer r.reserveMu.Unlock()
+
+	if working, ok := r.sending[reservation.symbol]; ok {
+		if reservation.qty.IsNegative() {
+			working.sell = working.sell.Add(reservation.qty)
+		} else {
+			working.buy = working.buy.Sub(reservation.qty)
+		}
+		r.sending[reservation.symbol] = working
+		if working.buy.IsZero() && working.sell.IsZero() {
+			delete(r.sending, reservation.symbol)
+		}
+	}
+	if r.Daily != nil {
+		r.Daily.Release(reservation.daily, sent)
+	}
+}
+
+// blocked reports the order err blocked and returns err
+func (r *RiskChecker) blocked(req OrderRequest, err *RiskError, now time.Time) error {
+	log.Println("Order blocked by risk:", err)
+	if r.OnRiskEvent != nil {
+		r.OnRiskEvent(RiskEvent{Time: now, Request: req, Err: err})
+	}
+	return err
+}
+
+// OnExecutionReport counts fills against the daily limits
+func (r *RiskChecker) OnExecutionReport(report ExecutionReport, now time.Time) {
+	if r.Daily != nil {
+		r.Daily.RecordFill(report, now)
+	}
+}
+
+// check checks req against the exposure limits, replacing an order of
+// quantity replaced, or zero for a new order. The unfilled part of the
+// replaced order counts as working and its filled part is in the position,
+// so both are taken out. Callers must hold reserveMu.
+func (r *RiskChecker) check(req OrderRequest, replaced decimal.Decimal) *RiskError {
+	limits := r.Limits()
+
+	qty, err := decimal.NewFromString(req.Quantity)
+	if err != nil {
+		return &RiskError{Rule: "InvalidQuantity", Symbol: req.Symbol}
+	}
+	if req.Side == "SELL" {
+		qty = qty.Neg()
+	}
+
+	if !limits.priced() {
+		return nil
+	}
+	position := r.positions.Get(req.Symbol)
+	price, ok := r.price(req, position)
+	if !ok {
+		return &RiskError{Rule: "NoReferencePrice", Symbol: req.Symbol}
+	}
+
+	notional := qty.Abs().Mul(price)
+	if limits.FatFingerNotional.IsPositive() && notional.GreaterThan(limits.FatFingerNotional) && !req.Force {
+		return &RiskError{Rule: "FatFingerNotional", Symbol: req.Symbol, Limit: limits.FatFingerNotional, Projected: notional}
+	}
+
+	// Working orders on the side of the new one count as filled, as do the
+	// orders still being sent
+	projectedQty := position.NetQty.Add(qty)
+	working := r.sending[req.Symbol]
+	if r.Working != nil {
+		buy, sell := r.Working(req.Symbol)
+		working.buy, working.sell = working.buy.Add(buy), working.sell.Add(sell)
+	}
+	if qty.IsNegative() {
+		projectedQty = projectedQty.Sub(working.sell).Add(replaced)
+	} else {
+		projectedQty = projectedQty.Add(working.buy).Sub(replaced)
+	}
+	projected := projectedQty.Mul(price)
+	if limit := limits.symbolLimit(req.Symbol); limit.IsPositive() && projected.Abs().GreaterThan(limit) {
+		return &RiskError{Rule: "MaxSymbolExposure", Symbol: req.Symbol, Limit: limit, Projected: projected.Abs()}
+	}
+
+	if limits.MaxPortfolioExposure.IsPositive() {
+		portfolio := projected
+		for _, other := range r.positions.All() {
+			if other.Symbol != req.Symbol {
+				portfolio = portfolio.Add(other.Exposure())
+			}
+		}
+		if portfolio.Abs().GreaterThan(limits.MaxPortfolioExposure) {
+			return &RiskError{Rule: "MaxPortfolioExposure", Symbol: req.Symbol, Limit: limits.MaxPortfolioExposure, Projected: portfolio.Abs()}
+		}
+	}
+
+	return nil
+}
+
+// price returns the price req is valued at: its limit price, or else the
+// reference price or the last fill price of its symbol
+func (r *RiskChecker) price(req OrderRequest, position Position) (decimal.Decimal, bool) {
+	if limitPrice, err := decimal.NewFromString(req.LimitPrice); err == nil && limitPrice.IsPositive() {
+		return limitPrice, true
+	}
+	if r.Reference != nil {
+		if price, ok := r.Reference(req.Symbol); ok && price.IsPositive() {
+			return price, true
+		}
+	}
+	if position.LastPrice.IsPositive() {
+		return position.LastPrice, true
+	}
+	return decimal.Zero, false
+}
+
+func (limits RiskLimits) symbolLimit(symbol string) decimal.Decimal {
+	if limit, ok := limits.SymbolExposure[symbol]; ok {
+		return limit
+	}
+	return limits.MaxSymbolExposure
+}
@@ -0,0 +1,213 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+func TestRiskChecker(t *testing.T) {
+	d := decimal.RequireFromString
+	limits := RiskLimits{
+		MaxSymbolExposure:    d("50000"),
+		SymbolExposure:       map[string]decimal.Decimal{"ETH-USD": d("1000")},
+		MaxPortfolioExposure: d("80000"),
+		FatFingerNotional:    d("40000"),
+	}
+	mids := map[string]decimal.Decimal{"BTC-USD": d("50000"), "ETH-USD": d("2000")}
+
+	for _, tc := range []struct {
+		name      string
+		limits    RiskLimits
+		positions []Position
+		working   [2]string // open buy and sell quantity in BTC-USD
+		mids      bool
+		req       OrderRequest
+		want      string // blocking rule, empty when allowed
+	}{
+		{"small limit order", limits, nil, [2]string{}, false, OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "0.5", LimitPrice: "50000"}, ""},
+		{"market order valued at the mid", limits, nil, [2]string{}, true, OrderRequest{Symbol: "BTC-USD", OrdType: "MARKET", Side: "BUY", Quantity: "1000"}, "FatFingerNotional"},
+		{"market order valued at the last fill", limits, []Position{{Symbol: "BTC-USD", LastPrice: d("50000")}}, [2]string{}, false, OrderRequest{Symbol: "BTC-USD", OrdType: "MARKET", Side: "BUY", Quantity: "1000"}, "FatFingerNotional"},
+		{"market order without a price", limits, nil, [2]string{}, false, OrderRequest{Symbol: "BTC-USD", OrdType: "MARKET", Side: "BUY", Quantity: "1000"}, "NoReferencePrice"},
+		{"market order without limits", RiskLimits{}, nil, [2]string{}, false, OrderRequest{Symbol: "BTC-USD", OrdType: "MARKET", Side: "BUY", Quantity: "1000"}, ""},
+		{"forced fat finger", limits, nil, [2]string{}, true, OrderRequest{Symbol: "BTC-USD", OrdType: "MARKET", Side: "SELL", Quantity: "0.9", Force: true}, ""},
+		{"symbol exposure", limits, []Position{{Symbol: "BTC-USD", NetQty: d("0.5"), LastPrice: d("50000")}}, [2]string{}, true, OrderRequest{Symbol: "BTC-USD", OrdType: "MARKET", Side: "BUY", Quantity: "0.6"}, "MaxSymbolExposure"},
+		{"symbol override", limits, nil, [2]string{}, true, OrderRequest{Symbol: "ETH-USD", OrdType: "MARKET", Side: "SELL", Quantity: "1"}, "MaxSymbolExposure"},
+		{"working buys count", limits, nil, [2]string{"0.7", "0"}, true, OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "0.4", LimitPrice: "50000"}, "MaxSymbolExposure"},
+		{"working buys do not hold back a sell", limits, nil, [2]string{"0.7", "0"}, true, OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "SELL", Quantity: "0.4", LimitPrice: "50000"}, ""},
+		{"portfolio exposure", limits, []Position{{Symbol: "SOL-USD", NetQty: d("500"), LastPrice: d("100")}}, [2]string{}, true, OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "0.7", LimitPrice: "50000"}, "MaxPortfolioExposure"},
+		{"invalid quantity", limits, nil, [2]string{}, true, OrderRequest{Symbol: "BTC-USD", OrdType: "MARKET", Side: "BUY", Quantity: "lots"}, "InvalidQuantity"},
+	} {
+		positions := NewPositionTracker()
+		positions.Restore(tc.positions)
+		risk := NewRiskChecker(tc.limits, positions)
+		if tc.mids {
+			risk.Reference = func(symbol string) (decimal.Decimal, bool) {
+				mid, ok := mids[symbol]
+				return mid, ok
+			}
+		}
+		risk.Working = func(symbol string) (buy, sell decimal.Decimal) {
+			if symbol != "BTC-USD" || tc.working[0] == "" {
+				return decimal.Zero, decimal.Zero
+			}
+			return d(tc.working[0]), d(tc.working[1])
+		}
+
+		var events []RiskEvent
+		risk.OnRiskEvent = func(event RiskEvent) { events = append(events, event) }
+		err := risk.Check(tc.req)
+		switch {
+		case tc.want == "" && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.want != "" && (err == nil || err.(*RiskError).Rule != tc.want):
+			t.Errorf("%s: got %v, want %s", tc.name, err, tc.want)
+		case tc.want != "" && len(events) != 1:
+			t.Errorf("%s: %d risk events", tc.name, len(events))
+		}
+	}
+}
+
+func TestOrderTrackerWorkingQty(t *testing.T) {
+	tracker := NewOrderTracker(time.Minute)
+	tracker.Add(Order{ClOrdID: "1", Symbol: "BTC-USD", Side: "BUY", Quantity: "2", CumQty: "0.5", State: OrderNew})
+	tracker.Add(Order{ClOrdID: "2", Symbol: "BTC-USD", Side: "SELL", Quantity: "1"})
+	tracker.Add(Order{ClOrdID: "3", Symbol: "BTC-USD", Side: "BUY", Quantity: "5", State: OrderCanceled})
+	tracker.Add(Order{ClOrdID: "4", Symbol: "ETH-USD", Side: "BUY", Quantity: "7"})
+
+	buy, sell := tracker.WorkingQty("BTC-USD")
+	if buy.String() != "1.5" || sell.String() != "1" {
+		t.Errorf("working buy %s and sell %s, want 1.5 and 1", buy, sell)
+	}
+}
+
+func TestRiskReserveCountsOrdersBeingSent(t *testing.T) {
+	positions := NewPositionTracker()
+	risk := NewRiskChecker(RiskLimits{MaxSymbolExposure: decimal.NewFromInt(1000)}, positions)
+	req := OrderRequest{Symbol: "BTC-USD", Side: "BUY", Quantity: "6", LimitPrice: "100"}
+
+	// Two orders that each fit alone: the second is blocked while the first
+	// is being sent, before the tracker counts it as working
+	first, err := risk.Reserve(req, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := risk.Reserve(req, time.Now()); err == nil || err.(*RiskError).Rule != "MaxSymbolExposure" {
+		t.Fatalf("second Reserve = %v, want MaxSymbolExposure", err)
+	}
+	// A sell reduces the exposure and passes
+	sell, err := risk.Reserve(OrderRequest{Symbol: "BTC-USD", Side: "SELL", Quantity: "6", LimitPrice: "100"}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	risk.Release(first, false)
+	risk.Release(sell, false)
+	if _, err := risk.Reserve(req, time.Now()); err != nil {
+		t.Fatalf("Reserve after the first order failed to send: %v", err)
+	}
+}
+
+func TestRiskReserveIsAtomic(t *testing.T) {
+	risk := NewRiskChecker(RiskLimits{MaxSymbolExposure: decimal.NewFromInt(1000)}, NewPositionTracker())
+	req := OrderRequest{Symbol: "BTC-USD", Side: "BUY", Quantity: "1", LimitPrice: "100"}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := risk.Reserve(req, time.Now()); err == nil {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if reserved != 10 {
+		t.Fatalf("reserved %d of 50 orders, want 10 within the 1000 limit", reserved)
+	}
+}
+
+func TestReplaceOrderRunsPreTradeChecks(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC))
+	app := &FixApplication{Clock: clock, Tracker: NewOrderTracker(time.Minute), Paper: NewPaperVenue(clock, 1)}
+	app.Paper.Deliver = func(msg *quickfix.Message) { app.FromApp(msg, quickfix.SessionID{}) }
+	app.Risk = NewRiskChecker(RiskLimits{MaxSymbolExposure: decimal.NewFromInt(1000)}, NewPositionTracker())
+	app.Risk.Working = app.Tracker.WorkingQty
+
+	app.OnQuote("BTC-USD", decimal.NewFromInt(90), decimal.NewFromInt(110))
+	clOrdID, err := app.PlaceOrder(OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "5", LimitPrice: "100"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Millisecond)
+
+	// The order itself already counts as working, so 8 fits and 20 does not
+	if err := app.ReplaceOrder(clOrdID, "20", "100"); err == nil || err.(*RiskError).Rule != "MaxSymbolExposure" {
+		t.Fatalf("replace to 20 = %v, want MaxSymbolExposure", err)
+	}
+	if err := app.ReplaceOrder(clOrdID, "8", "100"); err != nil {
+		t.Fatal(err)
+	}
+	// Until the venue answers, the larger quantity counts as working
+	if buy, _ := app.Tracker.WorkingQty("BTC-USD"); !buy.Equal(decimal.NewFromInt(8)) {
+		t.Errorf("working buy %s while the replace is pending, want 8", buy)
+	}
+	clock.Advance(time.Millisecond)
+
+	app.draining.Store(true)
+	if err := app.ReplaceOrder(clOrdID, "6", "100"); !errors.Is(err, ErrDraining) {
+		t.Fatalf("replace while draining = %v, want ErrDraining", err)
+	}
+}
+
+func TestRiskCheckerUsesClock(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+	}{
+		{"before the reset", time.Date(2024, 1, 1, 23, 59, 0, 0, time.UTC)},
+		{"after the reset", time.Date(2024, 1, 2, 0, 1, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		limiter, err := NewDailyLimiter(DailyLimits{MaxNotional: decimal.NewFromInt(5)}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		checker := NewRiskChecker(RiskLimits{}, NewPositionTracker())
+		checker.Daily = limiter
+		checker.Clock = NewFakeClock(tt.now)
+		var events []RiskEvent
+		checker.OnRiskEvent = func(event RiskEvent) { events = append(events, event) }
+
+		err = checker.Check(OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "10"})
+		if err == nil {
+			t.Fatalf("%s: order over the daily notional passed", tt.name)
+		}
+		if len(events) != 1 || !events[0].Time.Equal(tt.now) {
+			t.Errorf("%s: events %+v, want one at %s", tt.name, events, tt.now)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNoScheduledOrder is returned when canceling an order that is not, or no
+// longer, waiting for submission
+var ErrNoScheduledOrder = errors.New("no pending scheduled order")
+
+// ScheduledOrder is an order queued for submission at SubmitAt
+type ScheduledOrder struct {
+	Id       string
+	SubmitAt time.Time
+	Request  OrderRequest
+}
+
+// OrderScheduler holds orders until their submission time. When created with a
+// path, the queue is persisted there so scheduled orders survive a restart.
+// Orders stay queued, and persisted, until they are submitted; those due
+// while the session is logged out are held until Resume.
+type OrderScheduler struct {
+	mu     sync.Mutex
+	path   string
+	orders map[string]ScheduledOrder
+	timers map[string]Timer
+	held   map[string]bool // due while logged out, waiting for Resume
+	clock  Clock
+	submit func(req OrderRequest) (string, error)
+
+	// LateTolerance is how overdue an order may be when reloaded at startup
+	// or resumed at logon and still be submitted; older ones are dropped
+	LateTolerance time.Duration
+}
+
+// NewOrderScheduler creates a scheduler submitting due orders through submit,
+// reloading any orders persisted at path (which may be empty for none). A nil
+// clock uses the wall clock.
+func NewOrderScheduler(path string, lateTolerance time.Duration, clock Clock, submit func(req OrderRequest) (string, error)) (*OrderScheduler, error) {
+	s := &OrderScheduler{
+		path:          path,
+		orders:        make(map[string]ScheduledOrder),
+		timers:        make(map[string]Timer),
+		held:          make(map[string]bool),
+		clock:         clockOrSystem(clock),
+		submit:        submit,
+		LateTolerance: lateTolerance,
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var persisted []ScheduledOrder
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	err = s.persist(func(orders map[string]ScheduledOrder) {
+		for _, order := range persisted {
+			if now.Sub(order.SubmitAt) > lateTolerance {
+				log.Printf("Dropping scheduled order %s, due at %s", order.Id, order.SubmitAt)
+				continue
+			}
+			orders[order.Id] = order
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range s.orders {
+		s.arm(order)
+	}
+	return s, nil
+}
+
+// Schedule queues req for submission at at, returning the scheduled order's id
+func (s *OrderScheduler) Schedule(req OrderRequest, at time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ids come from the clock, which a fake clock may not have moved
+	var id string
+	for nanos := s.clock.Now().UnixNano(); ; nanos++ {
+		id = "sched-" + strconv.FormatInt(nanos, 10)
+		if _, taken := s.orders[id]; !taken {
+			break
+		}
+	}
+	order := ScheduledOrder{
+		Id:       id,
+		SubmitAt: at,
+		Request:  req,
+	}
+	if err := s.persist(func(orders map[string]ScheduledOrder) { orders[id] = order }); err != nil {
+		return "", err
+	}
+	s.arm(order)
+	return order.Id, nil
+}
+
+// Cancel removes a scheduled order that has not been submitted yet
+func (s *OrderScheduler) Cancel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	timer, armed := s.timers[id]
+	if !armed && !s.held[id] || armed && !timer.Stop() {
+		return fmt.Errorf("%w %s", ErrNoScheduledOrder, id)
+	}
+	if err := s.persist(func(orders map[string]ScheduledOrder) { delete(orders, id) }); err != nil {
+		if armed {
+			s.arm(s.orders[id])
+		}
+		return err
+	}
+	delete(s.timers, id)
+	delete(s.held, id)
+	return nil
+}
+
+// Resume submits the orders held while the session was logged out, dropping
+// those overdue by more than LateTolerance. Call it once logged on.
+func (s *OrderScheduler) Resume() {
+	s.mu.Lock()
+	now := s.clock.Now()
+	var due []ScheduledOrder
+	for id := range s.held {
+		order := s.orders[id]
+		delete(s.held, id)
+		if now.Sub(order.SubmitAt) > s.LateTolerance {
+			log.Printf("Dropping scheduled order %s, due at %s", id, order.SubmitAt)
+			s.remove(id)
+			continue
+		}
+		due = append(due, order)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool { return due[i].SubmitAt.Before(due[j].SubmitAt) })
+	for _, order := range due {
+		s.submitOrder(order)
+	}
+}
+
+// Pending returns the orders still waiting for submission, soonest first
+func (s *OrderScheduler) Pending() []ScheduledOrder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make([]ScheduledOrder, 0, len(s.orders))
+	for _, order := range s.orders {
+		pending = append(pending, order)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].SubmitAt.Before(pending[j].SubmitAt) })
+	return pending
+}
+
+// arm starts the timer of a queued order; callers must hold mu
+func (s *OrderScheduler) arm(order ScheduledOrder) {
+	s.timers[order.Id] = s.clock.AfterFunc(order.SubmitAt.Sub(s.clock.Now()), func() { s.fire(order.Id) })
+}
+
+func (s *OrderScheduler) fire(id string) {
+	s.mu.Lock()
+	order, ok := s.orders[id]
+	delete(s.timers, id)
+	s.mu.Unlock()
+
+	if ok {
+		s.submitOrder(order)
+	}
+}
+
+// submitOrder submits a due order, which stays queued when the session is
+// logged out and is removed otherwise
+func (s *OrderScheduler) submitOrder(order ScheduledOrder) {
+	clOrdId, err := s.submit(order.Request)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case errors.Is(err, ErrNotLoggedOn):
+		log.Printf("Holding scheduled order %s until logon", order.Id)
+		s.held[order.Id] = true
+		return
+	case err != nil:
+		log.Printf("Failed to submit scheduled order %s: %v", order.Id, err)
+	default:
+		log.Printf("Submitted scheduled order %s as ClOrdID=%s", order.Id, clOrdId)
+	}
+	s.remove(order.Id)
+}
+
+// remove drops a submitted or overdue order, from memory even when it cannot
+// be persisted, so it is never submitted twice; callers must hold mu
+func (s *OrderScheduler) remove(id string) {
+	if err := s.persist(func(orders map[string]ScheduledOrder) { delete(orders, id) }); err != nil {
+		log.Println("Failed to persist scheduled orders:", err)
+		delete(s.orders, id)
+	}
+}
+
+// persist writes the orders as changed by change to path, and only then
+// makes them the queue, so memory never runs ahead of the file; callers must
+// hold mu
+func (s *OrderScheduler) persist(change func(orders map[string]ScheduledOrder)) error {
+	orders := maps.Clone(s.orders)
+	change(orders)
+	if s.path != "" {
+		if err := writeScheduledOrders(s.path, orders); err != nil {
+			return err
+		}
+	}
+	s.orders = orders
+	return nil
+}
+
+func writeScheduledOrders(path string, orders map[string]ScheduledOrder) error {
+	pending := make([]ScheduledOrder, 0, len(orders))
+	for _, order := range orders {
+		pending = append(pending, order)
+	}
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,226 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOrderSchedulerCancel(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	var submitted []string
+	scheduler, err := NewOrderScheduler("", time.Minute, clock, func(req OrderRequest) (string, error) {
+		submitted = append(submitted, req.Symbol)
+		return "1", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	later, _ := scheduler.Schedule(OrderRequest{Symbol: "BTC-USD"}, clock.Now().Add(2*time.Hour))
+	sooner, _ := scheduler.Schedule(OrderRequest{Symbol: "ETH-USD"}, clock.Now().Add(time.Hour))
+	if later == sooner {
+		t.Fatalf("both orders scheduled as %s", later)
+	}
+	if pending := scheduler.Pending(); len(pending) != 2 || pending[0].Id != sooner || pending[1].Id != later {
+		t.Fatalf("pending = %+v, want soonest first", pending)
+	}
+
+	if err := scheduler.Cancel(later); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(3 * time.Hour)
+	if len(submitted) != 1 || submitted[0] != "ETH-USD" {
+		t.Errorf("submitted %v, want only ETH-USD", submitted)
+	}
+	for _, id := range []string{later, sooner, "sched-unknown"} {
+		if err := scheduler.Cancel(id); !errors.Is(err, ErrNoScheduledOrder) {
+			t.Errorf("Cancel(%s) = %v, want ErrNoScheduledOrder", id, err)
+		}
+	}
+	if pending := scheduler.Pending(); len(pending) != 0 {
+		t.Errorf("pending = %+v", pending)
+	}
+}
+
+func TestOrderSchedulerReloadsPersistedOrders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduled.json")
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	submit := func(req OrderRequest) (string, error) { return "1", nil }
+	scheduler, err := NewOrderScheduler(path, time.Minute, NewFakeClock(start), submit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduler.Schedule(OrderRequest{Symbol: "ETH-USD"}, start.Add(30*time.Second))
+	scheduler.Schedule(OrderRequest{Symbol: "BTC-USD"}, start.Add(2*time.Hour))
+	canceled, _ := scheduler.Schedule(OrderRequest{Symbol: "SOL-USD"}, start.Add(3*time.Hour))
+	scheduler.Cancel(canceled)
+
+	// Restarted five minutes later, the ETH-USD order is too late to submit
+	clock := NewFakeClock(start.Add(5 * time.Minute))
+	var submitted []string
+	restarted, err := NewOrderScheduler(path, time.Minute, clock, func(req OrderRequest) (string, error) {
+		submitted = append(submitted, req.Symbol)
+		return "1", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending := restarted.Pending(); len(pending) != 1 || pending[0].Request.Symbol != "BTC-USD" {
+		t.Fatalf("pending after restart = %+v", pending)
+	}
+	clock.Advance(2 * time.Hour)
+	if len(submitted) != 1 || submitted[0] != "BTC-USD" {
+		t.Errorf("submitted %v", submitted)
+	}
+}
+
+func TestOrderSchedulerHoldsOrdersWhileLoggedOut(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduled.json")
+	clock := NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	loggedOn := false
+	var submitted []string
+	scheduler, err := NewOrderScheduler(path, time.Minute, clock, func(req OrderRequest) (string, error) {
+		if !loggedOn {
+			return "", ErrNotLoggedOn
+		}
+		submitted = append(submitted, req.Symbol)
+		return "1", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scheduler.Schedule(OrderRequest{Symbol: "ETH-USD"}, clock.Now().Add(time.Hour))
+	stale, _ := scheduler.Schedule(OrderRequest{Symbol: "BTC-USD"}, clock.Now().Add(time.Minute))
+	clock.Advance(time.Hour)
+
+	// Both came due while logged out, so both are still queued, and persisted
+	if pending := scheduler.Pending(); len(pending) != 2 || len(submitted) != 0 {
+		t.Fatalf("pending = %+v, submitted %v", pending, submitted)
+	}
+	reloaded, err := NewOrderScheduler(path, 2*time.Hour, NewFakeClock(clock.Now()), func(OrderRequest) (string, error) { return "", ErrNotLoggedOn })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending := reloaded.Pending(); len(pending) != 2 {
+		t.Fatalf("persisted = %+v", pending)
+	}
+
+	// A held order can still be canceled; on logon the one overdue by more
+	// than LateTolerance is dropped
+	scheduler.LateTolerance = 30 * time.Minute
+	if err := scheduler.Cancel(stale); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.Schedule(OrderRequest{Symbol: "SOL-USD"}, clock.Now().Add(-time.Hour))
+	clock.Advance(0)
+	loggedOn = true
+	scheduler.Resume()
+	if len(submitted) != 1 || submitted[0] != "ETH-USD" {
+		t.Errorf("submitted %v, want ETH-USD", submitted)
+	}
+	if pending := scheduler.Pending(); len(pending) != 0 {
+		t.Errorf("pending after resume = %+v", pending)
+	}
+}
+
+func TestOrderSchedulerKeepsStateWhenPersistFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "scheduled.json")
+	clock := NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	var submitted []string
+	scheduler, err := NewOrderScheduler(path, time.Minute, clock, func(req OrderRequest) (string, error) {
+		submitted = append(submitted, req.Symbol)
+		return "1", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, err := scheduler.Schedule(OrderRequest{Symbol: "ETH-USD"}, clock.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// With the file's temporary path blocked, nothing can be persisted
+	if err := os.Mkdir(path+".tmp", 0700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scheduler.Schedule(OrderRequest{Symbol: "BTC-USD"}, clock.Now().Add(time.Hour)); err == nil {
+		t.Fatal("Schedule succeeded without persisting")
+	}
+	if err := scheduler.Cancel(kept); err == nil {
+		t.Fatal("Cancel succeeded without persisting")
+	}
+	if pending := scheduler.Pending(); len(pending) != 1 || pending[0].Id != kept {
+		t.Fatalf("pending = %+v, want only %s", pending, kept)
+	}
+	clock.Advance(2 * time.Hour)
+	if len(submitted) != 1 || submitted[0] != "ETH-USD" {
+		t.Errorf("submitted %v, want only ETH-USD", submitted)
+	}
+}
+
+func TestAdminScheduledOrders(t *testing.T) {
+	clock := NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	scheduler, err := NewOrderScheduler("", time.Minute, clock, func(req OrderRequest) (string, error) { return "1", nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := scheduler.Schedule(OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "100"}, clock.Now().Add(time.Hour))
+	manager := NewManager()
+	for _, tenant := range []*Tenant{{Name: "desk", App: &FixApplication{Scheduler: scheduler}}, {Name: "other", App: &FixApplication{}}} {
+		if err := manager.Add(tenant); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := NewAdminHandler(manager)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/scheduled", nil))
+	var scheduled map[string][]ScheduledOrder
+	if err := json.Unmarshal(rec.Body.Bytes(), &scheduled); err != nil {
+		t.Fatal(err, rec.Body.String())
+	}
+	if len(scheduled["desk"]) != 1 || scheduled["desk"][0].Id != id || scheduled["desk"][0].Request.LimitPrice != "100" {
+		t.Errorf("scheduled = %+v", scheduled)
+	}
+
+	for _, tc := range []struct {
+		query  string
+		status int
+	}{
+		{"", http.StatusBadRequest},
+		{"?id=" + id + "&tenant=nope", http.StatusBadRequest},
+		{"?id=" + id + "&tenant=other", http.StatusNotFound},
+		{"?id=" + id, http.StatusOK},
+		{"?id=" + id, http.StatusNotFound},
+	} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/scheduled/cancel"+tc.query, nil))
+		if rec.Code != tc.status {
+			t.Errorf("cancel%s answered %d, want %d: %s", tc.query, rec.Code, tc.status, rec.Body.String())
+		}
+	}
+	if pending := scheduler.Pending(); len(pending) != 0 {
+		t.Errorf("pending after cancel = %+v", pending)
+	}
+}
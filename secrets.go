@@ -0,0 +1,181 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// encryptedPrefix marks a config or environment value as an encrypted secret
+const encryptedPrefix = "enc:v1:"
+
+// SecretDecrypter decrypts secret values found in the config. The built-in
+// implementation uses a local AES key; a KMS or HSM backed one can be
+// installed with SetSecretDecrypter.
+type SecretDecrypter interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+var secretDecrypter SecretDecrypter
+
+// SetSecretDecrypter replaces the decrypter used for encrypted config values
+func SetSecretDecrypter(decrypter SecretDecrypter) {
+	secretDecrypter = decrypter
+}
+
+// AESDecrypter decrypts AES-256-GCM secrets, stored as nonce followed by ciphertext
+type AESDecrypter struct {
+	aead cipher.AEAD
+}
+
+// NewAESDecrypter creates a decrypter for the 32 byte key
+func NewAESDecrypter(key []byte) (*AESDecrypter, error) {
+	aead, err := newSecretAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &AESDecrypter{aead: aead}, nil
+}
+
+func (d *AESDecrypter) Decrypt(ciphertext []byte) ([]byte, error) {
+	size := d.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, errors.New("encrypted secret is too short")
+	}
+	return d.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
+
+// EncryptSecret encrypts plaintext under the 32 byte key, returning a value
+// that can be pasted into the config in place of the plaintext
+func EncryptSecret(key, plaintext []byte) (string, error) {
+	aead, err := newSecretAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func newSecretAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.New("secret key must be 32 bytes")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// secretKeyFromEnv reads the base64 AES key from PRIMEFIX_SECRET_KEY, or from
+// the file named by PRIMEFIX_SECRET_KEY_FILE
+func secretKeyFromEnv() ([]byte, error) {
+	encoded, ok := os.LookupEnv(envPrefix + "SECRET_KEY")
+	if !ok {
+		path, ok := os.LookupEnv(envPrefix + "SECRET_KEY_FILE")
+		if !ok {
+			return nil, fmt.Errorf("encrypted secrets need %sSECRET_KEY or %sSECRET_KEY_FILE", envPrefix, envPrefix)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(data)
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+}
+
+// credentialSecrets are left encrypted in the settings and only decrypted by
+// credentialBytes, so their plaintext never passes through a string
+var credentialSecrets = map[string]bool{
+	"SigningKey": true,
+	"Passphrase": true,
+}
+
+// resolveSecret returns value, decrypting it first if it is an encrypted secret
+func resolveSecret(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	plaintext, err := resolveSecretBytes(value)
+	if err != nil {
+		return "", err
+	}
+	defer clear(plaintext)
+	return string(plaintext), nil
+}
+
+// resolveSecretBytes is resolveSecret returning a plaintext the caller owns
+// and can zero
+func resolveSecretBytes(value string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return []byte(value), nil
+	}
+
+	if secretDecrypter == nil {
+		key, err := secretKeyFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		secretDecrypter, err = NewAESDecrypter(key)
+		clear(key) // the cipher keeps its own expanded key
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encrypted secret: %w", err)
+	}
+	plaintext, err := secretDecrypter.Decrypt(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return plaintext, nil
+}
+
+// decryptConfigSecrets decrypts every encrypted value in config text
+func decryptConfigSecrets(config string) (string, error) {
+	if !strings.Contains(config, encryptedPrefix) {
+		return config, nil
+	}
+
+	lines := strings.Split(config, "\n")
+	for i, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || !strings.HasPrefix(strings.TrimSpace(value), encryptedPrefix) || credentialSecrets[strings.TrimSpace(key)] {
+			continue
+		}
+		plaintext, err := resolveSecret(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", strings.TrimSpace(key), err)
+		}
+		lines[i] = key + "=" + plaintext
+	}
+	return strings.Join(lines, "\n"), nil
+}
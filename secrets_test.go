@@ -0,0 +1,153 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useSecretKey installs key as the environment's secret key, restoring the
+// lazily created decrypter after the test
+func useSecretKey(t *testing.T, key []byte) {
+	t.Setenv(envPrefix+"SECRET_KEY", base64.StdEncoding.EncodeToString(key))
+	SetSecretDecrypter(nil)
+	t.Cleanup(func() { SetSecretDecrypter(nil) })
+}
+
+func TestResolveSecret(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	useSecretKey(t, key)
+	sealed, err := EncryptSecret(key, []byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, encryptedPrefix) || strings.Contains(sealed, "hunter2") {
+		t.Fatalf("EncryptSecret = %q", sealed)
+	}
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{sealed, "hunter2", false},
+		{"plaintext", "plaintext", false},
+		{encryptedPrefix + "not base64!", "", true},
+		{encryptedPrefix + base64.StdEncoding.EncodeToString([]byte("short")), "", true},
+		{sealed[:len(sealed)-4] + "AAAA", "", true}, // tampered
+	}
+	for _, tt := range tests {
+		got, err := resolveSecret(tt.value)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("resolveSecret(%q) = %q, %v", tt.value, got, err)
+		}
+	}
+}
+
+func TestResolveSecretKeySources(t *testing.T) {
+	key := bytes.Repeat([]byte{9}, 32)
+	sealed, err := EncryptSecret(key, []byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("key file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret.key")
+		if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(envPrefix+"SECRET_KEY_FILE", path)
+		SetSecretDecrypter(nil)
+		t.Cleanup(func() { SetSecretDecrypter(nil) })
+
+		if got, err := resolveSecret(sealed); got != "hunter2" || err != nil {
+			t.Fatalf("resolveSecret = %q, %v", got, err)
+		}
+	})
+	t.Run("wrong key", func(t *testing.T) {
+		useSecretKey(t, bytes.Repeat([]byte{1}, 32))
+		if _, err := resolveSecret(sealed); err == nil {
+			t.Fatal("decrypted under the wrong key")
+		}
+	})
+	t.Run("short key", func(t *testing.T) {
+		useSecretKey(t, []byte("too short"))
+		if _, err := resolveSecret(sealed); err == nil {
+			t.Fatal("accepted a short key")
+		}
+	})
+	t.Run("no key", func(t *testing.T) {
+		for _, env := range []string{envPrefix + "SECRET_KEY", envPrefix + "SECRET_KEY_FILE"} {
+			t.Setenv(env, "") // restored after the test
+			os.Unsetenv(env)
+		}
+		SetSecretDecrypter(nil)
+		if _, err := resolveSecret(sealed); err == nil || !strings.Contains(err.Error(), "SECRET_KEY") {
+			t.Fatalf("resolveSecret = %v, want a missing key error", err)
+		}
+	})
+}
+
+// reverseDecrypter stands in for a KMS backed SecretDecrypter
+type reverseDecrypter struct{}
+
+func (reverseDecrypter) Decrypt(ciphertext []byte) ([]byte, error) {
+	plaintext := bytes.Clone(ciphertext)
+	for i, j := 0, len(plaintext)-1; i < j; i, j = i+1, j-1 {
+		plaintext[i], plaintext[j] = plaintext[j], plaintext[i]
+	}
+	return plaintext, nil
+}
+
+func TestDecryptConfigSecrets(t *testing.T) {
+	SetSecretDecrypter(reverseDecrypter{})
+	t.Cleanup(func() { SetSecretDecrypter(nil) })
+	sealed := func(plaintext string) string {
+		reversed, _ := reverseDecrypter{}.Decrypt([]byte(plaintext))
+		return encryptedPrefix + base64.StdEncoding.EncodeToString(reversed)
+	}
+
+	config := strings.Join([]string{
+		"[SESSION]",
+		"AccessKey=" + sealed("access"),
+		"Passphrase=" + sealed("passphrase"),
+		"SigningKey = " + sealed("signing"),
+		"SenderCompID=plain",
+	}, "\n")
+	got, err := decryptConfigSecrets(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(got, "\n")
+	if lines[1] != "AccessKey=access" || lines[4] != "SenderCompID=plain" {
+		t.Fatalf("decrypted config:\n%s", got)
+	}
+	// Credentials stay encrypted until credentialBytes reads them
+	if lines[2] != "Passphrase="+sealed("passphrase") || !strings.Contains(lines[3], sealed("signing")) {
+		t.Fatalf("credentials decrypted into the config:\n%s", got)
+	}
+	if plaintext, err := resolveSecretBytes(sealed("signing")); string(plaintext) != "signing" || err != nil {
+		t.Fatalf("resolveSecretBytes = %q, %v", plaintext, err)
+	}
+
+	if _, err := decryptConfigSecrets("AccessKey=" + encryptedPrefix + "!"); err == nil || !strings.HasPrefix(err.Error(), "AccessKey") {
+		t.Fatalf("decryptConfigSecrets = %v, want an error naming the setting", err)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ErrSendQueueFull is returned when an order is refused because the send
+// queue already holds as many orders as it may
+var ErrSendQueueFull = errors.New("send queue is full")
+
+// SendQueue shapes outbound messages to at most Rate per second. While the
+// rate is used up, messages wait in two tiers: cancels always go out before
+// any waiting new order or replace, so a burst of orders can never hold up
+// pulling them. Only the lower tier is bounded.
+type SendQueue struct {
+	Rate  int
+	Depth int
+	Clock Clock
+
+	mu      sync.Mutex
+	tokens  float64
+	filled  time.Time
+	cancels []*queuedSend
+	orders  []*queuedSend
+	ready   []*queuedSend // let through by the rate, not sent yet
+	sending bool          // a caller is sending ready
+	timer   Timer
+}
+
+type queuedSend struct {
+	msg  *quickfix.Message
+	send func(msg *quickfix.Message) error
+	done chan error
+}
+
+// NewSendQueue creates a queue sending rate messages per second, keeping at
+// most depth orders waiting
+func NewSendQueue(rate, depth int, clock Clock) *SendQueue {
+	return &SendQueue{Rate: rate, Depth: depth, Clock: clockOrSystem(clock), tokens: float64(rate)}
+}
+
+// Send sends msg with send once the rate allows, waiting its turn, and
+// returns the error of send
+func (q *SendQueue) Send(msg *quickfix.Message, send func(msg *quickfix.Message) error) error {
+	item := &queuedSend{msg: msg, send: send, done: make(chan error, 1)}
+
+	q.mu.Lock()
+	if isCancel(msg) {
+		q.cancels = append(q.cancels, item)
+	} else if q.Depth > 0 && len(q.orders) >= q.Depth {
+		q.mu.Unlock()
+		return ErrSendQueueFull
+	} else {
+		q.orders = append(q.orders, item)
+	}
+	q.pump()
+	q.flush()
+
+	return <-item.done
+}
+
+// Pending returns how many cancels and other messages are waiting
+func (q *SendQueue) Pending() (cancels, orders int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.cancels), len(q.orders)
+}
+
+// pump lets waiting messages through to ready while there are tokens,
+// cancels first, and otherwise arms a timer for when the next token is due;
+// callers must hold mu
+func (q *SendQueue) pump() {
+	now := q.Clock.Now()
+	if !q.filled.IsZero() {
+		q.tokens += now.Sub(q.filled).Seconds() * float64(q.Rate)
+		q.tokens = min(q.tokens, float64(q.Rate))
+	}
+	q.filled = now
+
+	for q.tokens >= 1 {
+		var item *queuedSend
+		switch {
+		case len(q.cancels) > 0:
+			item, q.cancels = q.cancels[0], q.cancels[1:]
+		case len(q.orders) > 0:
+			item, q.orders = q.orders[0], q.orders[1:]
+		default:
+			return
+		}
+		q.tokens--
+		q.ready = append(q.ready, item)
+	}
+
+	if q.timer == nil && len(q.cancels)+len(q.orders) > 0 {
+		wait := time.Duration((1 - q.tokens) / float64(q.Rate) * float64(time.Second))
+		q.timer = q.Clock.AfterFunc(wait, func() {
+			q.mu.Lock()
+			q.timer = nil
+			q.pump()
+			q.flush()
+		})
+	}
+}
+
+// flush sends the ready messages without holding mu, which it is called
+// with and releases. Only one caller sends at a time, so messages still go
+// on the wire in the order they were let through.
+func (q *SendQueue) flush() {
+	if q.sending {
+		q.mu.Unlock()
+		return
+	}
+	q.sending = true
+	for len(q.ready) > 0 {
+		item := q.ready[0]
+		q.ready = q.ready[1:]
+		q.mu.Unlock()
+		item.done <- item.send(item.msg)
+		q.mu.Lock()
+	}
+	q.sending = false
+	q.mu.Unlock()
+}
+
+// isCancel reports whether msg pulls orders rather than adding to them
+func isCancel(msg *quickfix.Message) bool {
+	msgType, _ := msg.MsgType()
+	return msgType == "F" || msgType == "q"
+}
+
+// sendQueueSetting creates the send queue from MaxMessagesPerSecond and
+// SendQueueDepth
+func sendQueueSetting(settings *quickfix.SessionSettings, clock Clock) (*SendQueue, error) {
+	rate, err := settings.IntSetting("MaxMessagesPerSecond")
+	if err != nil {
+		return nil, err
+	}
+	if rate <= 0 {
+		return nil, fmt.Errorf("MaxMessagesPerSecond must be positive, got %d", rate)
+	}
+	depth := 100
+	if settings.HasSetting("SendQueueDepth") {
+		if depth, err = settings.IntSetting("SendQueueDepth"); err != nil {
+			return nil, err
+		}
+	}
+	return NewSendQueue(rate, depth, clock), nil
+}
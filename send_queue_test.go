@@ -0,0 +1,117 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestSendQueueCancelsPreemptOrders(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC))
+	queue := NewSendQueue(2, 2, clock)
+	var sent []string
+	send := func(msg *quickfix.Message) error {
+		clOrdID, _ := msg.Body.GetString(quickfix.Tag(11))
+		sent = append(sent, clOrdID)
+		return nil
+	}
+
+	// The first two go straight out, the next two wait and the fifth is refused
+	for _, id := range []string{"o1", "o2"} {
+		if err := queue.Send(paperOrderMessage("D", id, "1", "2", "1", "100"), send); err != nil {
+			t.Fatal(err)
+		}
+	}
+	errs := make(chan error, 3)
+	for i, id := range []string{"o3", "o4"} {
+		go func() { errs <- queue.Send(paperOrderMessage("D", id, "1", "2", "1", "100"), send) }()
+		waitPending(t, queue, 0, i+1)
+	}
+	if err := queue.Send(paperOrderMessage("D", "o5", "1", "2", "1", "100"), send); !errors.Is(err, ErrSendQueueFull) {
+		t.Fatalf("got %v, want ErrSendQueueFull", err)
+	}
+
+	// A cancel is never refused and goes ahead of the waiting orders
+	go func() { errs <- queue.Send(paperOrderMessage("F", "c1", "1", "2", "1", ""), send) }()
+	waitPending(t, queue, 1, 2)
+	clock.Advance(500 * time.Millisecond)
+	clock.Advance(time.Second)
+	for range 3 {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := []string{"o1", "o2", "c1", "o3", "o4"}; !slices.Equal(sent, want) {
+		t.Fatalf("sent %v, want %v", sent, want)
+	}
+}
+
+func TestSendQueueSendsOutsideItsLock(t *testing.T) {
+	queue := NewSendQueue(10, 1, NewFakeClock(time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)))
+	sending, release := make(chan string, 2), make(chan struct{})
+	send := func(msg *quickfix.Message) error {
+		clOrdID, _ := msg.Body.GetString(quickfix.Tag(11))
+		sending <- clOrdID
+		<-release
+		return nil
+	}
+
+	// While o1 is on the wire, o2 is queued behind it and the queue answers
+	errs := make(chan error, 2)
+	go func() { errs <- queue.Send(paperOrderMessage("D", "o1", "1", "2", "1", "100"), send) }()
+	if id := <-sending; id != "o1" {
+		t.Fatalf("sending %s, want o1", id)
+	}
+	go func() { errs <- queue.Send(paperOrderMessage("D", "o2", "1", "2", "1", "100"), send) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		queue.mu.Lock()
+		ready := len(queue.ready)
+		queue.mu.Unlock()
+		if ready == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("o2 was never let through while o1 was sending")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	close(release)
+	if id := <-sending; id != "o2" {
+		t.Fatalf("sending %s, want o2", id)
+	}
+	for range 2 {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func waitPending(t *testing.T, queue *SendQueue, cancels, orders int) {
+	t.Helper()
+	for range 1000 {
+		if c, o := queue.Pending(); c == cancels && o == orders {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("queue never held %d cancels and %d orders", cancels, orders)
+}
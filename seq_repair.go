@@ -0,0 +1,168 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/store/file"
+)
+
+// ErrSessionRunning is returned when offline maintenance would change the
+// files of a session that may be connected
+var ErrSessionRunning = errors.New("the FIX session appears to be running, stop the client first")
+
+// SeqRepair edits the sequence numbers of one session in its persistent file
+// store, replacing manual edits of the store files after a sequence desync.
+// It must only run while the client is stopped.
+type SeqRepair struct {
+	SessionID quickfix.SessionID
+	Settings  *quickfix.Settings
+	StorePath string // FileStorePath of the session
+}
+
+// NewSeqRepair prepares a repair of the tenant's session
+func NewSeqRepair(tenant TenantConfig) (*SeqRepair, error) {
+	for sessionID, settings := range tenant.Settings.SessionSettings() {
+		path, err := settings.Setting("FileStorePath")
+		if err != nil {
+			return nil, errors.New("FileStorePath is not set: sequence numbers are not persisted, so a restart resets them")
+		}
+		return &SeqRepair{SessionID: sessionID, Settings: tenant.Settings, StorePath: path}, nil
+	}
+	return nil, errors.New("no session configured")
+}
+
+// Current returns the next sender and target sequence numbers in the store
+func (r *SeqRepair) Current() (SeqNums, error) {
+	store, err := file.NewStoreFactory(r.Settings).Create(r.SessionID)
+	if err != nil {
+		return SeqNums{}, err
+	}
+	defer store.Close()
+	return SeqNums{NextSender: store.NextSenderMsgSeqNum(), NextTarget: store.NextTargetMsgSeqNum()}, nil
+}
+
+// CheckStopped returns ErrSessionRunning unless the session is known to be
+// down: the leader lock, when configured, must be free and the session stats,
+// when saved, must not show a live logon
+func (r *SeqRepair) CheckStopped(now time.Time) error {
+	return checkStopped(r.Settings, now)
+}
+
+// checkStopped returns ErrSessionRunning unless the client configured by
+// settings is known to be stopped, see SeqRepair.CheckStopped
+func checkStopped(settings *quickfix.Settings, now time.Time) error {
+	global := settings.GlobalSettings()
+	if path, err := global.Setting("LeaderLockPath"); err == nil {
+		lock, err := NewFileLock(path)
+		if err != nil {
+			return err
+		}
+		acquired, err := lock.TryAcquire()
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			return fmt.Errorf("%w: %s is locked", ErrSessionRunning, path)
+		}
+		defer lock.Release()
+	}
+	if path, err := global.Setting("SessionStatsPath"); err == nil {
+		interval, err := global.DurationSetting("SessionStatsInterval")
+		if err != nil {
+			interval = 10 * time.Second
+		}
+		stats, err := LoadSessionStats(path)
+		if err == nil && stats.LoggedOn && now.Sub(stats.Time) < 3*interval {
+			return fmt.Errorf("%w: logged on as of %s", ErrSessionRunning, stats.Time.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// Apply backs up the sequence number files and sets the next target (in) and
+// sender (out) sequence numbers; a zero value is left unchanged
+func (r *SeqRepair) Apply(in, out int, now time.Time) error {
+	prefix := filepath.Join(r.StorePath, seqStorePrefix(r.SessionID))
+	suffix := ".bak-" + now.UTC().Format("20060102T150405")
+	for _, path := range []string{prefix + ".senderseqnums", prefix + ".targetseqnums"} {
+		if err := copyFile(path, path+suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("backup %s: %w", path, err)
+		}
+	}
+
+	store, err := file.NewStoreFactory(r.Settings).Create(r.SessionID)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if in > 0 {
+		if err := store.SetNextTargetMsgSeqNum(in); err != nil {
+			return err
+		}
+	}
+	if out > 0 {
+		if err := store.SetNextSenderMsgSeqNum(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seqStorePrefix is the file name prefix the quickfix file store uses for a session
+func seqStorePrefix(id quickfix.SessionID) string {
+	prefix := id.BeginString + "-" + id.SenderCompID
+	if id.SenderSubID != "" {
+		prefix += "_" + id.SenderSubID
+	}
+	if id.SenderLocationID != "" {
+		prefix += "_" + id.SenderLocationID
+	}
+	prefix += "-" + id.TargetCompID
+	if id.TargetSubID != "" {
+		prefix += "_" + id.TargetSubID
+	}
+	if id.TargetLocationID != "" {
+		prefix += "_" + id.TargetLocationID
+	}
+	if id.Qualifier != "" {
+		prefix += "-" + id.Qualifier
+	}
+	return prefix
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,160 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSeqRepairConfig writes a one session config storing its sequence
+// numbers under dir, with extra lines in the defaults
+func writeSeqRepairConfig(t *testing.T, dir, extra string) string {
+	path := filepath.Join(dir, "fix.cfg")
+	config := fmt.Sprintf("[DEFAULT]\nConnectionType=initiator\nFileStorePath=%s\n%s\n[SESSION]\nBeginString=FIXT.1.1\nSenderCompID=CLIENT\nTargetCompID=PRIME\n",
+		filepath.Join(dir, "store"), extra)
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadSeqRepair(t *testing.T, path string) *SeqRepair {
+	tenants, err := LoadTenantConfigs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repair, err := NewSeqRepair(tenants[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repair
+}
+
+func TestSeqRepairApply(t *testing.T) {
+	dir := t.TempDir()
+	repair := loadSeqRepair(t, writeSeqRepairConfig(t, dir, ""))
+	if current, err := repair.Current(); err != nil || current != (SeqNums{NextSender: 1, NextTarget: 1}) {
+		t.Fatalf("Current() = %+v, %v", current, err)
+	}
+
+	prefix := filepath.Join(dir, "store", "FIXT.1.1-CLIENT-PRIME")
+	before, err := os.ReadFile(prefix + ".targetseqnums")
+	if err != nil {
+		t.Fatalf("store files not where the backup looks for them: %v", err)
+	}
+	now := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	if err := repair.Apply(42, 7, now); err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := repair.Current(); current != (SeqNums{NextSender: 7, NextTarget: 42}) {
+		t.Fatalf("after Apply(42, 7) Current() = %+v", current)
+	}
+	for _, file := range []string{".senderseqnums", ".targetseqnums"} {
+		backup, err := os.ReadFile(prefix + file + ".bak-20250602T143000")
+		if err != nil {
+			t.Fatalf("no backup of %s: %v", file, err)
+		}
+		if file == ".targetseqnums" && string(backup) != string(before) {
+			t.Fatalf("backup %q, want the numbers before the repair %q", backup, before)
+		}
+	}
+
+	// Zero leaves a number unchanged
+	if err := repair.Apply(0, 9, now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := repair.Current(); current != (SeqNums{NextSender: 9, NextTarget: 42}) {
+		t.Fatalf("after Apply(0, 9) Current() = %+v", current)
+	}
+}
+
+func TestSeqRepairRequiresFileStore(t *testing.T) {
+	tenants, err := splitTenantConfigs("[DEFAULT]\nConnectionType=initiator\n[SESSION]\nBeginString=FIXT.1.1\nSenderCompID=CLIENT\nTargetCompID=PRIME\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSeqRepair(tenants[0]); err == nil {
+		t.Fatal("repair of a session without FileStorePath")
+	}
+}
+
+func TestSeqRepairRefusesRunningClient(t *testing.T) {
+	now := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		stats   *SessionStats
+		running bool
+	}{
+		{"no stats", nil, false},
+		{"logged on", &SessionStats{LoggedOn: true, Time: now.Add(-5 * time.Second)}, true},
+		{"logged out", &SessionStats{Time: now.Add(-5 * time.Second)}, false},
+		{"stale stats of a crashed client", &SessionStats{LoggedOn: true, Time: now.Add(-time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			statsPath := filepath.Join(dir, "stats.json")
+			if tt.stats != nil {
+				if err := SaveSessionStats(statsPath, *tt.stats); err != nil {
+					t.Fatal(err)
+				}
+			}
+			repair := loadSeqRepair(t, writeSeqRepairConfig(t, dir, "SessionStatsPath="+statsPath+"\nSessionStatsInterval=10s"))
+
+			err := repair.CheckStopped(now)
+			if errors.Is(err, ErrSessionRunning) != tt.running {
+				t.Fatalf("CheckStopped = %v, want running %t", err, tt.running)
+			}
+		})
+	}
+}
+
+func TestSeqRepairRefusesWhileLeaderLockHeld(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, "leader.lock")
+	leader, _ := NewFileLock(lockPath)
+	if acquired, err := leader.TryAcquire(); err != nil || !acquired {
+		t.Skip("file locks are not supported here")
+	}
+	repair := loadSeqRepair(t, writeSeqRepairConfig(t, dir, "LeaderLockPath="+lockPath))
+
+	if err := repair.CheckStopped(time.Now()); !errors.Is(err, ErrSessionRunning) {
+		t.Fatalf("CheckStopped = %v while the leader holds the lock", err)
+	}
+	leader.Release()
+	if err := repair.CheckStopped(time.Now()); err != nil {
+		t.Fatalf("CheckStopped = %v once the lock is free", err)
+	}
+}
+
+func TestRunResetSeq(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSeqRepairConfig(t, dir, "")
+
+	if code := runResetSeq([]string{"-config", path, "-in", "5", "-out", "12", "-yes"}); code != 0 {
+		t.Fatalf("reset-seq exited %d", code)
+	}
+	if current, _ := loadSeqRepair(t, path).Current(); current != (SeqNums{NextSender: 12, NextTarget: 5}) {
+		t.Fatalf("store numbers %+v after reset-seq -in 5 -out 12", current)
+	}
+	if code := runResetSeq([]string{"-config", path, "-in", "-1"}); code != 2 {
+		t.Fatalf("reset-seq with a negative number exited %d", code)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// exitUnrecoverable is the exit status for failures a restart cannot fix,
+// such as a credential lockout, so a service manager can be told not to
+// restart on it (EX_CONFIG)
+const exitUnrecoverable = 78
+
+// fatalUnrecoverable logs err and exits with exitUnrecoverable
+func fatalUnrecoverable(err error) {
+	log.Println(err)
+	os.Exit(exitUnrecoverable)
+}
+
+// RunService starts every tenant of manager and runs until SIGTERM, SIGINT
+// or quit is closed, then stops them, saving their snapshots. Under systemd it reports
+// readiness once the sessions are started, pings the watchdog and keeps the
+// unit status showing how many sessions are logged on.
+func RunService(manager *Manager, quit <-chan struct{}) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	if err := manager.StartAll(); err != nil {
+		return err
+	}
+	sdNotify("READY=1\nSTATUS=" + serviceStatus(manager))
+
+	// Ping at half the watchdog interval, as sd_watchdog_enabled(3) advises
+	interval := 10 * time.Second
+	watchdog := watchdogInterval()
+	if watchdog > 0 {
+		interval = watchdog / 2
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			state := "STATUS=" + serviceStatus(manager)
+			if watchdog > 0 {
+				state = "WATCHDOG=1\n" + state
+			}
+			sdNotify(state)
+		case sig := <-signals:
+			log.Printf("Received %s, stopping", sig)
+			sdNotify("STOPPING=1\nSTATUS=Stopping")
+			return manager.StopAll()
+		case <-quit:
+			log.Println("Quit, stopping")
+			sdNotify("STOPPING=1\nSTATUS=Stopping")
+			return manager.StopAll()
+		}
+	}
+}
+
+// serviceStatus summarises the sessions for the unit status
+func serviceStatus(manager *Manager) string {
+	tenants := manager.Tenants()
+	loggedOn := 0
+	for _, tenant := range tenants {
+		if tenant.App.IsLoggedOn() || tenant.App.Paper != nil {
+			loggedOn++
+		}
+	}
+	return fmt.Sprintf("%d of %d sessions logged on", loggedOn, len(tenants))
+}
+
+// sdNotify sends state to the service manager over NOTIFY_SOCKET, see
+// sd_notify(3); without a socket it does nothing
+func sdNotify(state string) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return
+	}
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:] // abstract namespace
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		log.Println("Failed to notify the service manager:", err)
+		return
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(state)); err != nil {
+		log.Println("Failed to notify the service manager:", err)
+	}
+}
+
+// watchdogInterval returns how often the service manager expects a watchdog
+// ping, zero when the watchdog is off or meant for another process
+func watchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// logToJournal drops the log timestamps when stderr goes to the systemd
+// journal, which stamps every line itself
+func logToJournal() {
+	if stderrIsJournal() {
+		log.SetFlags(0)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		usec string
+		pid  string
+		want time.Duration
+	}{
+		{"this process", "30000000", strconv.Itoa(os.Getpid()), 30 * time.Second},
+		{"no pid", "2000000", "", 2 * time.Second},
+		{"another process", "30000000", "1", 0},
+		{"off", "", "", 0},
+		{"malformed", "soon", "", 0},
+		{"zero", "0", "", 0},
+	}
+	for _, tt := range tests {
+		t.Setenv("WATCHDOG_USEC", tt.usec)
+		t.Setenv("WATCHDOG_PID", tt.pid)
+		if got := watchdogInterval(); got != tt.want {
+			t.Errorf("%s: watchdogInterval = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunServiceNotifies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify")
+	socket, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skip("unix datagram sockets unavailable:", err)
+	}
+	defer socket.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+	t.Setenv("WATCHDOG_USEC", "")
+
+	quit := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- RunService(NewManager(), quit) }()
+
+	for _, want := range []string{"READY=1\nSTATUS=0 of 0 sessions logged on", "STOPPING=1\nSTATUS=Stopping"} {
+		socket.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, 256)
+		n, err := socket.Read(buf)
+		if err != nil {
+			t.Fatalf("waiting for %q: %v", want, err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Errorf("notified %q, want %q", got, want)
+		}
+		if strings.HasPrefix(want, "READY") {
+			close(quit)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Errorf("RunService = %v", err)
+	}
+
+	// An unreachable service manager is logged, not fatal
+	t.Setenv("NOTIFY_SOCKET", filepath.Join(t.TempDir(), "missing"))
+	sdNotify("READY=1")
+}
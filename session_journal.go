@@ -0,0 +1,166 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Session lifecycle events recorded in a SessionJournal
+const (
+	SessionCreated       = "create"
+	SessionLogon         = "logon"
+	SessionReconnect     = "reconnect" // a logon after the first
+	SessionLogout        = "logout"
+	SessionLogonRejected = "logon-rejected"
+	SessionGapDetected   = "gap"              // we asked Prime to resend
+	SessionResendRequest = "resend-requested" // Prime asked us to resend
+	SessionSequenceReset = "sequence-reset"
+	SymbolHalted         = "symbol-halted"  // VenueStatus blocked a symbol
+	SymbolResumed        = "symbol-resumed" // and cleared it again
+)
+
+// SessionEvent is one lifecycle event of a FIX session
+type SessionEvent struct {
+	Time    time.Time `json:"time"`
+	Tenant  string    `json:"tenant,omitempty"`
+	Session string    `json:"session"`
+	Event   string    `json:"event"`
+	Detail  string    `json:"detail,omitempty"`
+
+	// Sequence numbers the session expected next when the event happened,
+	// zero when unknown
+	NextSenderMsgSeqNum int `json:"next_sender_seq,omitempty"`
+	NextTargetMsgSeqNum int `json:"next_target_seq,omitempty"`
+}
+
+// SessionJournal appends session lifecycle events to a JSON lines file for
+// `session timeline`
+type SessionJournal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenSessionJournal opens or creates the journal at path
+func OpenSessionJournal(path string) (*SessionJournal, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionJournal{file: file}, nil
+}
+
+// Record appends event to the journal
+func (j *SessionJournal) Record(event SessionEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the underlying file
+func (j *SessionJournal) Close() error {
+	return j.file.Close()
+}
+
+// journal records event for sessionId in the session journal, if there is one
+func (a *FixApplication) journal(sessionId quickfix.SessionID, event, detail string) {
+	if a.Journal == nil {
+		return
+	}
+	entry := SessionEvent{
+		Time:    a.now().UTC(),
+		Tenant:  a.Tenant,
+		Session: sessionId.String(),
+		Event:   event,
+		Detail:  detail,
+	}
+	entry.NextSenderMsgSeqNum, _ = quickfix.GetExpectedSenderNum(sessionId)
+	entry.NextTargetMsgSeqNum, _ = quickfix.GetExpectedTargetNum(sessionId)
+	if err := a.Journal.Record(entry); err != nil {
+		a.logger().Println("Failed to journal session event:", err)
+	}
+}
+
+// resendRange describes the range a ResendRequest asks for
+func resendRange(msg *quickfix.Message) string {
+	return "BeginSeqNo=" + bodyString(msg, quickfix.Tag(7)) + " EndSeqNo=" + bodyString(msg, quickfix.Tag(16))
+}
+
+// ReadSessionJournal returns the events in the journal at path from since up
+// to until, oldest first; zero times leave that end open
+func ReadSessionJournal(path string, since, until time.Time) ([]SessionEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var events []SessionEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event SessionEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, err
+		}
+		if !since.IsZero() && event.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !event.Time.Before(until) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// WriteSessionTimeline prints events one per line with the time since the
+// event before it in the same session
+func WriteSessionTimeline(w io.Writer, events []SessionEvent) {
+	last := make(map[string]time.Time)
+	for _, event := range events {
+		key := event.Tenant + "/" + event.Session
+		gap := "-"
+		if previous, ok := last[key]; ok {
+			gap = "+" + event.Time.Sub(previous).Round(time.Millisecond).String()
+		}
+		last[key] = event.Time
+
+		session := event.Session
+		if event.Tenant != "" {
+			session = event.Tenant + " " + session
+		}
+		fmt.Fprintf(w, "%s %-10s %-16s %s seq=%d/%d",
+			event.Time.Format(time.RFC3339Nano), gap, event.Event, session,
+			event.NextSenderMsgSeqNum, event.NextTargetMsgSeqNum)
+		if event.Detail != "" {
+			fmt.Fprintf(w, " %s", event.Detail)
+		}
+		fmt.Fprintln(w)
+	}
+}
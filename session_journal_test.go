@@ -0,0 +1,87 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestSessionJournalTimeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := OpenSessionJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+
+	clock := NewFakeClock(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+	app := &FixApplication{Tenant: "desk", Clock: clock, Journal: journal}
+	id := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "COIN"}
+
+	app.OnCreate(id)
+	clock.Advance(time.Second)
+	app.OnLogon(id)
+	clock.Advance(time.Minute)
+	app.OnLogout(id)
+	clock.Advance(time.Minute)
+	app.OnLogon(id)
+	clock.Advance(time.Minute)
+
+	resend := quickfix.NewMessage()
+	resend.Header.SetString(quickfix.Tag(35), "2")
+	resend.Body.SetInt(quickfix.Tag(7), 10)
+	resend.Body.SetInt(quickfix.Tag(16), 0)
+	app.ToAdmin(resend, id)
+
+	events, err := ReadSessionJournal(path, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, event := range events {
+		got = append(got, event.Event)
+	}
+	want := []string{SessionCreated, SessionLogon, SessionLogout, SessionReconnect, SessionGapDetected}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	if events[4].Detail != "BeginSeqNo=10 EndSeqNo=0" || events[4].Tenant != "desk" || events[4].Session != id.String() {
+		t.Errorf("gap event = %+v", events[4])
+	}
+
+	// The period is half-open: from the first logout up to the reconnect
+	events, err = ReadSessionJournal(path, time.Date(2024, 5, 1, 9, 1, 1, 0, time.UTC), time.Date(2024, 5, 1, 9, 2, 1, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Event != SessionLogout {
+		t.Fatalf("events in period = %+v", events)
+	}
+
+	var timeline strings.Builder
+	WriteSessionTimeline(&timeline, []SessionEvent{
+		{Time: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), Tenant: "desk", Session: "S", Event: SessionLogon},
+		{Time: time.Date(2024, 5, 1, 9, 5, 0, 0, time.UTC), Tenant: "desk", Session: "S", Event: SessionLogout, Detail: "maintenance"},
+	})
+	lines := strings.Split(strings.TrimSpace(timeline.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], " - ") || !strings.Contains(lines[1], "+5m0s") || !strings.HasSuffix(lines[1], "maintenance") {
+		t.Errorf("timeline:\n%s", timeline.String())
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// ErrNotLoggedOn is returned when sending while the FIX session is not logged on
+var ErrNotLoggedOn = errors.New("fix session is not logged on")
+
+// ErrAmbiguousSend is returned for an order whose send failed after the
+// message may have been written: it may yet reach the venue, and must not
+// be resubmitted until an OrderStatusRequest has settled its state
+var ErrAmbiguousSend = errors.New("order may have been sent")
+
+// sessionState guards the session identity and logon status, which are written
+// from quickfix callback goroutines and read by callers sending messages
+type sessionState struct {
+	mu       sync.RWMutex
+	id       quickfix.SessionID
+	loggedOn bool
+}
+
+func (s *sessionState) setId(id quickfix.SessionID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.id = id
+}
+
+func (s *sessionState) setLoggedOn(id quickfix.SessionID, loggedOn bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.id = id
+	s.loggedOn = loggedOn
+}
+
+func (s *sessionState) get() (quickfix.SessionID, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.id, s.loggedOn
+}
+
+// SessionID returns the current FIX session ID
+func (a *FixApplication) SessionID() quickfix.SessionID {
+	id, _ := a.session.get()
+	return id
+}
+
+// IsLoggedOn reports whether the FIX session is currently logged on
+func (a *FixApplication) IsLoggedOn() bool {
+	_, loggedOn := a.session.get()
+	return loggedOn
+}
+
+// Send sends msg on the current session, failing fast with ErrNotLoggedOn
+// instead of queueing it while the session is down. With a SendQueue, msg
+// waits for its turn under the rate limit first.
+func (a *FixApplication) Send(msg quickfix.Messagable) error {
+	if a.Paper != nil {
+		return a.Paper.Submit(msg.ToMessage())
+	}
+	if _, loggedOn := a.session.get(); !loggedOn {
+		return ErrNotLoggedOn
+	}
+	if a.SendQueue != nil {
+		return a.SendQueue.Send(msg.ToMessage(), a.sendNow)
+	}
+	return a.sendNow(msg.ToMessage())
+}
+
+// sendNow sends msg on the session as it is now, which may have gone down
+// while msg waited in the SendQueue. A failure after which msg may still
+// reach the venue is wrapped in ErrAmbiguousSend.
+func (a *FixApplication) sendNow(msg *quickfix.Message) error {
+	id, loggedOn := a.session.get()
+	if !loggedOn {
+		return ErrNotLoggedOn
+	}
+	// Reused messages, like the order builder's, keep the MsgSeqNum of their
+	// previous send, which would make any failure look ambiguous
+	msg.Header.Remove(quickfix.Tag(34))
+	if err := quickfix.SendToTarget(msg, id); err != nil {
+		if sendAmbiguous(msg, err) {
+			return fmt.Errorf("%w: %v", ErrAmbiguousSend, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// sendAmbiguous reports whether msg, which failed to send with err, may
+// still reach the venue. The session numbers a message just before handing it
+// to ToApp and persisting it, so a numbered message that ToApp did not veto
+// may be in the store, from where a resend would deliver it. msg must not
+// have been numbered before the send.
+func sendAmbiguous(msg *quickfix.Message, err error) bool {
+	return msg.Header.Has(quickfix.Tag(34)) && !errors.Is(err, quickfix.ErrDoNotSend)
+}
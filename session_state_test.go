@@ -0,0 +1,133 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func TestSendBeforeLogon(t *testing.T) {
+	app := &FixApplication{}
+	if err := app.Send(quickfix.NewMessage()); !errors.Is(err, ErrNotLoggedOn) {
+		t.Fatalf("expected ErrNotLoggedOn, got %v", err)
+	}
+}
+
+func TestSessionStateTransitions(t *testing.T) {
+	app := &FixApplication{}
+	id := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "COIN"}
+
+	app.OnCreate(id)
+	if app.SessionID() != id || app.IsLoggedOn() {
+		t.Fatalf("after create: id=%v loggedOn=%v", app.SessionID(), app.IsLoggedOn())
+	}
+
+	app.OnLogon(id)
+	if !app.IsLoggedOn() {
+		t.Fatal("expected logged on after OnLogon")
+	}
+
+	app.OnLogout(id)
+	if app.IsLoggedOn() {
+		t.Fatal("expected logged out after OnLogout")
+	}
+}
+
+// TestSessionStateConcurrency is meant to be run with -race
+func TestSessionStateConcurrency(t *testing.T) {
+	app := &FixApplication{}
+	id := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "COIN"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			app.OnCreate(id)
+		}()
+		go func() {
+			defer wg.Done()
+			app.OnLogon(id)
+		}()
+		go func() {
+			defer wg.Done()
+			app.OnLogout(id)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = app.SessionID()
+			_ = app.IsLoggedOn()
+			_ = app.Send(quickfix.NewMessage())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSendAmbiguous(t *testing.T) {
+	unnumbered := quickfix.NewMessage()
+	numbered := quickfix.NewMessage()
+	numbered.Header.SetInt(quickfix.Tag(34), 7)
+	storeErr := errors.New("disk full")
+
+	tests := []struct {
+		name string
+		msg  *quickfix.Message
+		err  error
+		want bool
+	}{
+		{"not logged on", unnumbered, ErrNotLoggedOn, false},
+		{"vetoed by ToApp", numbered, quickfix.ErrDoNotSend, false},
+		{"failed to persist", numbered, storeErr, true},
+	}
+	for _, tt := range tests {
+		if got := sendAmbiguous(tt.msg, tt.err); got != tt.want {
+			t.Errorf("%s: sendAmbiguous = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+// An order builder reuses its message, which keeps the MsgSeqNum of its last
+// send; a send refused after logout must not be taken for an ambiguous one
+func TestSendAfterLogoutIsNotAmbiguous(t *testing.T) {
+	app := &FixApplication{Tracker: NewOrderTracker(time.Minute), TargetCompId: "COIN"}
+	id := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "SENDER", TargetCompID: "COIN"}
+	app.OnCreate(id)
+	app.OnLogon(id)
+	req := OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "100"}
+	app.PlaceOrder(req)
+	app.builder.msg.Header.SetInt(quickfix.Tag(34), 7) // as numbered by a successful send
+
+	app.OnLogout(id)
+	clOrdID, err := app.PlaceOrder(req)
+	if !errors.Is(err, ErrNotLoggedOn) || errors.Is(err, ErrAmbiguousSend) {
+		t.Fatalf("err = %v, want ErrNotLoggedOn", err)
+	}
+	if _, ok := app.Tracker.Get(clOrdID); ok {
+		t.Error("refused order kept in the tracker")
+	}
+}
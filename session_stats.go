@@ -0,0 +1,212 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// SessionStats is a point-in-time view of the FIX session's health
+type SessionStats struct {
+	Tenant    string `json:",omitempty"`
+	SessionID string
+	LoggedOn  bool
+
+	// Version is the version of the client binary
+	Version string `json:",omitempty"`
+
+	// StartedAt is when the application was created and LoggedOnAt when the
+	// current logon completed, zero while logged out
+	StartedAt  time.Time
+	LoggedOnAt time.Time
+
+	MessagesIn  uint64
+	MessagesOut uint64
+
+	// Sequence numbers the session expects next, zero when there is no session
+	NextSenderMsgSeqNum int
+	NextTargetMsgSeqNum int
+
+	// Logons counts completed logons; every one after the first is a reconnect
+	Logons     int
+	Reconnects int
+
+	LastReceived  time.Time
+	LastHeartbeat time.Time // last Heartbeat (35=0) received
+
+	// Latency is the handling latency of each MsgType received
+	Latency map[string]LatencyHistogram `json:",omitempty"`
+
+	// Time is when the stats were taken
+	Time time.Time
+}
+
+// Uptime returns how long the current logon has lasted, zero while logged out
+func (s SessionStats) Uptime() time.Duration {
+	if !s.LoggedOn || s.LoggedOnAt.IsZero() {
+		return 0
+	}
+	return s.Time.Sub(s.LoggedOnAt)
+}
+
+// sessionCounters accumulates the message and logon counts behind SessionStats
+type sessionCounters struct {
+	mu            sync.Mutex
+	startedAt     time.Time
+	loggedOnAt    time.Time
+	in, out       uint64
+	logons        int
+	lastReceived  time.Time
+	lastHeartbeat time.Time
+}
+
+func (c *sessionCounters) received(msgType string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.in++
+	c.lastReceived = now
+	if msgType == "0" {
+		c.lastHeartbeat = now
+	}
+}
+
+func (c *sessionCounters) sent() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.out++
+}
+
+// loggedOn counts a completed logon, returning the number so far
+func (c *sessionCounters) loggedOn(now time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logons++
+	c.loggedOnAt = now
+	return c.logons
+}
+
+func (c *sessionCounters) loggedOut() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loggedOnAt = time.Time{}
+}
+
+// SessionStats returns the current session statistics
+func (a *FixApplication) SessionStats() SessionStats {
+	id, loggedOn := a.session.get()
+
+	a.stats.mu.Lock()
+	stats := SessionStats{
+		Tenant:        a.Tenant,
+		SessionID:     id.String(),
+		Version:       BuildInfo().Version,
+		LoggedOn:      loggedOn,
+		StartedAt:     a.stats.startedAt,
+		LoggedOnAt:    a.stats.loggedOnAt,
+		MessagesIn:    a.stats.in,
+		MessagesOut:   a.stats.out,
+		Logons:        a.stats.logons,
+		Reconnects:    max(a.stats.logons-1, 0),
+		LastReceived:  a.stats.lastReceived,
+		LastHeartbeat: a.stats.lastHeartbeat,
+		Time:          a.now(),
+	}
+	a.stats.mu.Unlock()
+	stats.Latency = a.Latencies()
+
+	if id != (quickfix.SessionID{}) {
+		stats.NextSenderMsgSeqNum, _ = quickfix.GetExpectedSenderNum(id)
+		stats.NextTargetMsgSeqNum, _ = quickfix.GetExpectedTargetNum(id)
+	}
+	return stats
+}
+
+// WriteSessionStats saves the session statistics to path every interval
+// until stop is closed, for `session stats` to read
+func (a *FixApplication) WriteSessionStats(path string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := SaveSessionStats(path, a.SessionStats()); err != nil {
+			a.logger().Println("Failed to save session stats:", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// SaveSessionStats atomically writes stats to path as JSON
+func SaveSessionStats(path string, stats SessionStats) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadSessionStats reads stats written by SaveSessionStats
+func LoadSessionStats(path string) (SessionStats, error) {
+	var stats SessionStats
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return stats, err
+	}
+	return stats, json.Unmarshal(data, &stats)
+}
+
+// WriteSessionStatsReport prints stats for an operator, with ages relative to now
+func WriteSessionStatsReport(w io.Writer, stats SessionStats, now time.Time) {
+	ago := func(t time.Time) string {
+		if t.IsZero() {
+			return "never"
+		}
+		return fmt.Sprintf("%s (%s ago)", t.Format(time.RFC3339), now.Sub(t).Round(time.Second))
+	}
+
+	if stats.Tenant != "" {
+		fmt.Fprintf(w, "Tenant:          %s\n", stats.Tenant)
+	}
+	fmt.Fprintf(w, "Session:         %s\n", stats.SessionID)
+	if stats.Version != "" {
+		fmt.Fprintf(w, "Version:         %s\n", stats.Version)
+	}
+	fmt.Fprintf(w, "Logged on:       %t\n", stats.LoggedOn)
+	fmt.Fprintf(w, "Uptime:          %s\n", stats.Uptime().Round(time.Second))
+	fmt.Fprintf(w, "Started:         %s\n", ago(stats.StartedAt))
+	fmt.Fprintf(w, "Messages in/out: %d / %d\n", stats.MessagesIn, stats.MessagesOut)
+	fmt.Fprintf(w, "Next seq nums:   sender %d, target %d\n", stats.NextSenderMsgSeqNum, stats.NextTargetMsgSeqNum)
+	fmt.Fprintf(w, "Logons:          %d (%d reconnects)\n", stats.Logons, stats.Reconnects)
+	fmt.Fprintf(w, "Last received:   %s\n", ago(stats.LastReceived))
+	fmt.Fprintf(w, "Last heartbeat:  %s\n", ago(stats.LastHeartbeat))
+	fmt.Fprintf(w, "As of:           %s\n", ago(stats.Time))
+	if len(stats.Latency) > 0 {
+		fmt.Fprintln(w)
+		writeLatencyReport(w, stats.Latency)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestSessionStats(t *testing.T) {
+	start := time.Date(2025, 6, 2, 14, 30, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	app := &FixApplication{Tenant: "desk-a", Clock: clock}
+
+	tests := []struct {
+		name           string
+		step           func()
+		wantLoggedOn   bool
+		wantReconnects int
+		wantUptime     time.Duration
+		wantIn         uint64
+	}{
+		{"first logon", func() { app.stats.loggedOn(clock.Now()) }, true, 0, 0, 0},
+		{"heartbeat", func() { clock.Advance(time.Minute); app.stats.received("0", clock.Now()) }, true, 0, time.Minute, 1},
+		{"logout", func() { app.stats.loggedOut() }, false, 0, 0, 1},
+		{"reconnect", func() { app.stats.loggedOn(clock.Now()); clock.Advance(time.Second) }, true, 1, time.Second, 1},
+	}
+	for _, tt := range tests {
+		tt.step()
+		app.session.setLoggedOn(quickfix.SessionID{}, tt.wantLoggedOn)
+		stats := app.SessionStats()
+		if stats.Reconnects != tt.wantReconnects || stats.Uptime() != tt.wantUptime || stats.MessagesIn != tt.wantIn {
+			t.Errorf("%s: reconnects %d uptime %s in %d, want %d %s %d", tt.name,
+				stats.Reconnects, stats.Uptime(), stats.MessagesIn, tt.wantReconnects, tt.wantUptime, tt.wantIn)
+		}
+	}
+
+	stats := app.SessionStats()
+	if !stats.LastHeartbeat.Equal(start.Add(time.Minute)) || stats.Tenant != "desk-a" {
+		t.Errorf("stats %+v", stats)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "stats.json")
+	if err := SaveSessionStats(path, stats); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSessionStats(path)
+	if err != nil || loaded.Logons != 2 || !loaded.Time.Equal(stats.Time) {
+		t.Fatalf("loaded %+v, %v", loaded, err)
+	}
+	var report bytes.Buffer
+	WriteSessionStatsReport(&report, loaded, loaded.Time.Add(time.Minute))
+	for _, want := range []string{"Tenant:          desk-a", "Logons:          2 (1 reconnects)", "(1m0s ago)"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, report.String())
+		}
+	}
+
+	if _, err := LoadSessionStats(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("loaded missing stats")
+	}
+	os.WriteFile(path, []byte("{"), 0600)
+	if _, err := LoadSessionStats(path); err == nil {
+		t.Error("loaded corrupt stats")
+	}
+}
@@ -0,0 +1,363 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+// ShadowOfKey is the metadata key of a shadow order holding the ClOrdID of
+// the production order it mirrors
+const ShadowOfKey = "shadow_of"
+
+// shadowMaxPairs bounds the orders a Shadow compares; the oldest are dropped
+const shadowMaxPairs = 1000
+
+// Shadow runs a tenant in dual-run mode: every order placed in production is
+// mirrored to a shadow session, a sandbox tenant or a paper venue, and the
+// acknowledgements and fills of both are compared. Cancels are mirrored too;
+// replaces are not.
+type Shadow struct {
+	// Target names the shadow: a tenant, or "paper"
+	Target string
+
+	Place  func(req OrderRequest) (string, error)
+	Cancel func(clOrdID string) error
+	Clock  Clock
+
+	// Quote, when set, is fed the quotes of the production tenant so a
+	// paper shadow can fill
+	Quote func(symbol string, bid, ask decimal.Decimal)
+
+	mu    sync.Mutex
+	pairs map[string]*ShadowPair // by production ClOrdID
+	order []string               // production ClOrdIDs, oldest first
+}
+
+// ShadowLeg is what one side of a dual run did with an order
+type ShadowLeg struct {
+	ClOrdID    string        `json:",omitempty"`
+	Error      string        `json:",omitempty"` // the order was not sent
+	Acked      bool          `json:",omitempty"`
+	AckLatency time.Duration `json:",omitempty"`
+	Rejected   RejectReason  `json:",omitempty"`
+	RejectText string        `json:",omitempty"`
+	Fills      int           `json:",omitempty"`
+	CumQty     string        `json:",omitempty"`
+	AvgPx      string        `json:",omitempty"`
+	OrdStatus  string        `json:",omitempty"`
+}
+
+// done reports whether the leg will not change any more
+func (l ShadowLeg) done() bool {
+	switch l.OrdStatus {
+	case "2", "3", "4", "8", "C": // Filled, Done for day, Canceled, Rejected, Expired
+		return true
+	}
+	return l.Error != ""
+}
+
+// apply updates the leg with report, received at now for an order placed at placedAt
+func (l *ShadowLeg) apply(report ExecutionReport, placedAt, now time.Time) {
+	switch report.ExecType {
+	case "0": // New
+		if !l.Acked {
+			l.Acked = true
+			l.AckLatency = now.Sub(placedAt)
+		}
+	case "8": // Rejected
+		l.Rejected = report.RejectReason()
+		l.RejectText = report.Text
+	case "1", "2", "F": // Partial fill, Fill, Trade
+		if !report.PossDup && !report.PossResend {
+			l.Fills++
+		}
+	}
+	if report.CumQty != "" {
+		l.CumQty = report.CumQty
+	}
+	if report.AvgPx != "" {
+		l.AvgPx = report.AvgPx
+	}
+	if report.OrdStatus != "" {
+		l.OrdStatus = report.OrdStatus
+	}
+}
+
+// ShadowPair compares a production order with its shadow
+type ShadowPair struct {
+	Symbol     string
+	Side       string
+	OrdType    string
+	Quantity   string
+	LimitPrice string `json:",omitempty"`
+	PlacedAt   time.Time
+
+	Production ShadowLeg
+	Shadow     ShadowLeg
+
+	// Diffs lists how the two legs diverged, in the order they are checked
+	Diffs []string `json:",omitempty"`
+
+	req OrderRequest
+}
+
+// Done reports whether neither leg will change any more
+func (p ShadowPair) Done() bool {
+	return p.Production.done() && p.Shadow.done()
+}
+
+// diffs compares the legs. Acknowledgements and rejects are compared as
+// soon as they arrive, final status and filled quantity once both legs are
+// done; fill counts and prices are expected to differ between venues.
+func (p ShadowPair) diffs() []string {
+	prod, shadow := p.Production, p.Shadow
+	var diffs []string
+	if (prod.Error == "") != (shadow.Error == "") {
+		diffs = append(diffs, fmt.Sprintf("sent: production=%q shadow=%q", prod.Error, shadow.Error))
+	}
+	if prod.Rejected != shadow.Rejected {
+		diffs = append(diffs, fmt.Sprintf("reject: production=%s shadow=%s", orDash(string(prod.Rejected)), orDash(string(shadow.Rejected))))
+	}
+	if !p.Done() {
+		return diffs
+	}
+	if prod.Acked != shadow.Acked {
+		diffs = append(diffs, fmt.Sprintf("ack: production=%t shadow=%t", prod.Acked, shadow.Acked))
+	}
+	if prod.OrdStatus != shadow.OrdStatus {
+		diffs = append(diffs, fmt.Sprintf("status: production=%s shadow=%s", OrdStatusName(prod.OrdStatus), OrdStatusName(shadow.OrdStatus)))
+	}
+	prodQty, _ := decimal.NewFromString(orZero(prod.CumQty))
+	shadowQty, _ := decimal.NewFromString(orZero(shadow.CumQty))
+	if !prodQty.Equal(shadowQty) {
+		diffs = append(diffs, fmt.Sprintf("filled: production=%s shadow=%s", prodQty, shadowQty))
+	}
+	return diffs
+}
+
+// ShadowReport summarizes a dual run
+type ShadowReport struct {
+	Target   string
+	Orders   int
+	Open     int // either leg still working
+	Matched  int
+	Diverged int
+	Pairs    []ShadowPair
+}
+
+// Add starts comparing the production order clOrdID, placed from req at
+// now; call it before the order is sent so no report can be missed
+func (s *Shadow) Add(clOrdID string, req OrderRequest, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pairs == nil {
+		s.pairs = make(map[string]*ShadowPair)
+	}
+	if len(s.order) >= shadowMaxPairs {
+		delete(s.pairs, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.pairs[clOrdID] = &ShadowPair{
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		OrdType:    req.OrdType,
+		Quantity:   req.Quantity,
+		LimitPrice: req.LimitPrice,
+		PlacedAt:   now,
+		Production: ShadowLeg{ClOrdID: clOrdID},
+		req:        req,
+	}
+	s.order = append(s.order, clOrdID)
+}
+
+// Sent mirrors the production order clOrdID to the shadow once it is sent,
+// or may have been; an order that was not sent leaves nothing to compare
+func (s *Shadow) Sent(clOrdID string, sent bool) {
+	s.mu.Lock()
+	pair, ok := s.pairs[clOrdID]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	if !sent {
+		delete(s.pairs, clOrdID)
+		s.order = slices.DeleteFunc(s.order, func(id string) bool { return id == clOrdID })
+		s.mu.Unlock()
+		return
+	}
+	req := pair.req
+	s.mu.Unlock()
+
+	// The shadow may answer before Place returns; its reports find the pair
+	// through the metadata
+	req.Metadata = maps.Clone(req.Metadata)
+	if req.Metadata == nil {
+		req.Metadata = make(map[string]string)
+	}
+	req.Metadata[ShadowOfKey] = clOrdID
+	req.CorrelationId = ""
+	shadowId, err := s.Place(req)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pair.Shadow.ClOrdID = shadowId
+	if err != nil {
+		pair.Shadow.Error = err.Error()
+		log.Printf("Failed to mirror order %s to shadow %s: %v", clOrdID, s.Target, err)
+	}
+}
+
+// Canceled mirrors the cancel of the production order clOrdID to its shadow
+func (s *Shadow) Canceled(clOrdID string) {
+	s.mu.Lock()
+	pair, ok := s.pairs[clOrdID]
+	if !ok || pair.Shadow.ClOrdID == "" || pair.Shadow.done() {
+		s.mu.Unlock()
+		return
+	}
+	shadowId := pair.Shadow.ClOrdID
+	s.mu.Unlock()
+
+	if err := s.Cancel(shadowId); err != nil {
+		log.Printf("Failed to mirror cancel of order %s to shadow %s: %v", clOrdID, s.Target, err)
+	}
+}
+
+// OnExecutionReport applies a production report to its pair
+func (s *Shadow) OnExecutionReport(report ExecutionReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pair, ok := s.pairs[report.ClOrdID]
+	if !ok {
+		pair, ok = s.pairs[report.OrigClOrdID]
+	}
+	if ok {
+		pair.Production.apply(report, pair.PlacedAt, clockOrSystem(s.Clock).Now())
+	}
+}
+
+// OnShadowReport applies a report of the shadow session to its pair
+func (s *Shadow) OnShadowReport(report ExecutionReport) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pair, ok := s.pairs[report.Metadata[ShadowOfKey]]; ok {
+		pair.Shadow.apply(report, pair.PlacedAt, clockOrSystem(s.Clock).Now())
+	}
+}
+
+// Report compares every order mirrored so far, oldest first
+func (s *Shadow) Report() ShadowReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := ShadowReport{Target: s.Target, Pairs: []ShadowPair{}}
+	for _, clOrdID := range s.order {
+		pair := *s.pairs[clOrdID]
+		pair.Diffs = pair.diffs()
+		switch {
+		case len(pair.Diffs) > 0:
+			report.Diverged++
+		case !pair.Done():
+			report.Open++
+		default:
+			report.Matched++
+		}
+		report.Orders++
+		report.Pairs = append(report.Pairs, pair)
+	}
+	return report
+}
+
+// newShadow mirrors the orders of app to the application shadow, named target
+func newShadow(target string, app, shadow *FixApplication) (*Shadow, error) {
+	s := &Shadow{Target: target, Place: shadow.PlaceOrder, Cancel: shadow.CancelOrder, Clock: app.Clock}
+	if shadow.Paper != nil {
+		s.Quote = shadow.OnQuote
+	}
+	reports, err := NewEventQueue(100, OverflowDropOldest, "", s.OnShadowReport)
+	if err != nil {
+		return nil, err
+	}
+	shadow.Sinks = append(shadow.Sinks, reports)
+	return s, nil
+}
+
+// paperShadow creates the paper venue a tenant with ShadowTenant=paper
+// mirrors its orders to, configured by the Paper settings
+func paperShadow(settings *quickfix.SessionSettings, app *FixApplication) (*FixApplication, error) {
+	shadow := &FixApplication{
+		Tenant:  app.Tenant + "-shadow",
+		Logger:  log.New(log.Writer(), "tenant="+app.Tenant+"-shadow ", log.Flags()|log.Lmsgprefix),
+		Clock:   app.Clock,
+		Symbols: app.Symbols,
+		Tracker: NewOrderTracker(30 * time.Second),
+	}
+	shadow.Tracker.Clock = app.Clock
+	var err error
+	if shadow.Paper, err = paperVenueSetting(settings, app.Clock); err != nil {
+		return nil, err
+	}
+	shadow.Paper.Deliver = func(msg *quickfix.Message) { shadow.FromApp(msg, quickfix.SessionID{}) }
+	return shadow, nil
+}
+
+// linkShadows points every tenant whose ShadowTenant names another tenant at
+// it, once all tenants are added. The shadow receives a copy of every order,
+// so it must be a sandbox or paper trading tenant.
+func linkShadows(manager *Manager) error {
+	for _, tenant := range manager.Tenants() {
+		target, err := tenant.Settings.GlobalSettings().Setting("ShadowTenant")
+		if err != nil || target == "paper" {
+			continue
+		}
+		shadow, ok := manager.Get(target)
+		if !ok || shadow == tenant {
+			return fmt.Errorf("tenant %s: ShadowTenant %q is not another tenant", tenant.Name, target)
+		}
+		if environment, _ := shadow.Settings.GlobalSettings().Setting("Environment"); environment != "sandbox" && shadow.App.Paper == nil {
+			return fmt.Errorf("tenant %s: ShadowTenant %q requires Environment=sandbox or PaperTrading", tenant.Name, target)
+		}
+		if tenant.App.Shadow, err = newShadow(target, tenant.App, shadow.App); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func orZero(value string) string {
+	if value == "" {
+		return "0"
+	}
+	return value
+}
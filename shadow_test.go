@@ -0,0 +1,145 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/shopspring/decimal"
+)
+
+func TestShadowMirrorsToPaper(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC))
+	newPaperApp := func(name string) *FixApplication {
+		app := &FixApplication{Tenant: name, Clock: clock, Tracker: NewOrderTracker(time.Minute), Paper: NewPaperVenue(clock, 1)}
+		app.Paper.Deliver = func(msg *quickfix.Message) { app.FromApp(msg, quickfix.SessionID{}) }
+		return app
+	}
+	production, paper := newPaperApp("desk"), newPaperApp("desk-shadow")
+	shadow, err := newShadow("paper", production, paper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	production.Shadow = shadow
+
+	production.OnQuote("BTC-USD", decimal.NewFromInt(49990), decimal.NewFromInt(50000))
+	clOrdID, err := production.PlaceOrder(OrderRequest{Symbol: "BTC-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "1", LimitPrice: "49000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Millisecond)
+	if err := production.CancelOrder(clOrdID); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Millisecond)
+
+	// Shadow reports reach the comparison through a queue
+	deadline := time.Now().Add(5 * time.Second)
+	var report ShadowReport
+	for {
+		if report = shadow.Report(); report.Matched == 1 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if report.Orders != 1 || report.Matched != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	pair := report.Pairs[0]
+	if pair.Production.ClOrdID != clOrdID || pair.Shadow.ClOrdID == "" || !pair.Production.Acked || !pair.Shadow.Acked || pair.Shadow.OrdStatus != "4" {
+		t.Fatalf("pair = %+v", pair)
+	}
+	if order, ok := paper.Tracker.Get(pair.Shadow.ClOrdID); !ok || order.Metadata[ShadowOfKey] != clOrdID {
+		t.Fatalf("shadow order = %+v", order)
+	}
+}
+
+func TestShadowReportsDivergence(t *testing.T) {
+	shadow := &Shadow{
+		Target: "sandbox",
+		Place:  func(req OrderRequest) (string, error) { return "s1", nil },
+		Cancel: func(string) error { return errors.New("not expected") },
+	}
+	req := OrderRequest{Symbol: "ETH-USD", OrdType: "LIMIT", Side: "BUY", Quantity: "2", LimitPrice: "1000"}
+	now := time.Now()
+
+	shadow.Add("p1", req, now)
+	shadow.Sent("p1", true)
+	shadow.OnExecutionReport(ExecutionReport{ClOrdID: "p1", ExecType: "0", OrdStatus: "0"})
+	shadow.OnShadowReport(ExecutionReport{ClOrdID: "s1", ExecType: "8", OrdStatus: "8", Text: "Insufficient funds", Metadata: map[string]string{ShadowOfKey: "p1"}})
+	shadow.OnExecutionReport(ExecutionReport{ClOrdID: "p1", ExecType: "F", OrdStatus: "2", CumQty: "2", AvgPx: "999"})
+
+	// Nothing is mirrored or compared when production did not send
+	shadow.Add("p2", req, now)
+	shadow.Sent("p2", false)
+
+	report := shadow.Report()
+	if report.Orders != 1 || report.Diverged != 1 || report.Matched != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+	want := []string{
+		"reject: production=- shadow=INSUFFICIENT_FUNDS",
+		"ack: production=true shadow=false",
+		"status: production=FILLED shadow=REJECTED",
+		"filled: production=2 shadow=0",
+	}
+	if diffs := report.Pairs[0].Diffs; len(diffs) != len(want) {
+		t.Fatalf("diffs = %q, want %q", diffs, want)
+	} else {
+		for i := range want {
+			if diffs[i] != want[i] {
+				t.Errorf("diff %d = %q, want %q", i, diffs[i], want[i])
+			}
+		}
+	}
+}
+
+func TestLinkShadowsRequiresSandbox(t *testing.T) {
+	newTenant := func(name string, settings map[string]string) *Tenant {
+		tenant := &Tenant{Name: name, App: &FixApplication{Tenant: name}, Settings: quickfix.NewSettings()}
+		for setting, value := range settings {
+			tenant.Settings.GlobalSettings().Set(setting, value)
+		}
+		return tenant
+	}
+	tests := []struct {
+		name   string
+		shadow *Tenant
+		ok     bool
+	}{
+		{"production", newTenant("shadow", map[string]string{"Environment": "production"}), false},
+		{"unset", newTenant("shadow", nil), false},
+		{"sandbox", newTenant("shadow", map[string]string{"Environment": "sandbox"}), true},
+	}
+	for _, test := range tests {
+		manager := NewManager()
+		production := newTenant("desk", map[string]string{"Environment": "production", "ShadowTenant": "shadow"})
+		if err := manager.Add(production); err != nil {
+			t.Fatal(err)
+		}
+		if err := manager.Add(test.shadow); err != nil {
+			t.Fatal(err)
+		}
+		if err := linkShadows(manager); (err == nil) != test.ok {
+			t.Errorf("%s: linkShadows = %v, want ok=%t", test.name, err, test.ok)
+		}
+		if (production.App.Shadow != nil) != test.ok {
+			t.Errorf("%s: shadow linked=%t, want %t", test.name, production.App.Shadow != nil, test.ok)
+		}
+	}
+}
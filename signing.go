@@ -0,0 +1,110 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+
+	"prime-fix-go/auth"
+)
+
+// Signing backends, chosen by SigningBackend
+const (
+	SigningBackendKey    = "key"    // HMAC in process with SigningKey (the default)
+	SigningBackendKMS    = "kms"    // AWS KMS GenerateMac with KMSKeyId
+	SigningBackendPKCS11 = "pkcs11" // a PKCS#11 HSM through PKCS11Command
+)
+
+// logonSigningTimeout bounds a logon signature computed outside the process
+const logonSigningTimeout = 5 * time.Second
+
+// signingBackend returns the tenant's SigningBackend
+func signingBackend(settings *quickfix.SessionSettings) string {
+	if backend, err := settings.Setting("SigningBackend"); err == nil && backend != "" {
+		return backend
+	}
+	return SigningBackendKey
+}
+
+// newSigner returns the Signer of the tenant's logon and REST signatures,
+// taking over signingKey and zeroing it. Outside the key backend the raw
+// signing key must not be configured at all, so that it is never held in
+// process memory.
+func newSigner(settings *quickfix.SessionSettings, signingKey []byte) (auth.Signer, error) {
+	backend := signingBackend(settings)
+	if backend != SigningBackendKey && len(signingKey) > 0 {
+		clear(signingKey)
+		return nil, fmt.Errorf("SigningKey is set but SigningBackend is %s; remove it", backend)
+	}
+	switch backend {
+	case SigningBackendKey:
+		return auth.NewHMACSigner(signingKey), nil
+	case SigningBackendKMS:
+		keyID, _ := settings.Setting("KMSKeyId")
+		region, _ := settings.Setting("KMSRegion")
+		if keyID == "" || region == "" {
+			return nil, fmt.Errorf("SigningBackend=kms needs KMSKeyId and KMSRegion")
+		}
+		signer := auth.NewKMSSigner(keyID, region)
+		signer.Endpoint, _ = settings.Setting("KMSEndpoint")
+		return signer, nil
+	case SigningBackendPKCS11:
+		command, err := settings.Setting("PKCS11Command")
+		if err != nil {
+			return nil, fmt.Errorf("SigningBackend=pkcs11 needs PKCS11Command")
+		}
+		output, _ := settings.Setting("PKCS11Output")
+		signer, err := auth.ParseCommandSigner(command, output)
+		if err != nil {
+			return nil, err
+		}
+		signer.Env = credentialFreeEnviron()
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unknown SigningBackend %q, want key, kms or pkcs11", backend)
+	}
+}
+
+// credentialFreeEnviron returns the process environment without the
+// credentials and the secret key, for the commands the client runs
+func credentialFreeEnviron() []string {
+	secret := map[string]bool{envPrefix + "SECRET_KEY": true}
+	for _, credential := range credentialSettings {
+		secret[credential[1]] = true
+		secret[envName(credential[0])] = true
+	}
+	var environ []string
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		if !secret[name] {
+			environ = append(environ, variable)
+		}
+	}
+	return environ
+}
+
+// ScrubSecrets zeroes the tenant's credentials. The tenant cannot log on or
+// sign REST requests afterwards, so it is only called on the way out.
+func (a *FixApplication) ScrubSecrets() {
+	a.Passphrase.Destroy()
+	if signer, ok := a.Signer.(interface{ Destroy() }); ok {
+		signer.Destroy()
+	}
+}
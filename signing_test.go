@@ -0,0 +1,98 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+
+	"prime-fix-go/auth"
+)
+
+func TestNewSigner(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		settings   map[string]string
+		signingKey string
+		want       string // empty when valid
+	}{
+		{"default", nil, "key", ""},
+		{"kms", map[string]string{"SigningBackend": "kms", "KMSKeyId": "alias/prime", "KMSRegion": "us-east-1"}, "", ""},
+		{"kms with key", map[string]string{"SigningBackend": "kms", "KMSKeyId": "alias/prime", "KMSRegion": "us-east-1"}, "key", "SigningKey is set"},
+		{"kms without region", map[string]string{"SigningBackend": "kms", "KMSKeyId": "alias/prime"}, "", "needs KMSKeyId and KMSRegion"},
+		{"pkcs11", map[string]string{"SigningBackend": "pkcs11", "PKCS11Command": "pkcs11-tool --sign", "PKCS11Output": "hex"}, "", ""},
+		{"pkcs11 without command", map[string]string{"SigningBackend": "pkcs11"}, "", "needs PKCS11Command"},
+		{"unknown", map[string]string{"SigningBackend": "vault"}, "", "unknown SigningBackend"},
+	} {
+		settings := quickfix.NewSessionSettings()
+		for key, value := range tc.settings {
+			settings.Set(key, value)
+		}
+		signer, err := newSigner(settings, []byte(tc.signingKey))
+		switch {
+		case tc.want == "" && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+			t.Errorf("%s: got %v, want %q", tc.name, err, tc.want)
+		}
+		if tc.name == "kms" {
+			if _, ok := signer.(*auth.KMSSigner); !ok {
+				t.Errorf("kms: signer is %T", signer)
+			}
+		}
+	}
+}
+
+// capturedLog records the messages a quickfix Log is given
+type capturedLog struct {
+	quickfix.Log
+	outgoing []string
+}
+
+func (l *capturedLog) OnOutgoing(raw []byte) { l.outgoing = append(l.outgoing, string(raw)) }
+
+func TestCredentialHygiene(t *testing.T) {
+	// The logon is logged without its signature, password or access key
+	log := &capturedLog{}
+	logon := "8=FIX.4.2\x0135=A\x0196=c2lnbmF0dXJl\x01554=my-passphrase\x019407=my-access-key\x0110=000\x01"
+	redactedLog{Log: log}.OnOutgoing([]byte(logon))
+	if got := log.outgoing[0]; strings.Contains(got, "my-passphrase") || strings.Contains(got, "c2lnbmF0dXJl") || !strings.Contains(got, "554=REDACTED") {
+		t.Errorf("logged %q", got)
+	}
+
+	// Signing commands do not inherit the credentials
+	t.Setenv("SIGNING_KEY", "key")
+	t.Setenv("PRIMEFIX_PASSPHRASE", "passphrase")
+	t.Setenv("PKCS11_PIN", "1234")
+	environ := credentialFreeEnviron()
+	if slices.Contains(environ, "SIGNING_KEY=key") || slices.Contains(environ, "PRIMEFIX_PASSPHRASE=passphrase") || !slices.Contains(environ, "PKCS11_PIN=1234") {
+		t.Errorf("environment = %v", environ)
+	}
+
+	// Scrubbed credentials can no longer be used
+	app := &FixApplication{Passphrase: auth.NewSecretString("passphrase"), Signer: auth.NewHMACSigner([]byte("key"))}
+	app.ScrubSecrets()
+	if _, err := app.Passphrase.Reveal(); !errors.Is(err, auth.ErrSecretDestroyed) {
+		t.Errorf("passphrase after scrub: %v", err)
+	}
+	if _, err := app.signer().Sign(context.Background(), []byte("message")); !errors.Is(err, auth.ErrSecretDestroyed) {
+		t.Errorf("signing after scrub: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Snapshot is the in-memory state of the client, enough to resume after a
+// warm restart or a blue/green switch without losing order context
+type Snapshot struct {
+	Time      time.Time
+	Orders    []Order
+	Aliases   map[string]string `json:",omitempty"`
+	Positions []Position
+	Dedup     []string // ExecID/ExecType keys, oldest first
+
+	// Sequence numbers the session expects next, zero when there is no
+	// session; Manager.Stop reads them once the session has stopped
+	NextSenderMsgSeqNum int
+	NextTargetMsgSeqNum int
+}
+
+// Snapshot captures open orders, positions and the execution dedup cache
+func (a *FixApplication) Snapshot() Snapshot {
+	snapshot := Snapshot{Time: time.Now()}
+	if a.Tracker != nil {
+		snapshot.Orders, snapshot.Aliases = a.Tracker.Snapshot()
+	}
+	if a.Positions != nil {
+		snapshot.Positions = a.Positions.All()
+	}
+	if a.Dedup != nil {
+		snapshot.Dedup = a.Dedup.Keys()
+	}
+	return snapshot
+}
+
+// retainingStoreFactory remembers the stores it creates, so that the sequence
+// numbers of a session can be read once its initiator has stopped writing
+// them. quickfix stores are not safe to read while the session runs.
+type retainingStoreFactory struct {
+	quickfix.MessageStoreFactory
+
+	mu     sync.Mutex
+	stores map[quickfix.SessionID]quickfix.MessageStore
+}
+
+func (f *retainingStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	store, err := f.MessageStoreFactory.Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stores == nil {
+		f.stores = make(map[quickfix.SessionID]quickfix.MessageStore)
+	}
+	f.stores[sessionID] = store
+	return store, nil
+}
+
+// seqNums returns the next sender and target sequence numbers of the store of
+// sessionId, zero when it has none. Only call it after the initiator stopped.
+func (f *retainingStoreFactory) seqNums(sessionId quickfix.SessionID) (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	store, ok := f.stores[sessionId]
+	if !ok {
+		return 0, 0
+	}
+	return store.NextSenderMsgSeqNum(), store.NextTargetMsgSeqNum()
+}
+
+// Restore loads snapshot into the client. Sequence numbers can only be
+// restored once the session exists, i.e. after the initiator is created and
+// before it is started, and only ever move forward: a persistent store that
+// is already ahead of the snapshot is kept.
+func (a *FixApplication) Restore(snapshot Snapshot) error {
+	if a.Tracker != nil {
+		a.Tracker.Restore(snapshot.Orders, snapshot.Aliases)
+	}
+	if a.Positions != nil {
+		a.Positions.Restore(snapshot.Positions)
+	}
+	if a.Dedup != nil {
+		a.Dedup.Restore(snapshot.Dedup)
+	}
+
+	sessionId := a.SessionID()
+	if sessionId == (quickfix.SessionID{}) {
+		return nil
+	}
+	if current, err := quickfix.GetExpectedSenderNum(sessionId); err == nil && snapshot.NextSenderMsgSeqNum > current {
+		if err := quickfix.SetNextSenderMsgSeqNum(sessionId, snapshot.NextSenderMsgSeqNum); err != nil {
+			return err
+		}
+	}
+	if current, err := quickfix.GetExpectedTargetNum(sessionId); err == nil && snapshot.NextTargetMsgSeqNum > current {
+		if err := quickfix.SetNextTargetMsgSeqNum(sessionId, snapshot.NextTargetMsgSeqNum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveSnapshot writes snapshot to path atomically
+func SaveSnapshot(path string, snapshot Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot
+func LoadSnapshot(path string) (Snapshot, error) {
+	var snapshot Snapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snapshot, err
+	}
+	err = json.Unmarshal(data, &snapshot)
+	return snapshot, err
+}
@@ -0,0 +1,164 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/store/file"
+)
+
+// encryptedMessagePrefix marks a stored message sealed by an encryptedStore
+var encryptedMessagePrefix = []byte("pfe1:")
+
+// NewEncryptedStoreFactory wraps factory so that the messages its stores
+// save are sealed with AES-256-GCM under a key derived from key. Sequence
+// numbers and creation times are left in the clear. Each message is bound
+// to its session and sequence number, so sealed messages cannot be swapped
+// around undetected.
+func NewEncryptedStoreFactory(factory quickfix.MessageStoreFactory, key []byte) (quickfix.MessageStoreFactory, error) {
+	// Derive a key of its own so the message store never shares one with
+	// the config secrets
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("prime-fix-go message store"))
+	aead, err := newSecretAEAD(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return encryptedStoreFactory{MessageStoreFactory: factory, aead: aead}, nil
+}
+
+type encryptedStoreFactory struct {
+	quickfix.MessageStoreFactory
+	aead cipher.AEAD
+}
+
+func (f encryptedStoreFactory) Create(sessionID quickfix.SessionID) (quickfix.MessageStore, error) {
+	store, err := f.MessageStoreFactory.Create(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedStore{MessageStore: store, aead: f.aead, session: sessionID.String()}, nil
+}
+
+type encryptedStore struct {
+	quickfix.MessageStore
+	aead    cipher.AEAD
+	session string
+}
+
+func (s *encryptedStore) SaveMessage(seqNum int, msg []byte) error {
+	sealed, err := s.seal(seqNum, msg)
+	if err != nil {
+		return err
+	}
+	return s.MessageStore.SaveMessage(seqNum, sealed)
+}
+
+func (s *encryptedStore) SaveMessageAndIncrNextSenderMsgSeqNum(seqNum int, msg []byte) error {
+	sealed, err := s.seal(seqNum, msg)
+	if err != nil {
+		return err
+	}
+	return s.MessageStore.SaveMessageAndIncrNextSenderMsgSeqNum(seqNum, sealed)
+}
+
+func (s *encryptedStore) GetMessages(beginSeqNum, endSeqNum int) ([][]byte, error) {
+	var msgs [][]byte
+	err := s.IterateMessages(beginSeqNum, endSeqNum, func(msg []byte) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	return msgs, err
+}
+
+// IterateMessages reads the stored messages one sequence number at a time, so
+// that each is checked against the number it was sealed under
+func (s *encryptedStore) IterateMessages(beginSeqNum, endSeqNum int, cb func([]byte) error) error {
+	for seqNum := beginSeqNum; seqNum <= endSeqNum; seqNum++ {
+		err := s.MessageStore.IterateMessages(seqNum, seqNum, func(stored []byte) error {
+			msg, err := s.open(seqNum, stored)
+			if err != nil {
+				return err
+			}
+			return cb(msg)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seal encrypts msg as the prefix, the sequence number, the nonce and the
+// ciphertext
+func (s *encryptedStore) seal(seqNum int, msg []byte) ([]byte, error) {
+	header := binary.BigEndian.AppendUint64(bytes.Clone(encryptedMessagePrefix), uint64(seqNum))
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := append(header, nonce...)
+	return s.aead.Seal(sealed, nonce, msg, s.additionalData(header)), nil
+}
+
+// open decrypts the message stored as seqNum, failing if it was sealed under
+// another sequence number. Messages stored before encryption was turned on
+// are returned as they are.
+func (s *encryptedStore) open(seqNum int, stored []byte) ([]byte, error) {
+	if !bytes.HasPrefix(stored, encryptedMessagePrefix) {
+		return stored, nil
+	}
+	headerSize := len(encryptedMessagePrefix) + 8
+	if len(stored) < headerSize+s.aead.NonceSize() {
+		return nil, errors.New("stored message is truncated")
+	}
+	header, rest := stored[:headerSize], stored[headerSize:]
+	nonce, ciphertext := rest[:s.aead.NonceSize()], rest[s.aead.NonceSize():]
+	msg, err := s.aead.Open(nil, nonce, ciphertext, s.additionalData(header))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt stored message %d: %w", seqNum, err)
+	}
+	if sealed := binary.BigEndian.Uint64(header[len(encryptedMessagePrefix):]); sealed != uint64(seqNum) {
+		return nil, fmt.Errorf("stored message %d was sealed as message %d", seqNum, sealed)
+	}
+	return msg, nil
+}
+
+func (s *encryptedStore) additionalData(header []byte) []byte {
+	return append([]byte(s.session+"\x00"), header...)
+}
+
+// fileStoreFactory returns the file store for settings, encrypting stored
+// messages under the secret key when MessageStoreEncryption=Y
+func fileStoreFactory(settings *quickfix.Settings) (quickfix.MessageStoreFactory, error) {
+	factory := file.NewStoreFactory(settings)
+	if encrypt, err := settings.GlobalSettings().BoolSetting("MessageStoreEncryption"); err != nil || !encrypt {
+		return factory, nil
+	}
+	key, err := secretKeyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewEncryptedStoreFactory(factory, key)
+}
@@ -0,0 +1,131 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/store/file"
+)
+
+func TestEncryptedFileStore(t *testing.T) {
+	dir := t.TempDir()
+	settings, err := quickfix.ParseSettings(strings.NewReader("[DEFAULT]\nFileStorePath=" + dir + "\n" +
+		"[SESSION]\nBeginString=FIX.4.2\nSenderCompID=CLIENT\nTargetCompID=COIN\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "CLIENT", TargetCompID: "COIN"}
+	key := bytes.Repeat([]byte{7}, 32)
+
+	factory, err := NewEncryptedStoreFactory(file.NewStoreFactory(settings), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := factory.Create(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := []byte("8=FIX.4.2\x0135=D\x0111=secret-order\x01")
+	if err := store.SaveMessageAndIncrNextSenderMsgSeqNum(1, order); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveMessage(2, []byte("8=FIX.4.2\x0135=F\x01")); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.body"))
+	if len(files) != 1 {
+		t.Fatalf("expected one body file, got %v", files)
+	}
+	body, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(body, []byte("secret-order")) {
+		t.Fatal("stored message is in the clear")
+	}
+
+	// Reopen as after a restart
+	store, err = factory.Create(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	msgs, err := store.GetMessages(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || !bytes.Equal(msgs[0], order) {
+		t.Fatalf("unexpected messages %q", msgs)
+	}
+
+	// A different key cannot read them
+	other, err := NewEncryptedStoreFactory(file.NewStoreFactory(settings), bytes.Repeat([]byte{8}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherStore, err := other.Create(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer otherStore.Close()
+	if _, err := otherStore.GetMessages(1, 2); err == nil {
+		t.Fatal("expected decryption to fail under another key")
+	}
+}
+
+func TestEncryptedStoreRejectsMovedMessage(t *testing.T) {
+	factory, err := NewEncryptedStoreFactory(quickfix.NewMemoryStoreFactory(), bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := factory.Create(quickfix.SessionID{BeginString: "FIX.4.2", SenderCompID: "CLIENT", TargetCompID: "COIN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for seqNum, msg := range []string{"8=FIX.4.2\x0135=D\x0111=one\x01", "8=FIX.4.2\x0135=D\x0111=two\x01"} {
+		if err := store.SaveMessage(seqNum+1, []byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	msgs, err := store.GetMessages(1, 3)
+	if err != nil || len(msgs) != 2 {
+		t.Fatalf("messages %q: %v", msgs, err)
+	}
+
+	// Message 2 copied into slot 4 still decrypts, but is refused there
+	underlying := store.(*encryptedStore).MessageStore
+	sealed, err := underlying.GetMessages(2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := underlying.SaveMessage(4, sealed[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetMessages(1, 4); err == nil || !strings.Contains(err.Error(), "stored message 4 was sealed as message 2") {
+		t.Errorf("moved message read back with %v", err)
+	}
+	if msgs, err := store.GetMessages(1, 3); err != nil || len(msgs) != 2 {
+		t.Errorf("messages in place %q: %v", msgs, err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Quote is a top of book for a symbol
+type Quote struct {
+	Time     time.Time
+	Symbol   string
+	Bid, Ask decimal.Decimal
+}
+
+// Trader is what a strategy trades through. FixApplication implements it,
+// whether connected to Prime, paper trading or run by a Backtest.
+type Trader interface {
+	PlaceOrder(req OrderRequest) (string, error)
+	CancelOrder(clOrdID string) error
+	ReplaceOrder(clOrdID, quantity, limitPrice string) error
+}
+
+// Strategy is trading logic driven by quotes and executions. The same
+// strategy runs live, set as FixApplication.Strategy, and in a Backtest.
+// Callbacks come from the session and from OnQuote callers, so a strategy
+// fed both concurrently must guard its own state.
+type Strategy interface {
+	OnQuote(trader Trader, quote Quote)
+	OnExecution(trader Trader, report ExecutionReport)
+}
+
+// OnQuote feeds a top of book to the paper venue, the repricer and the strategy
+func (a *FixApplication) OnQuote(symbol string, bid, ask decimal.Decimal) {
+	if a.Mids != nil {
+		a.Mids.OnQuote(symbol, bid, ask)
+	}
+	if a.Paper != nil {
+		a.Paper.OnQuote(symbol, bid, ask)
+	}
+	if a.Shadow != nil && a.Shadow.Quote != nil {
+		a.Shadow.Quote(symbol, bid, ask)
+	}
+	if a.Repricer != nil {
+		a.Repricer.OnQuote(symbol, bid, ask, a.now())
+	}
+	if a.Strategy != nil {
+		a.Strategy.OnQuote(a, Quote{Time: a.now(), Symbol: symbol, Bid: bid, Ask: ask})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Anomaly is venue behaviour the client does not expect: a report for an
+// order it does not know, an order state change the lifecycle does not
+// allow, or a field that should be a decimal but is not
+type Anomaly struct {
+	Kind   string
+	Detail string
+}
+
+func (a *Anomaly) Error() string {
+	return a.Kind + ": " + a.Detail
+}
+
+// anomaly logs a warning for an, or in strict mode raises a critical alert
+// and exits, halting trading until someone has looked into it
+func (a *FixApplication) anomaly(an *Anomaly) {
+	if !a.Strict {
+		a.logger().Println("ANOMALY:", an)
+		return
+	}
+	a.alert("anomaly:"+an.Kind, "critical", "Strict mode halted on "+an.Error())
+	if a.Alerts != nil {
+		a.Alerts.Flush()
+	}
+	fatalUnrecoverable(fmt.Errorf("strict mode: %w", an))
+}
+
+// decimalAnomaly returns an Anomaly for the first numeric field of report
+// that is set but does not parse as a decimal
+func decimalAnomaly(report ExecutionReport) *Anomaly {
+	fields := []struct{ name, value string }{
+		{"OrderQty", report.Quantity},
+		{"Price", report.Price},
+		{"LastShares", report.LastShares},
+		{"LastPx", report.LastPx},
+		{"CumQty", report.CumQty},
+		{"AvgPx", report.AvgPx},
+		{"Commission", report.Commission},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		if _, err := decimal.NewFromString(field.value); err != nil {
+			return &Anomaly{Kind: "invalid-decimal", Detail: fmt.Sprintf("ExecID %s has %s %q", report.ExecID, field.name, field.value)}
+		}
+	}
+	return nil
+}
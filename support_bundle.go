@@ -0,0 +1,317 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const redacted = "REDACTED"
+
+// secretSettings are the config settings stripped from a support bundle, on
+// top of any encrypted value
+var secretSettings = map[string]bool{
+	"AccessKey":           true,
+	"SigningKey":          true,
+	"Passphrase":          true,
+	"SlackWebhookURL":     true,
+	"PagerDutyRoutingKey": true,
+	"ExecutionWebhookURL": true,
+}
+
+// secretTagPattern matches the logon fields carrying credentials: RawData
+// (96, the signature), Password (554) and AccessKey (9407)
+var secretTagPattern = regexp.MustCompile(`(^|[\x01| ])(96|554|9407)=[^\x01|]*`)
+
+// SupportBundleOptions selects what goes into a support bundle
+type SupportBundleOptions struct {
+	ConfigPath string
+	Since      time.Duration // only logs modified within Since are included
+	MaxLogSize int64         // bytes kept from the end of each log
+}
+
+// VersionInfo identifies the build and platform a bundle was made on
+type VersionInfo struct {
+	Build
+	Generated time.Time
+}
+
+// SeqNums are the sequence numbers a session expects next
+type SeqNums struct {
+	NextSender int
+	NextTarget int
+}
+
+// SessionInfo is the session state found on disk
+type SessionInfo struct {
+	SeqNums map[string]SeqNums `json:",omitempty"` // by file store session prefix
+
+	SnapshotTime *time.Time `json:",omitempty"`
+	OpenOrders   int        `json:",omitempty"`
+	Positions    []Position `json:",omitempty"`
+}
+
+// versionInfo reads the build information embedded in the binary
+func versionInfo() VersionInfo {
+	return VersionInfo{Build: BuildInfo(), Generated: time.Now().UTC()}
+}
+
+// WriteSupportBundle writes a gzipped tarball of the version, the redacted
+// config and environment, the session state and the recent redacted FIX logs
+func WriteSupportBundle(w io.Writer, opts SupportBundleOptions) error {
+	// Parse without decrypting; the bundle never needs the secrets
+	config, err := resolveConfig(opts.ConfigPath)
+	if err != nil {
+		return err
+	}
+	settings, err := quickfix.ParseSettings(strings.NewReader(config))
+	if err != nil {
+		return err
+	}
+	global := settings.GlobalSettings()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		header := &tar.Header{Name: "support-bundle/" + name, Mode: 0600, Size: int64(len(data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, data)
+	}
+
+	if err := addJSON("version.json", versionInfo()); err != nil {
+		return err
+	}
+	if err := add("fix.cfg", redactConfig([]byte(config))); err != nil {
+		return err
+	}
+	if err := add("environment.txt", redactedEnvironment()); err != nil {
+		return err
+	}
+
+	var session SessionInfo
+	storePaths := make(map[string]bool)
+	if path, err := global.Setting("FileStorePath"); err == nil {
+		storePaths[path] = true
+	}
+	for _, sessionSettings := range settings.SessionSettings() {
+		if path, err := sessionSettings.Setting("FileStorePath"); err == nil {
+			storePaths[path] = true
+		}
+	}
+	for path := range storePaths {
+		seqNums, err := storeSeqNums(path)
+		if err != nil {
+			return err
+		}
+		for id, nums := range seqNums {
+			if session.SeqNums == nil {
+				session.SeqNums = make(map[string]SeqNums)
+			}
+			session.SeqNums[id] = nums
+		}
+	}
+	if path, err := global.Setting("SnapshotPath"); err == nil {
+		if snapshot, err := LoadSnapshot(path); err == nil {
+			session.SnapshotTime = &snapshot.Time
+			session.Positions = snapshot.Positions
+			for _, order := range snapshot.Orders {
+				if !order.State.Terminal() {
+					session.OpenOrders++
+				}
+			}
+		}
+	}
+	if err := addJSON("session.json", session); err != nil {
+		return err
+	}
+
+	if logPath, err := global.Setting("FileLogPath"); err == nil {
+		logs, err := recentLogs(logPath, time.Now().Add(-opts.Since))
+		if err != nil {
+			return err
+		}
+		for _, path := range logs {
+			data, err := tailFile(path, opts.MaxLogSize)
+			if err != nil {
+				return err
+			}
+			if err := add("logs/"+filepath.Base(path), redactFIX(data)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// redactConfig strips secret and encrypted values from config text
+func redactConfig(config []byte) []byte {
+	lines := strings.Split(string(config), "\n")
+	for i, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(strings.TrimSpace(key), "#") {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if secretSettings[key] || strings.HasPrefix(strings.TrimSpace(value), encryptedPrefix) {
+			lines[i] = key + "=" + redacted
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// redactedEnvironment lists the client's environment variables with secrets
+// replaced; credentials read from ACCESS_KEY and friends are only listed as set
+func redactedEnvironment() []byte {
+	var lines []string
+	for _, setting := range envSettings {
+		name := envName(setting)
+		if value, ok := os.LookupEnv(name); ok {
+			if secretSettings[setting] || strings.HasPrefix(value, encryptedPrefix) {
+				value = redacted
+			}
+			lines = append(lines, name+"="+value)
+		}
+	}
+	for _, name := range []string{
+		envPrefix + "SECRET_KEY", envPrefix + "SECRET_KEY_FILE",
+		"ACCESS_KEY", "SIGNING_KEY", "PASSPHRASE", "PORTFOLIO_ID",
+	} {
+		if _, ok := os.LookupEnv(name); ok {
+			lines = append(lines, name+"="+redacted)
+		}
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// redactFIX replaces the credential fields of every FIX message in data
+func redactFIX(data []byte) []byte {
+	return secretTagPattern.ReplaceAll(data, []byte("${1}${2}="+redacted))
+}
+
+// storeSeqNums reads the next sequence numbers from the quickfix file store in
+// dir, keyed by the session prefix of the store files
+func storeSeqNums(dir string) (map[string]SeqNums, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.senderseqnums"))
+	if err != nil {
+		return nil, err
+	}
+	seqNums := make(map[string]SeqNums, len(paths))
+	for _, path := range paths {
+		prefix := strings.TrimSuffix(path, ".senderseqnums")
+		sender, err := readSeqNum(path)
+		if err != nil {
+			return nil, err
+		}
+		target, err := readSeqNum(prefix + ".targetseqnums")
+		if err != nil {
+			return nil, err
+		}
+		seqNums[filepath.Base(prefix)] = SeqNums{NextSender: sender, NextTarget: target}
+	}
+	return seqNums, nil
+}
+
+func readSeqNum(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// recentLogs returns the log files in dir modified since
+func recentLogs(dir string, since time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		if info.ModTime().After(since) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// tailFile returns up to max bytes from the end of the file at path, starting
+// at a line boundary
+func tailFile(path string, max int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if max <= 0 || info.Size() <= max {
+		return io.ReadAll(file)
+	}
+
+	if _, err := file.Seek(info.Size()-max, io.SeekStart); err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(file)
+	if _, err := reader.ReadBytes('\n'); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,110 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteSupportBundle(t *testing.T) {
+	dir := t.TempDir()
+	store := filepath.Join(dir, "store")
+	logs := filepath.Join(dir, "logs")
+	for _, d := range []string{store, logs} {
+		if err := os.Mkdir(d, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(logs, "FIX.4.2-SENDER-COIN.messages.log"), "8=FIX.4.2\x0135=A\x01554=hunter2\x019407=key\x01\n")
+	config := "[DEFAULT]\nAccessKey=key\nFileStorePath=" + store + "\nFileLogPath=" + logs + "\n[SESSION]\nBeginString=FIX.4.2\nSenderCompID=SENDER\nTargetCompID=COIN\n"
+	configPath := filepath.Join(dir, "fix.cfg")
+	write(configPath, config)
+
+	tests := []struct {
+		name       string
+		configPath string
+		seqNum     string
+		wantErr    bool
+		want       map[string]string // file -> substring it contains
+	}{
+		{"bundle", configPath, "7", false, map[string]string{
+			"fix.cfg":                               "AccessKey=REDACTED",
+			"session.json":                          `"NextSender": 7`,
+			"logs/FIX.4.2-SENDER-COIN.messages.log": "554=REDACTED\x019407=REDACTED",
+		}},
+		{"missing config", filepath.Join(dir, "missing.cfg"), "7", true, nil},
+		{"corrupt store", configPath, "seven", true, nil},
+	}
+	for _, tt := range tests {
+		write(filepath.Join(store, "FIX.4.2-SENDER-COIN.senderseqnums"), tt.seqNum)
+		write(filepath.Join(store, "FIX.4.2-SENDER-COIN.targetseqnums"), "3")
+
+		var buf bytes.Buffer
+		err := WriteSupportBundle(&buf, SupportBundleOptions{ConfigPath: tt.configPath, Since: time.Hour})
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: err = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+
+		files := readBundle(t, &buf)
+		for name, want := range tt.want {
+			if !strings.Contains(files[name], want) {
+				t.Errorf("%s: %s = %q, want it to contain %q", tt.name, name, files[name], want)
+			}
+		}
+		if strings.Contains(files["fix.cfg"], "AccessKey=key") || strings.Contains(files["logs/FIX.4.2-SENDER-COIN.messages.log"], "hunter2") {
+			t.Errorf("%s: bundle leaked a secret", tt.name)
+		}
+	}
+}
+
+func readBundle(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[strings.TrimPrefix(header.Name, "support-bundle/")] = string(data)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/shopspring/decimal"
+)
+
+// SymbolDefault holds the order parameters applied to a symbol's orders
+// that leave them unset, and its largest order quantity
+type SymbolDefault struct {
+	// OrdType is the strategy, LIMIT, MARKET, STOP_LIMIT, TWAP or VWAP
+	OrdType string `json:"ord_type,omitempty"`
+	// TimeInForce is DAY, GTC, IOC, FOK or GTD
+	TimeInForce string `json:"time_in_force,omitempty"`
+	// MaxQuantity blocks larger orders when set
+	MaxQuantity string `json:"max_quantity,omitempty"`
+
+	maxQuantity decimal.Decimal
+}
+
+// SymbolDefaults are the SymbolDefaults of each symbol, with "*" applying to
+// symbols without their own
+type SymbolDefaults map[string]SymbolDefault
+
+// LoadSymbolDefaults reads a JSON object of symbols to their SymbolDefault
+func LoadSymbolDefaults(path string) (SymbolDefaults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var defaults SymbolDefaults
+	if err := json.Unmarshal(data, &defaults); err != nil {
+		return nil, fmt.Errorf("invalid symbol defaults %s: %w", path, err)
+	}
+	for symbol, d := range defaults {
+		switch d.OrdType {
+		case "", "LIMIT", "MARKET", "STOP_LIMIT", "TWAP", "VWAP":
+		default:
+			return nil, fmt.Errorf("symbol defaults %s: %s: unknown ord_type %q", path, symbol, d.OrdType)
+		}
+		if _, ok := TimeInForceCode(d.TimeInForce); d.TimeInForce != "" && !ok {
+			return nil, fmt.Errorf("symbol defaults %s: %s: unknown time_in_force %q", path, symbol, d.TimeInForce)
+		}
+		if d.MaxQuantity != "" {
+			if d.maxQuantity, err = decimal.NewFromString(d.MaxQuantity); err != nil || !d.maxQuantity.IsPositive() {
+				return nil, fmt.Errorf("symbol defaults %s: %s: invalid max_quantity %q", path, symbol, d.MaxQuantity)
+			}
+		}
+		defaults[symbol] = d
+	}
+	return defaults, nil
+}
+
+func (s SymbolDefaults) lookup(symbol string) (SymbolDefault, bool) {
+	if d, ok := s[symbol]; ok {
+		return d, true
+	}
+	d, ok := s["*"]
+	return d, ok
+}
+
+// Apply fills the OrdType and TimeInForce req leaves unset from the defaults
+// of its symbol. Algo orders keep their own TimeInForce.
+func (s SymbolDefaults) Apply(req OrderRequest) OrderRequest {
+	d, ok := s.lookup(req.Symbol)
+	if !ok {
+		return req
+	}
+	if req.OrdType == "" {
+		req.OrdType = d.OrdType
+	}
+	if req.TimeInForce == "" && !isAlgo(req.OrdType) {
+		req.TimeInForce = d.TimeInForce
+	}
+	return req
+}
+
+// Check blocks orders above the MaxQuantity of their symbol
+func (s SymbolDefaults) Check(req OrderRequest) error {
+	d, ok := s.lookup(req.Symbol)
+	if !ok || d.maxQuantity.IsZero() {
+		return nil
+	}
+	quantity, err := decimal.NewFromString(req.Quantity)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %q", req.Quantity)
+	}
+	if quantity.GreaterThan(d.maxQuantity) {
+		return fmt.Errorf("quantity %s exceeds the max quantity %s of %s", req.Quantity, d.MaxQuantity, req.Symbol)
+	}
+	return nil
+}
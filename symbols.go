@@ -0,0 +1,81 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SymbolMapper translates between internal instrument identifiers, as used
+// by a firm-wide security master, and Prime FIX symbols. The client works in
+// internal identifiers throughout and translates only on the wire. A nil
+// mapper, and any unmapped symbol, passes symbols through unchanged.
+type SymbolMapper struct {
+	toPrime    map[string]string
+	toInternal map[string]string
+}
+
+// NewSymbolMapper creates a mapper from internal identifiers to Prime symbols
+func NewSymbolMapper(internalToPrime map[string]string) (*SymbolMapper, error) {
+	m := &SymbolMapper{
+		toPrime:    make(map[string]string, len(internalToPrime)),
+		toInternal: make(map[string]string, len(internalToPrime)),
+	}
+	for internal, prime := range internalToPrime {
+		if other, ok := m.toInternal[prime]; ok {
+			return nil, fmt.Errorf("prime symbol %s is mapped from both %s and %s", prime, other, internal)
+		}
+		m.toPrime[internal] = prime
+		m.toInternal[prime] = internal
+	}
+	return m, nil
+}
+
+// LoadSymbolMapper reads a JSON object of internal identifiers to Prime symbols
+func LoadSymbolMapper(path string) (*SymbolMapper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var mapping map[string]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("invalid symbol map %s: %w", path, err)
+	}
+	return NewSymbolMapper(mapping)
+}
+
+// ToPrime returns the Prime symbol for an internal identifier
+func (m *SymbolMapper) ToPrime(internal string) string {
+	if m == nil {
+		return internal
+	}
+	if prime, ok := m.toPrime[internal]; ok {
+		return prime
+	}
+	return internal
+}
+
+// ToInternal returns the internal identifier for a Prime symbol
+func (m *SymbolMapper) ToInternal(prime string) string {
+	if m == nil {
+		return prime
+	}
+	if internal, ok := m.toInternal[prime]; ok {
+		return internal
+	}
+	return prime
+}
@@ -0,0 +1,66 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSymbolMapper(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"valid map", write("valid.json", `{"ETHUSD.X": "ETH-USD"}`), false},
+		{"missing file", filepath.Join(dir, "missing.json"), true},
+		{"invalid json", write("invalid.json", `{"ETHUSD.X":`), true},
+		{"duplicate prime symbol", write("dup.json", `{"ETHUSD.X": "ETH-USD", "ETH.USD": "ETH-USD"}`), true},
+	}
+	for _, tt := range tests {
+		mapper, err := LoadSymbolMapper(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: err = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if got := mapper.ToPrime("ETHUSD.X"); got != "ETH-USD" {
+			t.Errorf("%s: ToPrime = %q", tt.name, got)
+		}
+		if got := mapper.ToInternal("ETH-USD"); got != "ETHUSD.X" {
+			t.Errorf("%s: ToInternal = %q", tt.name, got)
+		}
+		if got := mapper.ToPrime("BTC-USD"); got != "BTC-USD" {
+			t.Errorf("%s: unmapped ToPrime = %q", tt.name, got)
+		}
+	}
+
+	var mapper *SymbolMapper
+	if mapper.ToPrime("ETHUSD.X") != "ETHUSD.X" || mapper.ToInternal("ETH-USD") != "ETH-USD" {
+		t.Error("nil mapper translated a symbol")
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TagDiffKind classifies a difference between a message and its template
+type TagDiffKind string
+
+const (
+	TagMissing   TagDiffKind = "MISSING"   // in the template but not the message
+	TagExtra     TagDiffKind = "EXTRA"     // in the message but not the template
+	TagFormat    TagDiffKind = "FORMAT"    // present in both but formatted differently
+	TagValue     TagDiffKind = "VALUE"     // same format, different value
+	TagDuplicate TagDiffKind = "DUPLICATE" // repeated outside a repeating group
+)
+
+// TagDiff is one difference found by DiffFIX
+type TagDiff struct {
+	Kind TagDiffKind
+	Tag  int
+	Got  string // value in the message
+	Want string // value in the template
+	Note string
+}
+
+// String formats the difference for display, e.g. "MISSING 59 (TimeInForce) want=1"
+func (d TagDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-9s %d", d.Kind, d.Tag)
+	if name, ok := tagNames[d.Tag]; ok {
+		fmt.Fprintf(&b, " (%s)", name)
+	}
+	if d.Kind != TagMissing {
+		fmt.Fprintf(&b, " got=%q", d.Got)
+	}
+	if d.Kind != TagExtra && d.Kind != TagDuplicate {
+		fmt.Fprintf(&b, " want=%q", d.Want)
+	}
+	if d.Note != "" {
+		b.WriteString(" - " + d.Note)
+	}
+	return b.String()
+}
+
+// tagNames names the tags the client sends and receives, for readable diffs
+var tagNames = map[int]string{
+	1: "Account", 6: "AvgPx", 8: "BeginString", 9: "BodyLength", 10: "CheckSum",
+	11: "ClOrdID", 12: "Commission", 13: "CommType", 14: "CumQty", 17: "ExecID", 18: "ExecInst",
+	31: "LastPx", 32: "LastShares", 34: "MsgSeqNum", 35: "MsgType", 37: "OrderID",
+	38: "OrderQty", 39: "OrdStatus", 40: "OrdType", 41: "OrigClOrdID", 43: "PossDupFlag",
+	44: "Price", 49: "SenderCompID", 52: "SendingTime", 54: "Side", 55: "Symbol",
+	56: "TargetCompID", 58: "Text", 59: "TimeInForce", 60: "TransactTime", 95: "RawDataLength",
+	96: "RawData", 97: "PossResend", 98: "EncryptMethod", 99: "StopPx", 102: "CxlRejReason",
+	103: "OrdRejReason", 108: "HeartBtInt", 126: "ExpireTime", 150: "ExecType", 151: "LeavesQty",
+	152: "CashOrderQty", 168: "EffectiveTime", 554: "Password", 847: "TargetStrategy", 849: "ParticipationRate",
+	9406: "DropCopyFlag", 9407: "AccessKey",
+}
+
+// volatileTags change on every message, so only their format is compared
+var volatileTags = map[int]bool{
+	9: true, 10: true, 11: true, 17: true, 34: true, 37: true, 41: true, 52: true,
+	60: true, 95: true, 96: true, 126: true, 168: true,
+}
+
+// groupTags start or belong to repeating groups and may legitimately repeat
+var groupTags = map[int]bool{136: true, 137: true, 138: true, 139: true}
+
+type rawField struct {
+	tag   int
+	value string
+}
+
+// parseRawFIX splits a FIX message as found in logs, delimited by SOH or '|'
+func parseRawFIX(raw string) ([]rawField, error) {
+	raw = strings.TrimSpace(raw)
+	delim := "\x01"
+	if !strings.Contains(raw, delim) {
+		delim = "|"
+	}
+
+	var fields []rawField
+	for _, part := range strings.Split(raw, delim) {
+		if part == "" {
+			continue
+		}
+		tag, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed field %q", part)
+		}
+		n, err := strconv.Atoi(tag)
+		if err != nil {
+			return nil, fmt.Errorf("malformed tag in field %q", part)
+		}
+		fields = append(fields, rawField{tag: n, value: value})
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("empty message")
+	}
+	return fields, nil
+}
+
+var (
+	fixTimestampPattern = regexp.MustCompile(`^\d{8}-\d{2}:\d{2}:\d{2}(\.\d{3,9})?$`)
+	fixIntPattern       = regexp.MustCompile(`^-?\d+$`)
+	fixDecimalPattern   = regexp.MustCompile(`^-?\d*\.\d+$`)
+)
+
+// valueFormat classifies value by shape so differently formatted values of the
+// same tag stand out, e.g. a timestamp without milliseconds or "1e-3" for 0.001
+func valueFormat(value string) string {
+	switch {
+	case value == "":
+		return "empty"
+	case strings.TrimSpace(value) != value:
+		return "padded"
+	case fixTimestampPattern.MatchString(value):
+		if len(value) == 17 {
+			return "timestamp (seconds)"
+		}
+		return fmt.Sprintf("timestamp (%d fractional digits)", len(value)-18)
+	case fixIntPattern.MatchString(value):
+		return "integer"
+	case fixDecimalPattern.MatchString(value):
+		return "decimal"
+	case strings.ContainsAny(value, "eE") && isFloat(value):
+		return "exponent"
+	case value == strings.ToUpper(value):
+		return "uppercase"
+	case value == strings.ToLower(value):
+		return "lowercase"
+	}
+	return "text"
+}
+
+func isFloat(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+// formatsCompatible reports whether values formatted as got may stand in for
+// values formatted as want, e.g. an integer quantity for a decimal one
+func formatsCompatible(got, want string) bool {
+	if got == want {
+		return true
+	}
+	numeric := map[string]bool{"integer": true, "decimal": true}
+	return numeric[got] && numeric[want]
+}
+
+// DiffFIX compares a message, typically one Prime rejected, with a template
+// known to be accepted, returning the missing, extra, duplicated and
+// differently formatted tags in template order followed by the extra tags.
+// IDs, sequence numbers, timestamps and lengths are compared by format only.
+func DiffFIX(message, template string) ([]TagDiff, error) {
+	got, err := parseRawFIX(message)
+	if err != nil {
+		return nil, fmt.Errorf("message: %w", err)
+	}
+	want, err := parseRawFIX(template)
+	if err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+
+	gotValues := make(map[int]string, len(got))
+	var diffs []TagDiff
+	for _, field := range got {
+		if _, seen := gotValues[field.tag]; seen && !groupTags[field.tag] {
+			diffs = append(diffs, TagDiff{Kind: TagDuplicate, Tag: field.tag, Got: field.value})
+			continue
+		}
+		gotValues[field.tag] = field.value
+	}
+
+	wantTags := make(map[int]bool, len(want))
+	for _, field := range want {
+		if wantTags[field.tag] {
+			continue
+		}
+		wantTags[field.tag] = true
+
+		value, ok := gotValues[field.tag]
+		if !ok {
+			diffs = append(diffs, TagDiff{Kind: TagMissing, Tag: field.tag, Want: field.value})
+			continue
+		}
+		gotFormat, wantFormat := valueFormat(value), valueFormat(field.value)
+		switch {
+		case !formatsCompatible(gotFormat, wantFormat):
+			diffs = append(diffs, TagDiff{Kind: TagFormat, Tag: field.tag, Got: value, Want: field.value,
+				Note: fmt.Sprintf("%s, expected %s", gotFormat, wantFormat)})
+		case value != field.value && !volatileTags[field.tag]:
+			diffs = append(diffs, TagDiff{Kind: TagValue, Tag: field.tag, Got: value, Want: field.value})
+		}
+	}
+
+	for _, field := range got {
+		if !wantTags[field.tag] {
+			diffs = append(diffs, TagDiff{Kind: TagExtra, Tag: field.tag, Got: field.value})
+			wantTags[field.tag] = true // report each extra tag once
+		}
+	}
+	return diffs, nil
+}
+
+// WriteTagDiffs writes one line per difference, or a note that there are none
+func WriteTagDiffs(w io.Writer, diffs []TagDiff) error {
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintln(w, "No differences")
+		return err
+	}
+	for _, diff := range diffs {
+		if _, err := fmt.Fprintln(w, diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
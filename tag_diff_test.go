@@ -0,0 +1,124 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValueFormat(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", "empty"},
+		{" 1", "padded"},
+		{"20250602-14:30:00", "timestamp (seconds)"},
+		{"20250602-14:30:00.123", "timestamp (3 fractional digits)"},
+		{"20250602-14:30:00.123456", "timestamp (6 fractional digits)"},
+		{"-12", "integer"},
+		{"0.001", "decimal"},
+		{".5", "decimal"},
+		{"1e-3", "exponent"},
+		{"GTC", "uppercase"},
+		{"limit", "lowercase"},
+		{"Limit", "text"},
+	}
+	for _, tt := range tests {
+		if got := valueFormat(tt.value); got != tt.want {
+			t.Errorf("valueFormat(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDiffFIX(t *testing.T) {
+	template := "8=FIX.4.2|35=D|34=7|52=20250602-14:30:00.123|11=a|55=BTC-USD|54=1|38=0.5|44=100|59=1|10=123|"
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"identical", template, nil},
+		{"volatile tags differ", "8=FIX.4.2|35=D|34=9|52=20250602-14:31:07.456|11=b|55=BTC-USD|54=1|38=0.5|44=100|59=1|10=045|", nil},
+		{"integer quantity", "8=FIX.4.2|35=D|34=7|52=20250602-14:30:00.123|11=a|55=BTC-USD|54=1|38=1|44=100|59=1|10=123|", []string{
+			`VALUE     38 (OrderQty) got="1" want="0.5"`,
+		}},
+		{"missing, extra and duplicated", "8=FIX.4.2|35=D|34=7|52=20250602-14:30:00|11=a|55=BTC-USD|55=ETH-USD|54=1|38=0.5|44=100|18=A|10=123|", []string{
+			`DUPLICATE 55 (Symbol) got="ETH-USD"`,
+			`FORMAT    52 (SendingTime) got="20250602-14:30:00" want="20250602-14:30:00.123" - timestamp (seconds), expected timestamp (3 fractional digits)`,
+			`MISSING   59 (TimeInForce) want="1"`,
+			`EXTRA     18 (ExecInst) got="A"`,
+		}},
+		{"exponent price", "8=FIX.4.2\x0135=D\x0134=7\x0152=20250602-14:30:00.123\x0111=a\x0155=BTC-USD\x0154=1\x0138=0.5\x0144=1e2\x0159=1\x0110=123\x01", []string{
+			`FORMAT    44 (Price) got="1e2" want="100" - exponent, expected integer`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs, err := DiffFIX(tt.message, template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, diff := range diffs {
+				got = append(got, diff.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("diffs:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestDiffFIXRepeatingGroups(t *testing.T) {
+	diffs, err := DiffFIX("35=D|136=2|137=1|139=4|137=1|139=4|", "35=D|136=1|137=1|139=4|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 1 || diffs[0].Kind != TagValue || diffs[0].Tag != 136 {
+		t.Fatalf("diffs %v, want only the group count", diffs)
+	}
+}
+
+func TestDiffFIXMalformed(t *testing.T) {
+	tests := []struct {
+		message, template, want string
+	}{
+		{"", "35=D|", "message: empty message"},
+		{"35=D|", "35D|", `template: malformed field "35D"`},
+		{"x=D|", "35=D|", `message: malformed tag in field "x=D"`},
+	}
+	for _, tt := range tests {
+		if _, err := DiffFIX(tt.message, tt.template); err == nil || err.Error() != tt.want {
+			t.Errorf("DiffFIX(%q, %q) = %v, want %q", tt.message, tt.template, err, tt.want)
+		}
+	}
+}
+
+func TestWriteTagDiffs(t *testing.T) {
+	var b strings.Builder
+	if err := WriteTagDiffs(&b, nil); err != nil || b.String() != "No differences\n" {
+		t.Fatalf("WriteTagDiffs(nil) wrote %q, %v", b.String(), err)
+	}
+	b.Reset()
+	diffs := []TagDiff{{Kind: TagMissing, Tag: 59, Want: "1"}, {Kind: TagExtra, Tag: 5000, Got: "x"}}
+	if err := WriteTagDiffs(&b, diffs); err != nil {
+		t.Fatal(err)
+	}
+	if want := "MISSING   59 (TimeInForce) want=\"1\"\nEXTRA     5000 got=\"x\"\n"; b.String() != want {
+		t.Fatalf("WriteTagDiffs wrote %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// TenantConfig is the configuration of one tenant: a single-session config
+// whose [DEFAULT] section is the shared defaults overlaid with the tenant's
+// own [SESSION] settings
+type TenantConfig struct {
+	Name     string
+	Settings *quickfix.Settings
+
+	defaults, session []string // config lines the settings were parsed from
+}
+
+// LoadTenantConfigs loads the FIX configuration file like LoadFIXConfig and
+// splits it into one config per [SESSION] section. Each session is a tenant
+// named by its Tenant setting, or its SenderCompID when unset, and carries its
+// own credentials (AccessKey, SigningKey, Passphrase, PortfolioId) and file
+// paths so that tenants share nothing but the defaults.
+func LoadTenantConfigs(path string) ([]TenantConfig, error) {
+	config, err := resolveConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if config, err = decryptConfigSecrets(config); err != nil {
+		return nil, err
+	}
+	return splitTenantConfigs(config)
+}
+
+func splitTenantConfigs(config string) ([]TenantConfig, error) {
+	var defaults []string
+	var sessions [][]string
+	for _, line := range strings.Split(config, "\n") {
+		switch strings.TrimSpace(line) {
+		case "[DEFAULT]":
+			continue
+		case "[SESSION]":
+			sessions = append(sessions, nil)
+			continue
+		}
+		if len(sessions) == 0 {
+			defaults = append(defaults, line)
+		} else {
+			sessions[len(sessions)-1] = append(sessions[len(sessions)-1], line)
+		}
+	}
+
+	var tenants []TenantConfig
+	names := make(map[string]bool)
+	for _, session := range sessions {
+		tenant := TenantConfig{defaults: defaults, session: session}
+		if err := tenant.parse(); err != nil {
+			return nil, err
+		}
+
+		name, err := tenant.Settings.GlobalSettings().Setting("Tenant")
+		if err != nil {
+			name, _ = tenant.Settings.GlobalSettings().Setting("SenderCompID")
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicate tenant %q, set Tenant in each [SESSION]", name)
+		}
+		names[name] = true
+		tenant.Name = name
+		tenants = append(tenants, tenant)
+	}
+	if len(tenants) == 0 {
+		return nil, errors.New("no [SESSION] configured")
+	}
+	return tenants, nil
+}
+
+// parse builds Settings from the config lines
+func (c *TenantConfig) parse() error {
+	// Later lines win, so the session's settings override the defaults
+	body := strings.Join(c.session, "\n") + "\n"
+	text := "[DEFAULT]\n" + strings.Join(c.defaults, "\n") + "\n" + body + "[SESSION]\n" + body
+	settings, err := quickfix.ParseSettings(strings.NewReader(text))
+	if err != nil {
+		return err
+	}
+	c.Settings = settings
+	return nil
+}
+
+// withSettings returns the config with settings overriding the tenant's own
+func (c TenantConfig) withSettings(settings map[string]string) (TenantConfig, error) {
+	c.session = slices.Clone(c.session)
+	for _, key := range sortedKeys(settings) {
+		c.session = append(c.session, key+"="+settings[key])
+	}
+	return c, c.parse()
+}
+
+// Tenant is one Prime FIX session with its own application, and therefore
+// its own trackers, risk limits and event streams
+type Tenant struct {
+	Name         string
+	App          *FixApplication
+	Settings     *quickfix.Settings
+	StoreFactory quickfix.MessageStoreFactory
+	LogFactory   quickfix.LogFactory
+
+	// SnapshotPath, when set, is restored on the first start and saved on every stop
+	SnapshotPath string
+
+	initiator *quickfix.Initiator
+	stores    *retainingStoreFactory // of the current initiator
+	restored  bool
+
+	// backoff restarts the tenant after a rejected logon, see Manager.Backoff
+	backoff Timer
+
+	// stop, when set, is closed to end the tenant's watchers
+	stop chan struct{}
+}
+
+// Manager runs several tenants in one process and starts and stops each of
+// them independently
+type Manager struct {
+	mu      sync.Mutex
+	tenants map[string]*Tenant
+
+	// Calendar, when set, keeps StartAll from connecting during a venue
+	// maintenance window, see RunMaintenance
+	Calendar *MaintenanceCalendar
+
+	// Clock times the maintenance check of StartAll and logon backoffs; nil
+	// uses the wall clock
+	Clock Clock
+}
+
+// NewManager creates a manager with no tenants
+func NewManager() *Manager {
+	return &Manager{tenants: make(map[string]*Tenant)}
+}
+
+// Add registers tenant, stopped. Its LogonGuard backs off through Backoff.
+func (m *Manager) Add(tenant *Tenant) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.tenants[tenant.Name]; ok {
+		return fmt.Errorf("tenant %q already exists", tenant.Name)
+	}
+	m.tenants[tenant.Name] = tenant
+	if guard := tenant.App.LogonGuard; guard != nil && guard.OnBackoff == nil {
+		name := tenant.Name
+		// Called from a session callback, which Stop would wait for
+		guard.OnBackoff = func(delay time.Duration) { go m.Backoff(name, delay) }
+	}
+	return nil
+}
+
+// Backoff disconnects the tenant name and connects it again after delay, so
+// no connection is held open while backing off. Stopping or starting the
+// tenant in the meantime cancels the reconnect.
+func (m *Manager) Backoff(name string, delay time.Duration) error {
+	if err := m.Stop(name); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tenant, ok := m.tenants[name]
+	if !ok {
+		return fmt.Errorf("tenant %q was removed while backing off", name)
+	}
+	log.Printf("Tenant %s logon rejected, reconnecting in %s", name, delay)
+	var timer Timer
+	timer = clockOrSystem(m.Clock).AfterFunc(delay, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if tenant.backoff != timer {
+			return // canceled after firing
+		}
+		if err := m.start(name); err != nil {
+			log.Printf("Tenant %s failed to reconnect: %v", name, err)
+		}
+	})
+	tenant.backoff = timer
+	return nil
+}
+
+// cancelBackoff stops a pending reconnect of tenant
+func (tenant *Tenant) cancelBackoff() {
+	if tenant.backoff != nil {
+		tenant.backoff.Stop()
+		tenant.backoff = nil
+	}
+}
+
+// Remove stops and unregisters the tenant name, ending its watchers and
+// closing its Dispatcher
+func (m *Manager) Remove(name string) error {
+	if err := m.Stop(name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	tenant, ok := m.tenants[name]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("unknown tenant %q", name)
+	}
+	delete(m.tenants, name)
+	stop := tenant.takeStop()
+	m.mu.Unlock()
+	tenant.close(stop)
+	return nil
+}
+
+// Get returns the tenant name
+func (m *Manager) Get(name string) (*Tenant, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tenant, ok := m.tenants[name]
+	return tenant, ok
+}
+
+// Tenants returns every tenant, sorted by name
+func (m *Manager) Tenants() []*Tenant {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenants := make([]*Tenant, 0, len(m.tenants))
+	for _, tenant := range m.tenants {
+		tenants = append(tenants, tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return tenants
+}
+
+// Running reports whether the tenant name is started
+func (m *Manager) Running(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tenant, ok := m.tenants[name]
+	return ok && tenant.initiator != nil
+}
+
+// Start connects the tenant name. A stopped tenant can be started again;
+// its application state is kept across the restart.
+func (m *Manager) Start(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.start(name)
+}
+
+func (m *Manager) start(name string) error {
+	tenant, ok := m.tenants[name]
+	if !ok {
+		return fmt.Errorf("unknown tenant %q", name)
+	}
+	tenant.cancelBackoff()
+	if tenant.initiator != nil {
+		return nil
+	}
+	tenant.App.draining.Store(false)
+	if tenant.App.Paper != nil {
+		log.Printf("Tenant %s is paper trading, not connecting to Prime", name)
+		return nil
+	}
+
+	// quickfix unregisters sessions on Stop, so every start needs a new initiator
+	stores := &retainingStoreFactory{MessageStoreFactory: tenant.StoreFactory}
+	initiator, err := quickfix.NewInitiator(tenant.App, stores, tenant.Settings, tenant.LogFactory)
+	if err != nil {
+		return fmt.Errorf("tenant %s: %w", name, err)
+	}
+
+	// Snapshots restore sequence numbers, so the session must exist but not be started
+	if tenant.SnapshotPath != "" && !tenant.restored {
+		if snapshot, err := LoadSnapshot(tenant.SnapshotPath); err == nil {
+			if err := tenant.App.Restore(snapshot); err != nil {
+				initiator.Stop()
+				return fmt.Errorf("tenant %s: restore snapshot: %w", name, err)
+			}
+			log.Printf("Tenant %s restored snapshot from %s taken at %s", name, tenant.SnapshotPath, snapshot.Time.Format(time.RFC3339))
+		} else if !errors.Is(err, os.ErrNotExist) {
+			initiator.Stop()
+			return fmt.Errorf("tenant %s: load snapshot: %w", name, err)
+		}
+		tenant.restored = true
+	}
+
+	if err := initiator.Start(); err != nil {
+		initiator.Stop()
+		return fmt.Errorf("tenant %s: %w", name, err)
+	}
+	tenant.initiator, tenant.stores = initiator, stores
+	return nil
+}
+
+// Stop disconnects the tenant name, saving its snapshot when configured
+func (m *Manager) Stop(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tenant, ok := m.tenants[name]
+	if !ok {
+		return fmt.Errorf("unknown tenant %q", name)
+	}
+	tenant.cancelBackoff()
+	if tenant.initiator == nil {
+		return nil
+	}
+
+	// Stop first: until then the session writes the sequence numbers, and
+	// afterwards they can only be read from its store
+	tenant.initiator.Stop()
+	snapshot := tenant.App.Snapshot()
+	snapshot.NextSenderMsgSeqNum, snapshot.NextTargetMsgSeqNum = tenant.stores.seqNums(tenant.App.SessionID())
+	tenant.initiator, tenant.stores = nil, nil
+	if tenant.SnapshotPath != "" {
+		if err := SaveSnapshot(tenant.SnapshotPath, snapshot); err != nil {
+			return fmt.Errorf("tenant %s: save snapshot: %w", name, err)
+		}
+		log.Printf("Tenant %s saved snapshot to %s", name, tenant.SnapshotPath)
+	}
+	return nil
+}
+
+// Restart stops and starts the tenant name
+func (m *Manager) Restart(name string) error {
+	if err := m.Stop(name); err != nil {
+		return err
+	}
+	return m.Start(name)
+}
+
+// StartAll starts every tenant, stopping at the first failure. During a
+// maintenance window of the Calendar it leaves them stopped.
+func (m *Manager) StartAll() error {
+	if end, in := m.Calendar.In(clockOrSystem(m.Clock).Now()); in {
+		log.Printf("In Prime maintenance until %s, not connecting", end.Format(time.RFC3339))
+		return nil
+	}
+	for _, tenant := range m.Tenants() {
+		if err := m.Start(tenant.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StopAll stops every tenant, returning the first error
+func (m *Manager) StopAll() error {
+	var first error
+	for _, tenant := range m.Tenants() {
+		if err := m.Stop(tenant.Name); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Close ends the watchers of every tenant once they are stopped, and waits
+// for their Dispatchers to handle the queued reports
+func (m *Manager) Close() {
+	m.mu.Lock()
+	stops := make(map[*Tenant]chan struct{}, len(m.tenants))
+	for _, tenant := range m.tenants {
+		stops[tenant] = tenant.takeStop()
+	}
+	m.mu.Unlock()
+
+	for tenant, stop := range stops {
+		tenant.close(stop)
+	}
+}
+
+// takeStop returns the stop channel of tenant for close, which only the
+// first caller gets; callers must hold the manager's mu
+func (tenant *Tenant) takeStop() chan struct{} {
+	stop := tenant.stop
+	tenant.stop = nil
+	return stop
+}
+
+// close ends the watchers of a stopped tenant and stops its ExecutionReport
+// workers
+func (tenant *Tenant) close(stop chan struct{}) {
+	if stop != nil {
+		close(stop)
+	}
+	if tenant.App.Dispatcher != nil {
+		tenant.App.Dispatcher.Close()
+	}
+}
+
+// ScrubSecrets zeroes the credentials of every tenant once they are stopped
+func (m *Manager) ScrubSecrets() {
+	for _, tenant := range m.Tenants() {
+		tenant.App.ScrubSecrets()
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestSplitTenantConfigs(t *testing.T) {
+	config := `[DEFAULT]
+ConnectionType=initiator
+BeginString=FIX.4.2
+TargetCompID=COIN
+MaxDailyOrders=100
+
+[SESSION]
+SenderCompID=DESK_A
+AccessKey=key-a
+
+[SESSION]
+Tenant=desk-b
+SenderCompID=DESK_B
+AccessKey=key-b
+MaxDailyOrders=5
+`
+	tenants, err := splitTenantConfigs(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tenants) != 2 || tenants[0].Name != "DESK_A" || tenants[1].Name != "desk-b" {
+		t.Fatalf("unexpected tenants %+v", tenants)
+	}
+
+	for _, tc := range []struct {
+		tenant         int
+		key, maxOrders string
+	}{
+		{0, "key-a", "100"},
+		{1, "key-b", "5"},
+	} {
+		settings := tenants[tc.tenant].Settings
+		if key, _ := settings.GlobalSettings().Setting("AccessKey"); key != tc.key {
+			t.Errorf("tenant %d: expected AccessKey %s, got %s", tc.tenant, tc.key, key)
+		}
+		if orders, _ := settings.GlobalSettings().Setting("MaxDailyOrders"); orders != tc.maxOrders {
+			t.Errorf("tenant %d: expected MaxDailyOrders %s, got %s", tc.tenant, tc.maxOrders, orders)
+		}
+		if n := len(settings.SessionSettings()); n != 1 {
+			t.Errorf("tenant %d: expected one session, got %d", tc.tenant, n)
+		}
+	}
+
+	if _, err := splitTenantConfigs(config + "[SESSION]\nSenderCompID=DESK_A\n"); err == nil {
+		t.Error("expected an error for a duplicate tenant")
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// UnknownMessagePolicy controls how inbound app messages of a type the client
+// does not handle are treated
+type UnknownMessagePolicy int
+
+const (
+	// UnknownLog logs the message and otherwise ignores it
+	UnknownLog UnknownMessagePolicy = iota
+	// UnknownHandle passes the message to the UnknownMessage handler
+	UnknownHandle
+	// UnknownReject answers the message with a BusinessMessageReject
+	UnknownReject
+)
+
+// ParseUnknownMessagePolicy parses "log", "handle" or "reject"
+func ParseUnknownMessagePolicy(value string) (UnknownMessagePolicy, error) {
+	switch value {
+	case "log":
+		return UnknownLog, nil
+	case "handle":
+		return UnknownHandle, nil
+	case "reject":
+		return UnknownReject, nil
+	}
+	return UnknownLog, fmt.Errorf("unknown message policy %q", value)
+}
+
+// unknownMessageCounts counts unknown inbound messages by MsgType
+type unknownMessageCounts struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (c *unknownMessageCounts) add(msgType string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]int)
+	}
+	c.counts[msgType]++
+}
+
+// UnknownMessageCounts returns how many inbound app messages of each
+// unhandled MsgType have been received
+func (a *FixApplication) UnknownMessageCounts() map[string]int {
+	a.unknown.mu.Lock()
+	defer a.unknown.mu.Unlock()
+
+	counts := make(map[string]int, len(a.unknown.counts))
+	for msgType, count := range a.unknown.counts {
+		counts[msgType] = count
+	}
+	return counts
+}
+
+// processUnknownMessage applies the UnknownPolicy to an unhandled message
+func (a *FixApplication) processUnknownMessage(msgType string, msg *quickfix.Message) quickfix.MessageRejectError {
+	a.unknown.add(msgType)
+
+	switch a.UnknownPolicy {
+	case UnknownHandle:
+		if a.UnknownMessage != nil {
+			a.UnknownMessage(msgType, msg)
+			return nil
+		}
+	case UnknownReject:
+		a.logger().Println("Rejecting unsupported message type:", msgType)
+		return quickfix.UnsupportedMessageType()
+	}
+	a.logger().Println("Ignoring unsupported message type:", msgType)
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func TestParseUnknownMessagePolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    UnknownMessagePolicy
+		wantErr bool
+	}{
+		{"log", UnknownLog, false},
+		{"handle", UnknownHandle, false},
+		{"reject", UnknownReject, false},
+		{"drop", UnknownLog, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnknownMessagePolicy(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%q: got %v, %v", tt.value, got, err)
+		}
+	}
+}
+
+func TestProcessUnknownMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      UnknownMessagePolicy
+		withHandler bool
+		wantReject  bool
+		wantHandled bool
+	}{
+		{"log", UnknownLog, true, false, false},
+		{"handle", UnknownHandle, true, false, true},
+		{"handle without handler", UnknownHandle, false, false, false},
+		{"reject", UnknownReject, true, true, false},
+	}
+	for _, tt := range tests {
+		handled := ""
+		app := &FixApplication{UnknownPolicy: tt.policy}
+		if tt.withHandler {
+			app.UnknownMessage = func(msgType string, msg *quickfix.Message) { handled = msgType }
+		}
+
+		reject := app.processUnknownMessage("UZ", quickfix.NewMessage())
+		if (reject != nil) != tt.wantReject {
+			t.Errorf("%s: reject = %v, want reject %t", tt.name, reject, tt.wantReject)
+		}
+		if (handled == "UZ") != tt.wantHandled {
+			t.Errorf("%s: handler got %q, want handled %t", tt.name, handled, tt.wantHandled)
+		}
+		app.processUnknownMessage("UZ", quickfix.NewMessage())
+		if got := app.UnknownMessageCounts()["UZ"]; got != 2 {
+			t.Errorf("%s: count = %d, want 2", tt.name, got)
+		}
+	}
+}
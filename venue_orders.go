@@ -0,0 +1,249 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+// Orphan sides of an OrderListEntry
+const (
+	// OrphanLocal is an order open locally that the venue does not report open
+	OrphanLocal = "local"
+	// OrphanVenue is an order the venue reports open that is not open locally
+	OrphanVenue = "venue"
+)
+
+// OrderListEntry is an order in an OrderList. Local is empty for orders the
+// tracker does not know, and Venue for orders the venue did not report.
+type OrderListEntry struct {
+	ClOrdID string
+	OrderID string `json:",omitempty"`
+	Symbol  string
+	Side    string
+	Local   OrderState `json:",omitempty"`
+	Venue   OrderState `json:",omitempty"`
+	Orphan  string     `json:",omitempty"`
+}
+
+// OrderList is the open orders of a tenant. Complete is false when the venue
+// did not finish answering before the deadline, so orders only it knows of
+// may be missing and unanswered local orders are flagged orphans.
+type OrderList struct {
+	Tenant   string
+	Source   string
+	Complete bool
+	Orders   []OrderListEntry
+}
+
+// LocalOrders lists the open orders of the tracker
+func (a *FixApplication) LocalOrders() OrderList {
+	list := OrderList{Tenant: a.Tenant, Source: "local", Complete: true}
+	for _, order := range openOrders(a) {
+		list.Orders = append(list.Orders, OrderListEntry{
+			ClOrdID: order.ClOrdID,
+			OrderID: order.OrderID,
+			Symbol:  order.Symbol,
+			Side:    order.Side,
+			Local:   order.State,
+		})
+	}
+	return list
+}
+
+// VenueOrders asks the venue for every order it holds open with an
+// OrderMassStatusRequest, and for the status of each locally open order with
+// an OrderStatusRequest, then diffs the answers against the tracker. It
+// returns what was answered when ctx is done first.
+func (a *FixApplication) VenueOrders(ctx context.Context) (OrderList, error) {
+	if a.Tracker == nil {
+		return OrderList{}, ErrNoTracker
+	}
+	if !a.IsLoggedOn() {
+		return OrderList{}, ErrNotLoggedOn
+	}
+
+	local := openOrders(a)
+	query := a.statusQueries.start(strconv.FormatInt(a.now().UnixNano(), 10), local)
+	defer a.statusQueries.stop(query)
+
+	if err := a.Send(createMassStatusRequestMessage(query.massID, a.PortfolioId)); err != nil {
+		return OrderList{}, err
+	}
+	for _, order := range local {
+		order.Symbol = a.Symbols.ToPrime(order.Symbol)
+		if err := a.Send(createStatusRequestMessage(order)); err != nil {
+			return OrderList{}, err
+		}
+	}
+
+	complete := true
+	select {
+	case <-query.done:
+	case <-ctx.Done():
+		complete = false
+	}
+	return a.diffVenueOrders(local, a.statusQueries.answersOf(query), complete), nil
+}
+
+// diffVenueOrders lists the locally open orders and the status answers of
+// the venue, flagging those open on one side only
+func (a *FixApplication) diffVenueOrders(local []Order, reports map[string]ExecutionReport, complete bool) OrderList {
+	list := OrderList{Tenant: a.Tenant, Source: "venue", Complete: complete}
+	seen := make(map[string]bool)
+	for _, order := range local {
+		seen[order.ClOrdID] = true
+		entry := OrderListEntry{ClOrdID: order.ClOrdID, OrderID: order.OrderID, Symbol: order.Symbol, Side: order.Side, Local: order.State}
+		if report, ok := reports[order.ClOrdID]; ok {
+			entry.Venue = orderStateFromOrdStatus[report.OrdStatus]
+		}
+		if entry.Venue == "" || entry.Venue.Terminal() {
+			entry.Orphan = OrphanLocal
+		}
+		list.Orders = append(list.Orders, entry)
+	}
+	for clOrdID, report := range reports {
+		state := orderStateFromOrdStatus[report.OrdStatus]
+		if seen[clOrdID] || state == "" || state.Terminal() {
+			continue
+		}
+		entry := OrderListEntry{ClOrdID: clOrdID, OrderID: report.OrderID, Symbol: report.Symbol, Side: SideName(report.Side), Venue: state, Orphan: OrphanVenue}
+		if order, ok := a.Tracker.Get(clOrdID); ok {
+			entry.Local = order.State
+		}
+		list.Orders = append(list.Orders, entry)
+	}
+	sortOrderList(list.Orders)
+	return list
+}
+
+func sortOrderList(orders []OrderListEntry) {
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ClOrdID < orders[j].ClOrdID })
+}
+
+// statusQueries routes the status answers (ExecType I) of the venue to the
+// VenueOrders calls waiting on them
+type statusQueries struct {
+	mu      sync.Mutex
+	queries map[*statusQuery]struct{}
+}
+
+type statusQuery struct {
+	massID   string
+	pending  map[string]bool // ClOrdIDs asked for individually, not yet answered
+	reports  map[string]ExecutionReport
+	massDone bool
+	massSeen int
+	done     chan struct{}
+}
+
+func (s *statusQueries) start(massID string, orders []Order) *statusQuery {
+	query := &statusQuery{
+		massID:  massID,
+		pending: make(map[string]bool),
+		reports: make(map[string]ExecutionReport),
+		done:    make(chan struct{}),
+	}
+	for _, order := range orders {
+		query.pending[order.ClOrdID] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queries == nil {
+		s.queries = make(map[*statusQuery]struct{})
+	}
+	s.queries[query] = struct{}{}
+	return query
+}
+
+func (s *statusQueries) stop(query *statusQuery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.queries, query)
+}
+
+// offer hands a status answer to the queries waiting on it, reporting
+// whether it answered a mass status request. Such answers may be of orders
+// the tracker does not know.
+func (s *statusQueries) offer(report ExecutionReport, msg *quickfix.Message) bool {
+	if report.ExecType != "I" { // Order Status
+		return false
+	}
+	massID := bodyString(msg, quickfix.Tag(584)) // MassStatusReqID
+	total, _ := strconv.Atoi(bodyString(msg, quickfix.Tag(911)))
+	last := bodyString(msg, quickfix.Tag(912)) == "Y" // LastRptRequested
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	mass := false
+	for query := range s.queries {
+		switch {
+		case massID != "" && massID == query.massID:
+			mass = true
+			query.massSeen++
+			query.massDone = last || total > 0 && query.massSeen >= total
+			if report.ClOrdID != "" {
+				query.record(report)
+			}
+		case query.pending[report.ClOrdID]:
+			query.record(report)
+		default:
+			continue
+		}
+		if query.massDone && len(query.pending) == 0 {
+			select {
+			case <-query.done:
+			default:
+				close(query.done)
+			}
+		}
+	}
+	return mass
+}
+
+func (q *statusQuery) record(report ExecutionReport) {
+	delete(q.pending, report.ClOrdID)
+	q.reports[report.ClOrdID] = report
+}
+
+func (s *statusQueries) answersOf(query *statusQuery) map[string]ExecutionReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reports := make(map[string]ExecutionReport, len(query.reports))
+	for clOrdID, report := range query.reports {
+		reports[clOrdID] = report
+	}
+	return reports
+}
+
+// createMassStatusRequestMessage builds an OrderMassStatusRequest (35=AF)
+// for every order of the portfolio
+func createMassStatusRequestMessage(massID, portfolioId string) *quickfix.Message {
+	request := quickfix.NewMessage()
+	request.Header.SetField(quickfix.Tag(35), quickfix.FIXString("AF")) // MsgType = OrderMassStatusRequest
+
+	request.Body.SetString(quickfix.Tag(584), massID) // MassStatusReqID
+	request.Body.SetString(quickfix.Tag(585), "7")    // MassStatusReqType = Status for all orders
+	if portfolioId != "" {
+		request.Body.SetString(quickfix.Tag(1), portfolioId) // Account (Portfolio ID)
+	}
+	return request
+}
@@ -0,0 +1,92 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+func statusAnswer(clOrdID, ordStatus, massID, last string) (ExecutionReport, *quickfix.Message) {
+	msg := quickfix.NewMessage()
+	msg.Body.SetString(quickfix.Tag(11), clOrdID)
+	if massID != "" {
+		msg.Body.SetString(quickfix.Tag(584), massID)
+		msg.Body.SetString(quickfix.Tag(912), last)
+	}
+	return ExecutionReport{ExecType: "I", ClOrdID: clOrdID, OrdStatus: ordStatus, Symbol: "ETH-USD", Side: "1"}, msg
+}
+
+func TestVenueOrdersDiffFlagsOrphans(t *testing.T) {
+	app := &FixApplication{Tenant: "desk", Tracker: NewOrderTracker(time.Minute)}
+	app.Tracker.Add(Order{ClOrdID: "both", Symbol: "ETH-USD", Side: "BUY", State: OrderNew})
+	app.Tracker.Add(Order{ClOrdID: "filled-at-venue", Symbol: "ETH-USD", Side: "BUY", State: OrderNew})
+	app.Tracker.Add(Order{ClOrdID: "unanswered", Symbol: "ETH-USD", Side: "BUY", State: OrderNew})
+	local := openOrders(app)
+
+	var queries statusQueries
+	query := queries.start("mass-1", local)
+	for _, answer := range []struct{ clOrdID, status, massID, last string }{
+		{"both", "0", "mass-1", "N"},
+		{"placed-elsewhere", "1", "mass-1", "N"},
+		{"filled-at-venue", "2", "", ""},
+		{"someone-elses", "0", "mass-2", "Y"},
+	} {
+		report, msg := statusAnswer(answer.clOrdID, answer.status, answer.massID, answer.last)
+		if mass := queries.offer(report, msg); mass != (answer.massID == "mass-1") {
+			t.Errorf("offer of %s reported mass status %v", answer.clOrdID, mass)
+		}
+	}
+	select {
+	case <-query.done:
+		t.Fatal("query done with the mass status and an order unanswered")
+	default:
+	}
+	report, msg := statusAnswer("", "", "mass-1", "Y")
+	queries.offer(report, msg)
+	report, msg = statusAnswer("unanswered", "8", "", "")
+	queries.offer(report, msg)
+	select {
+	case <-query.done:
+	default:
+		t.Fatal("query not done once every answer arrived")
+	}
+	queries.stop(query)
+
+	list := app.diffVenueOrders(local, queries.answersOf(query), true)
+	want := map[string]OrderListEntry{
+		"both":             {Local: OrderNew, Venue: OrderNew},
+		"filled-at-venue":  {Local: OrderNew, Venue: OrderFilled, Orphan: OrphanLocal},
+		"placed-elsewhere": {Venue: OrderPartiallyFilled, Orphan: OrphanVenue},
+		"unanswered":       {Local: OrderNew, Venue: OrderRejected, Orphan: OrphanLocal},
+	}
+	if len(list.Orders) != len(want) {
+		t.Fatalf("orders = %+v", list.Orders)
+	}
+	for _, entry := range list.Orders {
+		w := want[entry.ClOrdID]
+		if entry.Local != w.Local || entry.Venue != w.Venue || entry.Orphan != w.Orphan {
+			t.Errorf("%s = %+v, want %+v", entry.ClOrdID, entry, w)
+		}
+	}
+
+	// Without an answer the venue side is unknown, so the order is flagged too
+	list = app.diffVenueOrders(local, nil, false)
+	if list.Complete || list.Orders[0].Orphan != OrphanLocal {
+		t.Errorf("unanswered list = %+v", list)
+	}
+}
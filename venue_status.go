@@ -0,0 +1,148 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSymbolHalted is returned when placing an order in a symbol that is not tradable
+var ErrSymbolHalted = errors.New("symbol is not tradable")
+
+// haltTextPatterns are reject Text fragments taken to mean the symbol is halted
+var haltTextPatterns = []string{
+	"halt",
+	"trading disabled",
+	"trading is disabled",
+	"not tradable",
+	"suspended",
+	"cancel only",
+}
+
+// SymbolStatusEvent reports a symbol halting or resuming
+type SymbolStatusEvent struct {
+	Symbol   string
+	Tradable bool
+	Reason   string
+	Time     time.Time
+}
+
+// VenueStatus tracks which symbols are halted, from SecurityStatus (35=f)
+// messages when Prime sends them and otherwise inferred from reject text
+type VenueStatus struct {
+	mu     sync.Mutex
+	halted map[string]string // symbol -> reason
+
+	// OnStatusChange is called whenever a symbol halts or resumes
+	OnStatusChange func(event SymbolStatusEvent)
+}
+
+// NewVenueStatus creates a VenueStatus with every symbol tradable
+func NewVenueStatus() *VenueStatus {
+	return &VenueStatus{halted: make(map[string]string)}
+}
+
+// Tradable reports whether symbol is believed to be open for trading
+func (v *VenueStatus) Tradable(symbol string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	_, halted := v.halted[symbol]
+	return !halted
+}
+
+// OnSecurityStatus applies the SecurityTradingStatus (326) of a SecurityStatus
+// (35=f) message for symbol
+func (v *VenueStatus) OnSecurityStatus(symbol, status string) {
+	switch status {
+	case "2", "18": // Trading halt, Not available for trading
+		v.set(symbol, false, "SecurityTradingStatus="+status)
+	case "3", "17": // Resume, Ready to trade
+		v.set(symbol, true, "SecurityTradingStatus="+status)
+	}
+}
+
+// OnExecutionReport infers halts from reject text, and resumption from fills
+func (v *VenueStatus) OnExecutionReport(report ExecutionReport) {
+	switch report.ExecType {
+	case "8": // Rejected
+		text := strings.ToLower(report.Text)
+		for _, pattern := range haltTextPatterns {
+			if strings.Contains(text, pattern) {
+				v.set(report.Symbol, false, report.Text)
+				return
+			}
+		}
+	case "1", "2", "F": // Partial fill, Fill, Trade
+		v.set(report.Symbol, true, "fill received")
+	}
+}
+
+// Resume marks symbol tradable again, clearing a halt that was inferred from
+// rejects and would otherwise block orders until a fill is seen. It reports
+// whether symbol was halted
+func (v *VenueStatus) Resume(symbol string) bool {
+	return v.set(symbol, true, "resumed manually")
+}
+
+// Halted returns the reason each halted symbol is halted for
+func (v *VenueStatus) Halted() map[string]string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return maps.Clone(v.halted)
+}
+
+// set records whether symbol is tradable, reporting whether that changed
+func (v *VenueStatus) set(symbol string, tradable bool, reason string) bool {
+	if symbol == "" {
+		return false
+	}
+
+	v.mu.Lock()
+	_, halted := v.halted[symbol]
+	if halted == !tradable {
+		v.mu.Unlock()
+		return false
+	}
+	if tradable {
+		delete(v.halted, symbol)
+	} else {
+		v.halted[symbol] = reason
+	}
+	v.mu.Unlock()
+
+	log.Printf("Symbol %s tradable=%t: %s", symbol, tradable, reason)
+	if v.OnStatusChange != nil {
+		v.OnStatusChange(SymbolStatusEvent{Symbol: symbol, Tradable: tradable, Reason: reason, Time: time.Now()})
+	}
+	return true
+}
+
+// onSymbolStatus journals a symbol halting or resuming and alerts on it
+func (a *FixApplication) onSymbolStatus(event SymbolStatusEvent) {
+	if event.Tradable {
+		a.journal(a.SessionID(), SymbolResumed, event.Symbol+": "+event.Reason)
+		a.alert("symbol-resumed:"+event.Symbol, "info", fmt.Sprintf("%s is tradable again: %s", event.Symbol, event.Reason))
+		return
+	}
+	a.journal(a.SessionID(), SymbolHalted, event.Symbol+": "+event.Reason)
+	a.alert("symbol-halted:"+event.Symbol, "warning",
+		fmt.Sprintf("%s halted, orders blocked until it resumes: %s", event.Symbol, event.Reason))
+}
@@ -0,0 +1,195 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// alertRecorder is an AlertSink keeping the alerts it is sent
+type alertRecorder struct {
+	mu     sync.Mutex
+	alerts []Alert
+}
+
+func (s *alertRecorder) Send(alert Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alerts = append(s.alerts, alert)
+	return nil
+}
+
+func (s *alertRecorder) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for _, alert := range s.alerts {
+		keys = append(keys, alert.Key)
+	}
+	return keys
+}
+
+func TestVenueStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		apply    func(v *VenueStatus)
+		tradable bool
+		events   int
+	}{
+		{"tradable by default", func(v *VenueStatus) {}, true, 0},
+		{"security status halt", func(v *VenueStatus) { v.OnSecurityStatus("ETH-USD", "2") }, false, 1},
+		{"security status resume", func(v *VenueStatus) {
+			v.OnSecurityStatus("ETH-USD", "18")
+			v.OnSecurityStatus("ETH-USD", "17")
+		}, true, 2},
+		{"unknown security status ignored", func(v *VenueStatus) { v.OnSecurityStatus("ETH-USD", "1") }, true, 0},
+		{"halt inferred from reject text", func(v *VenueStatus) {
+			v.OnExecutionReport(ExecutionReport{ExecType: "8", Symbol: "ETH-USD", Text: "Product is in Cancel Only mode"})
+		}, false, 1},
+		{"other rejects do not halt", func(v *VenueStatus) {
+			v.OnExecutionReport(ExecutionReport{ExecType: "8", Symbol: "ETH-USD", Text: "Insufficient funds"})
+		}, true, 0},
+		{"fill clears an inferred halt", func(v *VenueStatus) {
+			v.OnExecutionReport(ExecutionReport{ExecType: "8", Symbol: "ETH-USD", Text: "trading halted"})
+			v.OnExecutionReport(ExecutionReport{ExecType: "F", Symbol: "ETH-USD"})
+		}, true, 2},
+		{"repeated halts change once", func(v *VenueStatus) {
+			v.OnSecurityStatus("ETH-USD", "2")
+			v.OnExecutionReport(ExecutionReport{ExecType: "8", Symbol: "ETH-USD", Text: "trading halted"})
+		}, false, 1},
+		{"resume clears an inferred halt", func(v *VenueStatus) {
+			v.OnExecutionReport(ExecutionReport{ExecType: "8", Symbol: "ETH-USD", Text: "symbol suspended"})
+			v.Resume("ETH-USD")
+		}, true, 2},
+		{"other symbols unaffected", func(v *VenueStatus) { v.OnSecurityStatus("BTC-USD", "2") }, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			venue := NewVenueStatus()
+			var events []SymbolStatusEvent
+			venue.OnStatusChange = func(event SymbolStatusEvent) { events = append(events, event) }
+			tt.apply(venue)
+			if got := venue.Tradable("ETH-USD"); got != tt.tradable {
+				t.Errorf("Tradable = %t, want %t", got, tt.tradable)
+			}
+			if len(events) != tt.events {
+				t.Errorf("events = %+v, want %d", events, tt.events)
+			}
+			if _, halted := venue.Halted()["ETH-USD"]; halted == tt.tradable {
+				t.Errorf("Halted = %v", venue.Halted())
+			}
+		})
+	}
+}
+
+func TestVenueStatusResumeReportsHalt(t *testing.T) {
+	venue := NewVenueStatus()
+	if venue.Resume("ETH-USD") {
+		t.Error("resumed a symbol that was not halted")
+	}
+	venue.OnSecurityStatus("ETH-USD", "2")
+	if !venue.Resume("ETH-USD") {
+		t.Error("did not resume a halted symbol")
+	}
+	if venue.Resume("") {
+		t.Error("resumed an empty symbol")
+	}
+}
+
+func TestSymbolStatusIsJournaledAndAlerted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	journal, err := OpenSessionJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	sink := &alertRecorder{}
+	app := &FixApplication{Venue: NewVenueStatus(), Journal: journal, Alerts: NewAlerter(time.Minute, sink)}
+	app.Venue.OnStatusChange = app.onSymbolStatus
+
+	app.Venue.OnExecutionReport(ExecutionReport{ExecType: "8", Symbol: "ETH-USD", Text: "trading halted"})
+	if _, err := app.PlaceOrder(OrderRequest{Symbol: "ETH-USD", Side: "BUY", OrdType: "LIMIT", Quantity: "1", LimitPrice: "100"}); !errors.Is(err, ErrSymbolHalted) {
+		t.Errorf("order in halted symbol: %v", err)
+	}
+	app.Venue.Resume("ETH-USD")
+	app.Alerts.Flush()
+
+	events, err := ReadSessionJournal(path, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Event != SymbolHalted || events[1].Event != SymbolResumed {
+		t.Fatalf("journal = %+v", events)
+	}
+	if events[0].Detail != "ETH-USD: trading halted" {
+		t.Errorf("halt detail = %q", events[0].Detail)
+	}
+	// Alerts are delivered asynchronously, so in no particular order
+	if keys := sink.keys(); !slices.Equal(slices.Sorted(slices.Values(keys)), []string{"symbol-halted:ETH-USD", "symbol-resumed:ETH-USD"}) {
+		t.Errorf("alerts = %v", keys)
+	}
+}
+
+func TestAdminVenueResume(t *testing.T) {
+	app := &FixApplication{Venue: NewVenueStatus()}
+	app.Venue.OnSecurityStatus("ETH-USD", "2")
+	manager := NewManager()
+	if err := manager.Add(&Tenant{Name: "desk", App: app}); err != nil {
+		t.Fatal(err)
+	}
+	handler := NewAdminHandler(manager)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/venue", nil))
+	var halted map[string]map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &halted); err != nil {
+		t.Fatal(err, rec.Body.String())
+	}
+	if halted["desk"]["ETH-USD"] != "SecurityTradingStatus=2" {
+		t.Errorf("halted = %v", halted)
+	}
+
+	for _, tt := range []struct {
+		query  string
+		status int
+	}{
+		{"", http.StatusBadRequest},
+		{"?symbol=ETH-USD&tenant=other", http.StatusBadRequest},
+	} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/venue/resume"+tt.query, nil))
+		if rec.Code != tt.status {
+			t.Errorf("resume%s answered %d, want %d", tt.query, rec.Code, tt.status)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/venue/resume?symbol=ETH-USD", nil))
+	var resumed map[string]bool
+	if err := json.Unmarshal(rec.Body.Bytes(), &resumed); err != nil {
+		t.Fatal(err, rec.Body.String())
+	}
+	if !resumed["desk"] || !app.Venue.Tradable("ETH-USD") {
+		t.Errorf("resumed = %v, tradable = %t", resumed, app.Venue.Tradable("ETH-USD"))
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WireTap records the raw bytes of the FIX connection exactly as they cross
+// the socket, before quickfix frames or parses them, to diagnose framing and
+// encoding problems without tcpdump. Every chunk is stamped with the wall
+// clock and the monotonic time elapsed since the tap started. The capture
+// includes the logon credentials, so it is written owner-readable only.
+type WireTap struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	conns int
+
+	// Hex writes a hex dump of each chunk instead of the escaped text
+	Hex bool
+}
+
+// NewWireTap creates a tap writing to w
+func NewWireTap(w io.Writer, hexDump bool) *WireTap {
+	return &WireTap{w: w, start: time.Now(), Hex: hexDump}
+}
+
+// record writes one chunk read from or written to the venue on connection conn
+func (t *WireTap) record(conn int, direction string, data []byte) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fmt.Fprintf(t.w, "%s +%.6fs conn=%d %s %d bytes\n",
+		now.UTC().Format("2006-01-02T15:04:05.000000Z"), now.Sub(t.start).Seconds(), conn, direction, len(data))
+	if t.Hex {
+		io.WriteString(t.w, hex.Dump(data))
+	} else {
+		fmt.Fprintln(t.w, escapeWire(data))
+	}
+}
+
+// escapeWire shows SOH as '|' and other unprintable bytes as \xNN
+func escapeWire(data []byte) string {
+	var b strings.Builder
+	for _, c := range data {
+		switch {
+		case c == 0x01:
+			b.WriteByte('|')
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Relay listens on a loopback port and forwards every connection to target,
+// recording the bytes in both directions. Pointing the session at the
+// returned listener's address puts the tap in the path; the connection to
+// target must be cleartext, as it is when stunnel terminates TLS.
+func (t *WireTap) Relay(target string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for {
+			client, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					log.Println("Wire tap stopped accepting:", err)
+				}
+				return
+			}
+			go t.relay(client, target)
+		}
+	}()
+	return listener, nil
+}
+
+func (t *WireTap) relay(client net.Conn, target string) {
+	t.mu.Lock()
+	t.conns++
+	conn := t.conns
+	t.mu.Unlock()
+
+	venue, err := net.DialTimeout("tcp", target, 10*time.Second)
+	if err != nil {
+		log.Printf("Wire tap failed to connect to %s: %v", target, err)
+		client.Close()
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	copyTapped := func(dst, src net.Conn, direction string) {
+		buf := make([]byte, 32<<10)
+		for {
+			n, err := src.Read(buf)
+			if n > 0 {
+				t.record(conn, direction, buf[:n])
+				if _, err := dst.Write(buf[:n]); err != nil {
+					break
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+		done <- struct{}{}
+	}
+	go copyTapped(venue, client, "OUT")
+	go copyTapped(client, venue, "IN")
+
+	// Either side closing ends the connection
+	<-done
+	client.Close()
+	venue.Close()
+	<-done
+}
+
+// wireTapConfig starts a wire tap writing to path and returns config with the
+// session connecting through it
+func wireTapConfig(config TenantConfig, path string) (TenantConfig, error) {
+	global := config.Settings.GlobalSettings()
+	for _, setting := range []string{"SocketUseSSL", "SocketCertificateFile", "SocketPrivateKeyFile"} {
+		if global.HasSetting(setting) {
+			return config, fmt.Errorf("WireTapPath needs a cleartext connection but %s is set, terminate TLS with stunnel instead", setting)
+		}
+	}
+	host, err := global.Setting("SocketConnectHost")
+	if err != nil {
+		return config, err
+	}
+	port, err := global.Setting("SocketConnectPort")
+	if err != nil {
+		return config, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return config, err
+	}
+	hexDump, _ := global.BoolSetting("WireTapHex")
+	listener, err := NewWireTap(file, hexDump).Relay(net.JoinHostPort(host, port))
+	if err != nil {
+		file.Close()
+		return config, err
+	}
+
+	addr := listener.Addr().(*net.TCPAddr)
+	log.Printf("Wire tap on %s relaying to %s:%s, writing to %s", addr, host, port, path)
+	return config.withSettings(map[string]string{
+		"SocketConnectHost": addr.IP.String(),
+		"SocketConnectPort": strconv.Itoa(addr.Port),
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright 2025-present Coinbase Global, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEscapeWire(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{[]byte("8=FIX.4.2\x019=5\x01"), "8=FIX.4.2|9=5|"},
+		{[]byte("58=caf\xc3\xa9\r\n"), `58=caf\xc3\xa9\x0d\x0a`},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := escapeWire(tt.data); got != tt.want {
+			t.Errorf("escapeWire(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestWireTapRelay(t *testing.T) {
+	venue, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer venue.Close()
+	go func() {
+		for {
+			conn, err := venue.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	var capture bytes.Buffer
+	tap := NewWireTap(&capture, false)
+	listener, err := tap.Relay(venue.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	message := "8=FIX.4.2\x0135=0\x01"
+	if _, err := client.Write([]byte(message)); err != nil {
+		t.Fatal(err)
+	}
+	echo := make([]byte, len(message))
+	if _, err := io.ReadFull(client, echo); err != nil || string(echo) != message {
+		t.Fatalf("relayed %q, %v", echo, err)
+	}
+
+	tap.mu.Lock()
+	got := capture.String()
+	tap.mu.Unlock()
+	for _, want := range []string{"conn=1 OUT 15 bytes\n8=FIX.4.2|35=0|\n", "conn=1 IN 15 bytes\n8=FIX.4.2|35=0|\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("capture %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestWireTapConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   string
+		wantErr bool
+	}{
+		{"cleartext", "SocketConnectHost=127.0.0.1\nSocketConnectPort=4198", false},
+		{"TLS", "SocketConnectHost=127.0.0.1\nSocketConnectPort=4198\nSocketUseSSL=Y", true},
+		{"no port", "SocketConnectHost=127.0.0.1", true},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		tenants, err := LoadTenantConfigs(writeSeqRepairConfig(t, dir, tt.extra))
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, err := wireTapConfig(tenants[0], filepath.Join(dir, "wire.log"))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if port, _ := config.Settings.GlobalSettings().Setting("SocketConnectPort"); port == "4198" {
+			t.Errorf("%s: session still connects to the venue directly", tt.name)
+		}
+	}
+}